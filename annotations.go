@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/metcalfc/brr/internal/reader"
+	"github.com/metcalfc/brr/internal/state"
+)
+
+// noAnnotationsNotice is shown when the notes at positions are asked for
+// before any were written.
+const noAnnotationsNotice = "No notes at positions yet; press I to write one"
+
+// newAnnotation attaches text to the current word.
+func newAnnotation(r *reader.Reader, text string, now time.Time) state.Annotation {
+	return state.Annotation{
+		WordIndex: r.Position(),
+		Text:      strings.TrimSpace(text),
+		Chapter:   r.CurrentChapterTitle(),
+		Created:   now,
+	}
+}
+
+// addAnnotation saves text as a note at r's position in the book saved
+// under hash, returning a notice saying how it went. Blank notes are not
+// saved.
+func addAnnotation(store *state.StateStore, hash string, r *reader.Reader, text string, now time.Time) string {
+	if store == nil || hash == "" {
+		return noNotesNotice
+	}
+	a := newAnnotation(r, text, now)
+	if a.Text == "" {
+		return ""
+	}
+	if err := store.AddAnnotation(hash, a); err != nil {
+		return "Could not save note: " + err.Error()
+	}
+	return "Note saved at word " + groupDigits(a.WordIndex+1)
+}
+
+// annotationRef names where an annotation was written: its chapter, if
+// any, and word number.
+func annotationRef(a state.Annotation) string {
+	ref := "word " + groupDigits(a.WordIndex+1)
+	if a.Chapter != "" {
+		ref = a.Chapter + ", " + ref
+	}
+	return ref
+}
+
+// goToAnnotation moves r to where a was written, as a jump that U can
+// undo.
+func goToAnnotation(r *reader.Reader, a state.Annotation) string {
+	return goTo(r, position{word: a.WordIndex + 1})
+}
+
+// writeAnnotations writes a book's notes at positions as a Markdown
+// section, to follow its marks.
+func writeAnnotations(w io.Writer, annotations []state.Annotation) error {
+	var sb strings.Builder
+	sb.WriteString("\n## Notes at positions\n")
+	for _, a := range annotations {
+		fmt.Fprintf(&sb, "\n- %s (%s)\n", a.Text, annotationRef(a))
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
@@ -0,0 +1,73 @@
+//go:build !gui
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/metcalfc/brr/internal/state"
+)
+
+func TestAnnotations(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	m := newModel("Call me Ishmael. Some years ago, never mind how long.", 300, nil, nil)
+	key := func(keys ...tea.KeyMsg) {
+		t.Helper()
+		for _, k := range keys {
+			updated, _ := m.Update(k)
+			m = updated.(model)
+		}
+	}
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	key(runes("i"))
+	if m.annotating || m.notice != noNotesNotice {
+		t.Fatalf("without a book, I should not ask for a note: notice %q", m.notice)
+	}
+	store, _ := state.NewStateStore()
+	m.stateStore, m.fileHash = store, "abcdef1234567890abcdef1234567890"
+	key(runes("l"))
+	if m.annotationsOpen || m.notice != noAnnotationsNotice {
+		t.Errorf("L before any notes: notice %q", m.notice)
+	}
+
+	m.Jump(3)
+	key(runes("i"))
+	if !m.annotating || !m.Paused {
+		t.Fatal("I should pause and ask for a note")
+	}
+	key(runes("Who"), tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}, runes("counts?"))
+	if !strings.Contains(m.View(), "Note: Who counts?") {
+		t.Errorf("the note should be typed at the bottom, got:\n%s", m.View())
+	}
+	key(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.annotating || m.notice != "Note saved at word 4" {
+		t.Errorf("Enter should save the note: notice %q", m.notice)
+	}
+	if got := store.Annotations(m.fileHash); len(got) != 1 || got[0].Text != "Who counts?" || got[0].WordIndex != 3 {
+		t.Errorf("saved annotations = %+v", got)
+	}
+
+	// N only ever finds matches, and I writes notes whatever was searched.
+	key(runes("n"))
+	if m.annotating {
+		t.Error("N should not ask for a note")
+	}
+	key(runes("/"), runes("never"), tea.KeyMsg{Type: tea.KeyEnter}, runes("i"))
+	if !m.annotating {
+		t.Error("I should ask for a note after a search")
+	}
+	key(tea.KeyMsg{Type: tea.KeyEsc})
+
+	m.Jump(0)
+	key(runes("l"))
+	if !m.annotationsOpen || !strings.Contains(m.View(), "Who counts?") || !strings.Contains(m.View(), "word 4") {
+		t.Fatalf("L should list the notes, got:\n%s", m.View())
+	}
+	key(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.annotationsOpen || m.CurrentIndex != 3 {
+		t.Errorf("Enter should go to the note: at word %d", m.CurrentIndex)
+	}
+}
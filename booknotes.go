@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// noNotesNotice is shown when notes are asked for without a book to keep
+// them with, such as text read from stdin.
+const noNotesNotice = "Notes are kept with a book; read a file to take them"
+
+// editorCommand returns the command that edits path in the user's $VISUAL
+// or $EDITOR, or in vi if neither is set.
+func editorCommand(path string) *exec.Cmd {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	args := strings.Fields(editor)
+	if len(args) == 0 {
+		args = []string{"vi"}
+	}
+	return exec.Command(args[0], append(args[1:], path)...)
+}
+
+// writeBookNotes writes a book's scratch notes as a Markdown section, to
+// follow its marks.
+func writeBookNotes(w io.Writer, notes string) error {
+	_, err := fmt.Fprintf(w, "\n## Scratch notes\n\n%s\n", strings.TrimSpace(notes))
+	return err
+}
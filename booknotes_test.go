@@ -0,0 +1,65 @@
+//go:build !gui
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/metcalfc/brr/internal/state"
+)
+
+func TestBookNotes(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	m := newModel("Call me Ishmael.", 300, nil, nil)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	if m = updated.(model); m.notesOpen || m.notice != noNotesNotice {
+		t.Errorf("without a book, notes should not open: notice %q", m.notice)
+	}
+
+	book := filepath.Join(t.TempDir(), "moby.txt")
+	if err := os.WriteFile(book, []byte("Call me Ishmael."), 0644); err != nil {
+		t.Fatal(err)
+	}
+	store, _ := state.NewStateStore()
+	hash, _ := state.ComputeHash(book)
+	m.stateStore, m.fileHash = store, hash
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	if m = updated.(model); !m.notesOpen || !m.Paused {
+		t.Fatal("O should pause and open the notes")
+	}
+	for _, msg := range []tea.KeyMsg{{Type: tea.KeyRunes, Runes: []rune("Who")}, {Type: tea.KeySpace, Runes: []rune(" ")}, {Type: tea.KeyRunes, Runes: []rune("narrates?")}} {
+		updated, _ = m.Update(msg)
+		m = updated.(model)
+	}
+	if view := m.View(); !strings.Contains(view, "Who narrates?") || !strings.Contains(view, "Ctrl+E") {
+		t.Errorf("notes view should show the notes being edited, got:\n%s", view)
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m = updated.(model); m.notesOpen || m.notice != "Notes saved" {
+		t.Errorf("ESC should save and close the notes: notice %q", m.notice)
+	}
+	if notes, _ := store.Notes(hash); notes != "Who narrates?" {
+		t.Errorf("saved notes = %q", notes)
+	}
+
+	store.AddMark(hash, state.Mark{WordIndex: 0, Quote: "Call me Ishmael."})
+	store.AddAnnotation(hash, state.Annotation{WordIndex: 2, Text: "The first line."})
+	var sb strings.Builder
+	if err := exportNotes(&sb, book); err != nil {
+		t.Fatalf("exportNotes: %v", err)
+	}
+	want := "# Notes: moby\n\n> Call me Ishmael.\n>\n> — word 1\n\n## Notes at positions\n\n- The first line. (word 3)\n\n## Scratch notes\n\nWho narrates?\n"
+	if sb.String() != want {
+		t.Errorf("exportNotes =\n%s\nwant\n%s", sb.String(), want)
+	}
+
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "code --wait")
+	if cmd := editorCommand("notes.md"); strings.Join(cmd.Args, " ") != "code --wait notes.md" {
+		t.Errorf("editorCommand args = %q", cmd.Args)
+	}
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+// chapterPreviewWords is how many words of a chapter its preview in the
+// table of contents shows.
+const chapterPreviewWords = 50
+
+// chapterPreview returns the opening words of the chapter TOC entry i
+// leads to, ending in an ellipsis if the chapter goes on, without moving r.
+func chapterPreview(r *reader.Reader, i int) string {
+	words := r.SampleTOC(i, chapterPreviewWords+1)
+	switch {
+	case len(words) == 0:
+		return "This chapter has no text."
+	case len(words) > chapterPreviewWords:
+		return strings.Join(words[:chapterPreviewWords], " ") + " …"
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,46 @@
+//go:build !gui
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+func TestTOCPreview(t *testing.T) {
+	text := "Call me Ishmael. " + strings.Repeat("whale ", 60)
+	chapters := []reader.Chapter{{Title: "One", WordStart: 0, WordEnd: 2}, {Title: "Two", WordStart: 3, WordEnd: 62}}
+	toc := []reader.TOCEntry{{Title: "One", WordIndex: 0}, {Title: "Two", WordIndex: 3}}
+	m := newModel(text, 300, toc, chapters)
+	m.width, m.height = 120, 30
+	m.tocVisible = true
+
+	press := func(keys ...tea.KeyMsg) {
+		for _, k := range keys {
+			updated, _ := m.Update(k)
+			m = updated.(model)
+		}
+	}
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	if view := m.View(); m.tocPreview == nil || !strings.Contains(view, "Call me Ishmael.") || strings.Contains(view, "whale") {
+		t.Fatalf("P should preview the first chapter alone:\n%s", view)
+	}
+	press(tea.KeyMsg{Type: tea.KeyDown})
+	if m.tocPreview != nil || !m.tocVisible || m.CurrentIndex != 0 {
+		t.Fatal("any key but Enter should close the preview and stay put")
+	}
+	press(tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	if got := chapterPreview(m.Reader, m.tocPreview.index); strings.Count(got, "whale") != chapterPreviewWords || !strings.HasSuffix(got, "…") {
+		t.Errorf("the preview should stop at %d words, got %q", chapterPreviewWords, got)
+	}
+	if m.CurrentIndex != 0 {
+		t.Errorf("previewing moved the reader to %d", m.CurrentIndex)
+	}
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.tocPreview != nil || m.tocVisible || m.CurrentIndex != 3 {
+		t.Errorf("Enter should go to the chapter previewed: at %d, TOC visible %v", m.CurrentIndex, m.tocVisible)
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+// chapterCheckpoint pauses reading at chapter boundaries with a recap of the
+// chapter just finished (--checkpoints), and at the end of the text with a
+// recap of the last. Like the session goal, time counts only while words
+// are being shown.
+type chapterCheckpoint struct {
+	elapsed time.Duration
+	// recap is the recap of the chapter just finished, until taken.
+	recap string
+	// ended is set once the last chapter has been recapped.
+	ended bool
+}
+
+// newChapterCheckpoint returns a checkpoint tracker, or nil if disabled.
+func newChapterCheckpoint(enabled bool) *chapterCheckpoint {
+	if !enabled {
+		return nil
+	}
+	return &chapterCheckpoint{}
+}
+
+// watch makes r pause each time it moves into a new chapter, keeping a
+// recap of the chapter it left for take.
+func (c *chapterCheckpoint) watch(r *reader.Reader) {
+	if c == nil {
+		return
+	}
+	r.OnChapter = func(from, to int) {
+		c.recap = c.finish(r.Chapters[from], chapterTitle(r, from))
+		r.Pause()
+	}
+}
+
+// advance records one word shown for delay.
+func (c *chapterCheckpoint) advance(delay time.Duration) {
+	if c != nil {
+		c.elapsed += delay
+	}
+}
+
+// take returns the recap kept when r last paused at a new chapter, or ""
+// if there is none.
+func (c *chapterCheckpoint) take() string {
+	if c == nil {
+		return ""
+	}
+	recap := c.recap
+	c.recap = ""
+	return recap
+}
+
+// end returns a recap of the chapter r has read to the end of the text in,
+// the first time it is called there, or "".
+func (c *chapterCheckpoint) end(r *reader.Reader) string {
+	if c == nil || c.ended || len(r.Chapters) == 0 {
+		return ""
+	}
+	c.ended = true
+	ch := min(r.CurrentChapter, len(r.Chapters)-1)
+	return c.finish(r.Chapters[ch], chapterTitle(r, ch))
+}
+
+// finish returns the recap of ch, read in the time since the last recap.
+func (c *chapterCheckpoint) finish(ch reader.Chapter, title string) string {
+	elapsed := c.elapsed.Round(time.Second)
+	c.elapsed = 0
+	return fmt.Sprintf("Finished %s: %d words in %d:%02d. Press SPACE to continue.",
+		title, ch.WordEnd-ch.WordStart+1, int(elapsed.Minutes()), int(elapsed.Seconds())%60)
+}
+
+// chapterTitle returns the title of chapter i, or "chapter n" if it has
+// none.
+func chapterTitle(r *reader.Reader, i int) string {
+	if title := r.Chapters[i].Title; title != "" {
+		return title
+	}
+	return fmt.Sprintf("chapter %d", i+1)
+}
+
+// finishedChapter returns the title of the chapter r has just left for
+// the current one.
+func finishedChapter(r *reader.Reader) string {
+	// Skip back over empty chapters kept with --keep-empty.
+	prev := max(r.CurrentChapter-1, 0)
+	for prev > 0 && r.Chapters[prev].WordEnd < r.Chapters[prev].WordStart {
+		prev--
+	}
+	return chapterTitle(r, prev)
+}
+
+// pauseAtChapters makes r pause each time it moves into a new chapter
+// (--pause-at-chapters).
+func pauseAtChapters(r *reader.Reader) {
+	r.OnChapter = func(from, to int) {
+		r.Pause()
+	}
+}
+
+// chapterEndNotice announces a pause at the end of the chapter r has just
+// left.
+func chapterEndNotice(r *reader.Reader) string {
+	return "End of " + finishedChapter(r) + ". Press SPACE to continue."
+}
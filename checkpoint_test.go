@@ -0,0 +1,86 @@
+//go:build !gui
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+func TestModelChapterCheckpoint(t *testing.T) {
+	chapters := []reader.Chapter{
+		{Title: "Opening", WordStart: 0, WordEnd: 1},
+		{Title: "Middle", WordStart: 2, WordEnd: 3},
+	}
+	m := newModel("one two three four", 300, nil, chapters)
+	m.recap = newChapterCheckpoint(true)
+	m.recap.watch(m.Reader)
+	m.goal = newSessionGoal(0, 100)
+
+	updatedModel, _ := m.Update(tickMsg(time.Now()))
+	m = updatedModel.(model)
+	if m.Paused {
+		t.Fatal("reading should continue within a chapter")
+	}
+
+	updatedModel, _ = m.Update(tickMsg(time.Now()))
+	m = updatedModel.(model)
+	if !m.Paused || m.CurrentWord() != "three" {
+		t.Fatalf("should pause at the start of the next chapter, paused=%v word=%q", m.Paused, m.CurrentWord())
+	}
+	want := "Finished Opening: 2 words in 0:00. Press SPACE to continue."
+	if m.notice != want {
+		t.Errorf("notice = %q, want %q", m.notice, want)
+	}
+	if m.goal.read != 2 {
+		t.Errorf("the word before a recap should count toward the goal, read %d", m.goal.read)
+	}
+
+	m.Paused = false
+	for range 2 {
+		updatedModel, _ = m.Update(tickMsg(time.Now()))
+		m = updatedModel.(model)
+	}
+	want = "Finished Middle: 2 words in 0:00. Press SPACE to continue."
+	if !m.Paused || m.quitting || m.notice != want {
+		t.Errorf("the last chapter should be recapped at the end, paused=%v notice %q", m.Paused, m.notice)
+	}
+	m.Paused = false
+	updatedModel, _ = m.Update(tickMsg(time.Now()))
+	if m = updatedModel.(model); !m.quitting {
+		t.Error("reading should end after the last recap")
+	}
+
+	var none *chapterCheckpoint
+	none.watch(m.Reader)
+	none.advance(time.Second)
+	if none.take() != "" || none.end(m.Reader) != "" {
+		t.Error("disabled checkpoints should never recap")
+	}
+}
+
+func TestPauseAtChapters(t *testing.T) {
+	m := newModel("a b c d", 300, nil, []reader.Chapter{{Title: "One", WordStart: 0, WordEnd: 1}, {Title: "Two", WordStart: 2, WordEnd: 3}})
+	m.width, m.height = 80, 24
+	pauseAtChapters(m.Reader)
+
+	if view := m.View(); !strings.Contains(view, "Word 1/2 in this chapter") {
+		t.Errorf("view does not show chapter progress:\n%s", view)
+	}
+	updated, _ := m.Update(tickMsg(time.Now()))
+	m = updated.(model)
+	if m.Paused {
+		t.Fatal("paused within a chapter")
+	}
+	updated, cmd := m.Update(tickMsg(time.Now()))
+	m = updated.(model)
+	if !m.Paused || cmd != nil || m.CurrentIndex != 2 {
+		t.Errorf("at the end of a chapter: paused=%v at word %d, want paused at word 2", m.Paused, m.CurrentIndex)
+	}
+	if want := "End of One. Press SPACE to continue."; m.notice != want {
+		t.Errorf("notice = %q, want %q", m.notice, want)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+// pauseDashboard is what the paused screen shows around the word: the
+// chapter, the sentence the word is in, how the session is going and how
+// long is left, and the keys to try.
+type pauseDashboard struct {
+	// heading names the chapter, if the text has chapters.
+	heading string
+	// before and after are the rest of the current sentence either side
+	// of word.
+	before, word, after string
+	// lines are the figures of pausedHUD, and the session's reading time
+	// and the time left in the book.
+	lines []string
+}
+
+// newPauseDashboard describes the reader's position and the session so
+// far for the paused screen.
+func newPauseDashboard(r *reader.Reader, s *sessionStats) pauseDashboard {
+	var d pauseDashboard
+	d.lines = pausedHUD(r, s)
+	if len(r.Chapters) > 0 {
+		d.heading, d.lines = d.lines[0], d.lines[1:]
+	}
+	if i := r.CurrentIndex; i < len(r.Words) {
+		d.before = strings.Join(r.Words[r.SentenceStart(i):i], " ")
+		d.word = r.Words[i]
+		d.after = strings.Join(r.Words[i+1:r.SentenceEnd(i)+1], " ")
+	}
+
+	if s != nil && s.elapsed > 0 {
+		d.lines = append(d.lines, "Read for "+clockTime(s.elapsed)+" this session")
+	}
+	if len(r.Chapters) > 0 {
+		// pausedHUD gives the time left in the chapter, so add the book's.
+		wpm := r.WPM
+		if s != nil && s.words > 0 {
+			wpm = max(s.wpm(), 1)
+		}
+		current, total := r.Progress()
+		left := time.Duration(float64(max(total-current, 0)) / float64(wpm) * float64(time.Minute))
+		d.lines = append(d.lines, "Book ends in "+clockTime(left))
+	}
+	return d
+}
+
+// around returns the sentence either side of the word cut to fit room
+// runes between them, keeping the words nearest it. Either side may use
+// room the other does not need.
+func (d pauseDashboard) around(room int) (before, after string) {
+	b, a := []rune(d.before), []rune(d.after)
+	room = max(room, 0)
+	keepB := min(len(b), max(room/2, room-len(a)))
+	keepA := min(len(a), room-keepB)
+	before, after = string(b), string(a)
+	if keepB < len(b) {
+		before = ""
+		if keepB > 0 {
+			before = "…" + strings.TrimLeft(string(b[len(b)-keepB+1:]), " ")
+		}
+	}
+	if keepA < len(a) {
+		after = ""
+		if keepA > 0 {
+			after = strings.TrimRight(string(a[:keepA-1]), " ") + "…"
+		}
+	}
+	return before, after
+}
+
+// clockTime formats d as minutes and seconds, or hours, minutes and
+// seconds from an hour on.
+func clockTime(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d >= time.Hour {
+		return fmt.Sprintf("%d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+	}
+	return fmt.Sprintf("%d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+func TestPauseDashboard(t *testing.T) {
+	r := reader.NewReader("One two. Three four five six. Seven eight nine ten.", 60)
+	r.SetChapters([]reader.Chapter{
+		{Title: "Start", WordStart: 0, WordEnd: 5},
+		{Title: "End", WordStart: 6, WordEnd: 9},
+	}, nil)
+	r.JumpToChapter(3)
+	s := &sessionStats{words: 3, elapsed: 3 * time.Second}
+
+	d := newPauseDashboard(r, s)
+	if d.heading != "Chapter 1 of 2: Start" || d.before != "Three" || d.word != "four" || d.after != "five six." {
+		t.Errorf("dashboard = %+v", d)
+	}
+	// 60 WPM effective leaves 6 words, 0:06, to the end of the book.
+	want := []string{"Sentence 2", "Read 3 words this session at 60 WPM effective", "Chapter ends in 0:02", "Read for 0:03 this session", "Book ends in 0:06"}
+	if !reflect.DeepEqual(d.lines, want) {
+		t.Errorf("lines = %q, want %q", d.lines, want)
+	}
+
+	d = pauseDashboard{before: "Call me Ishmael and", after: "some years ago never mind"}
+	for room, want := range map[int][2]string{
+		100: {"Call me Ishmael and", "some years ago never mind"},
+		20:  {"…hmael and", "some year…"},
+		0:   {"", ""},
+	} {
+		if before, after := d.around(room); before != want[0] || after != want[1] {
+			t.Errorf("around(%d) = %q, %q, want %q, %q", room, before, after, want[0], want[1])
+		}
+	}
+	if got := clockTime(time.Hour + 2*time.Minute + 3*time.Second); got != "1:02:03" {
+		t.Errorf("clockTime = %q", got)
+	}
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+// exportSubtitles writes the words of r as subtitles timed at its pace to
+// path. The format (SRT or WebVTT) comes from the file extension.
+func exportSubtitles(path string, r *reader.Reader) error {
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	if !slices.Contains(reader.SubtitleFormats, format) {
+		return fmt.Errorf("subtitle file must end in .%s", strings.Join(reader.SubtitleFormats, " or ."))
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := reader.WriteSubtitles(f, r, format); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// eyeBreak enforces rests for the eyes after every stretch of reading
+// (--eye-breaks), after the 20-20-20 rule: every 20 minutes, look at
+// something 20 feet away for 20 seconds. As with session goals, only time
+// spent reading counts toward the next break.
+type eyeBreak struct {
+	every, length time.Duration
+
+	read  time.Duration
+	until time.Time
+}
+
+// newEyeBreak returns breaks of length after every stretch of reading, or
+// nil if every is 0.
+func newEyeBreak(every, length time.Duration) *eyeBreak {
+	if every <= 0 || length <= 0 {
+		return nil
+	}
+	return &eyeBreak{every: every, length: length}
+}
+
+// advance records one word shown for delay, reporting true when a break
+// is due.
+func (b *eyeBreak) advance(delay time.Duration) bool {
+	if b == nil {
+		return false
+	}
+	b.read += delay
+	if b.read < b.every {
+		return false
+	}
+	b.read = 0
+	return true
+}
+
+// start begins a break at now.
+func (b *eyeBreak) start(now time.Time) {
+	b.until = now.Add(b.length)
+}
+
+// active reports whether a break is in progress, though it may be over.
+func (b *eyeBreak) active() bool {
+	return b != nil && !b.until.IsZero()
+}
+
+// left returns how long the break in progress lasts after now.
+func (b *eyeBreak) left(now time.Time) time.Duration {
+	return max(b.until.Sub(now), 0)
+}
+
+// end finishes the break in progress, recording it in stats as taken or
+// skipped.
+func (b *eyeBreak) end(stats *sessionStats, skipped bool) {
+	b.until = time.Time{}
+	if stats == nil {
+		return
+	}
+	if skipped {
+		stats.breaksSkipped++
+	} else {
+		stats.breaks++
+	}
+}
+
+// screen returns the lines shown during the break at now.
+func (b *eyeBreak) screen(now time.Time) []string {
+	left := b.left(now).Round(time.Second)
+	return []string{
+		"Eye break",
+		"Look at something about 20 feet (6 m) away",
+		fmt.Sprintf("%d:%02d", int(left.Minutes()), int(left.Seconds())%60),
+		"SPACE: skip",
+	}
+}
+
+// breakOverNotice is shown when a break's countdown ends.
+const breakOverNotice = "Break over. Press SPACE to read on."
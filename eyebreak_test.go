@@ -0,0 +1,75 @@
+//go:build !gui
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestEyeBreak(t *testing.T) {
+	if newEyeBreak(0, 20*time.Second) != nil {
+		t.Error("eye breaks should be off without --eye-breaks")
+	}
+	b := newEyeBreak(time.Second, 20*time.Second)
+	for i := 0; i < 4; i++ {
+		if b.advance(200 * time.Millisecond) {
+			t.Fatalf("break due after %d words", i+1)
+		}
+	}
+	if !b.advance(200 * time.Millisecond) {
+		t.Fatal("no break after a second of reading")
+	}
+	now := time.Now()
+	b.start(now)
+	if !b.active() || b.left(now.Add(5*time.Second)) != 15*time.Second {
+		t.Errorf("active %v, left %v after 5s; want 15s", b.active(), b.left(now.Add(5*time.Second)))
+	}
+	if got := b.screen(now.Add(5 * time.Second)); got[2] != "0:15" {
+		t.Errorf("countdown %q, want 0:15", got[2])
+	}
+	stats := &sessionStats{}
+	b.end(stats, true)
+	if b.active() || stats.breaksSkipped != 1 || stats.breaks != 0 {
+		t.Errorf("after skipping: active %v, stats %+v", b.active(), stats)
+	}
+}
+
+func TestModelEyeBreak(t *testing.T) {
+	m := newModel("one two three four five six", 300, nil, nil) // 200ms a word
+	m.stats = &sessionStats{}
+	m.eyes = newEyeBreak(400*time.Millisecond, time.Minute)
+	m.Paused = false
+	key := func(msg tea.Msg) tea.Cmd {
+		updated, cmd := m.Update(msg)
+		m = updated.(model)
+		return cmd
+	}
+
+	key(tickMsg(time.Now()))
+	key(tickMsg(time.Now()))
+	if !m.eyes.active() || !m.Paused || !strings.Contains(m.View(), "Eye break") {
+		t.Fatalf("after 400ms of reading: break %v, paused %v", m.eyes.active(), m.Paused)
+	}
+	key(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	key(tea.KeyMsg{Type: tea.KeyRight})
+	if !m.eyes.active() || m.CurrentIndex != 2 {
+		t.Errorf("keys other than SPACE should wait for the break: break %v, at word %d", m.eyes.active(), m.CurrentIndex)
+	}
+	if cmd := key(eyeBreakMsg{}); cmd == nil {
+		t.Error("the countdown stopped before the break was over")
+	}
+	key(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	if m.eyes.active() || m.Paused || m.stats.breaksSkipped != 1 {
+		t.Errorf("after SPACE: break %v, paused %v, %d skipped", m.eyes.active(), m.Paused, m.stats.breaksSkipped)
+	}
+
+	m.eyes.start(time.Now().Add(-2 * time.Minute))
+	key(eyeBreakMsg{})
+	if m.eyes.active() || m.notice != breakOverNotice || m.stats.breaks != 1 {
+		t.Errorf("after the countdown: break %v, notice %q, %d taken", m.eyes.active(), m.notice, m.stats.breaks)
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+// defaultFinishAt is the percentage of a document past which it counts as
+// finished. Back matter such as notes and an index means readers rarely
+// reach the last word.
+const defaultFinishAt = 98.0
+
+// checkFinishAt checks a --finish-at percentage.
+func checkFinishAt(percent float64) error {
+	if percent <= 0 || percent > 100 {
+		return fmt.Errorf("--finish-at must be a percentage above 0 and at most 100, not %g", percent)
+	}
+	return nil
+}
+
+// bookFinished reports whether r has read far enough to count its
+// document as finished: to its last word, or past finishAt percent of its
+// words. A document still streaming in is finished only at its end, and
+// one cut short by an error is not finished.
+func bookFinished(r *reader.Reader, finishAt float64) bool {
+	if r.Err() != nil {
+		return false
+	}
+	if r.AtEnd() {
+		return true
+	}
+	if r.Streaming() || r.Incomplete {
+		return false
+	}
+	total := documentLength(r)
+	return total > 0 && float64(r.Position()+1) >= finishAt/100*float64(total)
+}
@@ -0,0 +1,56 @@
+//go:build !gui
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/metcalfc/brr/internal/state"
+)
+
+func TestBookFinished(t *testing.T) {
+	m := newModel(strings.Repeat("word ", 100), 300, nil, nil)
+	for _, tt := range []struct {
+		word     int
+		finishAt float64
+		want     bool
+	}{
+		{96, defaultFinishAt, false},
+		{97, defaultFinishAt, true},
+		{97, 100, false},
+		{99, 100, true},
+		{49, 50, true},
+	} {
+		m.SeekTo(tt.word)
+		if got := bookFinished(m.Reader, tt.finishAt); got != tt.want {
+			t.Errorf("bookFinished at word %d of 100 with --finish-at %g = %v, want %v", tt.word+1, tt.finishAt, got, tt.want)
+		}
+	}
+	for _, bad := range []float64{0, -5, 101} {
+		if checkFinishAt(bad) == nil {
+			t.Errorf("--finish-at %g should be refused", bad)
+		}
+	}
+
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	store, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.stateStore, m.fileHash = store, "abc"
+	m.SeekTo(50)
+	m.savePosition()
+	if st, _ := store.Get("abc"); !st.Finished.IsZero() {
+		t.Error("a book half read should not be finished")
+	}
+	m.SeekTo(98)
+	m.savePosition()
+	if st, _ := store.Get("abc"); st.Finished.IsZero() {
+		t.Error("a book read into its back matter should be finished")
+	}
+	m.quitting = true
+	if view := m.View(); !strings.Contains(view, "Reading complete!") {
+		t.Errorf("quitting a finished book should say so, got %q", view)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+const formatsUsage = "usage: brr formats [--json]"
+
+// runFormatsCommand lists the registered document formats, their
+// extensions and what each can do besides extracting text, as a table or,
+// with --json, for programs building file filters.
+func runFormatsCommand(w io.Writer, args []string) error {
+	formats := reader.Formats()
+	switch {
+	case len(args) == 0:
+		return printFormats(w, formats)
+	case len(args) == 1 && (args[0] == "--json" || args[0] == "-json"):
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(formats)
+	}
+	return errors.New(formatsUsage)
+}
+
+// printFormats writes formats as a table, noting that other files are
+// read as plain text.
+func printFormats(w io.Writer, formats []reader.FormatInfo) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "FORMAT\tEXTENSIONS\tSUPPORTS")
+	for _, f := range formats {
+		var supports []string
+		for _, s := range []struct {
+			name string
+			ok   bool
+		}{
+			{"toc", f.TOC},
+			{"chapters", f.Chapters},
+			{"metadata", f.Metadata},
+			{"streaming", f.Streaming},
+		} {
+			if s.ok {
+				supports = append(supports, s.name)
+			}
+		}
+		if len(supports) == 0 {
+			supports = []string{"-"}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", f.Name, strings.Join(f.Extensions, " "), strings.Join(supports, ", "))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(w, "\nOther files are read as plain text.")
+	return err
+}
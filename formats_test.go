@@ -0,0 +1,37 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+func TestFormatsCommand(t *testing.T) {
+	var b strings.Builder
+	if err := runFormatsCommand(&b, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !regexp.MustCompile(`EPUB +\.epub +toc, chapters, metadata, streaming`).MatchString(b.String()) {
+		t.Errorf("table lacks EPUB:\n%s", b.String())
+	}
+
+	b.Reset()
+	if err := runFormatsCommand(&b, []string{"--json"}); err != nil {
+		t.Fatal(err)
+	}
+	var formats []reader.FormatInfo
+	if err := json.Unmarshal([]byte(b.String()), &formats); err != nil {
+		t.Fatalf("not JSON: %v\n%s", err, b.String())
+	}
+	if !reflect.DeepEqual(formats, reader.Formats()) {
+		t.Errorf("JSON = %+v, want %+v", formats, reader.Formats())
+	}
+
+	if err := runFormatsCommand(&b, []string{"--xml"}); err == nil {
+		t.Error("an unknown option should fail")
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+// A word shown later than its deadline by more than a quarter of the time
+// it is shown for, and at least minLate, counts as a dropped frame.
+const minLate = 20 * time.Millisecond
+
+// dropWindow is how many words sustained drops are judged over: half of
+// them late means the display cannot keep up.
+const dropWindow = 20
+
+// frameStats records how closely words were shown to their deadlines,
+// which a slow terminal, a remote connection or garbage collection pauses
+// can miss.
+type frameStats struct {
+	// autoSlow lowers the speed when the display keeps falling behind
+	// (--auto-slow).
+	autoSlow bool
+
+	words, dropped int
+	worst          time.Duration
+	// window and windowDropped count the words of the current dropWindow.
+	window, windowDropped int
+}
+
+// shown records that the reader's current word was shown late after its
+// deadline. When autoSlow is set and the display keeps falling behind, it
+// lowers the speed by 50 WPM and returns a notice saying so.
+func (f *frameStats) shown(r *reader.Reader, late time.Duration) string {
+	if f == nil {
+		return ""
+	}
+	f.words++
+	f.window++
+	if late > max(r.GetDelay()/4, minLate) {
+		f.dropped++
+		f.windowDropped++
+		f.worst = max(f.worst, late)
+	}
+	if f.window < dropWindow {
+		return ""
+	}
+	sustained := f.windowDropped*2 >= dropWindow
+	f.window, f.windowDropped = 0, 0
+	if !sustained || !f.autoSlow || r.WPM <= 100 {
+		return ""
+	}
+	r.WPM -= 50
+	return fmt.Sprintf("Display falling behind; slowed to %d WPM", r.WPM)
+}
+
+// summary describes the session's pacing for the end of the session, or
+// returns "" if every word was shown on time.
+func (f *frameStats) summary() string {
+	if f == nil || f.dropped == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Pacing: %d of %d words shown late (%.1f%%), the worst by %s. A slower speed or --auto-slow may read more smoothly.",
+		f.dropped, f.words, 100*float64(f.dropped)/float64(f.words), f.worst.Round(time.Millisecond))
+}
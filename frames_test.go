@@ -0,0 +1,66 @@
+//go:build !gui
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+func TestFrameStats(t *testing.T) {
+	r := reader.NewReader("one two three", 300) // 200ms a word
+	var f *frameStats
+	if f.shown(r, time.Second) != "" || f.summary() != "" {
+		t.Error("nil frameStats should record nothing")
+	}
+
+	f = &frameStats{}
+	for range dropWindow {
+		if notice := f.shown(r, 10*time.Millisecond); notice != "" {
+			t.Errorf("on-time word gave notice %q", notice)
+		}
+	}
+	if f.summary() != "" {
+		t.Errorf("summary with every word on time = %q, want none", f.summary())
+	}
+	for range dropWindow {
+		f.shown(r, 100*time.Millisecond)
+	}
+	if r.WPM != 300 {
+		t.Errorf("without autoSlow WPM = %d, want 300", r.WPM)
+	}
+	want := "Pacing: 20 of 40 words shown late (50.0%), the worst by 100ms. A slower speed or --auto-slow may read more smoothly."
+	if got := f.summary(); got != want {
+		t.Errorf("summary = %q, want %q", got, want)
+	}
+
+	f = &frameStats{autoSlow: true}
+	var notice string
+	for i := range dropWindow {
+		late := time.Duration(0)
+		if i%2 == 0 {
+			late = time.Second
+		}
+		notice = f.shown(r, late)
+	}
+	if r.WPM != 250 || notice != "Display falling behind; slowed to 250 WPM" {
+		t.Errorf("after sustained drops WPM = %d, notice %q", r.WPM, notice)
+	}
+}
+
+func TestTickDropsLateWords(t *testing.T) {
+	m := newModel("one two three four", 300, nil, nil)
+	m.frames = &frameStats{}
+	updated, _ := m.Update(tickMsg(time.Now().Add(-time.Second)))
+	m = updated.(model)
+	if m.frames.words != 1 || m.frames.dropped != 1 {
+		t.Errorf("a word a second late counted %d words, %d dropped; want 1, 1", m.frames.words, m.frames.dropped)
+	}
+	updated, _ = m.Update(tickMsg(time.Now()))
+	m = updated.(model)
+	if m.frames.words != 2 || m.frames.dropped != 1 {
+		t.Errorf("an on-time word counted %d words, %d dropped; want 2, 1", m.frames.words, m.frames.dropped)
+	}
+}
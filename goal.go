@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// sessionGoal tracks progress toward a reading goal set with --minutes or
+// --words. Time counts only while words are being shown, not while paused.
+type sessionGoal struct {
+	duration time.Duration
+	words    int
+
+	elapsed time.Duration
+	read    int
+	reached bool
+}
+
+// newSessionGoal returns a goal for the given targets, or nil if neither is
+// set.
+func newSessionGoal(minutes float64, words int) *sessionGoal {
+	if minutes <= 0 && words <= 0 {
+		return nil
+	}
+	return &sessionGoal{
+		duration: time.Duration(minutes * float64(time.Minute)),
+		words:    words,
+	}
+}
+
+// advance records one word shown for delay. It reports true exactly once,
+// when the first target is met.
+func (g *sessionGoal) advance(delay time.Duration) bool {
+	if g == nil || g.reached {
+		return false
+	}
+	g.elapsed += delay
+	g.read++
+	if (g.duration > 0 && g.elapsed >= g.duration) || (g.words > 0 && g.read >= g.words) {
+		g.reached = true
+		return true
+	}
+	return false
+}
+
+// status describes what is left of the goal for the status bar.
+func (g *sessionGoal) status() string {
+	if g == nil {
+		return ""
+	}
+	if g.reached {
+		return "Goal reached"
+	}
+	var parts []string
+	if g.duration > 0 {
+		left := (g.duration - g.elapsed).Round(time.Second)
+		parts = append(parts, fmt.Sprintf("%d:%02d left", int(left.Minutes()), int(left.Seconds())%60))
+	}
+	if g.words > 0 {
+		parts = append(parts, fmt.Sprintf("%d words left", g.words-g.read))
+	}
+	return "Goal: " + strings.Join(parts, ", ")
+}
@@ -0,0 +1,59 @@
+//go:build !gui
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSessionGoal(t *testing.T) {
+	if g := newSessionGoal(0, 0); g != nil {
+		t.Fatal("no targets should mean no goal")
+	}
+	var none *sessionGoal
+	if none.advance(time.Second) || none.status() != "" {
+		t.Error("nil goal should be inert")
+	}
+
+	g := newSessionGoal(0.05, 0) // 3 seconds
+	if got := g.status(); got != "Goal: 0:03 left" {
+		t.Errorf("status() = %q", got)
+	}
+	if g.advance(2*time.Second) || !g.advance(time.Second) {
+		t.Error("time goal should be reached after 3 seconds")
+	}
+	if g.advance(time.Second) {
+		t.Error("goal should only be reported once")
+	}
+	if got := g.status(); got != "Goal reached" {
+		t.Errorf("status() = %q", got)
+	}
+
+	g = newSessionGoal(0, 2)
+	g.advance(time.Millisecond)
+	if got := g.status(); got != "Goal: 1 words left" {
+		t.Errorf("status() = %q", got)
+	}
+}
+
+func TestModelGoalPauses(t *testing.T) {
+	m := newModel("one two three four", 300, nil, nil)
+	m.goal = newSessionGoal(0, 2)
+
+	updatedModel, cmd := m.Update(tickMsg(time.Now()))
+	m = updatedModel.(model)
+	if m.Paused || cmd == nil {
+		t.Fatal("reading should continue before the goal")
+	}
+
+	updatedModel, _ = m.Update(tickMsg(time.Now()))
+	m = updatedModel.(model)
+	if !m.Paused || !strings.Contains(m.notice, "Goal reached") {
+		t.Errorf("goal should pause with a banner, paused=%v notice=%q", m.Paused, m.notice)
+	}
+	if m.CurrentWord() != "three" {
+		t.Errorf("current word = %q, want three", m.CurrentWord())
+	}
+}
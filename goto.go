@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+// position is a place to go to in a document, given by --start,
+// --start-word or the G key: a percentage of the way through it, or a word
+// number counting from 1.
+type position struct {
+	percent float64
+	word    int
+}
+
+// parsePosition parses "45%" or "45.5%" as a percentage and "10000" as a
+// word number.
+func parsePosition(s string) (position, error) {
+	s = strings.TrimSpace(s)
+	if pct, ok := strings.CutSuffix(s, "%"); ok {
+		p, err := strconv.ParseFloat(strings.TrimSpace(pct), 64)
+		if err != nil || p < 0 || p > 100 {
+			return position{}, fmt.Errorf("invalid percentage %q (use 0%% to 100%%)", s)
+		}
+		return position{percent: p}, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 {
+		return position{}, fmt.Errorf("invalid position %q (use a percentage such as 45%% or a word number)", s)
+	}
+	return position{word: n}, nil
+}
+
+// index returns the absolute index of the word at p in a document of
+// total words. A percentage is kept inside the document.
+func (p position) index(total int) int {
+	if p.word > 0 {
+		return p.word - 1
+	}
+	return min(int(p.percent/100*float64(total)), max(total-1, 0))
+}
+
+// documentLength returns how many words r's document holds, or an
+// estimate when it is streamed. Unlike Progress, it counts every chapter,
+// so positions match a paper copy whatever the reading order.
+func documentLength(r *reader.Reader) int {
+	if r.Streaming() {
+		_, total := r.Progress()
+		return total
+	}
+	return len(r.Words)
+}
+
+// goTo moves r to p as a jump that U can undo, returning a notice saying
+// where it went or why it could not.
+func goTo(r *reader.Reader, p position) string {
+	total := documentLength(r)
+	idx := p.index(total)
+	if local := idx - r.Base; local >= 0 && local < len(r.Words) {
+		r.Jump(local)
+	} else if !r.SeekTo(idx) {
+		return fmt.Sprintf("Word %s is past the end", groupDigits(idx+1))
+	}
+	return fmt.Sprintf("At word %s of %s (%d%%)", groupDigits(r.Position()+1), groupDigits(total), (r.Position()+1)*100/max(total, 1))
+}
+
+// startPosition returns where --start or --start-word says to start
+// reading, or nil if neither was given.
+func startPosition(start string, word int) (*position, error) {
+	switch {
+	case start != "" && word != 0:
+		return nil, fmt.Errorf("give --start or --start-word, not both")
+	case word < 0:
+		return nil, fmt.Errorf("invalid --start-word %d", word)
+	case word > 0:
+		return &position{word: word}, nil
+	case start != "":
+		p, err := parsePosition(start)
+		if err != nil {
+			return nil, fmt.Errorf("--start: %w", err)
+		}
+		return &p, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,51 @@
+//go:build !gui
+
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestGoTo(t *testing.T) {
+	for in, want := range map[string]position{"45%": {percent: 45}, " 12.5 %": {percent: 12.5}, "10000": {word: 10000}} {
+		if got, err := parsePosition(in); err != nil || got != want {
+			t.Errorf("parsePosition(%q) = %+v, %v, want %+v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "0", "-3", "101%", "half"} {
+		if _, err := parsePosition(in); err == nil {
+			t.Errorf("parsePosition(%q) should fail", in)
+		}
+	}
+	if _, err := startPosition("45%", 100); err == nil {
+		t.Error("--start and --start-word together should fail")
+	}
+	if p, err := startPosition("", 0); p != nil || err != nil {
+		t.Errorf("no start flags = %+v, %v, want nil", p, err)
+	}
+
+	m := newModel("one two three four five six seven eight nine ten", 300, nil, nil)
+	if got, want := goTo(m.Reader, position{percent: 50}), "At word 6 of 10 (60%)"; got != want || m.Position() != 5 {
+		t.Errorf("goTo(50%%) = %q at %d, want %q at 5", got, m.Position(), want)
+	}
+	if got := goTo(m.Reader, position{word: 11}); got != "Word 11 is past the end" || m.Position() != 5 {
+		t.Errorf("goTo(word 11) = %q at %d", got, m.Position())
+	}
+
+	m.SeekTo(0)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	if m = updated.(model); !m.goingTo || !m.Paused {
+		t.Fatal("g should pause and ask where to go")
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("3")})
+	updated, _ = updated.(model).Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m = updated.(model); m.goingTo || m.Position() != 2 {
+		t.Errorf("entering 3 should go to word 3: position %d", m.Position())
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+	if m = updated.(model); m.Position() != 0 {
+		t.Errorf("u should undo going to word 3: position %d", m.Position())
+	}
+}
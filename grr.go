@@ -3,22 +3,34 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"image"
 	"image/color"
-	"io"
 	"os"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/layout"
+	fynetheme "fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 	"github.com/metcalfc/brr/internal/reader"
 	"github.com/metcalfc/brr/internal/state"
+	"github.com/metcalfc/brr/internal/theme"
+	"github.com/metcalfc/brr/internal/translate"
+	"github.com/metcalfc/brr/internal/tts"
+	"rsc.io/qr"
 )
 
 // Version info (injected via ldflags)
@@ -30,77 +42,496 @@ var (
 
 type model struct {
 	*reader.Reader
+	renderer   reader.WordRenderer
+	presets    []preset
+	keys       keymap
+	colors     theme.Theme
+	speaker    tts.Speaker
 	fontSize   float32
 	tocVisible bool
 	stateStore *state.StateStore
 	fileHash   string
+	goal       *sessionGoal
+	eyes       *eyeBreak
+	lock       *focusLock
+	recap      *chapterCheckpoint
+	stats      *sessionStats
+	frames     *frameStats
+	marks      *sessionMarks
+	notice     string
+	sourceFile string
+	// book is the title and author of sourceFile.
+	book reader.Metadata
+
+	// session names the saved position read and saved in place of the
+	// book's own (--session), and conflict is a sync conflict over the
+	// book's position awaiting the reader's choice.
+	session  string
+	conflict *syncConflict
+	// huge is a document over the --max-words limit, until the reader
+	// chooses how much of it to read.
+	huge *hugeInput
+	// skipped reports sections skipped as a document read a chapter at a
+	// time is parsed.
+	skipped *skipWatch
+	// resume is a book reopened at its saved position, until the reader
+	// continues from there or starts over.
+	resume *resumePrompt
+	// pendingResume is a saved position in the part of a document read
+	// early that is still loading; reading resumes there once it arrives.
+	pendingResume int
+
+	// capturePath and captureTemplate configure the A key; see
+	// captureSentence.
+	capturePath     string
+	captureTemplate string
+
+	// handoffURL is the template of the link the H key shows as a QR
+	// code.
+	handoffURL string
+
+	// translator translates the sentence paused in (--translate) into
+	// translation.
+	translator  translate.Translator
+	translation *sentenceTranslation
+
+	// parallel is the --parallel translation, shown by the parallel
+	// display.
+	parallel *reader.ParallelText
+
+	// mini shows the word alone in a small window, toggled with P.
+	mini bool
+
+	// night switches to the night theme while --night says to.
+	night *nightSwitch
+
+	// finishAt is the percentage of the document past which it counts as
+	// finished (--finish-at).
+	finishAt float64
+
+	// overflow is how words too wide for the window are shown. capacity
+	// is how many characters of the current word fit, or 0 if it fits
+	// whole; marquee counts the moves made scrolling it, and showLong
+	// shows it whole, wrapped, while paused.
+	overflow overflowMode
+	capacity int
+	marquee  int
+	showLong bool
 }
 
 func newModel(text string, wpm int, toc []reader.TOCEntry, chapters []reader.Chapter) *model {
 	r := reader.NewReader(text, wpm)
 	r.SetChapters(chapters, toc)
+	return newReaderModel(r)
+}
+
+// newReaderModel wraps an existing Reader, such as a streaming one.
+func newReaderModel(r *reader.Reader) *model {
 	r.Paused = true // GUI starts paused
 	return &model{
 		Reader:   r,
+		renderer: reader.Renderers[0],
+		colors:   theme.Default(),
 		fontSize: 72,
+		marks:    &sessionMarks{},
+		finishAt: defaultFinishAt,
+	}
+}
+
+var (
+	focusColor color.Color = color.RGBA{R: 255, G: 0, B: 0, A: 255}
+	plainColor color.Color = color.White
+	// contextColor dims the neighbouring words of context display modes.
+	contextColor color.Color = color.Gray{Y: 0x66}
+)
+
+// grrTheme overrides Fyne's default background and foreground colors with
+// those of a brr color theme.
+type grrTheme struct {
+	fyne.Theme
+	colors theme.Theme
+}
+
+func (t *grrTheme) Color(name fyne.ThemeColorName, variant fyne.ThemeVariant) color.Color {
+	switch name {
+	case fynetheme.ColorNameBackground:
+		if c, err := theme.RGBA(t.colors.Background); err == nil {
+			return c
+		}
+	case fynetheme.ColorNameForeground:
+		if c, err := theme.RGBA(t.colors.Status); err == nil {
+			return c
+		}
+	}
+	if t.colors.Dark {
+		variant = fynetheme.VariantDark
+	}
+	return t.Theme.Color(name, variant)
+}
+
+// applyTheme switches the word colors and window theme to a color scheme.
+func applyTheme(a fyne.App, t theme.Theme) {
+	if c, err := theme.RGBA(t.Focus); err == nil {
+		focusColor = c
+	}
+	if c, err := theme.RGBA(t.Word); err == nil {
+		plainColor = c
+	}
+	if c, err := theme.RGBA(t.Controls); err == nil {
+		contextColor = c
+	}
+	a.Settings().SetTheme(&grrTheme{Theme: fynetheme.DefaultTheme(), colors: t})
+	// A new theme may bring new fonts.
+	clear(textWidths)
+}
+
+// skipRest skips the rest of the sentence or paragraph while reading goes
+// on, reading the next sentence aloud when TTS is enabled.
+func (m *model) skipRest(paragraph bool) {
+	if len(m.Words) == 0 {
+		return
+	}
+	m.notice = skipRest(m.Reader, m.stats, paragraph)
+	if !m.Paused {
+		m.stopSpeech()
+		m.speak(m.CurrentIndex)
 	}
 }
 
-func createWordDisplay(word string, fontSize float32, windowWidth float32) *fyne.Container {
-	runes := []rune(word)
-	orp := reader.GetORPPosition(word)
-	if orp >= len(runes) {
-		orp = len(runes) - 1
+// speak reads the sentence from idx aloud when TTS is enabled.
+func (m *model) speak(idx int) {
+	if m.speaker != nil {
+		m.speaker.Speak(m.SentenceFrom(idx), m.WPM)
 	}
-	if orp < 0 {
-		orp = 0
+}
+
+func (m *model) stopSpeech() {
+	if m.speaker != nil {
+		m.speaker.Stop()
+	}
+}
+
+// goalReached pauses reading when the session goal is met.
+func (m *model) goalReached() {
+	m.Pause()
+	m.stopSpeech()
+	if m.stateStore != nil && m.fileHash != "" {
+		m.stateStore.RecordGoal(m.fileHash)
+	}
+}
+
+func (m *model) savePosition() {
+	// A document read early could overwrite the saved position with one
+	// short of it, so nothing is saved until it has loaded.
+	if m.stateStore != nil && m.fileHash != "" && !m.Incomplete {
+		p := state.Progress{
+			Settings:  currentSettings(m.Reader, m.renderer),
+			WordIndex: m.Position(),
+			Session:   m.session,
+		}
+		if !m.Streaming() {
+			p.Bookmark = &state.Bookmark{
+				Chapter:  m.ChapterIndex(m.CurrentIndex),
+				Sentence: m.SentenceIndex(m.CurrentIndex),
+			}
+		}
+		if bookFinished(m.Reader, m.finishAt) {
+			p.Finished = time.Now()
+		}
+		m.stateStore.SaveProgress(m.fileHash, p)
 	}
+}
 
-	before := string(runes[:orp])
-	focus := string(runes[orp])
-	after := ""
-	if orp+1 < len(runes) {
-		after = string(runes[orp+1:])
+// capture appends the current sentence to the capture file and reports the
+// result in the status line.
+func (m *model) capture() {
+	if m.capturePath == "" {
+		m.notice = "Set --capture FILE to capture sentences"
+		return
+	}
+	if len(m.Words) == 0 {
+		return
 	}
+	path, err := captureSentence(m.Reader, m.capturePath, m.captureTemplate, m.sourceFile, time.Now())
+	if err != nil {
+		m.notice = "Could not capture: " + err.Error()
+		return
+	}
+	m.notice = "Captured to " + path
+}
 
-	beforeText := canvas.NewText(before, color.White)
-	beforeText.TextSize = fontSize
-	beforeText.TextStyle.Bold = true
+// maxTextWidths is how many measurements textWidth keeps before starting
+// over, enough for the words around the current one at every size in use.
+const maxTextWidths = 4096
+
+type textWidthKey struct {
+	text  string
+	size  float32
+	style fyne.TextStyle
+}
+
+// textWidths caches text measurements, which shape the text each time and
+// would otherwise be repeated for every word at high speeds.
+var textWidths = make(map[textWidthKey]float32)
+
+// textWidth returns the width of text drawn at size in style.
+func textWidth(text string, size float32, style fyne.TextStyle) float32 {
+	key := textWidthKey{text, size, style}
+	if w, ok := textWidths[key]; ok {
+		return w
+	}
+	if len(textWidths) >= maxTextWidths {
+		clear(textWidths)
+	}
+	w := fyne.MeasureText(text, size, style).Width
+	textWidths[key] = w
+	return w
+}
 
-	focusText := canvas.NewText(focus, color.RGBA{R: 255, G: 0, B: 0, A: 255})
-	focusText.TextSize = fontSize
-	focusText.TextStyle.Bold = true
+// displayWord drops the characters of word that are never drawn but that
+// fonts can give a width, such as soft hyphens in words added through the
+// library, so they don't push the ORP letter aside.
+func displayWord(word string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '\u00ad', '\u200b', '\u2060', '\ufeff':
+			return -1
+		}
+		return r
+	}, word)
+}
 
-	afterText := canvas.NewText(after, color.White)
-	afterText.TextSize = fontSize
-	afterText.TextStyle.Bold = true
+// wordCapacity returns how many characters of word fit across width at
+// fontSize, or 0 if the whole word fits.
+func wordCapacity(word string, fontSize, width float32) int {
+	word = displayWord(word)
+	w := textWidth(word, fontSize, fyne.TextStyle{Bold: true})
+	if w <= width || w == 0 {
+		return 0
+	}
+	// Leave a character's margin at the window edges.
+	return max(int(float32(len([]rune(word)))*width/w)-1, 1)
+}
 
-	beforeSize := beforeText.MinSize()
-	focusSize := focusText.MinSize()
+// minFitFontSize is the smallest a long word is shrunk to fit the window;
+// a word that would need to be smaller overflows instead.
+const minFitFontSize = 20
+
+// fitFontSize returns the largest size up to fontSize at which word fits
+// across width with its ORP letter at the middle, where createWordDisplay
+// anchors every word, or fontSize if it would need to be smaller than
+// minFitFontSize.
+func fitFontSize(word string, fontSize, width float32) float32 {
+	word = displayWord(word)
+	runes := []rune(reader.VisualText(word))
+	anchor := reader.VisualORP(word)
+	if anchor >= len(runes) {
+		return fontSize
+	}
+	style := fyne.TextStyle{Bold: true}
+	// The ORP letter starts at the middle, so the text before it has to
+	// fit in the left half and the rest in the right. Text scales with its
+	// size, so the larger side is measured once and scaled.
+	left := textWidth(string(runes[:anchor]), fontSize, style)
+	right := textWidth(string(runes[anchor:]), fontSize, style)
+	half := max(left, right)
+	if half <= width/2 {
+		return fontSize
+	}
+	// Leave a character's margin at the window edges, as wordCapacity does.
+	size := fontSize * (width/2 - fontSize/2) / half
+	if size < minFitFontSize {
+		return fontSize
+	}
+	return float32(int(size))
+}
 
-	centerX := windowWidth / 2
-	beforeX := centerX - beforeSize.Width
-	focusX := centerX
-	afterX := centerX + focusSize.Width
+func createWordDisplay(word string, renderer reader.WordRenderer, fontSize float32, windowWidth float32) *fyne.Container {
+	// Fyne lays text out left to right only, so right-to-left words are
+	// drawn reversed with their ORP mirrored.
+	word = displayWord(word)
+	segments := splitSegment(reader.VisualSegments(word, renderer.Segments(word)), reader.VisualORP(word))
+
+	// Plain text is drawn bold unless the mode uses bold for emphasis.
+	emphasisBold := false
+	for _, seg := range segments {
+		if seg.Kind == reader.SegmentBold {
+			emphasisBold = true
+		}
+	}
 
-	if beforeX < 0 {
-		beforeX = 0
+	// The ORP letter starts a text of its own, so the text before it is
+	// measured exactly as it is drawn: ligatures and marks never span two
+	// texts, and the letter lands in the same place for every word.
+	anchor := reader.VisualORP(word)
+	texts := make([]*canvas.Text, len(segments))
+	widths := make([]float32, len(segments))
+	var anchorOffset float32
+	runeCount := 0
+	for i, seg := range segments {
+		t := canvas.NewText(seg.Text, plainColor)
+		t.TextSize = fontSize
+		t.TextStyle.Bold = true
+		switch seg.Kind {
+		case reader.SegmentFocus:
+			t.Color = focusColor
+		case reader.SegmentPlain:
+			t.TextStyle.Bold = !emphasisBold
+		case reader.SegmentPunct:
+			t.Color = contextColor
+			t.TextStyle.Bold = !emphasisBold
+		}
+		texts[i] = t
+		widths[i] = textWidth(seg.Text, fontSize, t.TextStyle)
+		if runeCount < anchor {
+			anchorOffset += widths[i]
+		}
+		runeCount += len([]rune(seg.Text))
 	}
 
-	c := &fyne.Container{
-		Layout: &centerVerticalLayout{},
-		Objects: []fyne.CanvasObject{
-			beforeText,
-			focusText,
-			afterText,
-		},
+	x := windowWidth/2 - anchorOffset
+	if x < 0 {
+		x = 0
+	}
+
+	objects := make([]fyne.CanvasObject, len(texts))
+	for i, t := range texts {
+		t.Move(fyne.NewPos(x, 0))
+		x += widths[i]
+		objects[i] = t
+	}
+
+	return &fyne.Container{
+		Layout:  &centerVerticalLayout{},
+		Objects: objects,
+	}
+}
+
+// splitSegment splits the segment running over rune index at in two, so
+// that a segment starts there.
+func splitSegment(segs []reader.Segment, at int) []reader.Segment {
+	start := 0
+	for i, seg := range segs {
+		runes := []rune(seg.Text)
+		if at > start && at < start+len(runes) {
+			cut := at - start
+			return slices.Concat(segs[:i], []reader.Segment{
+				{Text: string(runes[:cut]), Kind: seg.Kind},
+				{Text: string(runes[cut:]), Kind: seg.Kind},
+			}, segs[i+1:])
+		}
+		start += len(runes)
+	}
+	return segs
+}
+
+// createColumnDisplay stacks dimmed neighbouring words above and below the
+// current word, padding short runs so the current word stays centered.
+func createColumnDisplay(prev []string, current fyne.CanvasObject, next []string, before, after int, fontSize, windowWidth float32) fyne.CanvasObject {
+	contextRow := func(word string) fyne.CanvasObject {
+		row := createWordDisplay(word, reader.ORPRenderer{}, fontSize*0.6, windowWidth)
+		for _, o := range row.Objects {
+			o.(*canvas.Text).Color = contextColor
+		}
+		return row
+	}
+
+	rows := []fyne.CanvasObject{layout.NewSpacer()}
+	for i := len(prev); i < before; i++ {
+		rows = append(rows, contextRow(" "))
+	}
+	for _, w := range prev {
+		rows = append(rows, contextRow(w))
+	}
+	rows = append(rows, current)
+	for _, w := range next {
+		rows = append(rows, contextRow(w))
 	}
+	for i := len(next); i < after; i++ {
+		rows = append(rows, contextRow(" "))
+	}
+	rows = append(rows, layout.NewSpacer())
+	return container.NewVBox(rows...)
+}
+
+// addGhostWords puts dimmed neighbouring words on either side of a word
+// display made by createWordDisplay, leaving out any that would run off
+// the window.
+func addGhostWords(word *fyne.Container, prev, next []string, fontSize, windowWidth float32) {
+	if len(word.Objects) == 0 {
+		return
+	}
+	gap := fontSize / 2
+	ghost := func(words []string) *canvas.Text {
+		t := canvas.NewText(strings.Join(words, " "), contextColor)
+		t.TextSize = fontSize * 0.6
+		return t
+	}
+	first, last := word.Objects[0], word.Objects[len(word.Objects)-1]
+	if len(prev) > 0 {
+		t := ghost(prev)
+		if x := first.Position().X - gap - textWidth(t.Text, t.TextSize, t.TextStyle); x >= 0 {
+			t.Move(fyne.NewPos(x, 0))
+			word.Objects = append(word.Objects, t)
+		}
+	}
+	if len(next) > 0 {
+		t := ghost(next)
+		lastText := last.(*canvas.Text)
+		if x := last.Position().X + textWidth(lastText.Text, lastText.TextSize, lastText.TextStyle) + gap; x+textWidth(t.Text, t.TextSize, t.TextStyle) <= windowWidth {
+			t.Move(fyne.NewPos(x, 0))
+			word.Objects = append(word.Objects, t)
+		}
+	}
+}
+
+const (
+	// miniPreference remembers whether grr was last left in the mini
+	// window.
+	miniPreference = "mini"
+	// miniFontSize is the largest words are drawn in the mini window.
+	miniFontSize = 36
+)
+
+// miniWindowSize is the size of the mini window.
+var miniWindowSize = fyne.NewSize(360, 80)
+
+// progressLayout stretches its first object, a track, across its space
+// and its second, the fill, as far along it as fraction.
+type progressLayout struct {
+	fraction float32
+}
 
-	beforeText.Move(fyne.NewPos(beforeX, 0))
-	focusText.Move(fyne.NewPos(focusX, 0))
-	afterText.Move(fyne.NewPos(afterX, 0))
+func (l *progressLayout) MinSize([]fyne.CanvasObject) fyne.Size {
+	return fyne.NewSize(0, 3)
+}
+
+func (l *progressLayout) Layout(objects []fyne.CanvasObject, size fyne.Size) {
+	objects[0].Resize(size)
+	objects[1].Resize(fyne.NewSize(size.Width*min(max(l.fraction, 0), 1), size.Height))
+}
+
+// resizeLayout fills its space with each object, as a max layout does,
+// and calls onResize when that space changes size.
+type resizeLayout struct {
+	size     fyne.Size
+	onResize func()
+}
 
-	return c
+func (l *resizeLayout) MinSize(objects []fyne.CanvasObject) fyne.Size {
+	return layout.NewMaxLayout().MinSize(objects)
+}
+
+func (l *resizeLayout) Layout(objects []fyne.CanvasObject, size fyne.Size) {
+	layout.NewMaxLayout().Layout(objects, size)
+	if size == l.size {
+		return
+	}
+	l.size = size
+	if l.onResize != nil {
+		// Redraw once this layout pass is over, not in the middle of it.
+		fyne.Do(l.onResize)
+	}
 }
 
 type centerVerticalLayout struct{}
@@ -117,22 +548,11 @@ func (l *centerVerticalLayout) MinSize(objects []fyne.CanvasObject) fyne.Size {
 }
 
 func (l *centerVerticalLayout) Layout(objects []fyne.CanvasObject, size fyne.Size) {
-	var maxH float32
-	for _, o := range objects {
-		objSize := o.MinSize()
-		if objSize.Height > maxH {
-			maxH = objSize.Height
-		}
-	}
-
-	y := (size.Height - maxH) / 2
-	if y < 0 {
-		y = 0
-	}
-
+	// Each object is centred on its own height, so smaller ghost words
+	// line up with the middle of the word.
 	for _, o := range objects {
-		pos := o.Position()
-		o.Move(fyne.NewPos(pos.X, y))
+		y := max((size.Height-o.MinSize().Height)/2, 0)
+		o.Move(fyne.NewPos(o.Position().X, y))
 		o.Resize(o.MinSize())
 	}
 }
@@ -143,10 +563,72 @@ func main() {
 	showVersionLong := flag.Bool("version", false, "Show version information")
 	showTOC := flag.Bool("toc", false, "Show table of contents at startup")
 	freshStart := flag.Bool("fresh", false, "Ignore saved reading position")
+	display := flag.String("display", "orp", "Word display mode: "+strings.Join(reader.RendererNames(), ", "))
+	themeName := flag.String("theme", envOr(themeEnv, "default"), "Color theme: "+strings.Join(theme.Names(), ", "))
+	nightName := flag.String("night", envOr(nightEnv, "off"), "Switch to the night theme: off, on, system (with the system's dark mode) or a daily time range such as 21:00-07:00")
+	ttsBackend := flag.String("tts", "", "Speak each sentence aloud using a speech backend: "+strings.Join(tts.Backends(), ", "))
+	streamInput := flag.Bool("stream", false, "Read words lazily instead of loading the whole input (automatic for plain text over 16MB and EPUBs with over 4MB of text)")
+	follow := flag.Bool("f", false, "Follow the file or stdin, waiting for appended text at the end")
+	followLong := flag.Bool("follow", false, "Follow the file or stdin, waiting for appended text at the end")
+	transcriptMode := flag.Bool("transcript", false, "Clean up a meeting or podcast transcript (drop fillers, cues and timestamps)")
+	goalMinutes := flag.Float64("minutes", 0, "Session goal: pause after this many minutes of reading")
+	goalWords := flag.Int("words", 0, "Session goal: pause after reading this many words")
+	eyeBreaks := flag.Duration("eye-breaks", 0, "Rest the eyes after every stretch of reading this long, such as 20m (0 disables)")
+	eyeBreakLength := flag.Duration("eye-break-length", 20*time.Second, "How long each eye break lasts")
+	lockFor := flag.Duration("lock", 0, "Keep quitting and the table of contents behind a held key for this long, such as 20m (0 disables)")
+	strict := flag.Bool("strict", false, "Fail on unreadable parts of a document instead of skipping them")
+	paragraphPause := flag.Float64("paragraph-pause", reader.DefaultParagraphPause, "Show the first word of a paragraph this many times longer (1 disables)")
+	chapterPause := flag.Float64("chapter-pause", reader.DefaultChapterPause, "Show the first word of a chapter this many times longer (1 disables)")
+	rewind := flag.Int("rewind", 1, "Sentences to back up when resuming after a long pause (0 disables)")
+	rewindAfter := flag.Duration("rewind-after", reader.DefaultRewindAfter, "Shortest pause after which resuming rewinds")
+	maxWordLength := flag.Int("max-word-length", reader.DefaultMaxWordLength, "Split longer words, such as URLs, into hyphenated parts (0 disables)")
+	maxWords := flag.Int("max-words", defaultMaxWords, "Ask before reading a document with more words than this (0 disables)")
+	maxSizeFlag := flag.String("max-size", defaultMaxSize, "Largest file read into memory whole; larger piped text is streamed (0 disables)")
+	pacing := flag.String("pacing", "fixed", "How long each word is shown: "+strings.Join(reader.PacerNames(), ", "))
+	difficulty := flag.Float64("difficulty", 0, "Show rare and long words up to this much longer, such as 0.5 for half as long again (0 disables)")
+	autoSlow := flag.Bool("auto-slow", false, "Lower the speed by 50 WPM whenever the display keeps showing words late")
+	announceRecords := flag.Bool("records", false, "Announce personal records broken when the session ends")
+	stdinJSON := flag.Bool("stdin-json", false, "Read a JSON document with chapters, a table of contents and metadata from stdin (see brr(1))")
+	jsonSummaryPath := flag.String("json-summary", "", "On exit, write a JSON summary of the session to this file (- for stdout)")
+	presetsPath := flag.String("presets", envOr(presetsEnv, defaultPresetsPath()), "File of named presets switched to with the keys 1-9")
+	keysFlag := flag.String("keys", envOr(keysEnv, "default"), "Control scheme: default or one-handed (every control within reach of the left hand)")
+	linksFlag := flag.String("links", "full", "URLs and email addresses: full (shown longer), domain, placeholder or skip")
+	overflowFlag := flag.String("overflow", "marquee", "Words too wide for the display: marquee (scroll through them) or placeholder (Enter shows them)")
+	stopwordFlag := flag.String("stopwords", "off", "Function words like \"the\" and \"of\": off, half (shown for half the time) or skip")
+	lang := flag.String("lang", "en", "Language of the text, for its punctuation, function words and word frequencies: "+strings.Join(reader.Languages(), ", "))
+	translateWith := flag.String("translate", os.Getenv(translateEnv), "Translate the sentence paused in with a LibreTranslate server URL or a dictionary file")
+	translateTo := flag.String("translate-to", "en", "Language to translate into with --translate")
+	parallelPath := flag.String("parallel", "", "Translation of the document, shown a sentence at a time below its words (see --display parallel)")
+	capturePath := flag.String("capture", os.Getenv(captureEnv), "Markdown file that A appends the current sentence to ({date} is replaced, e.g. a daily note)")
+	captureTemplate := flag.String("capture-template", envOr(captureTemplateEnv, defaultCaptureTemplate), "Template for captured sentences: {sentence}, {source}, {chapter}, {position}, {ref}, {date}, {time}")
+	handoffURL := flag.String("handoff-url", envOr(handoffURLEnv, defaultHandoffURL), "Link shown as a QR code by H while paused: {hash}, {word}, {percent}")
+	checkpoints := flag.Bool("checkpoints", false, "Pause at each new chapter with a recap of the one just read")
+	pauseChapters := flag.Bool("pause-at-chapters", false, "Pause at the end of each chapter")
+	finishAt := flag.Float64("finish-at", defaultFinishAt, "Count a book as finished once past this percentage of it, since back matter is rarely read")
+	figures := flag.Bool("figures", false, "Show EPUB images as [Figure: alt text] so captions keep their context")
+	keepEmpty := flag.Bool("keep-empty", false, "Keep EPUB sections with no text, such as image-only pages, in the table of contents")
+	footnotes := flag.String("footnotes", "skip", "EPUB footnotes and endnotes: skip, appendix (read them in a final Notes chapter) or inline")
+	rubyFlag := flag.String("ruby", "skip", "EPUB ruby annotations such as furigana: skip (read the text alone) or inline (read each reading after its text)")
+	skipMatter := flag.Bool("skip-matter", false, "Leave out EPUB covers, copyright pages, dedications, contents and indexes, starting at the first chapter")
+	subtitles := flag.String("export-subtitles", "", "Write word-timed subtitles (.srt or .vtt) at the -w pace to this file and exit")
+	notesPath := flag.String("notes", "", "Append the sentences marked with M to this Markdown file on quit (default: print them)")
+	exportNotesFor := flag.String("export-notes", "", "Print all saved marks for this book as Markdown and exit")
+	session := flag.String("session", "", "Read from and save to a named reading session, such as one kept from another device")
+	startFlag := flag.String("start", "", "Start at a percentage of the document, such as 45%, or a word number, instead of the saved position")
+	startWord := flag.Int("start-word", 0, "Start at this word number, instead of the saved position")
+	fillers := flag.String("fillers", "", "Filler word list for --transcript, one word or phrase per line")
+	archive := flag.String("archive", os.Getenv(archiveEnv), "Archive/proxy URL template to retry truncated web articles ({url} is replaced)")
+	cookies := flag.String("cookies", os.Getenv(cookiesEnv), "Netscape-format cookie file for fetching web articles")
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Grr - GUI Speed Reading Tool\n\n")
 		fmt.Fprintf(os.Stderr, "Usage:\n")
-		fmt.Fprintf(os.Stderr, "  grr [options] [file]\n\n")
+		fmt.Fprintf(os.Stderr, "  grr [options] [--] [file|url ...]\n")
+		fmt.Fprintf(os.Stderr, "  grr [options] site [-depth n] [-max-pages n] <url>\n")
+		fmt.Fprintf(os.Stderr, "  grr [options] podcast [-episode n] <feed or episode url>\n")
+		fmt.Fprintf(os.Stderr, "  grr [options] yt [-lang code] <video url>\n")
+		fmt.Fprintf(os.Stderr, "  grr [options] releases [-n count] <owner/repo>\n")
+		fmt.Fprintf(os.Stderr, "  grr [options] zotero [-library file] [key or title]\n")
+		fmt.Fprintf(os.Stderr, "  grr state backup | restore [n]\n\n")
 		fmt.Fprintf(os.Stderr, "Options:\n")
 		flag.PrintDefaults()
 		fmt.Fprintf(os.Stderr, "\nExamples:\n")
@@ -154,61 +636,215 @@ func main() {
 		fmt.Fprintf(os.Stderr, "  grr -w 500 file.txt       Read from file at 500 WPM\n")
 		fmt.Fprintf(os.Stderr, "  grr --toc book.epub       Show TOC panel at startup\n")
 		fmt.Fprintf(os.Stderr, "  cat file.txt | grr        Read from stdin\n")
+		fmt.Fprintf(os.Stderr, "  tool | grr --stdin-json  Read a JSON document with chapters\n")
+		fmt.Fprintf(os.Stderr, "  grr https://example.com  Read a web article\n")
 	}
 	flag.Parse()
 
+	renderer, ok := reader.RendererByName(*display)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: Unknown display mode '%s' (choose from %s)\n", *display, strings.Join(reader.RendererNames(), ", "))
+		os.Exit(1)
+	}
+
+	pacer, ok := reader.ParsePacer(*pacing)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: Unknown --pacing strategy '%s' (choose from %s)\n", *pacing, strings.Join(reader.PacerNames(), ", "))
+		os.Exit(1)
+	}
+	presets, err := loadPresets(*presetsPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	linkPolicy, ok := reader.ParseLinkPolicy(*linksFlag)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: Unknown --links policy '%s' (choose from %s)\n", *linksFlag, strings.Join(reader.LinkPolicyNames(), ", "))
+		os.Exit(1)
+	}
+	noteMode, ok := reader.ParseNoteMode(*footnotes)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: Unknown --footnotes mode '%s' (choose from %s)\n", *footnotes, strings.Join(reader.NoteModeNames(), ", "))
+		os.Exit(1)
+	}
+	rubyMode, ok := reader.ParseRubyMode(*rubyFlag)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: Unknown --ruby mode '%s' (choose from %s)\n", *rubyFlag, strings.Join(reader.RubyModeNames(), ", "))
+		os.Exit(1)
+	}
+	keys, ok := parseKeymap(*keysFlag)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: Unknown --keys scheme '%s' (choose from %s)\n", *keysFlag, strings.Join(keymapNames, ", "))
+		os.Exit(1)
+	}
+	overflow, ok := parseOverflowMode(*overflowFlag)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: Unknown --overflow mode '%s' (choose from %s)\n", *overflowFlag, strings.Join(overflowModeNames, ", "))
+		os.Exit(1)
+	}
+	stopwordMode, ok := reader.ParseStopwordMode(*stopwordFlag)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: Unknown --stopwords mode '%s' (choose from off, half, skip)\n", *stopwordFlag)
+		os.Exit(1)
+	}
+	language, ok := reader.LanguageFor(*lang)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: Unknown language '%s' (choose from %s)\n", *lang, strings.Join(reader.Languages(), ", "))
+		os.Exit(1)
+	}
+	// Languages without a function word list have none shortened or
+	// skipped.
+	stopwords, _ := reader.Stopwords(*lang)
+	// Languages without a frequency list have their words paced by length
+	// alone.
+	frequencies, _ := reader.Frequencies(*lang)
+	maxSize, err := parseSize(*maxSizeFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --max-size: %v\n", err)
+		os.Exit(1)
+	}
+	if err := checkFinishAt(*finishAt); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	colors, ok := theme.ByName(*themeName)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: Unknown theme '%s' (choose from %s)\n", *themeName, strings.Join(theme.Names(), ", "))
+		os.Exit(1)
+	}
+	night, err := parseNightMode(*nightName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	startAt, err := startPosition(*startFlag, *startWord)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
 	if *showVersion || *showVersionLong {
 		fmt.Printf("grr %s (commit: %s, built: %s)\n", version, commit, date)
 		os.Exit(0)
 	}
+	command := subcommand(os.Args[1:], flag.Args())
+	zotero := &zoteroSession{}
+	if command == "zotero" {
+		// With no item named, the library is listed rather than read.
+		listed, err := runZoteroList(os.Stdout, flag.Args()[1:], zotero)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if listed {
+			os.Exit(0)
+		}
+	}
 
 	var text string
+	// stdinBook is the title and author of a --stdin-json document.
+	var stdinBook reader.Metadata
 	var toc []reader.TOCEntry
 	var chapters []reader.Chapter
 	var sourceFile string
 
-	if flag.NArg() > 0 {
-		sourceFile = flag.Arg(0)
-
-		lower := strings.ToLower(sourceFile)
-		var tocProvider reader.TOCProvider
-		var chapterExtractor reader.ChapterExtractor
+	if *exportNotesFor != "" {
+		if err := exportNotes(os.Stdout, *exportNotesFor); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	if command == "state" {
+		if err := runStateCommand(os.Stdout, flag.Args()[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	if command == "stats" {
+		if err := runStatsCommand(os.Stdout, flag.Args()[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	if command == "formats" {
+		if err := runFormatsCommand(os.Stdout, flag.Args()[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
 
-		switch {
-		case strings.HasSuffix(lower, ".epub"):
-			tocProvider = &reader.EPUBFormat{}
-			chapterExtractor = &reader.EPUBFormat{}
-		case strings.HasSuffix(lower, ".md"), strings.HasSuffix(lower, ".markdown"):
-			tocProvider = &reader.MarkdownFormat{}
-			chapterExtractor = &reader.MarkdownFormat{}
+	transcript, err := newTranscript(*transcriptMode, *fillers)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	var warnings reader.Warnings
+	opts := loadOptions{archive: *archive, cookies: *cookies, stream: *streamInput, follow: *follow || *followLong, figures: *figures, keepEmpty: *keepEmpty, notes: noteMode, ruby: rubyMode, skipMatter: *skipMatter, strict: *strict, warnings: &warnings, transcript: transcript, maxSize: maxSize, zotero: zotero}
+
+	// files are the files or URLs to read, one after another when there
+	// are several.
+	var files []string
+	if flag.NArg() > 0 && !isSubcommand(command) {
+		var err error
+		files, err = expandFiles(flag.Args())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
 		}
+	}
 
-		if tocProvider != nil {
-			var err error
-			toc, err = tocProvider.TOC(sourceFile)
-			if err != nil {
-				toc = nil
-			}
+	var stream *reader.Reader
+	if len(files) == 1 {
+		var err error
+		stream, err = openStream(files[0], *wpm, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to read file '%s': %v\n", files[0], err)
+			os.Exit(1)
 		}
+		if stream != nil {
+			sourceFile = files[0]
+		}
+	}
 
-		if chapterExtractor != nil {
-			var words []string
-			var err error
-			chapters, words, err = chapterExtractor.ExtractChapters(sourceFile)
-			if err == nil && len(words) > 0 {
-				text = strings.Join(words, " ")
-			}
+	// load, when set, extracts the document in the background while the
+	// window shows a loading indicator.
+	var load loadFunc
+	// sizeNotice explains that piped input too large to hold was streamed.
+	var sizeNotice string
+	switch {
+	case stream != nil:
+		// Words are read lazily as the reader advances.
+
+	case len(files) == 1:
+		sourceFile = files[0]
+		load = fileLoader(sourceFile, opts)
+
+	case len(files) > 1:
+		sourceFile = files[0]
+		load = playlistLoader(files, opts)
+
+	case flag.NArg() > 0:
+		var err error
+		load, err = sourceLoader(flag.Args(), opts, &sourceFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to read '%s': %v\n", sourceFile, err)
+			os.Exit(1)
 		}
 
-		if text == "" {
-			var err error
-			text, err = reader.ExtractText(sourceFile)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: Failed to read file '%s': %v\n", sourceFile, err)
-				os.Exit(1)
-			}
+	case *stdinJSON:
+		var err error
+		text, toc, chapters, stdinBook, err = readDocument(os.Stdin, maxSize)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading stdin: %v\n", err)
+			os.Exit(1)
 		}
-	} else {
+
+	default:
 		stat, _ := os.Stdin.Stat()
 		if (stat.Mode() & os.ModeCharDevice) != 0 {
 			fmt.Fprintln(os.Stderr, "Error: No input provided. Provide a file or pipe text to stdin.")
@@ -216,57 +852,698 @@ func main() {
 			os.Exit(1)
 		}
 
-		data, err := io.ReadAll(os.Stdin)
+		if opts.follow {
+			stream = reader.NewTailReader(reader.NewPollReader(os.Stdin), *wpm)
+			break
+		}
+		if opts.stream && opts.transcript == nil {
+			stream = reader.NewStreamReader(os.Stdin, 0, *wpm)
+			break
+		}
+		data, rest, err := readLimited(os.Stdin, maxSize)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error reading stdin: %v\n", err)
 			os.Exit(1)
 		}
-		text = string(data)
+		if rest != nil {
+			if opts.transcript != nil {
+				fmt.Fprintf(os.Stderr, "Error: Input is larger than the %s limit; raise it with --max-size\n", formatSize(maxSize))
+				os.Exit(1)
+			}
+			stream = reader.NewStreamReader(rest, 0, *wpm)
+			sizeNotice = fmt.Sprintf("Input is over %s, so it is read as it goes", formatSize(maxSize))
+			break
+		}
+		text = reader.DecodeText(data)
+		if opts.transcript != nil {
+			text = opts.transcript.Sanitize(text)
+		}
 	}
 
-	if strings.TrimSpace(text) == "" {
-		fmt.Fprintln(os.Stderr, "Error: No text to read.")
+	// now is the load done before the reader starts.
+	var now loadFunc
+	if load != nil && *subtitles != "" {
+		now, load = load, nil
+	}
+	pre, err := preload(now, sourceFile, *parallelPath, opts)
+	if err != nil {
+		exitIfCancelled(err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	if now != nil {
+		text, toc, chapters = pre.text, pre.toc, pre.chapters
+	}
+	parallel := pre.parallel
+
+	if *subtitles != "" {
+		if stream != nil {
+			fmt.Fprintln(os.Stderr, "Error: Cannot export subtitles from streamed or followed input.")
+			os.Exit(1)
+		}
+		// The cues are paced as reading the document would be.
+		r := reader.NewReader(text, *wpm)
+		r.SetChapters(chapters, toc)
+		r.ParagraphPause = *paragraphPause
+		r.ChapterPause = *chapterPause
+		r.Stopwords = stopwords
+		r.StopwordMode = stopwordMode
+		r.Frequencies = frequencies
+		r.DifficultyStrength = *difficulty
+		r.MaxWordLength = *maxWordLength
+		r.LinkPolicy = linkPolicy
+		r.Pacer = pacer
+		r.SetLanguage(language)
+		if err := exportSubtitles(*subtitles, r); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to export subtitles: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
 
-	m := newModel(text, *wpm, toc, chapters)
+	var speaker tts.Speaker
+	if *ttsBackend != "" {
+		speaker, err = tts.New(*ttsBackend)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	translator, err := newTranslator(*translateWith, *translateTo, *lang)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --translate: %v\n", err)
+		os.Exit(1)
+	}
 
-	if sourceFile != "" {
-		store, err := state.NewStateStore()
-		if err == nil {
-			m.stateStore = store
-			hash, err := state.ComputeHash(sourceFile)
+	// start builds the reading model once the document is loaded, or
+	// with incomplete set, once its first parts have loaded.
+	start := func(text string, toc []reader.TOCEntry, chapters []reader.Chapter, incomplete bool) (*model, error) {
+		var m *model
+		if stream != nil {
+			if len(stream.Words) == 0 && !stream.Waiting() {
+				return nil, errors.New("no text to read")
+			}
+			m = newReaderModel(stream)
+		} else {
+			if strings.TrimSpace(text) == "" {
+				return nil, errors.New("no text to read")
+			}
+			m = newModel(text, *wpm, toc, chapters)
+		}
+		m.renderer = renderer
+		m.goal = newSessionGoal(*goalMinutes, *goalWords)
+		m.eyes = newEyeBreak(*eyeBreaks, *eyeBreakLength)
+		m.lock = newFocusLock(*lockFor, time.Now())
+		m.stats = &sessionStats{}
+		m.frames = &frameStats{autoSlow: *autoSlow}
+		m.recap = newChapterCheckpoint(*checkpoints)
+		if *pauseChapters {
+			pauseAtChapters(m.Reader)
+		}
+		m.recap.watch(m.Reader)
+		m.overflow = overflow
+		m.finishAt = *finishAt
+		m.ParagraphPause = *paragraphPause
+		m.ChapterPause = *chapterPause
+		m.RewindSentences = *rewind
+		m.RewindAfter = *rewindAfter
+		m.Stopwords = stopwords
+		m.StopwordMode = stopwordMode
+		m.Frequencies = frequencies
+		m.DifficultyStrength = *difficulty
+		m.MaxWordLength = *maxWordLength
+		m.LinkPolicy = linkPolicy
+		m.Pacer = pacer
+		m.SetLanguage(language)
+		m.presets = presets
+		m.keys = keys
+		m.sourceFile = sourceFile
+		m.capturePath = *capturePath
+		m.captureTemplate = *captureTemplate
+		m.handoffURL = *handoffURL
+		if len(warnings) > 0 {
+			m.notice = "Warning: " + warnings.Summary()
+		}
+		if stream != nil {
+			m.skipped = newSkipWatch(&warnings)
+		}
+		if sizeNotice != "" {
+			m.notice = sizeNotice
+		}
+		m.speaker = speaker
+		m.translator = translator
+		m.colors = colors
+		m.night = &nightSwitch{mode: night}
+
+		m.book = stdinBook
+		if sourceFile != "" {
+			m.book = bookInfo(sourceFile)
+			verticalDisplay(m.book, &m.renderer)
+			if len(files) > 1 {
+				m.book = playlistInfo(files)
+			}
+			store, err := state.NewStateStore()
 			if err == nil {
-				m.fileHash = hash
-				if !*freshStart {
-					if pos := store.GetPosition(hash); pos > 0 && pos < len(m.Words) {
-						m.CurrentIndex = pos
+				m.stateStore = store
+				if msg := store.Recovered(); msg != "" {
+					m.notice = msg
+				}
+				hash, err := sourceHash(sourceFile, files)
+				if err == nil {
+					m.fileHash = hash
+					if !incomplete {
+						// A document read early is recorded once it has
+						// loaded, with its full length.
+						_, total := m.Progress()
+						recordBook(store, hash, sourceFile, m.book, total)
+					}
+					restoreSettings(store.GetSettings(hash), m.Reader, &m.renderer)
+					m.SetSequence(store.GetSequence(hash))
+					if *session != "" {
+						m.session = *session
+						if s, ok := store.GetSession(hash, *session); ok && !*freshStart {
+							if m.SeekTo(s.WordIndex) {
+								m.resume = newResumePrompt(s.WordIndex)
+							} else if incomplete {
+								m.pendingResume = s.WordIndex
+							}
+						}
+					} else {
+						if !*freshStart {
+							if pos := store.GetPosition(hash); pos > 0 && m.SeekTo(pos) {
+								m.resume = newResumePrompt(pos)
+							} else if pos > 0 && incomplete {
+								m.pendingResume = pos
+							}
+						}
+						if c := findSyncConflict(store, hash); c != nil {
+							// The conflict prompt already says where each copy left off.
+							m.resume, m.pendingResume = nil, 0
+							m.conflict = c
+							m.notice = c.prompt(m.Reader)
+							m.Paused = true
+						}
+					}
+					if m.resume != nil {
+						m.notice = m.resume.prompt(m.Reader)
+						m.Paused = true
+					}
+					if m.pendingResume > 0 {
+						m.notice = pendingResumeNotice(m.pendingResume)
+						m.Paused = true
 					}
 				}
 			}
 		}
+		if parallel != "" {
+			p, err := useParallel(m.Reader, parallel, &m.renderer)
+			if err != nil {
+				m.notice = "Warning: " + err.Error()
+			}
+			m.parallel = p
+		}
+		if startAt != nil {
+			// An explicit start replaces the saved position.
+			m.resume, m.pendingResume = nil, 0
+			notice := goTo(m.Reader, *startAt)
+			if m.conflict == nil {
+				m.notice = notice
+			}
+		}
+		m.stats.begin(m.Reader, time.Now())
+		if h := findHugeInput(m.Reader, *maxWords); h != nil {
+			// Asked once any sync conflict or resume prompt is settled.
+			m.huge = h
+			m.Paused = true
+			if m.conflict == nil && m.resume == nil && m.pendingResume == 0 {
+				m.notice = h.prompt(m.WPM)
+			}
+		}
+
+		if *showTOC && len(toc) > 0 {
+			m.tocVisible = true
+		}
+		return m, nil
 	}
 
-	if *showTOC && len(toc) > 0 {
-		m.tocVisible = true
+	var m *model
+	if load == nil {
+		m, err = start(text, toc, chapters, false)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error: No text to read.")
+			os.Exit(1)
+		}
 	}
 
-	a := app.New()
+	// The ID keeps grr's preferences, such as the mini window, between runs.
+	a := app.NewWithID("com.github.metcalfc.brr")
+	applyTheme(a, colors)
 	w := a.NewWindow("grr - Speed Reader")
+	w.Resize(fyne.NewSize(800, 600))
 
+	var loadErr error
+	// stopLoading cancels a load still running when the window closes;
+	// showReader replaces the window's close handler.
+	stopLoading := func() {}
+	if m != nil {
+		showReader(a, w, m)
+	} else {
+		loadCtx, cancel := context.WithCancel(context.Background())
+		w.SetOnClosed(cancel)
+		stopLoading = cancel
+		// early is set once reading starts before loading finishes.
+		var early atomic.Bool
+		progress := showLoading(w, sourceFile, func() {
+			cancel()
+			loadErr = context.Canceled
+			a.Quit()
+		}, func(parts []loadedPart) {
+			// Read what has loaded while the rest arrives.
+			text, toc, chapters := joinParts(parts)
+			opened, err := start(text, toc, chapters, true)
+			if err != nil {
+				cancel()
+				loadErr = err
+				a.Quit()
+				return
+			}
+			opened.Incomplete = true
+			m = opened
+			early.Store(true)
+			showReader(a, w, m)
+		})
+		go func() {
+			text, toc, chapters, err := load(loadCtx, func(e loadEvent) {
+				fyne.Do(func() {
+					if m == nil {
+						progress(e)
+					} else if part := e.loaded; part != nil {
+						m.Append(part.text, part.toc, part.chapters)
+						if m.pendingResume > 0 {
+							if m.resume = resumeLoaded(m.Reader, m.pendingResume); m.resume != nil {
+								m.pendingResume = 0
+								m.notice = m.resume.prompt(m.Reader)
+								m.Pause()
+								m.stopSpeech()
+							}
+						}
+					}
+				})
+			})
+			var opened *model
+			if err == nil && !early.Load() {
+				opened, err = start(text, toc, chapters, false)
+			}
+			fyne.Do(func() {
+				if loadErr != nil {
+					return
+				}
+				if m != nil {
+					// Reading started early; the parts hold the document.
+					m.Incomplete = false
+					if err == nil {
+						recordLoaded(m.stateStore, m.fileHash, sourceFile, m.book, m.Reader)
+					}
+					if m.pendingResume > 0 {
+						// The saved position is past the end of the document.
+						m.pendingResume = 0
+						m.notice = ""
+					}
+					if err != nil && !errors.Is(err, context.Canceled) {
+						m.notice = "Loading stopped: " + err.Error()
+					}
+					return
+				}
+				if err != nil {
+					loadErr = err
+					a.Quit()
+					return
+				}
+				m = opened
+				showReader(a, w, m)
+			})
+		}()
+	}
+
+	w.ShowAndRun()
+	stopLoading()
+
+	for _, warning := range warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", warning)
+	}
+	if loadErr != nil {
+		exitIfCancelled(loadErr)
+		fmt.Fprintf(os.Stderr, "Error: Failed to read '%s': %v\n", sourceFile, loadErr)
+		os.Exit(1)
+	}
+	if m != nil {
+		if m.stateStore != nil {
+			if msg := m.stateStore.Recovered(); msg != "" {
+				fmt.Fprintf(os.Stderr, "Warning: %s\n", msg)
+			}
+		}
+		if summary := m.frames.summary(); summary != "" {
+			fmt.Fprintln(os.Stderr, summary)
+		}
+		if toast, err := recordSession(m.stats, *announceRecords, time.Now()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to save personal records: %v\n", err)
+		} else if toast != "" {
+			fmt.Fprintln(os.Stderr, toast)
+		}
+		if *jsonSummaryPath != "" {
+			sum := newJSONSummary(m.Reader, m.stats, sourceFile, m.fileHash, m.book, m.finishAt, time.Now())
+			if err := writeJSONSummary(*jsonSummaryPath, sum); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Failed to write session summary: %v\n", err)
+			}
+		}
+		opts.zotero.noteProgress(m.Reader)
+		if err := saveSessionMarks(m.marks, *notesPath, notesTitle(sourceFile)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to write notes: %v\n", err)
+		}
+		if err := m.Err(); err != nil {
+			// A section --strict would not skip ended the text early.
+			fmt.Fprintf(os.Stderr, "Error: Failed to read '%s': %v\n", sourceFile, err)
+			os.Exit(1)
+		}
+	}
+}
+
+// translatePaused starts translating the sentence paused in, with
+// --translate, unless it is translated already or on its way, and calls
+// done on the main thread when the translation arrives.
+func (m *model) translatePaused(done func()) {
+	if m.translator == nil || !m.Paused || len(m.Words) == 0 || m.resume != nil || m.conflict != nil || m.huge != nil {
+		return
+	}
+	start, sentence := pausedSentence(m.Reader)
+	if m.translation != nil && m.translation.start == start {
+		return
+	}
+	pending := &sentenceTranslation{start: start, pending: true}
+	m.translation = pending
+	translator := m.translator
+	go func() {
+		text, err := translator.Translate(context.Background(), sentence)
+		fyne.Do(func() {
+			// Only the latest sentence's translation is kept.
+			if m.translation == pending {
+				m.translation = &sentenceTranslation{start: start, text: text, err: err}
+				done()
+			}
+		})
+	}()
+}
+
+// handoffSize is the smallest side of the QR code the H key shows.
+const handoffSize = 240
+
+// showHandoff shows a QR code of a link to the current position in a
+// dialog, for reading on from there on another device.
+func showHandoff(m *model, w fyne.Window, updateDisplay func()) {
+	if m.fileHash == "" {
+		m.notice = noHandoffNotice
+		updateDisplay()
+		return
+	}
+	link := handoffLink(m.handoffURL, m.fileHash, m.Reader)
+	code, err := handoffCode(link)
+	if err != nil {
+		m.notice = "Could not make a QR code: " + err.Error()
+		updateDisplay()
+		return
+	}
+	img := canvas.NewImageFromImage(qrImage(code))
+	img.FillMode = canvas.ImageFillContain
+	img.ScaleMode = canvas.ImageScalePixels
+	img.SetMinSize(fyne.NewSquareSize(handoffSize))
+	linkLabel := widget.NewLabel(link)
+	linkLabel.Selectable = true
+	linkLabel.Wrapping = fyne.TextWrapBreak
+	linkLabel.Alignment = fyne.TextAlignCenter
+	dialog.ShowCustom("Scan to read on from here", "Close", container.NewVBox(img, linkLabel), w)
+}
+
+// showChapterPreview shows the opening words of the chapter TOC entry i
+// leads to in a dialog, calling jump if asked to go there.
+func showChapterPreview(m *model, w fyne.Window, i int, jump func()) {
+	text := widget.NewLabel(chapterPreview(m.Reader, i))
+	text.Wrapping = fyne.TextWrapWord
+	d := dialog.NewCustomConfirm(m.TOC[i].Title, "Go there", "Close", text, func(ok bool) {
+		if ok {
+			jump()
+		}
+	}, w)
+	d.Resize(fyne.NewSize(w.Canvas().Size().Width*0.6, w.Canvas().Size().Height*0.5))
+	d.Show()
+}
+
+// showAnnotate pauses and asks in a dialog for a note to attach to the
+// current word.
+func showAnnotate(m *model, w fyne.Window, updateDisplay func()) {
+	if m.stateStore == nil || m.fileHash == "" {
+		m.notice = noNotesNotice
+		updateDisplay()
+		return
+	}
+	m.Pause()
+	m.stopSpeech()
+	updateDisplay()
+	entry := widget.NewEntry()
+	entry.SetPlaceHolder("A thought on this passage")
+	var d *dialog.ConfirmDialog
+	save := func() {
+		m.notice = addAnnotation(m.stateStore, m.fileHash, m.Reader, entry.Text, time.Now())
+		updateDisplay()
+	}
+	entry.OnSubmitted = func(string) {
+		d.Hide()
+		save()
+	}
+	d = dialog.NewCustomConfirm("Note at word "+groupDigits(m.Position()+1), "Save", "Cancel", entry, func(ok bool) {
+		if ok {
+			save()
+		}
+	}, w)
+	d.Resize(fyne.NewSize(w.Canvas().Size().Width*0.6, d.MinSize().Height))
+	d.Show()
+	w.Canvas().Focus(entry)
+}
+
+// showAnnotations lists the book's notes at positions in a dialog, going
+// to the one chosen.
+func showAnnotations(m *model, w fyne.Window, updateDisplay func()) {
+	if m.stateStore == nil || m.fileHash == "" {
+		m.notice = noNotesNotice
+		updateDisplay()
+		return
+	}
+	annotations := m.stateStore.Annotations(m.fileHash)
+	if len(annotations) == 0 {
+		m.notice = noAnnotationsNotice
+		updateDisplay()
+		return
+	}
+	m.Pause()
+	m.stopSpeech()
+	updateDisplay()
+	var d dialog.Dialog
+	list := widget.NewList(
+		func() int { return len(annotations) },
+		func() fyne.CanvasObject {
+			ref := widget.NewLabel("")
+			ref.Importance = widget.LowImportance
+			return container.NewVBox(widget.NewLabel(""), ref)
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			labels := obj.(*fyne.Container).Objects
+			labels[0].(*widget.Label).SetText(annotations[id].Text)
+			labels[1].(*widget.Label).SetText(annotationRef(annotations[id]))
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		d.Hide()
+		m.notice = goToAnnotation(m.Reader, annotations[id])
+		updateDisplay()
+	}
+	d = dialog.NewCustom("Notes at positions", "Close", list, w)
+	d.Resize(fyne.NewSize(w.Canvas().Size().Width*0.6, w.Canvas().Size().Height*0.8))
+	d.Show()
+}
+
+// qrImage draws code black on white with a border of handoffQuiet
+// modules, a pixel to a module, to be scaled up without smoothing.
+func qrImage(code *qr.Code) image.Image {
+	size := code.Size + 2*handoffQuiet
+	img := image.NewGray(image.Rect(0, 0, size, size))
+	for y := range size {
+		for x := range size {
+			if !code.Black(x-handoffQuiet, y-handoffQuiet) {
+				img.SetGray(x, y, color.Gray{Y: 0xff})
+			}
+		}
+	}
+	return img
+}
+
+// pauseHints are the keys the pause dashboard suggests, besides those on
+// the controls line.
+const pauseHints = "M: mark  I: note  O: notes  U: undo jump  A: capture  H: hand off"
+
+// pauseSentenceRoom is how much of the current sentence, in runes, the
+// pause dashboard shows around the word.
+const pauseSentenceRoom = 200
+
+// showLoading fills the window with a progress indicator while the
+// document is extracted, calling cancel if the user presses Q or Escape.
+// Once part of a document loaded a part at a time is ready, Enter or a
+// button calls readEarly with the parts so far. It returns a function
+// showing the loader's events, to be called on the UI goroutine.
+func showLoading(w fyne.Window, source string, cancel func(), readEarly func([]loadedPart)) func(loadEvent) {
+	label := widget.NewLabel("Loading " + source + "...")
+	label.Alignment = fyne.TextAlignCenter
+	status := widget.NewLabel("")
+	status.Alignment = fyne.TextAlignCenter
+	hint := widget.NewLabel("Q: cancel")
+	hint.Alignment = fyne.TextAlignCenter
+
+	var parts []loadedPart
+	more := false
+	startReading := widget.NewButton("Start reading", func() {
+		if more {
+			readEarly(parts)
+		}
+	})
+	startReading.Hide()
+	w.SetContent(container.NewCenter(container.NewVBox(label, widget.NewProgressBarInfinite(), status, container.NewCenter(startReading), hint)))
+
+	w.Canvas().SetOnTypedKey(func(key *fyne.KeyEvent) {
+		switch key.Name {
+		case fyne.KeyQ, fyne.KeyEscape:
+			cancel()
+		case fyne.KeyReturn, fyne.KeyEnter:
+			if more {
+				readEarly(parts)
+			}
+		}
+	})
+	return func(e loadEvent) {
+		status.SetText(e.String())
+		if e.loaded == nil {
+			return
+		}
+		parts = append(parts, *e.loaded)
+		if more = e.more(); more {
+			startReading.Show()
+			hint.SetText("ENTER: start reading, Q: cancel")
+		} else {
+			startReading.Hide()
+			hint.SetText("Q: cancel")
+		}
+	}
+}
+
+// guiKeys are the window's keys for the keys a keymap translates to that
+// are not typed as runes.
+var guiKeys = map[string]fyne.KeyName{
+	" ":     fyne.KeySpace,
+	"up":    fyne.KeyUp,
+	"down":  fyne.KeyDown,
+	"left":  fyne.KeyLeft,
+	"right": fyne.KeyRight,
+	"enter": fyne.KeyReturn,
+}
+
+// showReader fills the window with the reading view for m and starts it.
+// showReadingOrder opens the editor of the chapters to read and their
+// order, calling saved once a new order is read from.
+func showReadingOrder(m *model, w fyne.Window, saved func()) {
+	o := newReadingOrder(m.Reader)
+	if o == nil {
+		return
+	}
+	var list *widget.List
+	list = widget.NewList(
+		func() int { return len(o.items) },
+		func() fyne.CanvasObject {
+			return container.NewBorder(nil, nil, widget.NewCheck("", nil),
+				container.NewHBox(widget.NewButton("↑", nil), widget.NewButton("↓", nil)),
+				widget.NewLabel("Title"))
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			row := obj.(*fyne.Container)
+			label := row.Objects[0].(*widget.Label)
+			check := row.Objects[1].(*widget.Check)
+			buttons := row.Objects[2].(*fyne.Container)
+			label.SetText(o.chapters[o.items[id].chapter].Title)
+			check.OnChanged = nil
+			check.SetChecked(o.items[id].included)
+			check.OnChanged = func(bool) {
+				o.cursor = id
+				o.toggle()
+			}
+			for i, delta := range []int{-1, 1} {
+				buttons.Objects[i].(*widget.Button).OnTapped = func() {
+					o.cursor = id
+					o.move(delta)
+					list.Refresh()
+				}
+			}
+		},
+	)
+	reset := widget.NewButton("Document order", func() {
+		o.reset()
+		list.Refresh()
+	})
+	content := container.NewBorder(nil, reset, nil, nil, list)
+	d := dialog.NewCustomConfirm("Reading order", "Save", "Cancel", content, func(save bool) {
+		if !save {
+			return
+		}
+		m.notice = applyReadingOrder(m.Reader, m.stateStore, m.fileHash, o.sequence())
+		saved()
+	}, w)
+	d.Resize(fyne.NewSize(w.Canvas().Size().Width*0.8, w.Canvas().Size().Height*0.8))
+	d.Show()
+}
+
+func showReader(a fyne.App, w fyne.Window, m *model) {
+	bookInfo := ""
+	if title := m.book.String(); title != "" {
+		w.SetTitle(title + " - grr")
+		bookInfo = title + " | "
+	}
+	windowTitle := w.Title()
 	current, total := m.Progress()
-	statusLabel := widget.NewLabel(fmt.Sprintf("Word %d/%d | %d WPM | Font: %.0f [PAUSED]",
-		current, total, m.WPM, m.fontSize))
+	statusLabel := widget.NewLabel(fmt.Sprintf("%sWord %d/%d | %d WPM | Font: %.0f [PAUSED]",
+		bookInfo, current, total, m.WPM, m.fontSize))
 	statusLabel.Alignment = fyne.TextAlignCenter
 
 	tocHint := ""
 	if len(m.TOC) > 0 {
 		tocHint = "  T: TOC"
 	}
-	controlsLabel := widget.NewLabel("SPACE: pause  ↑/↓: speed  +/-: font  ←/→: sentence  R: restart" + tocHint + "  F: fullscreen  Q: quit")
+	controlsLabel := widget.NewLabel("SPACE: pause  ↑/↓: speed  +/-: font  ←/→: sentence  B: mode  C: theme  R: restart  Y: copy sentence" + tocHint + "  F: fullscreen  P: mini window  Q: quit")
 	controlsLabel.Alignment = fyne.TextAlignCenter
-
-	wordContainer := container.NewMax()
+	hudLabel := widget.NewLabel("")
+	hudLabel.Alignment = fyne.TextAlignCenter
+	hudLabel.Importance = widget.LowImportance
+	// While paused, the chapter heads the word and the rest of its
+	// sentence sits over the session's figures below it.
+	headingLabel := widget.NewLabel("")
+	headingLabel.Alignment = fyne.TextAlignCenter
+	headingLabel.TextStyle = fyne.TextStyle{Bold: true}
+	sentenceLabel := widget.NewLabel("")
+	sentenceLabel.Alignment = fyne.TextAlignCenter
+	sentenceLabel.Wrapping = fyne.TextWrapWord
+	translationLabel := widget.NewLabel("")
+	translationLabel.Alignment = fyne.TextAlignCenter
+	translationLabel.Wrapping = fyne.TextWrapWord
+	translationLabel.TextStyle = fyne.TextStyle{Italic: true}
+
+	// The word is anchored to the middle of the space it is shown in,
+	// redrawn whenever that changes size.
+	wordArea := &resizeLayout{}
+	wordContainer := container.New(wordArea)
 
 	var tocList *widget.List
 	var tocPanel *container.Split
@@ -276,16 +1553,22 @@ func main() {
 		tocList = widget.NewList(
 			func() int { return len(m.TOC) },
 			func() fyne.CanvasObject {
-				return container.NewVBox(
+				preview := widget.NewButtonWithIcon("", fynetheme.VisibilityIcon(), nil)
+				preview.Importance = widget.LowImportance
+				return container.NewBorder(nil, nil, nil, preview, container.NewVBox(
 					widget.NewLabel("Title"),
 					widget.NewLabel("Preview"),
-				)
+				))
 			},
 			func(id widget.ListItemID, obj fyne.CanvasObject) {
 				entry := m.TOC[id]
-				vbox := obj.(*fyne.Container)
+				row := obj.(*fyne.Container)
+				vbox := row.Objects[0].(*fyne.Container)
 				titleLabel := vbox.Objects[0].(*widget.Label)
 				previewLabel := vbox.Objects[1].(*widget.Label)
+				row.Objects[1].(*widget.Button).OnTapped = func() {
+					showChapterPreview(m, w, id, func() { tocList.OnSelected(id) })
+				}
 
 				indent := strings.Repeat("  ", entry.Level)
 				titleLabel.SetText(indent + entry.Title)
@@ -301,7 +1584,7 @@ func main() {
 
 		tocList.OnSelected = func(id widget.ListItemID) {
 			if id < len(m.TOC) {
-				m.JumpToChapter(m.TOC[id].WordIndex)
+				m.JumpToTOC(id)
 				m.tocVisible = false
 				tocPanel.Leading.Hide()
 				tocPanel.Refresh()
@@ -310,16 +1593,20 @@ func main() {
 	}
 
 	readingContent := container.NewBorder(
-		statusLabel,
-		controlsLabel,
+		container.NewVBox(statusLabel, headingLabel),
+		container.NewVBox(sentenceLabel, translationLabel, hudLabel, controlsLabel),
 		nil, nil,
 		wordContainer,
 	)
 
+	// The mini window shows the word over a thin progress line.
+	progress := &progressLayout{}
+	progressLine := container.New(progress, canvas.NewRectangle(contextColor), canvas.NewRectangle(focusColor))
+
 	if len(m.TOC) > 0 {
 		tocContainer := container.NewBorder(
 			widget.NewLabel("Table of Contents"),
-			widget.NewLabel("Click to jump • T to close"),
+			widget.NewLabel("Click to jump • Eye: preview • E: reading order • T to close"),
 			nil, nil,
 			tocList,
 		)
@@ -335,32 +1622,163 @@ func main() {
 	} else {
 		mainContainer = container.NewMax(readingContent)
 	}
+	fullContent := mainContainer.Objects[0]
+	fullSize := w.Canvas().Size()
+	miniContent := container.NewBorder(nil, progressLine, nil, nil, wordContainer)
 
 	ticker := time.NewTicker(m.GetDelay())
 	done := make(chan bool)
 	var closeOnce sync.Once
 
-	updateDisplay := func() {
-		if m.CurrentIndex >= len(m.Words) {
+	var updateDisplay func()
+	updateDisplay = func() {
+		if notice := m.skipped.notice(); notice != "" {
+			m.notice = notice
+		}
+		if len(m.Words) > 0 && m.CurrentIndex >= len(m.Words) {
 			m.CurrentIndex = len(m.Words) - 1
 		}
 
-		canvasWidth := w.Canvas().Size().Width
+		canvasWidth := wordArea.size.Width
+		if canvasWidth <= 0 {
+			canvasWidth = w.Canvas().Size().Width
+		}
 		if canvasWidth <= 0 {
 			canvasWidth = 800
 		}
 
-		newWordDisplay := createWordDisplay(m.CurrentWord(), m.fontSize, canvasWidth)
+		word := m.CurrentWord()
+		baseSize := m.fontSize
+		if m.mini {
+			baseSize = min(baseSize, miniFontSize)
+		}
+		fontSize := fitFontSize(word, baseSize, canvasWidth)
+		m.capacity = wordCapacity(word, fontSize, canvasWidth)
+		var newWordDisplay fyne.CanvasObject
+		switch {
+		case m.eyes.active():
+			label := widget.NewLabel(strings.Join(m.eyes.screen(time.Now()), "\n\n"))
+			label.Alignment = fyne.TextAlignCenter
+			newWordDisplay = container.NewCenter(label)
+		case m.capacity == 0:
+			newWordDisplay = createWordDisplay(word, m.renderer, fontSize, canvasWidth)
+			if m.mini {
+				// The mini window has room for the word alone.
+				break
+			}
+			if cr, ok := m.renderer.(reader.ContextRenderer); ok {
+				before, after := cr.Context()
+				prev, next := m.WordsAround(before, after)
+				newWordDisplay = createColumnDisplay(prev, newWordDisplay, next, before, after, m.fontSize, canvasWidth)
+			}
+			if sr, ok := m.renderer.(reader.StripRenderer); ok {
+				prev, next := m.WordsAround(sr.Strip())
+				addGhostWords(newWordDisplay.(*fyne.Container), prev, next, m.fontSize, canvasWidth)
+			}
+			if line := parallelLine(m.Reader, m.renderer, m.parallel); line != "" {
+				// The translation of the sentence, below the word.
+				label := widget.NewLabel(line)
+				label.Alignment = fyne.TextAlignCenter
+				label.Wrapping = fyne.TextWrapWord
+				label.TextStyle = fyne.TextStyle{Italic: true}
+				newWordDisplay = container.NewBorder(nil, label, nil, nil, newWordDisplay)
+			}
+		case m.showLong:
+			label := widget.NewLabel(word)
+			label.Wrapping = fyne.TextWrapBreak
+			label.TextStyle.Bold = true
+			newWordDisplay = container.NewCenter(label)
+		case m.overflow == overflowPlaceholder:
+			placeholder := canvas.NewText(longTokenPlaceholder, contextColor)
+			placeholder.TextSize = baseSize / 2
+			newWordDisplay = container.NewCenter(placeholder)
+		default:
+			text := canvas.NewText(marqueeWindow(word, m.capacity, m.marquee), plainColor)
+			text.TextSize = baseSize
+			text.TextStyle.Bold = true
+			newWordDisplay = container.NewCenter(text)
+		}
 		wordContainer.Objects = []fyne.CanvasObject{newWordDisplay}
 		wordContainer.Refresh()
 
 		pauseText := ""
 		if m.Paused {
 			pauseText = " [PAUSED]"
+		} else if m.Waiting() {
+			pauseText = " [WAITING]"
+		}
+		goalText := ""
+		if g := m.goal.status(); g != "" {
+			goalText = " | " + g
+		}
+		if l := m.lock.status(time.Now()); l != "" {
+			goalText += " | " + l
+		}
+		if m.notice != "" {
+			goalText += " | " + m.notice
 		}
 		current, total := m.Progress()
-		statusLabel.SetText(fmt.Sprintf("Word %d/%d | %d WPM | Font: %.0f%s",
-			current, total, m.WPM, m.fontSize, pauseText))
+		if cur, n := m.ChapterProgress(); len(m.Chapters) > 1 {
+			goalText = fmt.Sprintf(" | Word %d/%d in this chapter", cur, n) + goalText
+		}
+		statusLabel.SetText(fmt.Sprintf("%sWord %d/%d | %d WPM | Font: %.0f%s%s",
+			bookInfo, current, total, m.WPM, m.fontSize, pauseText, goalText))
+		if total > 0 {
+			progress.fraction = float32(current) / float32(total)
+		}
+		progressLine.Refresh()
+		if m.mini {
+			// The mini window's title bar stands in for the status bar.
+			w.SetTitle(fmt.Sprintf("%d%%%s%s - grr", current*100/max(total, 1), pauseText, goalText))
+		}
+
+		if m.Paused {
+			d := newPauseDashboard(m.Reader, m.stats)
+			before, after := d.around(pauseSentenceRoom)
+			headingLabel.SetText(d.heading)
+			if d.heading != "" {
+				headingLabel.Show()
+			} else {
+				headingLabel.Hide()
+			}
+			sentenceLabel.SetText(strings.TrimSpace(before + " " + d.word + " " + after))
+			sentenceLabel.Show()
+			m.translatePaused(updateDisplay)
+			if line := m.translation.line(m.Reader); line != "" {
+				translationLabel.SetText(line)
+				translationLabel.Show()
+			} else {
+				translationLabel.Hide()
+			}
+			hudLabel.SetText(strings.Join(append(d.lines, pauseHints), "\n"))
+			hudLabel.Show()
+		} else {
+			headingLabel.Hide()
+			sentenceLabel.Hide()
+			translationLabel.Hide()
+			hudLabel.Hide()
+		}
+	}
+
+	// setMini switches between the full window and the mini window,
+	// remembering which for next time.
+	setMini := func(mini bool) {
+		m.mini = mini
+		if mini {
+			if w.FullScreen() {
+				w.SetFullScreen(false)
+			}
+			fullSize = w.Canvas().Size()
+			mainContainer.Objects = []fyne.CanvasObject{miniContent}
+			w.Resize(miniWindowSize)
+		} else {
+			mainContainer.Objects = []fyne.CanvasObject{fullContent}
+			w.SetTitle(windowTitle)
+			w.Resize(fullSize)
+		}
+		mainContainer.Refresh()
+		a.Preferences().SetBool(miniPreference, mini)
+		updateDisplay()
 	}
 
 	go func() {
@@ -368,24 +1786,157 @@ func main() {
 			select {
 			case <-done:
 				return
-			case <-ticker.C:
+			case due := <-ticker.C:
+				if m.eyes.active() {
+					// Count the break down, ending it when its time is up.
+					if m.eyes.left(time.Now()) == 0 {
+						m.eyes.end(m.stats, false)
+						m.notice = breakOverNotice
+					}
+					fyne.Do(updateDisplay)
+					continue
+				}
+				if steps := marqueeSteps(m.CurrentWord(), m.capacity); !m.Paused && m.overflow == overflowMarquee && m.marquee < steps {
+					// Scroll through the word, then hold its end for
+					// the usual time.
+					m.marquee++
+					if m.marquee < steps {
+						ticker.Reset(marqueeStep)
+					} else {
+						ticker.Reset(m.WordDelay())
+					}
+					fyne.Do(updateDisplay)
+					continue
+				}
 				if !m.Paused && !m.AtEnd() {
-					m.Advance()
+					delay := m.WordDelay()
+					m.recap.advance(delay)
+					if m.Advance() {
+						m.marquee = 0
+						ticker.Reset(m.WordDelay())
+						m.stats.advance(m.Reader, delay)
+						// The word is late by however long the window
+						// takes to show it after the tick.
+						fyne.Do(func() {
+							if notice := m.frames.shown(m.Reader, time.Since(due)); notice != "" {
+								m.notice = notice
+								ticker.Reset(m.WordDelay())
+							}
+						})
+						// The word counts toward the goal even when a
+						// chapter pause follows it.
+						reached := m.goal.advance(delay)
+						if m.Paused {
+							// Paused at a new chapter by --checkpoints or
+							// --pause-at-chapters.
+							m.stopSpeech()
+							if m.notice = m.recap.take(); m.notice == "" {
+								m.notice = chapterEndNotice(m.Reader)
+							}
+							if reached {
+								m.goalReached()
+							}
+						} else {
+							if m.Frame() == 0 && m.IsSentenceStart(m.CurrentIndex) {
+								m.speak(m.CurrentIndex)
+							}
+							if reached {
+								m.goalReached()
+							} else if m.eyes.advance(delay) {
+								m.Pause()
+								m.stopSpeech()
+								m.eyes.start(time.Now())
+							}
+						}
+					}
 					fyne.Do(updateDisplay)
 				} else if m.AtEnd() && !m.Paused {
 					m.Paused = true
+					m.recap.advance(m.WordDelay())
+					if recap := m.recap.end(m.Reader); recap != "" {
+						m.stopSpeech()
+						m.notice = recap
+					}
 					fyne.Do(updateDisplay)
 				}
 			}
 		}
 	}()
 
-	w.Canvas().SetOnTypedKey(func(key *fyne.KeyEvent) {
+	redo := func() {
+		if !m.Forward() {
+			m.notice = "No later position"
+		}
+		updateDisplay()
+	}
+
+	resolveResume := func(key string) {
+		notice, read, ok := m.resume.resolve(key, m.Reader)
+		if !ok {
+			return
+		}
+		m.resume = nil
+		m.notice = notice
+		if m.huge != nil {
+			m.notice = m.huge.prompt(m.WPM)
+		} else if read {
+			m.ResumeWithRewind()
+			m.speak(m.CurrentIndex)
+		}
+		updateDisplay()
+	}
+
+	typedKey := func(key *fyne.KeyEvent) {
+		if to, ok := m.keys[strings.ToLower(string(key.Name))]; ok {
+			// Letters are translated as runes below; only named keys
+			// such as Tab are translated here.
+			name, named := guiKeys[to]
+			if len(key.Name) == 1 || !named {
+				return
+			}
+			key = &fyne.KeyEvent{Name: name}
+		}
+		if m.eyes.active() {
+			// SPACE skips the break and reads on; only quitting also works.
+			if key.Name == fyne.KeySpace {
+				m.eyes.end(m.stats, true)
+			} else if key.Name != fyne.KeyQ {
+				return
+			}
+		}
+		if m.resume != nil {
+			// Only ENTER and R, typed as a rune, answer the resume prompt.
+			if key.Name == fyne.KeyReturn || key.Name == fyne.KeyEnter {
+				resolveResume("enter")
+			}
+			if key.Name != fyne.KeyQ {
+				return
+			}
+		}
 		switch key.Name {
 		case fyne.KeySpace:
-			m.Paused = !m.Paused
+			if m.conflict != nil || m.huge != nil {
+				return
+			}
+			m.notice = ""
+			m.showLong = false
+			if m.Paused {
+				m.ResumeWithRewind()
+				m.speak(m.CurrentIndex)
+			} else {
+				m.Pause()
+				m.stopSpeech()
+			}
 			updateDisplay()
 
+		case fyne.KeyReturn, fyne.KeyEnter:
+			if m.capacity > 0 {
+				m.Pause()
+				m.stopSpeech()
+				m.showLong = true
+				updateDisplay()
+			}
+
 		case fyne.KeyUp:
 			if m.WPM < 1500 {
 				m.WPM += 50
@@ -407,6 +1958,7 @@ func main() {
 			}
 			m.LastArrowPress = now
 			m.JumpToPrevSentence()
+			m.stats.regress()
 			updateDisplay()
 
 		case fyne.KeyRight:
@@ -422,20 +1974,78 @@ func main() {
 			w.SetFullScreen(!w.FullScreen())
 
 		case fyne.KeyQ:
-			if m.stateStore != nil && m.fileHash != "" {
-				m.stateStore.SetPosition(m.fileHash, m.CurrentIndex)
+			if now := time.Now(); !m.lock.hold("q", now) {
+				m.notice = m.lock.notice("Q", "quit", now)
+				updateDisplay()
+				return
 			}
+			m.stopSpeech()
+			m.savePosition()
 			closeOnce.Do(func() {
 				close(done)
 			})
 			a.Quit()
 		}
-	})
+	}
+	w.Canvas().SetOnTypedKey(typedKey)
 
 	w.Canvas().SetOnTypedRune(func(r rune) {
+		if m.eyes.active() {
+			return
+		}
+		if m.resume != nil {
+			resolveResume(string(r))
+			return
+		}
+		if m.conflict != nil {
+			if notice, ok := m.conflict.resolve(string(r), m.Reader, m.stateStore, m.fileHash); ok {
+				m.conflict = nil
+				m.notice = notice
+				if m.huge != nil {
+					m.notice = m.huge.prompt(m.WPM)
+				}
+				updateDisplay()
+			}
+			return
+		}
+		if m.huge != nil {
+			if notice, ok := m.huge.resolve(string(r), m.Reader); ok {
+				m.huge = nil
+				m.notice = notice
+				updateDisplay()
+			}
+			return
+		}
+		if (r == 'e' || r == 'E') && m.tocVisible {
+			// Over the table of contents, E edits the reading order
+			// whatever the keymap.
+			showReadingOrder(m, w, func() {
+				m.tocVisible = false
+				tocPanel.Leading.Hide()
+				tocPanel.Refresh()
+				updateDisplay()
+			})
+			return
+		}
+		if to, ok := m.keys[string(r)]; ok {
+			if name, ok := guiKeys[to]; ok {
+				typedKey(&fyne.KeyEvent{Name: name})
+				return
+			}
+			if to == "ctrl+r" {
+				redo()
+				return
+			}
+			r = []rune(to)[0]
+		}
 		switch r {
 		case 't', 'T':
 			if tocPanel != nil && len(m.TOC) > 0 {
+				if now := time.Now(); !m.tocVisible && !m.lock.hold("t", now) {
+					m.notice = m.lock.notice("T", "open the contents", now)
+					updateDisplay()
+					return
+				}
 				m.tocVisible = !m.tocVisible
 				if m.tocVisible {
 					m.Paused = true
@@ -447,10 +2057,112 @@ func main() {
 				updateDisplay()
 			}
 
+		case 'b', 'B':
+			m.renderer = nextRenderer(m.renderer, m.parallel)
+			updateDisplay()
+
+		case 's', 'S':
+			m.StopwordMode = m.StopwordMode.Next()
+			m.notice = "Function words: " + m.StopwordMode.String()
+			updateDisplay()
+
+		case 'c', 'C':
+			m.colors = theme.Next(m.colors)
+			applyTheme(a, m.colors)
+			updateDisplay()
+
+		case 'a', 'A':
+			m.capture()
+			updateDisplay()
+
+		case '1', '2', '3', '4', '5', '6', '7', '8', '9':
+			if p, ok := presetKey(m.presets, string(r)); ok {
+				m.notice = p.apply(m.Reader, &m.renderer)
+				updateDisplay()
+			}
+
+		case 'm', 'M':
+			if len(m.Words) > 0 {
+				added := m.marks.add(newMark(m.Reader, time.Now()), m.stateStore, m.fileHash)
+				m.notice = m.marks.markNotice(added)
+				updateDisplay()
+			}
+
+		case 'o', 'O':
+			if m.stateStore == nil || m.fileHash == "" {
+				m.notice = noNotesNotice
+				updateDisplay()
+				return
+			}
+			notes, err := m.stateStore.Notes(m.fileHash)
+			if err != nil {
+				m.notice = "Could not read notes: " + err.Error()
+				updateDisplay()
+				return
+			}
+			m.Pause()
+			m.stopSpeech()
+			updateDisplay()
+			entry := widget.NewMultiLineEntry()
+			entry.SetPlaceHolder("Thoughts on the book so far")
+			entry.Wrapping = fyne.TextWrapWord
+			entry.SetText(notes)
+			title := "Notes"
+			if m.book.Title != "" {
+				title += ": " + m.book.Title
+			}
+			d := dialog.NewCustomConfirm(title, "Save", "Cancel", entry, func(save bool) {
+				if !save {
+					return
+				}
+				m.notice = "Notes saved"
+				if err := m.stateStore.SetNotes(m.fileHash, entry.Text); err != nil {
+					m.notice = "Could not save notes: " + err.Error()
+				}
+				updateDisplay()
+			}, w)
+			d.Resize(fyne.NewSize(w.Canvas().Size().Width*0.8, w.Canvas().Size().Height*0.8))
+			d.Show()
+			w.Canvas().Focus(entry)
+
+		case 'i', 'I':
+			if len(m.Words) > 0 {
+				showAnnotate(m, w, updateDisplay)
+			}
+
+		case 'l', 'L':
+			showAnnotations(m, w, updateDisplay)
+
+		case 'h', 'H':
+			if m.Paused && len(m.Words) > 0 {
+				showHandoff(m, w, updateDisplay)
+			}
+
+		case 'p', 'P':
+			setMini(!m.mini)
+
+		case 'y', 'Y':
+			if m.Paused && len(m.Words) > 0 {
+				a.Clipboard().SetContent(sentenceQuote(m.Reader))
+				m.notice = "Copied sentence to clipboard"
+				updateDisplay()
+			}
+
+		case ']', '}':
+			m.skipRest(r == '}')
+			ticker.Reset(m.WordDelay())
+			updateDisplay()
+
+		case 'u', 'U':
+			if !m.Back() {
+				m.notice = "No earlier position"
+			}
+			updateDisplay()
+
 		case 'r', 'R':
-			m.CurrentIndex = 0
+			m.Jump(m.Start())
 			if m.stateStore != nil && m.fileHash != "" {
-				m.stateStore.Clear(m.fileHash)
+				m.stateStore.SetPosition(m.fileHash, m.Start())
 			}
 			updateDisplay()
 
@@ -467,40 +2179,69 @@ func main() {
 		}
 	})
 
-	w.Resize(fyne.NewSize(800, 600))
+	// Showing the window, resizing it, or resizing the table of contents
+	// beside the word re-anchors the word at once without interrupting
+	// reading.
+	wordArea.onResize = updateDisplay
 	w.SetContent(mainContainer)
+	if a.Preferences().Bool(miniPreference) {
+		setMini(true)
+	}
 
-	var lastWidth float32 = 800
-	go func() {
-		for {
+	// Night mode follows the window's idea of the system's dark mode.
+	windowDark := func() bool {
+		return a.Settings().ThemeVariant() == fynetheme.VariantDark
+	}
+	if th, ok := m.night.update(time.Now(), windowDark, m.colors); ok {
+		m.colors = th
+		applyTheme(a, th)
+		updateDisplay()
+	}
+	if m.night != nil && m.night.mode.changes() {
+		go func() {
+			check := time.NewTicker(nightCheckInterval)
+			defer check.Stop()
+			for {
+				select {
+				case <-done:
+					return
+				case <-check.C:
+					fyne.Do(func() {
+						if th, ok := m.night.update(time.Now(), windowDark, m.colors); ok {
+							m.colors = th
+							applyTheme(a, th)
+							m.notice = m.night.notice()
+							updateDisplay()
+						}
+					})
+				}
+			}
+		}()
+	}
+
+	if left := m.lock.left(time.Now()); left > 0 {
+		go func() {
 			select {
 			case <-done:
-				return
-			default:
-				time.Sleep(100 * time.Millisecond)
-				currentWidth := w.Canvas().Size().Width
-				if currentWidth > 0 && currentWidth != lastWidth {
-					lastWidth = currentWidth
-					m.Paused = true
-					fyne.Do(updateDisplay)
-				}
+			case <-time.After(left):
+				fyne.Do(func() {
+					m.notice = lockOverNotice
+					updateDisplay()
+				})
 			}
-		}
-	}()
+		}()
+	}
+
+	w.Canvas().AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyR, Modifier: fyne.KeyModifierShortcutDefault}, func(fyne.Shortcut) {
+		redo()
+	})
 
 	w.SetOnClosed(func() {
-		if m.stateStore != nil && m.fileHash != "" {
-			m.stateStore.SetPosition(m.fileHash, m.CurrentIndex)
-		}
+		m.stopSpeech()
+		m.savePosition()
 		closeOnce.Do(func() {
 			close(done)
 		})
 	})
 
-	go func() {
-		time.Sleep(100 * time.Millisecond)
-		fyne.Do(updateDisplay)
-	}()
-
-	w.ShowAndRun()
 }
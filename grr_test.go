@@ -0,0 +1,660 @@
+//go:build gui
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/metcalfc/brr/internal/reader"
+	"github.com/metcalfc/brr/internal/state"
+)
+
+const guiText = "Call me Ishmael. Some years ago, never mind how long precisely. There now is your insular city."
+
+// showTestReader shows m in a window of a headless test app, as grr does
+// once a document is loaded.
+func showTestReader(t *testing.T, m *model) fyne.Window {
+	t.Helper()
+	a := test.NewTempApp(t)
+	w := a.NewWindow("grr")
+	w.Resize(fyne.NewSize(800, 600))
+	showReader(a, w, m)
+	t.Cleanup(func() {
+		// Stop the reader's ticker unless the test closed the window.
+		for _, open := range a.Driver().AllWindows() {
+			if open == w {
+				w.Close()
+			}
+		}
+	})
+	return w
+}
+
+func typeKey(w fyne.Window, name fyne.KeyName) {
+	w.Canvas().OnTypedKey()(&fyne.KeyEvent{Name: name})
+}
+
+// findList returns the first list in the widget tree under obj.
+func findList(obj fyne.CanvasObject) *widget.List {
+	switch o := obj.(type) {
+	case *widget.List:
+		return o
+	case *fyne.Container:
+		for _, child := range o.Objects {
+			if l := findList(child); l != nil {
+				return l
+			}
+		}
+	case fyne.Widget:
+		for _, child := range test.WidgetRenderer(o).Objects() {
+			if l := findList(child); l != nil {
+				return l
+			}
+		}
+	}
+	return nil
+}
+
+func TestGUIKeys(t *testing.T) {
+	m := newModel(guiText, 300, nil, nil)
+	w := showTestReader(t, m)
+
+	test.TypeOnCanvas(w.Canvas(), "b")
+	if m.renderer.Name() != "bionic" {
+		t.Errorf("after B display mode = %s, want bionic", m.renderer.Name())
+	}
+	test.TypeOnCanvas(w.Canvas(), "s")
+	if m.StopwordMode != reader.StopwordsHalf || m.notice != "Function words: half" {
+		t.Errorf("after S function words %s, notice %q", m.StopwordMode, m.notice)
+	}
+	theme := m.colors.Name
+	test.TypeOnCanvas(w.Canvas(), "c")
+	if m.colors.Name == theme {
+		t.Errorf("C left the theme at %s", theme)
+	}
+	test.TypeOnCanvas(w.Canvas(), "+")
+	if m.fontSize != 77 {
+		t.Errorf("after + font size = %v, want 77", m.fontSize)
+	}
+
+	typeKey(w, fyne.KeyUp)
+	typeKey(w, fyne.KeyUp)
+	typeKey(w, fyne.KeyDown)
+	if m.WPM != 350 {
+		t.Errorf("after up, up, down WPM = %d, want 350", m.WPM)
+	}
+	typeKey(w, fyne.KeyRight)
+	if m.CurrentIndex != 3 || !m.Paused {
+		t.Errorf("after right at word %d, paused=%v, want paused at word 3", m.CurrentIndex, m.Paused)
+	}
+	typeKey(w, fyne.KeyLeft)
+	if m.CurrentIndex != 0 {
+		t.Errorf("after left at word %d, want 0", m.CurrentIndex)
+	}
+}
+
+func TestGUIPauseResume(t *testing.T) {
+	m := newModel(guiText, 300, nil, nil)
+	w := showTestReader(t, m)
+	if !m.Paused {
+		t.Fatal("grr did not start paused")
+	}
+
+	m.notice = "Theme: default"
+	typeKey(w, fyne.KeySpace)
+	if m.Paused || m.notice != "" {
+		t.Errorf("after SPACE paused=%v, notice %q, want reading with no notice", m.Paused, m.notice)
+	}
+	typeKey(w, fyne.KeySpace)
+	if !m.Paused {
+		t.Error("second SPACE did not pause")
+	}
+}
+
+func TestGUITOCSelection(t *testing.T) {
+	toc := []reader.TOCEntry{{Title: "One", WordIndex: 0}, {Title: "Two", WordIndex: 7}}
+	chapters := []reader.Chapter{{Title: "One", WordStart: 0, WordEnd: 6}, {Title: "Two", WordStart: 7, WordEnd: 16}}
+	m := newModel(guiText, 300, toc, chapters)
+	w := showTestReader(t, m)
+
+	test.TypeOnCanvas(w.Canvas(), "t")
+	if !m.tocVisible {
+		t.Fatal("T did not open the table of contents")
+	}
+	list := findList(w.Content())
+	if list == nil {
+		t.Fatal("no table of contents list in the window")
+	}
+	list.Select(1)
+	if m.CurrentIndex != 7 || m.CurrentChapterTitle() != "Two" || m.tocVisible {
+		t.Errorf("after selecting Two: word %d in %q, TOC visible %v", m.CurrentIndex, m.CurrentChapterTitle(), m.tocVisible)
+	}
+
+	test.TypeOnCanvas(w.Canvas(), "u")
+	if m.CurrentIndex != 0 || m.CurrentChapterTitle() != "One" {
+		t.Errorf("after U: word %d in %q, want back at the start of One", m.CurrentIndex, m.CurrentChapterTitle())
+	}
+}
+
+func TestGUISavesOnClose(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	store, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newModel(guiText, 300, nil, nil)
+	m.stateStore, m.fileHash = store, "hash"
+	w := showTestReader(t, m)
+
+	typeKey(w, fyne.KeyRight)
+	typeKey(w, fyne.KeyUp)
+	w.Close()
+	if got := store.GetPosition("hash"); got != 3 {
+		t.Errorf("saved position = %d, want 3", got)
+	}
+	if got := store.GetSettings("hash").WPM; got != 350 {
+		t.Errorf("saved WPM = %d, want 350", got)
+	}
+}
+
+func TestGUIOneHandedKeys(t *testing.T) {
+	m := newModel(guiText, 300, nil, nil)
+	m.keys, _ = parseKeymap("one-handed")
+	w := showTestReader(t, m)
+
+	test.TypeOnCanvas(w.Canvas(), "ww")
+	if m.WPM != 400 {
+		t.Errorf("after w, w WPM = %d, want 400", m.WPM)
+	}
+	test.TypeOnCanvas(w.Canvas(), "dx")
+	if m.CurrentIndex != 3 || m.StopwordMode != reader.StopwordsHalf {
+		t.Errorf("after d, x at word %d with function words %s, want word 3, half", m.CurrentIndex, m.StopwordMode)
+	}
+	test.TypeOnCanvas(w.Canvas(), "z")
+	if m.CurrentIndex != 0 {
+		t.Errorf("after z at word %d, want 0", m.CurrentIndex)
+	}
+	test.TypeOnCanvas(w.Canvas(), "Z")
+	if m.CurrentIndex != 3 {
+		t.Errorf("after Z at word %d, want 3", m.CurrentIndex)
+	}
+	test.TypeOnCanvas(w.Canvas(), "f")
+	if len(m.marks.marks) != 1 || w.FullScreen() {
+		t.Errorf("after f %d marks, full screen %v; want one mark", len(m.marks.marks), w.FullScreen())
+	}
+}
+
+func TestGUIBookNotes(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	m := newModel(guiText, 300, nil, nil)
+	w := showTestReader(t, m)
+	test.TypeOnCanvas(w.Canvas(), "o")
+	if m.notice != noNotesNotice {
+		t.Errorf("without a book, notice = %q", m.notice)
+	}
+
+	store, _ := state.NewStateStore()
+	m.stateStore, m.fileHash = store, "abcdef1234567890abcdef1234567890"
+	store.SetNotes(m.fileHash, "Who narrates?")
+	test.TypeOnCanvas(w.Canvas(), "o")
+	entry, ok := w.Canvas().Focused().(*widget.Entry)
+	if !ok || entry.Text != "Who narrates?" || !m.Paused {
+		t.Fatalf("O should pause and focus the saved notes, focused %v", w.Canvas().Focused())
+	}
+}
+
+func TestGUIChapterPreview(t *testing.T) {
+	toc := []reader.TOCEntry{{Title: "One", WordIndex: 0}, {Title: "Two", WordIndex: 7}}
+	chapters := []reader.Chapter{{Title: "One", WordStart: 0, WordEnd: 6}, {Title: "Two", WordStart: 7, WordEnd: 16}}
+	m := newModel(guiText, 300, toc, chapters)
+	w := showTestReader(t, m)
+
+	test.TypeOnCanvas(w.Canvas(), "t")
+	preview := findButton(findList(w.Content()), "")
+	if preview == nil {
+		t.Fatal("the table of contents should have preview buttons")
+	}
+	test.Tap(preview)
+	overlay := w.Canvas().Overlays().Top()
+	if overlay == nil || findLabel(overlay, "Call me Ishmael. Some years ago, never") == nil {
+		t.Fatal("the preview should show the first chapter's words")
+	}
+	if m.CurrentIndex != 0 || !m.tocVisible {
+		t.Error("previewing should not move the reader")
+	}
+	test.Tap(findButton(overlay, "Go there"))
+	if m.tocVisible {
+		t.Error("Go there should close the table of contents")
+	}
+}
+
+func TestGUIResumePrompt(t *testing.T) {
+	m := newModel(guiText, 300, nil, nil)
+	m.SeekTo(3)
+	m.resume = newResumePrompt(3)
+	w := showTestReader(t, m)
+
+	typeKey(w, fyne.KeySpace)
+	test.TypeOnCanvas(w.Canvas(), "x")
+	if m.resume == nil || !m.Paused {
+		t.Fatal("keys other than the answers should be ignored while the prompt is shown")
+	}
+	test.TypeOnCanvas(w.Canvas(), "R")
+	if m.resume != nil || m.Position() != 0 || !m.Paused {
+		t.Errorf("after R at word %d, paused %v; want the start, paused", m.Position(), m.Paused)
+	}
+
+	m.SeekTo(3)
+	m.resume = newResumePrompt(3)
+	typeKey(w, fyne.KeyReturn)
+	if m.resume != nil || m.Paused {
+		t.Errorf("after ENTER paused %v, want reading", m.Paused)
+	}
+}
+
+// findButton returns the button labelled text in the widget tree under
+// obj.
+func findButton(obj fyne.CanvasObject, text string) *widget.Button {
+	switch o := obj.(type) {
+	case *widget.Button:
+		if o.Text == text {
+			return o
+		}
+	case *fyne.Container:
+		for _, child := range o.Objects {
+			if b := findButton(child, text); b != nil {
+				return b
+			}
+		}
+	case fyne.Widget:
+		for _, child := range test.WidgetRenderer(o).Objects() {
+			if b := findButton(child, text); b != nil {
+				return b
+			}
+		}
+	}
+	return nil
+}
+
+func TestGUIReadingOrder(t *testing.T) {
+	toc := []reader.TOCEntry{{Title: "One", WordIndex: 0}, {Title: "Two", WordIndex: 7}}
+	chapters := []reader.Chapter{{Title: "One", WordStart: 0, WordEnd: 6}, {Title: "Two", WordStart: 7, WordEnd: 16}}
+	m := newModel(guiText, 300, toc, chapters)
+	m.SetSequence([]int{1})
+	w := showTestReader(t, m)
+
+	test.TypeOnCanvas(w.Canvas(), "e")
+	if w.Canvas().Overlays().Top() != nil {
+		t.Fatal("E should only edit the reading order over the table of contents")
+	}
+	test.TypeOnCanvas(w.Canvas(), "te")
+	overlay := w.Canvas().Overlays().Top()
+	if overlay == nil {
+		t.Fatal("E did not open the reading order")
+	}
+	if list := findList(overlay); list == nil || list.Length() != 2 {
+		t.Fatal("the reading order should list both chapters")
+	}
+	test.Tap(findButton(overlay, "Document order"))
+	test.Tap(findButton(overlay, "Save"))
+	if m.Sequence() != nil || m.tocVisible || m.notice != "Reading every chapter in order" {
+		t.Errorf("after saving document order: sequence %v, TOC visible %v, notice %q", m.Sequence(), m.tocVisible, m.notice)
+	}
+}
+
+func TestFitFontSize(t *testing.T) {
+	test.NewTempApp(t)
+	const width = 800
+	if got := fitFontSize("word", 72, width); got != 72 {
+		t.Errorf("a short word is shrunk to %v", got)
+	}
+
+	word := "incomprehensibilities"
+	size := fitFontSize(word, 200, width)
+	if size >= 200 || size < minFitFontSize {
+		t.Fatalf("fitFontSize(%q) = %v", word, size)
+	}
+	if wordCapacity(word, size, width) != 0 {
+		t.Errorf("%q still overflows at %v", word, size)
+	}
+	// The ORP letter stays where it is for every other word.
+	display := createWordDisplay(word, reader.ORPRenderer{}, size, width)
+	first, last := display.Objects[0], display.Objects[len(display.Objects)-1]
+	if first.Position().X <= 0 || last.Position().X+last.MinSize().Width > width {
+		t.Errorf("%q runs from %v to %v", word, first.Position().X, last.Position().X+last.MinSize().Width)
+	}
+	for _, obj := range display.Objects {
+		if text := obj.(*canvas.Text); text.Color == focusColor && text.Position().X != width/2 {
+			t.Errorf("ORP letter at %v, want %v", text.Position().X, width/2)
+		}
+	}
+
+	long := strings.Repeat("pneumonoultramicroscopic", 4)
+	if got := fitFontSize(long, 72, width); got != 72 {
+		t.Errorf("a word too long to shrink is shrunk to %v", got)
+	}
+}
+
+func TestGUIWordAnchor(t *testing.T) {
+	test.NewTempApp(t)
+	const width = 800
+	for _, renderer := range []reader.WordRenderer{reader.ORPRenderer{}, reader.BionicRenderer{Fraction: 0.5}} {
+		for _, word := range []string{"office", "extra\u00adordinary", "nai\u0308ve", "שָׁלוֹם", "(effluent)"} {
+			display := createWordDisplay(word, renderer, 72, width)
+			anchor := reader.VisualORP(displayWord(word))
+			runes, found := 0, false
+			for _, obj := range display.Objects {
+				text := obj.(*canvas.Text)
+				if strings.ContainsRune(text.Text, '\u00ad') {
+					t.Errorf("%q is drawn with its soft hyphen", word)
+				}
+				if runes == anchor {
+					found = true
+					if text.Position().X != width/2 {
+						t.Errorf("%T: the ORP letter of %q is at %v, want %v", renderer, word, text.Position().X, width/2)
+					}
+				}
+				runes += len([]rune(text.Text))
+			}
+			if !found {
+				t.Errorf("%T: the ORP letter of %q does not start a text", renderer, word)
+			}
+		}
+	}
+}
+
+// findWordArea returns the container the current word is drawn in.
+func findWordArea(obj fyne.CanvasObject) *fyne.Container {
+	switch o := obj.(type) {
+	case *fyne.Container:
+		if _, ok := o.Layout.(*resizeLayout); ok {
+			return o
+		}
+		for _, child := range o.Objects {
+			if c := findWordArea(child); c != nil {
+				return c
+			}
+		}
+	case fyne.Widget:
+		for _, child := range test.WidgetRenderer(o).Objects() {
+			if c := findWordArea(child); c != nil {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+func TestGUIResize(t *testing.T) {
+	m := newModel(guiText, 300, nil, nil)
+	w := showTestReader(t, m)
+	typeKey(w, fyne.KeySpace)
+
+	for _, size := range []fyne.Size{fyne.NewSize(500, 400), fyne.NewSize(1000, 400)} {
+		w.Resize(size)
+		area := findWordArea(w.Content())
+		if area == nil {
+			t.Fatal("no word area")
+		}
+		var focus *canvas.Text
+		for _, obj := range area.Objects[0].(*fyne.Container).Objects {
+			if text := obj.(*canvas.Text); text.Color == focusColor {
+				focus = text
+			}
+		}
+		if focus == nil {
+			t.Fatalf("no ORP letter in %q", m.CurrentWord())
+		}
+		if mid := area.Size().Width / 2; focus.Position().X < mid-1 || focus.Position().X > mid+1 {
+			t.Errorf("at %v the ORP letter is at %v, want %v", size, focus.Position().X, mid)
+		}
+		if m.Paused {
+			t.Errorf("resizing to %v paused reading", size)
+		}
+	}
+}
+
+func TestGUIMiniWindow(t *testing.T) {
+	m := newModel(guiText, 300, nil, nil)
+	w := showTestReader(t, m)
+	full := w.Canvas().Size()
+
+	test.TypeOnCanvas(w.Canvas(), "p")
+	if !m.mini || fyne.CurrentApp().Preferences().Bool(miniPreference) != true {
+		t.Fatal("P did not switch to the mini window and remember it")
+	}
+	if size := w.Canvas().Size(); size.Width >= full.Width || size.Height >= full.Height {
+		t.Errorf("mini window is %v, full window %v", size, full)
+	}
+	if findWordArea(w.Content()) == nil {
+		t.Error("the mini window does not show the word")
+	}
+	if w.Title() == "grr" || !strings.Contains(w.Title(), "PAUSED") {
+		t.Errorf("mini window title %q should show the status", w.Title())
+	}
+
+	test.TypeOnCanvas(w.Canvas(), "p")
+	if m.mini || fyne.CurrentApp().Preferences().Bool(miniPreference) {
+		t.Error("P again did not return to the full window")
+	}
+	if w.Canvas().Size() != full || w.Title() != "grr" {
+		t.Errorf("back to %v titled %q, want %v titled grr", w.Canvas().Size(), w.Title(), full)
+	}
+}
+
+func TestGUILoadingReadEarly(t *testing.T) {
+	a := test.NewTempApp(t)
+	w := a.NewWindow("grr")
+	var read []loadedPart
+	progress := showLoading(w, "docs", func() {}, func(parts []loadedPart) { read = parts })
+
+	progress(loadEvent{stage: loadFetching, source: "https://example.com/a", part: 1, parts: 2})
+	button := findButton(w.Content(), "Start reading")
+	if button == nil || button.Visible() {
+		t.Fatal("Start reading should be hidden until part of the document is ready")
+	}
+	typeKey(w, fyne.KeyReturn)
+	if read != nil {
+		t.Error("Enter should do nothing before any part is ready")
+	}
+
+	part := &loadedPart{text: "One two.", words: 2}
+	progress(loadEvent{stage: loadReady, words: 2, part: 1, parts: 2, loaded: part})
+	if !button.Visible() {
+		t.Error("Start reading should show once the first part is ready")
+	}
+	typeKey(w, fyne.KeyReturn)
+	if len(read) != 1 || read[0].text != "One two." {
+		t.Errorf("Enter should read the parts so far, got %+v", read)
+	}
+}
+
+func TestGUIFocusLock(t *testing.T) {
+	toc := []reader.TOCEntry{{Title: "One", WordIndex: 0}, {Title: "Two", WordIndex: 7}}
+	m := newModel(guiText, 300, toc, nil)
+	m.lock = newFocusLock(time.Minute, time.Now())
+	w := showTestReader(t, m)
+
+	typeKey(w, fyne.KeyQ)
+	if !strings.Contains(m.notice, "Hold Q to quit") {
+		t.Errorf("Q should not quit while locked: notice %q", m.notice)
+	}
+	test.TypeOnCanvas(w.Canvas(), "t")
+	if m.tocVisible || !strings.Contains(m.notice, "Hold T to open the contents") {
+		t.Errorf("T should not open the contents while locked: notice %q", m.notice)
+	}
+}
+
+// findLabel returns the first visible label in the widget tree under obj
+// whose text contains text.
+func findLabel(obj fyne.CanvasObject, text string) *widget.Label {
+	switch o := obj.(type) {
+	case *widget.Label:
+		if o.Visible() && strings.Contains(o.Text, text) {
+			return o
+		}
+	case *fyne.Container:
+		for _, child := range o.Objects {
+			if l := findLabel(child, text); l != nil {
+				return l
+			}
+		}
+	case fyne.Widget:
+		for _, child := range test.WidgetRenderer(o).Objects() {
+			if l := findLabel(child, text); l != nil {
+				return l
+			}
+		}
+	}
+	return nil
+}
+
+func TestGUIPauseDashboard(t *testing.T) {
+	chapters := []reader.Chapter{{Title: "Loomings", WordStart: 0, WordEnd: 9}, {Title: "Ashore", WordStart: 10, WordEnd: 16}}
+	m := newModel(guiText, 300, nil, chapters)
+	m.JumpToChapter(5)
+	w := showTestReader(t, m)
+
+	for _, text := range []string{"Chapter 1 of 2: Loomings", "Some years ago, never mind how long precisely.", "Book ends in", "U: undo jump"} {
+		if findLabel(w.Content(), text) == nil {
+			t.Errorf("the paused window should show %q", text)
+		}
+	}
+	typeKey(w, fyne.KeySpace)
+	if findLabel(w.Content(), "Book ends in") != nil {
+		t.Error("the dashboard should hide while reading")
+	}
+}
+
+func TestGUIHandoff(t *testing.T) {
+	m := newModel(guiText, 300, nil, nil)
+	m.handoffURL = defaultHandoffURL
+	m.Paused = true
+	w := showTestReader(t, m)
+
+	test.TypeOnCanvas(w.Canvas(), "h")
+	if w.Canvas().Overlays().Top() != nil || m.notice != noHandoffNotice {
+		t.Fatalf("H without a file: notice %q", m.notice)
+	}
+	m.fileHash = "abc123"
+	test.TypeOnCanvas(w.Canvas(), "h")
+	overlay := w.Canvas().Overlays().Top()
+	if overlay == nil {
+		t.Fatal("H did not show the QR code")
+	}
+	if findLabel(overlay, "brr://read/abc123?word=1") == nil {
+		t.Error("the dialog should show the link")
+	}
+}
+
+func TestGUISkipKeys(t *testing.T) {
+	m := newModel(guiText, 300, nil, nil)
+	m.stats = &sessionStats{}
+	w := showTestReader(t, m)
+
+	m.Jump(1)
+	test.TypeOnCanvas(w.Canvas(), "]")
+	if m.CurrentIndex != 3 || m.stats.skipped != 2 {
+		t.Errorf("] skipped to %d, counting %d words; want 3 and 2", m.CurrentIndex, m.stats.skipped)
+	}
+	if m.notice != "Skipped the rest of the sentence (2 words)" {
+		t.Errorf("notice = %q", m.notice)
+	}
+	test.TypeOnCanvas(w.Canvas(), "}")
+	if m.CurrentIndex != len(m.Words)-1 {
+		t.Errorf("} in the only paragraph should skip to the last word, at %d", m.CurrentIndex)
+	}
+}
+
+func TestGUIAnnotations(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	m := newModel(guiText, 300, nil, nil)
+	w := showTestReader(t, m)
+
+	test.TypeOnCanvas(w.Canvas(), "i")
+	if w.Canvas().Overlays().Top() != nil || m.notice != noNotesNotice {
+		t.Fatalf("I without a file: notice %q", m.notice)
+	}
+	m.stateStore, _ = state.NewStateStore()
+	m.fileHash = "abcdef1234567890abcdef1234567890"
+	m.Jump(3)
+	test.TypeOnCanvas(w.Canvas(), "i")
+	if w.Canvas().Overlays().Top() == nil {
+		t.Fatal("I did not ask for a note")
+	}
+	entry, ok := w.Canvas().Focused().(*widget.Entry)
+	if !ok {
+		t.Fatal("the note should have the focus")
+	}
+	test.Type(entry, "Who counts?")
+	entry.TypedKey(&fyne.KeyEvent{Name: fyne.KeyReturn})
+	if m.notice != "Note saved at word 4" || w.Canvas().Overlays().Top() != nil {
+		t.Fatalf("Enter should save the note: notice %q", m.notice)
+	}
+
+	m.Jump(0)
+	test.TypeOnCanvas(w.Canvas(), "l")
+	overlay := w.Canvas().Overlays().Top()
+	if overlay == nil {
+		t.Fatal("L did not list the notes")
+	}
+	list := findList(overlay)
+	if list == nil || list.Length() != 1 {
+		t.Fatal("the notes should be listed")
+	}
+	list.Select(0)
+	if m.CurrentIndex != 3 {
+		t.Errorf("choosing the note should go to it, at word %d", m.CurrentIndex)
+	}
+}
+
+// upperTranslator translates by putting text in upper case.
+type upperTranslator struct{}
+
+func (upperTranslator) Name() string { return "upper" }
+
+func (upperTranslator) Translate(ctx context.Context, text string) (string, error) {
+	return strings.ToUpper(text), nil
+}
+
+func TestGUITranslatePaused(t *testing.T) {
+	m := newModel(guiText, 300, nil, nil)
+	m.translator = upperTranslator{}
+	w := showTestReader(t, m)
+
+	// grr opens paused, on the first sentence.
+	wait := func(want string) {
+		t.Helper()
+		for deadline := time.Now().Add(2 * time.Second); m.translation != nil && m.translation.pending && time.Now().Before(deadline); {
+			time.Sleep(10 * time.Millisecond)
+		}
+		if findLabel(w.Content(), want) == nil {
+			t.Errorf("the translation %q should show under the sentence: %+v", want, m.translation)
+		}
+	}
+	wait("CALL ME ISHMAEL.")
+	typeKey(w, fyne.KeyRight)
+	wait("SOME YEARS AGO")
+}
+
+func TestGUIParallel(t *testing.T) {
+	m := newModel(guiText, 300, nil, nil)
+	m.parallel = reader.NewParallelText(m.Reader, "Appelez-moi Ismaël. Il y a quelques années, peu importe combien précisément. Voici votre ville insulaire.")
+	m.renderer = reader.ParallelRenderer{}
+	w := showTestReader(t, m)
+
+	if findLabel(w.Content(), "Appelez-moi Ismaël.") == nil {
+		t.Error("the translation of the first sentence should show below the word")
+	}
+	typeKey(w, fyne.KeyRight)
+	if findLabel(w.Content(), "Il y a quelques années") == nil {
+		t.Error("the translation should follow the sentence being read")
+	}
+}
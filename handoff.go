@@ -0,0 +1,73 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/metcalfc/brr/internal/reader"
+	"rsc.io/qr"
+)
+
+// defaultHandoffURL is the link H shows as a QR code when --handoff-url is
+// not set.
+const defaultHandoffURL = "brr://read/{hash}?word={word}"
+
+// noHandoffNotice is shown when H is pressed without a file to hand off,
+// such as text read from stdin.
+const noHandoffNotice = "Handoff links name a file by its hash; read a file to hand it off"
+
+// handoffQuiet is the width in modules of the light border a QR code needs
+// around it to scan.
+const handoffQuiet = 2
+
+// handoffLink fills in template, such as defaultHandoffURL, for r's
+// position in the document saved under hash: {hash}, {word} (the word
+// number, from 1) and {percent}.
+func handoffLink(template, hash string, r *reader.Reader) string {
+	pos := r.Position()
+	return strings.NewReplacer(
+		"{hash}", hash,
+		"{word}", strconv.Itoa(pos+1),
+		"{percent}", strconv.Itoa(pos*100/max(documentLength(r), 1)),
+	).Replace(template)
+}
+
+// handoffScreen is what the H key shows in brr: a handoff link and the
+// lines of its QR code.
+type handoffScreen struct {
+	link string
+	code []string
+}
+
+// handoffCode encodes link as a QR code, with enough error correction to
+// scan off a screen.
+func handoffCode(link string) (*qr.Code, error) {
+	return qr.Encode(link, qr.M)
+}
+
+// qrBlocks draws code in half blocks, two rows of modules to a line, dark
+// modules as the foreground inside a border of handoffQuiet light ones. It
+// is meant to be shown dark on light.
+func qrBlocks(code *qr.Code) []string {
+	lo, hi := -handoffQuiet, code.Size+handoffQuiet
+	var lines []string
+	for y := lo; y < hi; y += 2 {
+		var b strings.Builder
+		for x := lo; x < hi; x++ {
+			// Black is false outside the code, so the border is light.
+			top, bottom := code.Black(x, y), y+1 < hi && code.Black(x, y+1)
+			switch {
+			case top && bottom:
+				b.WriteString("█")
+			case top:
+				b.WriteString("▀")
+			case bottom:
+				b.WriteString("▄")
+			default:
+				b.WriteString(" ")
+			}
+		}
+		lines = append(lines, b.String())
+	}
+	return lines
+}
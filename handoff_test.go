@@ -0,0 +1,54 @@
+//go:build !gui
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestHandoff(t *testing.T) {
+	m := newModel("one two three four five six seven eight nine ten", 300, nil, nil)
+	m.SeekTo(4)
+	if got, want := handoffLink(defaultHandoffURL, "abc123", m.Reader), "brr://read/abc123?word=5"; got != want {
+		t.Errorf("handoffLink = %q, want %q", got, want)
+	}
+	if got := handoffLink("https://example.com/{hash}#{percent}", "abc123", m.Reader); got != "https://example.com/abc123#40" {
+		t.Errorf("handoffLink with {percent} = %q", got)
+	}
+
+	code, err := handoffCode("brr://read/abc123?word=5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := qrBlocks(code)
+	side := code.Size + 2*handoffQuiet
+	if len(lines) != (side+1)/2 || utf8.RuneCountInString(lines[0]) != side {
+		t.Fatalf("%d modules drawn as %d lines of %d", code.Size, len(lines), utf8.RuneCountInString(lines[0]))
+	}
+	// The top two rows of the finder pattern, below the light border: dark
+	// across seven modules, then at either end.
+	if got := []rune(lines[1])[handoffQuiet : handoffQuiet+7]; string(got) != "█▀▀▀▀▀█" {
+		t.Errorf("finder pattern edge = %q", string(got))
+	}
+
+	m.width, m.height = 100, 40
+	m.Paused = true
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")})
+	if m = updated.(model); m.handoff != nil || m.notice != noHandoffNotice {
+		t.Fatalf("h without a file: notice %q", m.notice)
+	}
+	m.fileHash = "abc123"
+	m.handoffURL = defaultHandoffURL
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")})
+	if m = updated.(model); m.handoff == nil || !strings.Contains(m.View(), "brr://read/abc123?word=5") {
+		t.Fatalf("h should show the link:\n%s", m.View())
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	if m = updated.(model); m.handoff != nil || m.quitting {
+		t.Error("any key should close the QR code, and only close it")
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+// sessionStats counts the words shown this session and the time spent
+// showing them, including the longer pauses at paragraph and chapter
+// starts but not time spent paused, the jumps back to reread a sentence,
+// and the eye breaks taken and skipped. It also notes where and when the
+// session began, for --json-summary.
+type sessionStats struct {
+	words       int
+	elapsed     time.Duration
+	regressions int
+	// skipped counts the words passed over by skipping the rest of a
+	// sentence or paragraph.
+	skipped int
+
+	start int
+	began time.Time
+
+	breaks, breaksSkipped int
+}
+
+// advance records the move to the reader's current word after showing the
+// previous one for delay. Later frames of a split word are not counted as
+// words.
+func (s *sessionStats) advance(r *reader.Reader, delay time.Duration) {
+	if s == nil {
+		return
+	}
+	s.elapsed += delay
+	if r.Frame() == 0 {
+		s.words++
+	}
+}
+
+// begin notes that the session starts at the reader's current word at now.
+func (s *sessionStats) begin(r *reader.Reader, now time.Time) {
+	if s == nil {
+		return
+	}
+	s.start = r.Position()
+	s.began = now
+}
+
+// regress records a jump back to reread a sentence.
+func (s *sessionStats) regress() {
+	if s != nil {
+		s.regressions++
+	}
+}
+
+// skip records n words skipped without being read.
+func (s *sessionStats) skip(n int) {
+	if s != nil {
+		s.skipped += n
+	}
+}
+
+// wpm returns the effective reading speed, or 0 before anything is read.
+func (s *sessionStats) wpm() int {
+	if s == nil || s.elapsed <= 0 {
+		return 0
+	}
+	return int(float64(s.words) / s.elapsed.Minutes())
+}
+
+// pausedHUD returns the lines of the panel shown while paused: the current
+// chapter and sentence, what has been read this session and at what
+// effective speed, and the time left in the chapter at that speed.
+func pausedHUD(r *reader.Reader, s *sessionStats) []string {
+	var lines []string
+	pos := r.Position()
+	end := -1
+	if n := len(r.Chapters); n > 0 {
+		ch := r.Chapters[r.CurrentChapter]
+		line := fmt.Sprintf("Chapter %d of %d", r.CurrentChapter+1, n)
+		if ch.Title != "" {
+			line += ": " + ch.Title
+		}
+		lines = append(lines, line)
+		end = ch.WordEnd
+	}
+	lines = append(lines, fmt.Sprintf("Sentence %d", r.SentenceIndex(r.CurrentIndex)+1))
+
+	wpm := r.WPM
+	if s != nil && s.words > 0 {
+		wpm = max(s.wpm(), 1)
+		lines = append(lines, fmt.Sprintf("Read %d words this session at %d WPM effective", s.words, s.wpm()))
+	}
+	if s != nil && s.skipped > 0 {
+		lines = append(lines, fmt.Sprintf("Skipped %d words", s.skipped))
+	}
+	if s != nil && s.breaks+s.breaksSkipped > 0 {
+		lines = append(lines, fmt.Sprintf("Eye breaks: %d taken, %d skipped", s.breaks, s.breaksSkipped))
+	}
+
+	label := "Chapter"
+	if end < 0 {
+		_, total := r.Progress()
+		end, label = total-1, "Text"
+	}
+	left := time.Duration(float64(max(end-pos, 0)) / float64(wpm) * float64(time.Minute)).Round(time.Second)
+	lines = append(lines, fmt.Sprintf("%s ends in %d:%02d", label, int(left.Minutes()), int(left.Seconds())%60))
+	return lines
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+func TestPausedHUD(t *testing.T) {
+	r := reader.NewReader("One two. Three four five. Six seven eight nine ten.", 300)
+	r.SetChapters([]reader.Chapter{
+		{Title: "Start", WordStart: 0, WordEnd: 4},
+		{Title: "End", WordStart: 5, WordEnd: 9},
+	}, nil)
+	r.JumpToChapter(3)
+
+	want := []string{"Chapter 1 of 2: Start", "Sentence 2", "Chapter ends in 0:00"}
+	if got := pausedHUD(r, nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("pausedHUD = %q, want %q", got, want)
+	}
+
+	// Three words in a minute is 3 WPM, so the last word of the chapter
+	// takes 20s.
+	s := &sessionStats{}
+	r.JumpToChapter(5)
+	for range 3 {
+		r.Advance()
+		s.advance(r, 20*time.Second)
+	}
+	want = []string{"Chapter 2 of 2: End", "Sentence 3", "Read 3 words this session at 3 WPM effective", "Chapter ends in 0:20"}
+	if got := pausedHUD(r, s); !reflect.DeepEqual(got, want) {
+		t.Errorf("pausedHUD = %q, want %q", got, want)
+	}
+
+	plain := reader.NewReader("a b c d e f g h i j", 60)
+	if got := pausedHUD(plain, nil); got[len(got)-1] != "Text ends in 0:09" {
+		t.Errorf("without chapters, last line = %q", got[len(got)-1])
+	}
+}
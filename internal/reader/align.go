@@ -0,0 +1,170 @@
+package reader
+
+import (
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// ParallelText is a translation of the document a Reader reads, aligned
+// with it sentence by sentence, for reading parallel texts.
+type ParallelText struct {
+	// sentences holds the translation of each of the reader's sentences,
+	// "" for one with none. Sentences translated together share a text.
+	sentences []string
+}
+
+// NewParallelText aligns translation with the sentences of r, which must
+// hold its whole document rather than read it lazily.
+func NewParallelText(r *Reader, translation string) *ParallelText {
+	src := sentenceTexts(r.Words, r.SentenceStarts)
+	t := NewReader(translation, r.WPM)
+	dst := sentenceTexts(t.Words, t.SentenceStarts)
+	p := &ParallelText{sentences: make([]string, len(src))}
+	i, j := 0, 0
+	for _, b := range alignSentences(runeLengths(src), runeLengths(dst)) {
+		text := strings.Join(dst[j:j+b.dst], " ")
+		for k := i; k < i+b.src; k++ {
+			p.sentences[k] = text
+		}
+		i, j = i+b.src, j+b.dst
+	}
+	return p
+}
+
+// Sentence returns the translation of the reader's sentence i, or "" if
+// it has none.
+func (p *ParallelText) Sentence(i int) string {
+	if p == nil || i < 0 || i >= len(p.sentences) {
+		return ""
+	}
+	return p.sentences[i]
+}
+
+// sentenceTexts joins words into the sentences that start at starts.
+func sentenceTexts(words []string, starts []int) []string {
+	if len(words) == 0 {
+		return nil
+	}
+	texts := make([]string, len(starts))
+	for i, start := range starts {
+		end := len(words)
+		if i+1 < len(starts) {
+			end = starts[i+1]
+		}
+		texts[i] = strings.Join(words[start:end], " ")
+	}
+	return texts
+}
+
+func runeLengths(texts []string) []int {
+	lengths := make([]int, len(texts))
+	for i, t := range texts {
+		lengths[i] = utf8.RuneCountInString(t)
+	}
+	return lengths
+}
+
+// bead is a step of an alignment: src sentences of the document matched
+// with dst sentences of its translation.
+type bead struct {
+	src, dst int
+	// cost is the penalty for the step: minus the log of how often
+	// translations match sentences so, from Gale and Church's counts.
+	cost float64
+}
+
+var beads = []bead{
+	{1, 1, -math.Log(0.89)},
+	{1, 0, -math.Log(0.0099)},
+	{0, 1, -math.Log(0.0099)},
+	{2, 1, -math.Log(0.089)},
+	{1, 2, -math.Log(0.089)},
+	{2, 2, -math.Log(0.011)},
+}
+
+// alignBand is how far from the diagonal, in sentences, an alignment may
+// stray besides the difference in the texts' sentence counts. It keeps
+// aligning a book quick.
+const alignBand = 50
+
+// alignSentences aligns sentences of the given lengths by length, as Gale
+// and Church did: a sentence and its translation are about as long, in
+// proportion to the texts' lengths. It returns the steps of the cheapest
+// alignment, in order.
+func alignSentences(src, dst []int) []bead {
+	n, m := len(src), len(dst)
+	if n == 0 || m == 0 {
+		return []bead{{src: n, dst: m}}
+	}
+	var srcTotal, dstTotal int
+	for _, l := range src {
+		srcTotal += l
+	}
+	for _, l := range dst {
+		dstTotal += l
+	}
+	ratio := float64(max(dstTotal, 1)) / float64(max(srcTotal, 1))
+	band := alignBand + max(n-m, m-n)
+	inBand := func(i, j int) bool {
+		return math.Abs(float64(i)*float64(m)/float64(n)-float64(j)) <= float64(band)
+	}
+
+	// cost[i][j] is the cheapest alignment of the first i and j sentences,
+	// and step[i][j] its last bead.
+	cost := make([][]float64, n+1)
+	step := make([][]int8, n+1)
+	for i := range cost {
+		cost[i] = make([]float64, m+1)
+		step[i] = make([]int8, m+1)
+		for j := range cost[i] {
+			cost[i][j] = math.Inf(1)
+		}
+	}
+	cost[0][0] = 0
+	for i := 0; i <= n; i++ {
+		for j := 0; j <= m; j++ {
+			if (i > 0 || j > 0) && !inBand(i, j) {
+				continue
+			}
+			for k, b := range beads {
+				pi, pj := i-b.src, j-b.dst
+				if pi < 0 || pj < 0 || math.IsInf(cost[pi][pj], 1) {
+					continue
+				}
+				ls, lt := 0, 0
+				for _, l := range src[pi:i] {
+					ls += l
+				}
+				for _, l := range dst[pj:j] {
+					lt += l
+				}
+				if c := cost[pi][pj] + b.cost + lengthCost(ls, lt, ratio); c < cost[i][j] {
+					cost[i][j] = c
+					step[i][j] = int8(k)
+				}
+			}
+		}
+	}
+
+	var path []bead
+	for i, j := n, m; i > 0 || j > 0; {
+		b := beads[step[i][j]]
+		path = append(path, b)
+		i, j = i-b.src, j-b.dst
+	}
+	for l, r := 0, len(path)-1; l < r; l, r = l+1, r-1 {
+		path[l], path[r] = path[r], path[l]
+	}
+	return path
+}
+
+// lengthCost is the penalty for matching text of ls runes with a
+// translation of lt runes: the further lt is from ls scaled by ratio, the
+// less likely they are each other's translation.
+func lengthCost(ls, lt int, ratio float64) float64 {
+	// Gale and Church's variance of the lengths of translated sentences.
+	const variance = 6.8
+	delta := (float64(lt) - float64(ls)*ratio) / math.Sqrt(float64(ls+1)*variance)
+	return delta * delta / 2
+}
@@ -0,0 +1,43 @@
+package reader
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAlignSentences(t *testing.T) {
+	tests := []struct {
+		name     string
+		src, dst []int
+		want     []bead
+	}{
+		{"one to one", []int{20, 40, 10}, []int{22, 38, 11}, []bead{beads[0], beads[0], beads[0]}},
+		{"split in translation", []int{20, 20, 40, 20}, []int{20, 20, 20, 20, 20}, []bead{beads[0], beads[0], beads[4], beads[0]}},
+		{"joined in translation", []int{20, 20, 20, 20, 20}, []int{20, 20, 40, 20}, []bead{beads[0], beads[0], beads[3], beads[0]}},
+		{"no translation", []int{20, 30}, nil, []bead{{src: 2}}},
+	}
+	for _, tt := range tests {
+		if got := alignSentences(tt.src, tt.dst); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: alignSentences(%v, %v) = %v, want %v", tt.name, tt.src, tt.dst, got, tt.want)
+		}
+	}
+}
+
+func TestParallelText(t *testing.T) {
+	r := NewReader("Call me Ishmael. Some years ago, never mind how long precisely. It rains. It pours.", 300)
+	p := NewParallelText(r, "Appelez-moi Ismaël. Il y a quelques années, peu importe combien précisément. Il pleut à verse.")
+	for i, want := range []string{
+		"Appelez-moi Ismaël.",
+		"Il y a quelques années, peu importe combien précisément.",
+		"Il pleut à verse.",
+		"Il pleut à verse.",
+		"",
+	} {
+		if got := p.Sentence(i); got != want {
+			t.Errorf("Sentence(%d) = %q, want %q", i, got, want)
+		}
+	}
+	if got := (*ParallelText)(nil).Sentence(0); got != "" {
+		t.Errorf("a nil text should have no sentences, got %q", got)
+	}
+}
@@ -0,0 +1,50 @@
+package reader
+
+import (
+	"net/url"
+	"strings"
+)
+
+// minArticleWords is the word count below which a fetched article is
+// considered suspiciously short.
+const minArticleWords = 150
+
+// paywallPhrases appear on pages that only show a teaser of the article.
+var paywallPhrases = []string{
+	"subscribe to continue reading",
+	"to continue reading",
+	"already a subscriber",
+	"create a free account to continue",
+	"this article is for subscribers",
+	"subscribers only",
+	"sign in to read",
+	"you have reached your limit of free articles",
+}
+
+// LooksTruncated reports whether extracted article text appears to be cut
+// off by a paywall or teaser page.
+func LooksTruncated(text string) bool {
+	if len(strings.Fields(text)) < minArticleWords {
+		return true
+	}
+	key := normalizeBlock(text)
+	for _, p := range paywallPhrases {
+		if strings.Contains(key, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// ArchiveURL builds the URL to fetch rawURL through an archive or proxy
+// endpoint. The template may contain {url} (raw) or {url_escaped}
+// (query-escaped); otherwise the URL is appended.
+func ArchiveURL(template, rawURL string) string {
+	switch {
+	case strings.Contains(template, "{url_escaped}"):
+		return strings.ReplaceAll(template, "{url_escaped}", url.QueryEscape(rawURL))
+	case strings.Contains(template, "{url}"):
+		return strings.ReplaceAll(template, "{url}", rawURL)
+	}
+	return template + rawURL
+}
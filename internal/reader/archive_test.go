@@ -0,0 +1,47 @@
+package reader
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLooksTruncated(t *testing.T) {
+	full := strings.Repeat("Plenty of real article words here. ", 50)
+
+	tests := []struct {
+		name     string
+		text     string
+		expected bool
+	}{
+		{"short teaser", "Only a few words.", true},
+		{"full article", full, false},
+		{"paywall prompt", full + " Subscribe to continue reading.", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LooksTruncated(tt.text); got != tt.expected {
+				t.Errorf("LooksTruncated() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestArchiveURL(t *testing.T) {
+	raw := "https://example.com/a?b=c"
+
+	tests := []struct {
+		template string
+		expected string
+	}{
+		{"https://archive.example/newest/{url}", "https://archive.example/newest/https://example.com/a?b=c"},
+		{"https://proxy.example/?u={url_escaped}", "https://proxy.example/?u=https%3A%2F%2Fexample.com%2Fa%3Fb%3Dc"},
+		{"https://archive.example/", "https://archive.example/https://example.com/a?b=c"},
+	}
+
+	for _, tt := range tests {
+		if got := ArchiveURL(tt.template, raw); got != tt.expected {
+			t.Errorf("ArchiveURL(%q) = %q, want %q", tt.template, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package reader
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadCookieFile(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := r.Cookie("session")
+		if err != nil {
+			http.Error(w, "login required", http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("welcome " + c.Value))
+	}))
+	defer srv.Close()
+
+	host := strings.TrimPrefix(srv.URL, "http://")
+	host = host[:strings.Index(host, ":")]
+
+	cookieFile := filepath.Join(t.TempDir(), "cookies.txt")
+	content := "# Netscape HTTP Cookie File\n" +
+		"#HttpOnly_" + host + "\tFALSE\t/\tFALSE\t0\tsession\tsubscriber\n"
+	if err := os.WriteFile(cookieFile, []byte(content), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	jar, err := LoadCookieFile(cookieFile)
+	if err != nil {
+		t.Fatalf("LoadCookieFile: %v", err)
+	}
+
+	ctx := WithClient(context.Background(), &http.Client{Jar: jar})
+	text, err := ExtractTextFromURLContext(ctx, srv.URL)
+	if err != nil {
+		t.Fatalf("ExtractTextFromURLContext: %v", err)
+	}
+	if text != "welcome subscriber" {
+		t.Errorf("got %q, want %q", text, "welcome subscriber")
+	}
+
+	if _, err := ExtractTextFromURL(srv.URL); err == nil {
+		t.Error("the cookies should only be sent with the client they were given to")
+	}
+}
+
+func TestLoadCookieFileMalformed(t *testing.T) {
+	cookieFile := filepath.Join(t.TempDir(), "cookies.txt")
+	os.WriteFile(cookieFile, []byte("example.com\tTRUE\t/\n"), 0644)
+
+	if _, err := LoadCookieFile(cookieFile); err == nil {
+		t.Error("expected error for malformed line")
+	}
+}
@@ -0,0 +1,242 @@
+package reader
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// CrawlOptions bounds a documentation site crawl.
+type CrawlOptions struct {
+	MaxDepth int
+	MaxPages int
+	// OnPage, if set, is called with each page's section as it is read,
+	// so the pages can be read before the crawl is done.
+	OnPage func(Section)
+}
+
+// DefaultCrawlOptions keeps crawls small enough to read in one session.
+var DefaultCrawlOptions = CrawlOptions{MaxDepth: 2, MaxPages: 50}
+
+// CrawlSiteContext fetches the pages under start's path prefix, following
+// links breadth-first in document order so pages come out in navigation
+// order. robots.txt on the host is respected. Each page becomes one
+// Section. The crawl stops early when ctx is cancelled.
+func CrawlSiteContext(ctx context.Context, start string, opts CrawlOptions) ([]Section, error) {
+	base, err := url.Parse(start)
+	if err != nil {
+		return nil, err
+	}
+	prefix := base.Path
+	if !strings.HasSuffix(prefix, "/") {
+		prefix = path.Dir(prefix)
+		if !strings.HasSuffix(prefix, "/") {
+			prefix += "/"
+		}
+	}
+
+	robots := fetchRobots(ctx, base)
+
+	type queued struct {
+		u     *url.URL
+		depth int
+	}
+	queue := []queued{{u: base, depth: 0}}
+	seen := map[string]bool{canonicalURL(base): true}
+	var sections []Section
+
+	for len(queue) > 0 && len(sections) < opts.MaxPages {
+		q := queue[0]
+		queue = queue[1:]
+
+		if robots.disallowed(q.u.Path) {
+			continue
+		}
+
+		data, contentType, err := fetchURLContext(ctx, q.u.String())
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err != nil {
+			if q.depth == 0 {
+				return nil, err
+			}
+			continue
+		}
+		if !strings.Contains(contentType, "html") {
+			continue
+		}
+
+		doc, err := html.Parse(bytes.NewReader(data))
+		if err != nil {
+			continue
+		}
+
+		title, links := pageTitleAndLinks(doc)
+		text := ExtractHTML(string(data))
+		if title == "" {
+			title = q.u.Path
+		}
+		sections = append(sections, Section{Title: title, Text: text})
+		if opts.OnPage != nil {
+			opts.OnPage(sections[len(sections)-1])
+		}
+
+		if q.depth >= opts.MaxDepth {
+			continue
+		}
+		for _, href := range links {
+			ref, err := url.Parse(href)
+			if err != nil {
+				continue
+			}
+			next := q.u.ResolveReference(ref)
+			next.Fragment = ""
+			if next.Host != base.Host || !strings.HasPrefix(next.Path, prefix) {
+				continue
+			}
+			key := canonicalURL(next)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			queue = append(queue, queued{u: next, depth: q.depth + 1})
+		}
+	}
+
+	if len(sections) == 0 {
+		return nil, fmt.Errorf("no readable pages found under %s", start)
+	}
+	return sections, nil
+}
+
+// canonicalURL identifies a page regardless of fragment or trailing index file.
+func canonicalURL(u *url.URL) string {
+	p := strings.TrimSuffix(u.Path, "index.html")
+	return u.Host + p + "?" + u.RawQuery
+}
+
+// pageTitleAndLinks returns the page's first heading (or <title>) and its
+// link targets in document order.
+func pageTitleAndLinks(doc *html.Node) (string, []string) {
+	var title, h1 string
+	var links []string
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "title":
+				if title == "" {
+					title = strings.TrimSpace(nodeText(n))
+				}
+			case "h1":
+				if h1 == "" {
+					h1 = strings.TrimSpace(nodeText(n))
+				}
+			case "a":
+				for _, a := range n.Attr {
+					if a.Key == "href" && a.Val != "" {
+						links = append(links, a.Val)
+					}
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	if h1 != "" {
+		return h1, links
+	}
+	return title, links
+}
+
+// nodeText returns the concatenated text content of n.
+func nodeText(n *html.Node) string {
+	var sb strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+// robotsRules holds the Disallow prefixes that apply to brr.
+type robotsRules struct {
+	disallow []string
+}
+
+func (r robotsRules) disallowed(p string) bool {
+	for _, d := range r.disallow {
+		if strings.HasPrefix(p, d) {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchRobots loads robots.txt for base's host. A missing or unreadable
+// file allows everything.
+func fetchRobots(ctx context.Context, base *url.URL) robotsRules {
+	robotsURL := &url.URL{Scheme: base.Scheme, Host: base.Host, Path: "/robots.txt"}
+	data, _, err := fetchURLContext(ctx, robotsURL.String())
+	if err != nil {
+		return robotsRules{}
+	}
+	return parseRobots(string(data))
+}
+
+// parseRobots extracts Disallow rules for the "*" and "brr" user agents.
+func parseRobots(s string) robotsRules {
+	var rules robotsRules
+	applies := false
+	inAgents := false
+	scanner := bufio.NewScanner(strings.NewReader(s))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if !inAgents {
+				applies = false
+			}
+			inAgents = true
+			agent := strings.ToLower(value)
+			if agent == "*" || agent == "brr" {
+				applies = true
+			}
+		case "disallow":
+			inAgents = false
+			if applies && value != "" {
+				rules.disallow = append(rules.disallow, value)
+			}
+		default:
+			inAgents = false
+		}
+	}
+	return rules
+}
@@ -0,0 +1,95 @@
+package reader
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestCrawlSite(t *testing.T) {
+	pages := map[string]string{
+		"/robots.txt": "User-agent: *\nDisallow: /guide/private\n",
+		"/guide/": `<html><head><title>Guide</title></head><body>
+			<nav><a href="intro.html">Intro</a> <a href="setup.html">Setup</a>
+			<a href="private/secret.html">Secret</a> <a href="/other/page.html">Other</a></nav>
+			<h1>Guide</h1><p>Welcome to the guide.</p></body></html>`,
+		"/guide/intro.html": `<html><body><h1>Intro</h1><p>Intro text.</p>
+			<a href="deep.html">Deep</a> <a href="/guide/#top">Home</a></body></html>`,
+		"/guide/setup.html":          `<html><body><h1>Setup</h1><p>Setup text.</p></body></html>`,
+		"/guide/deep.html":           `<html><body><h1>Deep</h1><p>Deep text.</p><a href="deeper.html">x</a></body></html>`,
+		"/guide/deeper.html":         `<html><body><h1>Deeper</h1><p>Too deep.</p></body></html>`,
+		"/guide/private/secret.html": `<html><body><h1>Secret</h1></body></html>`,
+		"/other/page.html":           `<html><body><h1>Other</h1></body></html>`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Path == "/robots.txt" {
+			w.Header().Set("Content-Type", "text/plain")
+		} else {
+			w.Header().Set("Content-Type", "text/html")
+		}
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	var read []string
+	onPage := func(s Section) { read = append(read, s.Title) }
+	sections, err := CrawlSiteContext(context.Background(), srv.URL+"/guide/", CrawlOptions{MaxDepth: 2, MaxPages: 10, OnPage: onPage})
+	if err != nil {
+		t.Fatalf("CrawlSiteContext: %v", err)
+	}
+
+	expected := []string{"Guide", "Intro", "Setup", "Deep"}
+	if len(sections) != len(expected) {
+		titles := make([]string, len(sections))
+		for i, s := range sections {
+			titles[i] = s.Title
+		}
+		t.Fatalf("got sections %v, want %v", titles, expected)
+	}
+	for i, title := range expected {
+		if sections[i].Title != title {
+			t.Errorf("section %d: got %q, want %q", i, sections[i].Title, title)
+		}
+	}
+	if !slices.Equal(read, expected) {
+		t.Errorf("OnPage saw %v, want %v", read, expected)
+	}
+}
+
+func TestParseRobots(t *testing.T) {
+	rules := parseRobots("User-agent: googlebot\nDisallow: /g\n\nUser-agent: *\nDisallow: /private # no\nAllow: /public\n")
+	if !rules.disallowed("/private/x") {
+		t.Error("/private should be disallowed")
+	}
+	if rules.disallowed("/g/page") {
+		t.Error("googlebot rules should not apply")
+	}
+}
+
+func TestAssembleSections(t *testing.T) {
+	chapters, toc, words := AssembleSections([]Section{
+		{Title: "One", Text: "first section words"},
+		{Title: "Empty", Text: "  "},
+		{Title: "Two", Text: "second section", Level: 1},
+	})
+
+	if len(words) != 5 {
+		t.Fatalf("expected 5 words, got %d", len(words))
+	}
+	if len(chapters) != 2 || len(toc) != 2 {
+		t.Fatalf("expected 2 chapters and TOC entries, got %d and %d", len(chapters), len(toc))
+	}
+	if chapters[1].WordStart != 3 || chapters[1].WordEnd != 4 {
+		t.Errorf("chapter 2 bounds = %d-%d, want 3-4", chapters[1].WordStart, chapters[1].WordEnd)
+	}
+	if toc[1].Level != 1 || toc[1].WordIndex != 3 {
+		t.Errorf("unexpected TOC entry %+v", toc[1])
+	}
+}
@@ -0,0 +1,328 @@
+package reader
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// DocsPage is a page of a documentation tree: a Markdown file, or, when
+// File is empty, a heading over the pages that follow it at deeper levels.
+type DocsPage struct {
+	Title string
+	File  string
+	Level int
+}
+
+// DocsTree lists the Markdown pages under dir in reading order. A
+// SUMMARY.md, as mdBook keeps, or the nav of an mkdocs.yml sets the order
+// and nesting when there is one. Otherwise each directory's index.md or
+// README.md comes first, followed by its other files and subdirectories in
+// natural order, so ch2 comes before ch10, each subdirectory a level
+// deeper.
+func DocsTree(dir string) ([]DocsPage, error) {
+	for _, summary := range []string{filepath.Join(dir, "SUMMARY.md"), filepath.Join(dir, "src", "SUMMARY.md")} {
+		if pages, err := summaryPages(summary); err == nil && len(pages) > 0 {
+			return pages, nil
+		}
+	}
+	if pages, err := mkdocsPages(dir); err == nil && len(pages) > 0 {
+		return pages, nil
+	}
+	return walkDocs(dir, 0)
+}
+
+// isMarkdown reports whether name is a Markdown file.
+func isMarkdown(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	return ext == ".md" || ext == ".markdown"
+}
+
+// walkDocs lists the Markdown pages under dir, at level and deeper.
+func walkDocs(dir string, level int) ([]DocsPage, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(entries, func(a, b os.DirEntry) int {
+		return naturalCompare(a.Name(), b.Name())
+	})
+
+	var pages, rest []DocsPage
+	for _, e := range entries {
+		name := e.Name()
+		path := filepath.Join(dir, name)
+		switch {
+		case strings.HasPrefix(name, "."):
+		case e.IsDir():
+			sub, err := walkDocs(path, level+1)
+			if err != nil {
+				return nil, err
+			}
+			if len(sub) == 0 {
+				continue
+			}
+			// A subdirectory's index page stands for it; without one
+			// it is a heading over its pages.
+			head := DocsPage{Title: docsTitle(name), Level: level}
+			if filepath.Dir(sub[0].File) == path && isIndexPage(sub[0].File) {
+				head = DocsPage{Title: sub[0].Title, File: sub[0].File, Level: level}
+				sub = sub[1:]
+			}
+			rest = append(rest, head)
+			rest = append(rest, sub...)
+		case isMarkdown(name) && isIndexPage(name):
+			pages = append(pages, DocsPage{Title: markdownTitle(path), File: path, Level: level})
+		case isMarkdown(name):
+			rest = append(rest, DocsPage{Title: markdownTitle(path), File: path, Level: level})
+		}
+	}
+	if len(pages) > 1 {
+		// index.md is preferred to README.md when there are both.
+		slices.SortStableFunc(pages, func(a, b DocsPage) int {
+			return strings.Compare(strings.ToLower(filepath.Base(a.File)), strings.ToLower(filepath.Base(b.File)))
+		})
+	}
+	return append(pages, rest...), nil
+}
+
+// isIndexPage reports whether path names a directory's own page.
+func isIndexPage(path string) bool {
+	base := strings.ToLower(filepath.Base(path))
+	base = strings.TrimSuffix(base, filepath.Ext(base))
+	return base == "index" || base == "readme"
+}
+
+// docsTitle turns a file or directory name into a title, as in
+// "getting-started" to "getting started".
+func docsTitle(name string) string {
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	return strings.TrimSpace(strings.NewReplacer("-", " ", "_", " ").Replace(name))
+}
+
+// markdownTitle returns the first heading of a Markdown file, or a title
+// made from its name.
+func markdownTitle(path string) string {
+	f, err := os.Open(path)
+	if err == nil {
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		for lines := 0; scanner.Scan() && lines < 50; lines++ {
+			if m := headerRegex.FindStringSubmatch(strings.TrimSpace(scanner.Text())); m != nil {
+				return CleanMarkdown(m[2])
+			}
+		}
+	}
+	return docsTitle(filepath.Base(path))
+}
+
+// naturalCompare compares names ignoring case and with runs of digits
+// compared by value, so "ch2" sorts before "ch10".
+func naturalCompare(a, b string) int {
+	ar, br := []rune(strings.ToLower(a)), []rune(strings.ToLower(b))
+	for len(ar) > 0 && len(br) > 0 {
+		if unicode.IsDigit(ar[0]) && unicode.IsDigit(br[0]) {
+			an, arest := digitRun(ar)
+			bn, brest := digitRun(br)
+			if c := len(an) - len(bn); c != 0 {
+				return c
+			}
+			if c := strings.Compare(string(an), string(bn)); c != 0 {
+				return c
+			}
+			ar, br = arest, brest
+			continue
+		}
+		if ar[0] != br[0] {
+			return int(ar[0]) - int(br[0])
+		}
+		ar, br = ar[1:], br[1:]
+	}
+	return len(ar) - len(br)
+}
+
+// digitRun splits the digits leading s, without leading zeros, from the
+// rest of it.
+func digitRun(s []rune) (digits, rest []rune) {
+	i := 0
+	for i < len(s) && unicode.IsDigit(s[i]) {
+		i++
+	}
+	digits = s[:i]
+	for len(digits) > 1 && digits[0] == '0' {
+		digits = digits[1:]
+	}
+	return digits, s[i:]
+}
+
+// summaryItemRegex matches an entry of an mdBook SUMMARY.md, listed or not:
+// "- [Title](path.md)" or "[Title](path.md)".
+var summaryItemRegex = regexp.MustCompile(`^(\s*)(?:[-*+]\s+)?\[([^\]]*)\]\(([^)]*)\)\s*$`)
+
+// summaryPages lists the pages of an mdBook SUMMARY.md, nested as its list
+// is. Draft chapters, with no file, are left out.
+func summaryPages(summary string) ([]DocsPage, error) {
+	f, err := os.Open(summary)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var pages []DocsPage
+	var indents []int
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		m := summaryItemRegex.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+		level := nestingLevel(&indents, len(strings.ReplaceAll(m[1], "\t", "    ")))
+		file := docsLink(filepath.Dir(summary), m[3])
+		if file == "" {
+			continue
+		}
+		pages = append(pages, DocsPage{Title: CleanMarkdown(m[2]), File: file, Level: level})
+	}
+	return pages, scanner.Err()
+}
+
+// nestingLevel returns how deeply a list item indented by indent is nested
+// under those before it, tracked in indents.
+func nestingLevel(indents *[]int, indent int) int {
+	for len(*indents) > 0 && (*indents)[len(*indents)-1] >= indent {
+		*indents = (*indents)[:len(*indents)-1]
+	}
+	*indents = append(*indents, indent)
+	return len(*indents) - 1
+}
+
+// docsLink returns the local Markdown file a link in dir points to, or ""
+// if it is empty, a URL or not a Markdown file that exists.
+func docsLink(dir, link string) string {
+	link, _, _ = strings.Cut(strings.TrimSpace(link), "#")
+	if link == "" || IsURL(link) || !isMarkdown(link) {
+		return ""
+	}
+	path := filepath.Join(dir, filepath.FromSlash(link))
+	if info, err := os.Stat(path); err != nil || !info.Mode().IsRegular() {
+		return ""
+	}
+	return path
+}
+
+// mkdocsPages lists the pages of the nav of the mkdocs.yml in dir, or in
+// its parent when dir is the site's docs directory.
+func mkdocsPages(dir string) ([]DocsPage, error) {
+	site := filepath.Clean(dir)
+	data, err := os.ReadFile(filepath.Join(site, "mkdocs.yml"))
+	if err != nil {
+		site = filepath.Dir(site)
+		if data, err = os.ReadFile(filepath.Join(site, "mkdocs.yml")); err != nil {
+			return nil, err
+		}
+	}
+	nav, docsDir := parseMkdocs(string(data))
+	docsDir = filepath.Join(site, filepath.FromSlash(docsDir))
+	if site != filepath.Clean(dir) && docsDir != filepath.Clean(dir) {
+		// The parent's site keeps its pages somewhere else.
+		return nil, nil
+	}
+
+	var pages []DocsPage
+	for _, item := range nav {
+		if item.File == "" {
+			pages = append(pages, item)
+			continue
+		}
+		file := docsLink(docsDir, item.File)
+		if file == "" {
+			continue
+		}
+		if item.Title == "" {
+			item.Title = markdownTitle(file)
+		}
+		item.File = file
+		pages = append(pages, item)
+	}
+	return pages, nil
+}
+
+// mkdocsNavRegex matches an item of an mkdocs.yml nav: "- Title: page.md",
+// "- page.md" or "- Section:".
+var mkdocsNavRegex = regexp.MustCompile(`^(\s*)-\s+(.*)$`)
+
+// parseMkdocs reads the nav and docs_dir of an mkdocs.yml, which defaults
+// to "docs". Nav items hold the page's path as written; sections have no
+// File. Only the block list form of nav is understood.
+func parseMkdocs(config string) (nav []DocsPage, docsDir string) {
+	docsDir = "docs"
+	inNav := false
+	var indents []int
+	for _, line := range strings.Split(config, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") && !strings.HasPrefix(line, "-") {
+			key, value, _ := strings.Cut(line, ":")
+			inNav = key == "nav"
+			if key == "docs_dir" {
+				docsDir = unquoteYAML(value)
+			}
+			continue
+		}
+		if !inNav {
+			continue
+		}
+		m := mkdocsNavRegex.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		level := nestingLevel(&indents, len(m[1]))
+		title, file := "", m[2]
+		if t, f, ok := cutYAMLKey(m[2]); ok {
+			title, file = t, f
+		}
+		nav = append(nav, DocsPage{Title: unquoteYAML(title), File: unquoteYAML(file), Level: level})
+	}
+	return nav, docsDir
+}
+
+// cutYAMLKey splits "key: value" or "key:", minding quoted keys.
+func cutYAMLKey(s string) (key, value string, ok bool) {
+	if s == "" {
+		return "", "", false
+	}
+	if q := s[0]; q == '"' || q == '\'' {
+		if end := strings.IndexByte(s[1:], q); end >= 0 {
+			rest := strings.TrimSpace(s[end+2:])
+			if strings.HasPrefix(rest, ":") {
+				return s[:end+2], strings.TrimSpace(rest[1:]), true
+			}
+		}
+		return "", "", false
+	}
+	if i := strings.Index(s, ": "); i >= 0 {
+		return s[:i], strings.TrimSpace(s[i+2:]), true
+	}
+	if strings.HasSuffix(s, ":") {
+		return strings.TrimSuffix(s, ":"), "", true
+	}
+	return "", "", false
+}
+
+// unquoteYAML strips the quotes and any trailing comment from a YAML scalar.
+func unquoteYAML(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	if i := strings.Index(s, " #"); i >= 0 {
+		s = strings.TrimSpace(s[:i])
+	}
+	return s
+}
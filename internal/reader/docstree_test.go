@@ -0,0 +1,141 @@
+package reader
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeDocs creates files under dir, with their parent directories.
+func writeDocs(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, text := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// pageList describes pages as "level title file" with file relative to dir.
+func pageList(t *testing.T, dir string, pages []DocsPage) []string {
+	t.Helper()
+	var list []string
+	for _, p := range pages {
+		file := p.File
+		if file != "" {
+			file, _ = filepath.Rel(dir, file)
+			file = filepath.ToSlash(file)
+		}
+		list = append(list, string(rune('0'+p.Level))+" "+p.Title+" "+file)
+	}
+	return list
+}
+
+func TestDocsTreeWalk(t *testing.T) {
+	dir := t.TempDir()
+	writeDocs(t, dir, map[string]string{
+		"README.md":              "# Welcome\n\nHello.",
+		"ch10.md":                "Ten.",
+		"ch2.md":                 "# Second\n\nTwo.",
+		"guide/index.md":         "# The Guide\n\nGuide.",
+		"guide/install.md":       "Install.",
+		"reference/api.md":       "# API\n\nCalls.",
+		"reference/notes.txt":    "Not Markdown.",
+		".hidden/secret.md":      "Hidden.",
+		"empty/picture.png":      "",
+		"reference/cli/flags.md": "Flags.",
+	})
+	pages, err := DocsTree(dir)
+	if err != nil {
+		t.Fatalf("DocsTree: %v", err)
+	}
+	want := []string{
+		"0 Welcome README.md",
+		"0 Second ch2.md",
+		"0 ch10 ch10.md",
+		"0 The Guide guide/index.md",
+		"1 install guide/install.md",
+		"0 reference ",
+		"1 API reference/api.md",
+		"1 cli ",
+		"2 flags reference/cli/flags.md",
+	}
+	if got := pageList(t, dir, pages); !reflect.DeepEqual(got, want) {
+		t.Errorf("DocsTree =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestDocsTreeSummary(t *testing.T) {
+	dir := t.TempDir()
+	writeDocs(t, dir, map[string]string{
+		"src/SUMMARY.md":       "# Summary\n\n[Introduction](intro.md)\n\n- [Getting *Started*](start.md)\n    - [Install](start/install.md#linux)\n- [Draft]()\n- [Elsewhere](https://example.com/page.md)\n- [Usage](usage.md)\n",
+		"src/intro.md":         "Intro.",
+		"src/start.md":         "Start.",
+		"src/start/install.md": "Install.",
+		"src/usage.md":         "Usage.",
+		"src/aardvark.md":      "Not in the summary.",
+	})
+	pages, err := DocsTree(dir)
+	if err != nil {
+		t.Fatalf("DocsTree: %v", err)
+	}
+	want := []string{
+		"0 Introduction src/intro.md",
+		"0 Getting Started src/start.md",
+		"1 Install src/start/install.md",
+		"0 Usage src/usage.md",
+	}
+	if got := pageList(t, dir, pages); !reflect.DeepEqual(got, want) {
+		t.Errorf("DocsTree =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestDocsTreeMkdocs(t *testing.T) {
+	root := t.TempDir()
+	writeDocs(t, root, map[string]string{
+		"mkdocs.yml":             "site_name: Demo\nnav:\n  - Home: index.md\n  - 'User Guide':\n      - 'Writing docs': guide/writing.md\n      - guide/styling.md\n  - About: https://example.com  # external\ntheme: material\n",
+		"docs/index.md":          "Home.",
+		"docs/guide/writing.md":  "Writing.",
+		"docs/guide/styling.md":  "# Styling Pages\n\nStyle.",
+		"docs/guide/zz-extra.md": "Not in the nav.",
+	})
+	want := []string{
+		"0 Home docs/index.md",
+		"0 User Guide ",
+		"1 Writing docs docs/guide/writing.md",
+		"1 Styling Pages docs/guide/styling.md",
+	}
+	for _, dir := range []string{root, filepath.Join(root, "docs")} {
+		pages, err := DocsTree(dir)
+		if err != nil {
+			t.Fatalf("DocsTree(%s): %v", dir, err)
+		}
+		if got := pageList(t, root, pages); !reflect.DeepEqual(got, want) {
+			t.Errorf("DocsTree(%s) =\n%q\nwant\n%q", dir, got, want)
+		}
+	}
+}
+
+func TestNaturalCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		less bool
+	}{
+		{"ch2", "ch10", true},
+		{"ch10", "ch2", false},
+		{"Ch02", "ch3", true},
+		{"appendix", "Chapter", true},
+		{"part1-ch9", "part1-ch10", true},
+		{"a", "a1", true},
+	}
+	for _, tt := range tests {
+		if got := naturalCompare(tt.a, tt.b) < 0; got != tt.less {
+			t.Errorf("naturalCompare(%q, %q) < 0 = %v, want %v", tt.a, tt.b, got, tt.less)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package reader
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Document is a structured document handed over as JSON, so tools in any
+// language can feed brr chapters and a table of contents along with the
+// text (brr --stdin-json). It holds either Text, split into words as any
+// text is, or Words, one per display word. Chapters and TOC entries point
+// at words by their index, counting from 0.
+type Document struct {
+	Title    string            `json:"title,omitempty"`
+	Author   string            `json:"author,omitempty"`
+	Text     string            `json:"text,omitempty"`
+	Words    []string          `json:"words,omitempty"`
+	Chapters []DocumentChapter `json:"chapters,omitempty"`
+	TOC      []DocumentEntry   `json:"toc,omitempty"`
+}
+
+// DocumentChapter is a chapter of a Document, running from its Start word
+// to the word before the next chapter's start.
+type DocumentChapter struct {
+	Title string `json:"title"`
+	Start int    `json:"start"`
+}
+
+// DocumentEntry is a table of contents entry of a Document. Entries nested
+// under others have a higher Level, starting from 0.
+type DocumentEntry struct {
+	Title string `json:"title"`
+	Word  int    `json:"word"`
+	Level int    `json:"level,omitempty"`
+}
+
+// ReadDocument decodes a Document from r, rejecting unknown fields so a
+// misspelled one is not silently ignored.
+func ReadDocument(r io.Reader) (Document, error) {
+	var d Document
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&d); err != nil {
+		return d, fmt.Errorf("invalid document: %w", err)
+	}
+	return d, nil
+}
+
+// Metadata returns the document's title and author.
+func (d Document) Metadata() Metadata {
+	return Metadata{Title: cleanMetadata(d.Title), Author: cleanMetadata(d.Author)}
+}
+
+// Content returns the document's text with its table of contents and
+// chapters, checking that they point at words it has. Without a TOC, the
+// chapters make one.
+func (d Document) Content() (string, []TOCEntry, []Chapter, error) {
+	text := d.Text
+	if len(d.Words) > 0 {
+		if text != "" {
+			return "", nil, nil, errors.New("invalid document: has both text and words")
+		}
+		text = strings.Join(d.Words, " ")
+		if n := len(ParseText(text)); n != len(d.Words) {
+			return "", nil, nil, fmt.Errorf("invalid document: %d words split into %d; words must not hold spaces", len(d.Words), n)
+		}
+	}
+	words := ParseText(text)
+
+	var chapters []Chapter
+	for i, c := range d.Chapters {
+		if c.Start < 0 || c.Start >= len(words) || (i > 0 && c.Start <= d.Chapters[i-1].Start) {
+			return "", nil, nil, fmt.Errorf("invalid document: chapter %d starts at word %d, out of order or past the %d words", i+1, c.Start, len(words))
+		}
+		end := len(words) - 1
+		if i+1 < len(d.Chapters) {
+			end = d.Chapters[i+1].Start - 1
+		}
+		chapters = append(chapters, Chapter{Title: c.Title, WordStart: c.Start, WordEnd: end})
+	}
+
+	var toc []TOCEntry
+	for i, e := range d.TOC {
+		if e.Word < 0 || e.Word >= len(words) {
+			return "", nil, nil, fmt.Errorf("invalid document: TOC entry %d points at word %d, past the %d words", i+1, e.Word, len(words))
+		}
+		toc = append(toc, TOCEntry{Title: e.Title, Preview: sectionPreview(words[e.Word:]), WordIndex: e.Word, Level: e.Level})
+	}
+	if len(d.TOC) == 0 {
+		for _, c := range chapters {
+			toc = append(toc, TOCEntry{Title: c.Title, Preview: sectionPreview(words[c.WordStart:]), WordIndex: c.WordStart})
+		}
+	}
+	return text, toc, chapters, nil
+}
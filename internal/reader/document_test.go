@@ -0,0 +1,57 @@
+package reader
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadDocument(t *testing.T) {
+	doc, err := ReadDocument(strings.NewReader(`{
+		"title": "Stories", "author": "A. Writer",
+		"words": ["Once", "upon", "a", "time.", "The", "end."],
+		"chapters": [{"title": "Opening", "start": 0}, {"title": "Ending", "start": 4}]
+	}`))
+	if err != nil {
+		t.Fatalf("ReadDocument: %v", err)
+	}
+	if got := doc.Metadata().String(); got != "Stories by A. Writer" {
+		t.Errorf("Metadata = %q", got)
+	}
+	text, toc, chapters, err := doc.Content()
+	if err != nil {
+		t.Fatalf("Content: %v", err)
+	}
+	if text != "Once upon a time. The end." {
+		t.Errorf("text = %q", text)
+	}
+	if len(chapters) != 2 || chapters[0].WordEnd != 3 || chapters[1].WordStart != 4 || chapters[1].WordEnd != 5 {
+		t.Errorf("chapters = %+v", chapters)
+	}
+	if len(toc) != 2 || toc[1].Title != "Ending" || toc[1].WordIndex != 4 || toc[1].Preview != "The end...." {
+		t.Errorf("toc made from the chapters = %+v", toc)
+	}
+
+	doc, _ = ReadDocument(strings.NewReader(`{"text": "One. Two. Three.", "toc": [{"title": "Part", "word": 0}, {"title": "Three", "word": 2, "level": 1}]}`))
+	if _, toc, chapters, err := doc.Content(); err != nil || len(chapters) != 0 || len(toc) != 2 || toc[1].Level != 1 {
+		t.Errorf("Content = %+v, %+v, %v", toc, chapters, err)
+	}
+
+	for _, bad := range []string{
+		`{"text": "One two", "words": ["One", "two"]}`,
+		`{"words": ["One two"]}`,
+		`{"text": "One two", "chapters": [{"start": 2}]}`,
+		`{"text": "One two", "chapters": [{"start": 1}, {"start": 1}]}`,
+		`{"text": "One two", "toc": [{"word": -1}]}`,
+	} {
+		doc, err := ReadDocument(strings.NewReader(bad))
+		if err != nil {
+			t.Fatalf("ReadDocument(%s): %v", bad, err)
+		}
+		if _, _, _, err := doc.Content(); err == nil {
+			t.Errorf("Content of %s should fail", bad)
+		}
+	}
+	if _, err := ReadDocument(strings.NewReader(`{"txet": "typo"}`)); err == nil {
+		t.Error("unknown fields should be rejected")
+	}
+}
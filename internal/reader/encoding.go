@@ -0,0 +1,109 @@
+package reader
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// sniffSize is how much of a stream DecodeReader looks at to choose its
+// encoding.
+const sniffSize = 4096
+
+// DecodeText returns text read from a file or stdin as UTF-8. UTF-8, with
+// or without a byte order mark, is returned as is; UTF-16 is recognised by
+// its byte order mark or by the zero bytes of ASCII text; anything else is
+// taken as Windows-1252, which agrees with Latin-1 on its printable
+// characters.
+func DecodeText(data []byte) string {
+	enc := detectEncoding(data, true)
+	if enc == nil {
+		return string(bytes.TrimPrefix(data, utf8BOM))
+	}
+	out, err := enc.NewDecoder().Bytes(data)
+	if err != nil {
+		return string(data)
+	}
+	return string(out)
+}
+
+// DecodeReader is DecodeText for a stream, choosing the encoding from the
+// start of r.
+func DecodeReader(r io.Reader) io.Reader {
+	br := bufio.NewReaderSize(r, sniffSize)
+	sample, _ := br.Peek(sniffSize)
+	enc := detectEncoding(sample, len(sample) < sniffSize)
+	if enc == nil {
+		if bytes.HasPrefix(sample, utf8BOM) {
+			br.Discard(len(utf8BOM))
+		}
+		return br
+	}
+	return transform.NewReader(br, enc.NewDecoder())
+}
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// detectEncoding returns the encoding of text starting with sample, or nil
+// for UTF-8. Unless complete, sample may end part way through a character.
+func detectEncoding(sample []byte, complete bool) encoding.Encoding {
+	switch {
+	case bytes.HasPrefix(sample, utf8BOM):
+		return nil
+	case bytes.HasPrefix(sample, []byte{0xFF, 0xFE}):
+		return unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM)
+	case bytes.HasPrefix(sample, []byte{0xFE, 0xFF}):
+		return unicode.UTF16(unicode.BigEndian, unicode.ExpectBOM)
+	}
+	if order, ok := utf16Order(sample); ok {
+		return unicode.UTF16(order, unicode.IgnoreBOM)
+	}
+	if !complete {
+		// Leave out a character cut off at the end of the sample.
+		for i := 1; i <= utf8.UTFMax && i <= len(sample); i++ {
+			if utf8.RuneStart(sample[len(sample)-i]) {
+				if !utf8.FullRune(sample[len(sample)-i:]) {
+					sample = sample[:len(sample)-i]
+				}
+				break
+			}
+		}
+	}
+	if utf8.Valid(sample) {
+		return nil
+	}
+	return charmap.Windows1252
+}
+
+// utf16Order recognises UTF-16 without a byte order mark by its zero
+// bytes: mostly-ASCII text has a zero in every other byte, the high byte
+// of each character.
+func utf16Order(sample []byte) (unicode.Endianness, bool) {
+	n := len(sample) &^ 1
+	if n < 4 {
+		return unicode.BigEndian, false
+	}
+	var even, odd int
+	for i := 0; i < n; i += 2 {
+		if sample[i] == 0 {
+			even++
+		}
+		if sample[i+1] == 0 {
+			odd++
+		}
+	}
+	pairs := n / 2
+	switch {
+	case odd*2 > pairs && even*10 < pairs:
+		return unicode.LittleEndian, true
+	case even*2 > pairs && odd*10 < pairs:
+		return unicode.BigEndian, true
+	}
+	return unicode.BigEndian, false
+}
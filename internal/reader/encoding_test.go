@@ -0,0 +1,81 @@
+package reader
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// utf16 encodes ASCII s as UTF-16, little or big endian.
+func utf16(s string, little bool) []byte {
+	var out []byte
+	for _, c := range []byte(s) {
+		if little {
+			out = append(out, c, 0)
+		} else {
+			out = append(out, 0, c)
+		}
+	}
+	return out
+}
+
+func TestDecodeText(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"utf-8", []byte("naïve café"), "naïve café"},
+		{"utf-8 bom", []byte("\xEF\xBB\xBFcafé"), "café"},
+		{"utf-16le bom", append([]byte{0xFF, 0xFE}, utf16("Hello there", true)...), "Hello there"},
+		{"utf-16be bom", append([]byte{0xFE, 0xFF}, utf16("Hello there", false)...), "Hello there"},
+		{"utf-16le", utf16("Hello there", true), "Hello there"},
+		{"utf-16be", utf16("Hello there", false), "Hello there"},
+		{"latin-1", []byte("caf\xE9 cr\xE8me br\xFBl\xE9e"), "café crème brûlée"},
+		{"windows-1252", []byte("\x93Quoted\x94 \x96 it\x92s"), "“Quoted” – it’s"},
+		{"empty", nil, ""},
+	}
+	for _, tt := range tests {
+		if got := DecodeText(tt.data); got != tt.want {
+			t.Errorf("%s: DecodeText = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDecodeReader(t *testing.T) {
+	// A multi-byte character straddles the end of the sniffed sample.
+	utf8Text := strings.Repeat("a", sniffSize-1) + "é and more"
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"utf-8 across the sample", []byte(utf8Text), utf8Text},
+		{"utf-8 bom", []byte("\xEF\xBB\xBFcafé"), "café"},
+		{"utf-16le bom", append([]byte{0xFF, 0xFE}, utf16("Hello there", true)...), "Hello there"},
+		{"latin-1", []byte("caf\xE9"), "café"},
+	}
+	for _, tt := range tests {
+		got, err := io.ReadAll(DecodeReader(strings.NewReader(string(tt.data))))
+		if err != nil || string(got) != tt.want {
+			t.Errorf("%s: read %q, %v; want %q", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestExtractTextConvertsEncoding(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "latin1.txt")
+	if err := os.WriteFile(path, []byte("Voil\xE0 un r\xE9sum\xE9."), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ExtractText(path); err != nil || got != "Voilà un résumé." {
+		t.Errorf("ExtractText = %q, %v", got, err)
+	}
+
+	r := NewStreamReader(strings.NewReader(string(append([]byte{0xFF, 0xFE}, utf16("streamed words", true)...))), 0, 300)
+	if got := strings.Join(r.Words, " "); got != "streamed words" {
+		t.Errorf("streamed UTF-16 words = %q", got)
+	}
+}
@@ -1,6 +1,9 @@
 package reader
 
 import (
+	"archive/zip"
+	"context"
+	"encoding/xml"
 	"fmt"
 	"io"
 	"strings"
@@ -10,7 +13,32 @@ import (
 )
 
 // EPUBFormat implements Format for EPUB files.
-type EPUBFormat struct{}
+type EPUBFormat struct {
+	// Figures adds a "[Figure: alt text]" token for each described image.
+	Figures bool
+	// Strict fails extraction on the first unreadable section instead of
+	// skipping it.
+	Strict bool
+	// Warnings, when set, collects the sections that were skipped.
+	Warnings *Warnings
+	// KeepEmpty keeps sections with no text, such as image-only pages, as
+	// zero-length chapters and TOC entries. By default they are left out
+	// so every entry leads to something to read.
+	KeepEmpty bool
+	// Notes sets what becomes of footnotes and endnotes; by default they
+	// are left out.
+	Notes NoteMode
+	// SkipMatter leaves out the cover, title page, copyright page,
+	// dedication, table of contents and index, so a book starts at its
+	// first chapter.
+	SkipMatter bool
+	// Workers is how many spine items are read and parsed at once; 0
+	// means one per CPU.
+	Workers int
+	// Ruby sets what becomes of ruby annotations such as furigana; by
+	// default the readings are left out.
+	Ruby RubyMode
+}
 
 func init() {
 	Register(&EPUBFormat{})
@@ -19,11 +47,76 @@ func init() {
 func (f *EPUBFormat) Name() string       { return "EPUB" }
 func (f *EPUBFormat) Extensions() []string { return []string{".epub"} }
 func (f *EPUBFormat) Extract(filename string) (string, error) {
-	return ExtractTextFromEPUB(filename)
+	return f.extractText(context.Background(), filename)
+}
+
+// ExtractContext is Extract stopping between chapters when ctx is cancelled.
+func (f *EPUBFormat) ExtractContext(ctx context.Context, filename string) (string, error) {
+	return f.extractText(ctx, filename)
+}
+
+// epubPackage is the part of an EPUB's package document read for its
+// metadata.
+type epubPackage struct {
+	Titles    []string `xml:"metadata>title"`
+	Creators  []string `xml:"metadata>creator"`
+	Languages []string `xml:"metadata>language"`
+	Metas     []struct {
+		Name    string `xml:"name,attr"`
+		Content string `xml:"content,attr"`
+	} `xml:"metadata>meta"`
+	Spine struct {
+		Direction string `xml:"page-progression-direction,attr"`
+	} `xml:"spine"`
+}
+
+// Metadata returns the book's dc:title and dc:creator, and whether it is
+// typeset vertically. Several creators are listed together, as in "A, B
+// and C".
+func (f *EPUBFormat) Metadata(filename string) (Metadata, error) {
+	rc, err := epub.OpenReader(filename)
+	if err != nil {
+		return Metadata{}, fmt.Errorf("failed to open epub: %w", err)
+	}
+	defer rc.Close()
+
+	if len(rc.Rootfiles) == 0 {
+		return Metadata{}, fmt.Errorf("no rootfiles found in epub")
+	}
+	zr, err := zip.OpenReader(filename)
+	if err != nil {
+		return Metadata{}, err
+	}
+	defer zr.Close()
+	opfData, err := readZipFile(&zr.Reader, rc.Rootfiles[0].FullPath)
+	if err != nil {
+		return Metadata{}, err
+	}
+	var opf epubPackage
+	if err := xml.Unmarshal(opfData, &opf); err != nil {
+		return Metadata{}, fmt.Errorf("failed to parse OPF: %w", err)
+	}
+
+	meta := Metadata{Vertical: isVerticalWriting(opf)}
+	if len(opf.Titles) > 0 {
+		meta.Title = cleanMetadata(opf.Titles[0])
+	}
+	var authors []string
+	for _, c := range opf.Creators {
+		if c = cleanMetadata(c); c != "" {
+			authors = append(authors, c)
+		}
+	}
+	meta.Author = joinAuthors(authors)
+	return meta, nil
 }
 
 // ExtractTextFromEPUB extracts all text content from an EPUB file.
 func ExtractTextFromEPUB(filename string) (string, error) {
+	return (&EPUBFormat{}).extractText(context.Background(), filename)
+}
+
+func (f *EPUBFormat) extractText(ctx context.Context, filename string) (string, error) {
 	rc, err := epub.OpenReader(filename)
 	if err != nil {
 		return "", fmt.Errorf("failed to open epub: %w", err)
@@ -35,47 +128,118 @@ func ExtractTextFromEPUB(filename string) (string, error) {
 	}
 
 	book := rc.Rootfiles[0]
-	var out strings.Builder
+	sections, err := f.readSpineSections(ctx, book, f.matter(filename, book))
+	if err != nil {
+		return "", err
+	}
+	var out, notes strings.Builder
 
-	for _, ref := range book.Spine.Itemrefs {
-		if ref.Item == nil {
-			continue
-		}
-		r, err := ref.Item.Open()
-		if err != nil {
+	for _, s := range sections {
+		if s.matter {
 			continue
 		}
-		data, err := io.ReadAll(r)
-		r.Close()
-		if err != nil {
+		if s.err != nil {
+			if err := f.skip(s.item.HREF, s.err); err != nil {
+				return "", err
+			}
 			continue
 		}
-		out.WriteString(extractTextFromHTML(string(data)))
+		out.WriteString(s.text)
 		out.WriteString(" ")
+		notes.WriteString(s.notes)
 	}
+	out.WriteString(notes.String())
 
 	return out.String(), nil
 }
 
+// readEPUBItem returns the contents of a manifest item.
+func readEPUBItem(item *epub.Item) ([]byte, error) {
+	r, err := item.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// skip handles a section that could not be read: in strict mode it becomes
+// the extraction error, otherwise it is recorded as a warning and skipped.
+func (f *EPUBFormat) skip(section string, err error) error {
+	serr := &SectionError{Section: section, Err: err}
+	if f.Strict {
+		return serr
+	}
+	if f.Warnings != nil {
+		f.Warnings.Add(serr)
+	}
+	return nil
+}
+
 func extractTextFromHTML(s string) string {
+	return extractHTMLText(s, false)
+}
+
+// extractHTMLText returns the text of an HTML document, notes included.
+// With figures set, images with alt text become "[Figure: alt text]" so
+// figure captions keep their context.
+func extractHTMLText(s string, figures bool) string {
+	text, _ := extractSection(s, figures, NotesInline, RubySkip)
+	return text
+}
+
+// extractSection returns the text of an EPUB section. Unless notes is
+// NotesInline, footnotes and endnotes are left out along with the markers
+// referring to them; with NotesAppendix their text is returned separately.
+// Ruby annotations are read as ruby says.
+func extractSection(s string, figures bool, notes NoteMode, ruby RubyMode) (text, noteText string) {
 	doc, err := html.Parse(strings.NewReader(s))
 	if err != nil {
-		return ""
+		return "", ""
 	}
 
-	var out strings.Builder
-	var walk func(*html.Node)
-	walk = func(n *html.Node) {
+	var out, notesOut sectionWriter
+	var walk func(*html.Node, *sectionWriter)
+	walk = func(n *html.Node, out *sectionWriter) {
 		if n.Type == html.TextNode {
-			if t := strings.TrimSpace(n.Data); t != "" {
-				out.WriteString(t)
-				out.WriteString(" ")
+			out.write(strings.TrimSpace(n.Data))
+		}
+		if n.Type == html.ElementNode && notes != NotesInline {
+			if isNoteLink(n) {
+				return
+			}
+			if isNote(n) && out != &notesOut {
+				if notes == NotesAppendix {
+					for c := n.FirstChild; c != nil; c = c.NextSibling {
+						walk(c, &notesOut)
+					}
+				}
+				return
+			}
+		}
+		if n.Type == html.ElementNode {
+			out.element(n)
+			switch n.Data {
+			case "ruby":
+				out.write(rubyText(n, ruby))
+				return
+			case "rt", "rp":
+				// Stray readings and their brackets go with ruby.
+				return
+			}
+		}
+		if figures && n.Type == html.ElementNode && n.Data == "img" {
+			if alt := strings.Join(strings.Fields(htmlAttr(n, "alt")), " "); alt != "" {
+				out.write("[Figure: " + alt + "]")
 			}
 		}
 		for c := n.FirstChild; c != nil; c = c.NextSibling {
-			walk(c)
+			walk(c, out)
+		}
+		if n.Type == html.ElementNode {
+			out.element(n)
 		}
 	}
-	walk(doc)
-	return out.String()
+	walk(doc, &out)
+	return out.String(), notesOut.String()
 }
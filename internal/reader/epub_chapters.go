@@ -0,0 +1,119 @@
+package reader
+
+import (
+	"archive/zip"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/taylorskalyo/goreader/epub"
+)
+
+// EPUBChapters reads the sections of an EPUB as chapters on demand. It
+// implements ChapterProvider; see EPUBFormat.OpenChapters.
+type EPUBChapters struct {
+	f      *EPUBFormat
+	rc     *epub.ReadCloser
+	items  []*epub.Item
+	titles []string
+	sizes  []int64
+	// notes holds the notes of each section parsed so far for the final
+	// chapter, when Notes is NotesAppendix.
+	notes []string
+}
+
+// OpenChapters opens an EPUB to be read a section at a time, parsing each
+// only when it is asked for, so a long book opens at once. The sections
+// are those ExtractChapters would read, and the chapters are named as it
+// names them; notes gathered by NotesAppendix follow as a last chapter.
+// Close the result when done with it.
+func (f *EPUBFormat) OpenChapters(filename string) (*EPUBChapters, error) {
+	rc, err := epub.OpenReader(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open epub: %w", err)
+	}
+	if len(rc.Rootfiles) == 0 {
+		rc.Close()
+		return nil, fmt.Errorf("no rootfiles found in epub")
+	}
+	book := rc.Rootfiles[0]
+
+	sizes := make(map[string]int64)
+	if zr, err := zip.OpenReader(filename); err == nil {
+		for _, zf := range zr.File {
+			sizes[zf.Name] = int64(zf.UncompressedSize64)
+		}
+		zr.Close()
+	}
+
+	c := &EPUBChapters{f: f, rc: rc}
+	tocByHref := buildTOCHrefMap(filename, book)
+	matter := f.matter(filename, book)
+	for i, ref := range book.Spine.Itemrefs {
+		if ref.Item == nil || isMatter(matter, ref.Item.HREF) {
+			continue
+		}
+		title, _ := sectionTitle(tocByHref, ref.Item.HREF, i)
+		c.items = append(c.items, ref.Item)
+		c.titles = append(c.titles, title)
+		c.sizes = append(c.sizes, sizes[path.Join(path.Dir(book.FullPath), ref.Item.HREF)])
+	}
+	c.notes = make([]string, len(c.items))
+	return c, nil
+}
+
+// OpenChapterProvider is OpenChapters, making EPUBFormat a ChapterOpener.
+func (f *EPUBFormat) OpenChapterProvider(filename string) (ChapterProvider, error) {
+	c, err := f.OpenChapters(filename)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Len returns the number of sections, and one more for the notes when
+// they are gathered into a chapter.
+func (c *EPUBChapters) Len() int {
+	if c.f.Notes == NotesAppendix {
+		return len(c.items) + 1
+	}
+	return len(c.items)
+}
+
+// Title returns the title of section i, making EPUBChapters a
+// ChapterTitler.
+func (c *EPUBChapters) Title(i int) string {
+	if i == len(c.items) {
+		return appendixTitle
+	}
+	return c.titles[i]
+}
+
+// Chapter parses section i. Read in order, the last chapter of notes
+// holds those of every section.
+func (c *EPUBChapters) Chapter(i int) (string, []string, error) {
+	if i == len(c.items) {
+		return appendixTitle, ParseText(strings.Join(c.notes, "")), nil
+	}
+	data, err := readEPUBItem(c.items[i])
+	if err != nil {
+		return c.titles[i], nil, c.f.skip(c.items[i].HREF, err)
+	}
+	text, noteText := extractSection(string(data), c.f.Figures, c.f.Notes, c.f.Ruby)
+	c.notes[i] = noteText
+	return c.titles[i], ParseText(text), nil
+}
+
+// Size returns the size of section i, uncompressed.
+func (c *EPUBChapters) Size(i int) int64 {
+	if i == len(c.items) {
+		return 0
+	}
+	return c.sizes[i]
+}
+
+// Close closes the EPUB.
+func (c *EPUBChapters) Close() error {
+	c.rc.Close()
+	return nil
+}
@@ -0,0 +1,123 @@
+package reader
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/taylorskalyo/goreader/epub"
+	"golang.org/x/net/html"
+)
+
+// matterTypes are the guide reference and landmark types of the front and
+// back matter left out with SkipMatter.
+var matterTypes = []string{"cover", "title-page", "titlepage", "copyright-page", "dedication", "toc", "index"}
+
+// matterTitle matches table of contents titles of front and back matter,
+// which many books leave out of their guide and landmarks.
+var matterTitle = regexp.MustCompile(`(?i)^(cover( page)?|title( page)?|copyright( page)?|dedication|(table of )?contents|index)$`)
+
+// opfGuide reads the EPUB2 guide, which points at a book's landmarks.
+type opfGuide struct {
+	References []struct {
+		Type string `xml:"type,attr"`
+		HREF string `xml:"href,attr"`
+	} `xml:"guide>reference"`
+}
+
+// matterHrefs returns the hrefs, relative to the OPF, of the spine items
+// that are front or back matter: cover, title page, copyright page,
+// dedication, table of contents and index. They are found in the EPUB2
+// guide, the EPUB3 landmarks and by their TOC titles.
+func matterHrefs(filename string, book *epub.Rootfile) map[string]bool {
+	matter := make(map[string]bool)
+	add := func(href string) {
+		if i := strings.Index(href, "#"); i >= 0 {
+			href = href[:i]
+		}
+		if href != "" {
+			matter[href] = true
+		}
+	}
+
+	if zr, err := zip.OpenReader(filename); err == nil {
+		if opfData, err := readZipFile(&zr.Reader, book.FullPath); err == nil {
+			var guide opfGuide
+			if xml.Unmarshal(opfData, &guide) == nil {
+				for _, ref := range guide.References {
+					if isMatterType(ref.Type) {
+						add(ref.HREF)
+					}
+				}
+			}
+		}
+		if data, dir, err := readNavFile(&zr.Reader, book); err == nil {
+			for _, href := range landmarkHrefs(data) {
+				add(path.Clean(path.Join(dir, href)))
+			}
+		}
+		zr.Close()
+	}
+	for href, title := range buildTOCHrefMap(filename, book) {
+		if !strings.Contains(href, "#") && matterTitle.MatchString(title) {
+			add(href)
+		}
+	}
+	return matter
+}
+
+// isMatterType reports whether any of the space-separated types names
+// front or back matter.
+func isMatterType(types string) bool {
+	for _, t := range strings.Fields(types) {
+		for _, m := range matterTypes {
+			if t == m {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// landmarkHrefs returns the hrefs of the front and back matter listed in a
+// nav document's landmarks.
+func landmarkHrefs(data []byte) []string {
+	doc, err := html.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil
+	}
+	var hrefs []string
+	var walk func(n *html.Node, landmarks bool)
+	walk = func(n *html.Node, landmarks bool) {
+		if n.Type == html.ElementNode {
+			switch {
+			case n.Data == "nav" && hasEPUBType(n, "landmarks"):
+				landmarks = true
+			case n.Data == "a" && landmarks && isMatterType(htmlAttr(n, "epub:type")):
+				hrefs = append(hrefs, htmlAttr(n, "href"))
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c, landmarks)
+		}
+	}
+	walk(doc, false)
+	return hrefs
+}
+
+// matter returns the hrefs of the spine items to leave out, which are
+// none unless SkipMatter is set.
+func (f *EPUBFormat) matter(filename string, book *epub.Rootfile) map[string]bool {
+	if !f.SkipMatter {
+		return nil
+	}
+	return matterHrefs(filename, book)
+}
+
+// isMatter reports whether the spine item at href is among matter.
+func isMatter(matter map[string]bool, href string) bool {
+	return matter[href] || matter[path.Base(href)]
+}
@@ -0,0 +1,178 @@
+package reader
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/taylorskalyo/goreader/epub"
+	"golang.org/x/net/html"
+)
+
+// opfManifest reads the manifest item properties that goreader ignores.
+type opfManifest struct {
+	Items []struct {
+		HREF       string `xml:"href,attr"`
+		Properties string `xml:"properties,attr"`
+	} `xml:"manifest>item"`
+}
+
+// readNavDocument parses the EPUB3 navigation document (the manifest item
+// with properties="nav") into nav points with hrefs relative to the OPF,
+// matching the spine's item hrefs.
+func readNavDocument(filename string, book *epub.Rootfile) ([]navPoint, error) {
+	zr, err := zip.OpenReader(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	data, dir, err := readNavFile(&zr.Reader, book)
+	if err != nil {
+		return nil, err
+	}
+	points := parseNavDocument(data, dir)
+	if len(points) == 0 {
+		return nil, fmt.Errorf("navigation document has no table of contents")
+	}
+	return points, nil
+}
+
+// readNavFile returns the contents of the navigation document and its
+// directory relative to the OPF.
+func readNavFile(zr *zip.Reader, book *epub.Rootfile) ([]byte, string, error) {
+	opfData, err := readZipFile(zr, book.FullPath)
+	if err != nil {
+		return nil, "", err
+	}
+	var manifest opfManifest
+	if err := xml.Unmarshal(opfData, &manifest); err != nil {
+		return nil, "", fmt.Errorf("failed to parse OPF: %w", err)
+	}
+
+	var navHref string
+	for _, item := range manifest.Items {
+		for _, p := range strings.Fields(item.Properties) {
+			if p == "nav" {
+				navHref = item.HREF
+			}
+		}
+	}
+	if navHref == "" {
+		return nil, "", fmt.Errorf("no navigation document found in EPUB")
+	}
+
+	data, err := readZipFile(zr, path.Join(path.Dir(book.FullPath), navHref))
+	if err != nil {
+		return nil, "", err
+	}
+	return data, path.Dir(navHref), nil
+}
+
+// readZipFile returns the contents of the named file in an archive.
+func readZipFile(zr *zip.Reader, name string) ([]byte, error) {
+	for _, f := range zr.File {
+		if f.Name == name {
+			rc, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+			defer rc.Close()
+			return io.ReadAll(rc)
+		}
+	}
+	return nil, fmt.Errorf("%s not found in archive", name)
+}
+
+// parseNavDocument converts the nested ol/li list of a nav document's toc
+// into nav points. hrefs are resolved against dir, the nav document's
+// directory relative to the OPF.
+func parseNavDocument(data []byte, dir string) []navPoint {
+	doc, err := html.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil
+	}
+
+	var navs []*html.Node
+	var find func(*html.Node)
+	find = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "nav" {
+			navs = append(navs, n)
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			find(c)
+		}
+	}
+	find(doc)
+	if len(navs) == 0 {
+		return nil
+	}
+
+	// Prefer the toc nav over landmarks and page-list navs.
+	toc := navs[0]
+	for _, n := range navs {
+		if strings.Contains(htmlAttr(n, "epub:type"), "toc") || htmlAttr(n, "role") == "doc-toc" {
+			toc = n
+			break
+		}
+	}
+	if list := firstChildElement(toc, "ol"); list != nil {
+		return navListPoints(list, dir)
+	}
+	return nil
+}
+
+// navListPoints converts the li items of an ol into nav points.
+func navListPoints(list *html.Node, dir string) []navPoint {
+	var points []navPoint
+	for li := list.FirstChild; li != nil; li = li.NextSibling {
+		if li.Type != html.ElementNode || li.Data != "li" {
+			continue
+		}
+		var np navPoint
+		if a := firstChildElement(li, "a"); a != nil {
+			np.Label.Text = nodeText(a)
+			if href := htmlAttr(a, "href"); href != "" {
+				np.Content.Src = path.Clean(path.Join(dir, href))
+			}
+		} else if span := firstChildElement(li, "span"); span != nil {
+			np.Label.Text = nodeText(span)
+		}
+		if sub := firstChildElement(li, "ol"); sub != nil {
+			np.Children = navListPoints(sub, dir)
+		}
+		if np.Label.Text != "" || len(np.Children) > 0 {
+			points = append(points, np)
+		}
+	}
+	return points
+}
+
+// firstChildElement returns n's first direct child element named tag.
+func firstChildElement(n *html.Node, tag string) *html.Node {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && c.Data == tag {
+			return c
+		}
+	}
+	return nil
+}
+
+// htmlAttr returns the value of n's attribute key, or "".
+func htmlAttr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		name := a.Key
+		if a.Namespace != "" {
+			name = a.Namespace + ":" + a.Key
+		}
+		if name == key {
+			return a.Val
+		}
+	}
+	return ""
+}
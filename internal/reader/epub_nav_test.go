@@ -0,0 +1,191 @@
+package reader
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeTestEPUB builds a minimal EPUB from the given archive files, adding
+// the mimetype and container entries pointing at OEBPS/content.opf.
+func writeTestEPUB(t *testing.T, files map[string]string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "book.epub")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	add := func(name, content string) {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("zip Create: %v", err)
+		}
+		w.Write([]byte(content))
+	}
+	add("mimetype", "application/epub+zip")
+	add("META-INF/container.xml", `<?xml version="1.0"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles><rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/></rootfiles>
+</container>`)
+	for name, content := range files {
+		add(name, content)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("zip Close: %v", err)
+	}
+	return path
+}
+
+const navOPF = `<?xml version="1.0"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/"><dc:title>Nav Book</dc:title></metadata>
+  <manifest>
+    <item id="nav" href="nav/toc.xhtml" media-type="application/xhtml+xml" properties="nav"/>
+    <item id="c1" href="text/ch1.xhtml" media-type="application/xhtml+xml"/>
+    <item id="c2" href="text/ch2.xhtml" media-type="application/xhtml+xml"/>
+  </manifest>
+  <spine><itemref idref="c1"/><itemref idref="c2"/></spine>
+</package>`
+
+const navDocument = `<?xml version="1.0"?>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops"><body>
+  <nav epub:type="landmarks"><ol><li><a href="../text/ch2.xhtml">Skip me</a></li></ol></nav>
+  <nav epub:type="toc"><h1>Contents</h1><ol>
+    <li><a href="../text/ch1.xhtml">Chapter <em>One</em></a>
+      <ol><li><a href="../text/ch1.xhtml#s1">Part A</a></li></ol></li>
+    <li><span>Part Two</span><ol><li><a href="../text/ch2.xhtml">Chapter Two</a></li></ol></li>
+  </ol></nav>
+</body></html>`
+
+func TestEPUBNavTOC(t *testing.T) {
+	path := writeTestEPUB(t, map[string]string{
+		"OEBPS/content.opf":    navOPF,
+		"OEBPS/nav/toc.xhtml":  navDocument,
+		"OEBPS/text/ch1.xhtml": `<html><body><p>one two three</p></body></html>`,
+		"OEBPS/text/ch2.xhtml": `<html><body><p>four five</p></body></html>`,
+	})
+
+	f := &EPUBFormat{}
+	toc, err := f.TOC(path)
+	if err != nil {
+		t.Fatalf("TOC: %v", err)
+	}
+	want := []TOCEntry{
+		{Title: "Chapter One", WordIndex: 0, Level: 0},
+		{Title: "Part A", WordIndex: 0, Level: 1},
+		{Title: "Part Two", WordIndex: 3, Level: 0},
+		{Title: "Chapter Two", WordIndex: 3, Level: 1},
+	}
+	if len(toc) != len(want) {
+		t.Fatalf("got %+v, want %+v", toc, want)
+	}
+	for i := range want {
+		got := toc[i]
+		if got.Title != want[i].Title || got.WordIndex != want[i].WordIndex || got.Level != want[i].Level {
+			t.Errorf("entry %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+
+	chapters, _, err := f.ExtractChapters(path)
+	if err != nil {
+		t.Fatalf("ExtractChapters: %v", err)
+	}
+	if len(chapters) != 2 || chapters[0].Title != "Chapter One" || chapters[1].Title != "Chapter Two" {
+		t.Errorf("chapters = %+v", chapters)
+	}
+
+	// One pass over the spine gives the same TOC and chapters.
+	bothTOC, bothChapters, words, err := f.TOCAndChaptersContext(context.Background(), path)
+	if err != nil {
+		t.Fatalf("TOCAndChaptersContext: %v", err)
+	}
+	if !reflect.DeepEqual(bothTOC, toc) || !reflect.DeepEqual(bothChapters, chapters) || len(words) != 5 {
+		t.Errorf("TOCAndChaptersContext = %+v, %+v, %q", bothTOC, bothChapters, words)
+	}
+}
+
+func TestEPUBEmptySections(t *testing.T) {
+	path := writeTestEPUB(t, map[string]string{
+		"OEBPS/content.opf": `<?xml version="1.0"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0">
+  <manifest>
+    <item id="nav" href="nav/toc.xhtml" media-type="application/xhtml+xml" properties="nav"/>
+    <item id="cover" href="text/cover.xhtml" media-type="application/xhtml+xml"/>
+    <item id="c1" href="text/ch1.xhtml" media-type="application/xhtml+xml"/>
+    <item id="plate" href="text/plate.xhtml" media-type="application/xhtml+xml"/>
+    <item id="blank" href="text/blank.xhtml" media-type="application/xhtml+xml"/>
+    <item id="c2" href="text/ch2.xhtml" media-type="application/xhtml+xml"/>
+  </manifest>
+  <spine><itemref idref="cover"/><itemref idref="c1"/><itemref idref="plate"/><itemref idref="blank"/><itemref idref="c2"/></spine>
+</package>`,
+		"OEBPS/nav/toc.xhtml": `<?xml version="1.0"?>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops"><body>
+  <nav epub:type="toc"><ol>
+    <li><a href="../text/cover.xhtml">Cover</a></li>
+    <li><a href="../text/ch1.xhtml">One</a></li>
+    <li><a href="../text/plate.xhtml">Plate</a></li>
+    <li><a href="../text/ch2.xhtml">Two</a></li>
+  </ol></nav>
+</body></html>`,
+		"OEBPS/text/cover.xhtml": `<html><body><img src="cover.jpg"/></body></html>`,
+		"OEBPS/text/ch1.xhtml":   `<html><body><p>one two three</p></body></html>`,
+		"OEBPS/text/plate.xhtml": `<html><body><img src="plate.jpg"/></body></html>`,
+		"OEBPS/text/blank.xhtml": `<html><body> </body></html>`,
+		"OEBPS/text/ch2.xhtml":   `<html><body><p>four five</p></body></html>`,
+	})
+
+	tests := []struct {
+		keepEmpty bool
+		toc       []string
+		chapters  []Chapter
+	}{
+		{false,
+			[]string{"One@0", "Two@3"},
+			[]Chapter{{Title: "One", WordStart: 0, WordEnd: 2}, {Title: "Two", WordStart: 3, WordEnd: 4}}},
+		{true,
+			[]string{"Cover@0", "One@0", "Plate@3", "Two@3"},
+			[]Chapter{
+				{Title: "Cover", WordStart: 0, WordEnd: -1},
+				{Title: "One", WordStart: 0, WordEnd: 2},
+				{Title: "Plate", WordStart: 3, WordEnd: 2},
+				{Title: "Two", WordStart: 3, WordEnd: 4},
+			}},
+	}
+	for _, tt := range tests {
+		f := &EPUBFormat{KeepEmpty: tt.keepEmpty}
+		toc, err := f.TOC(path)
+		if err != nil {
+			t.Fatalf("TOC: %v", err)
+		}
+		var got []string
+		for _, e := range toc {
+			got = append(got, fmt.Sprintf("%s@%d", e.Title, e.WordIndex))
+		}
+		if !reflect.DeepEqual(got, tt.toc) {
+			t.Errorf("KeepEmpty=%v: TOC = %v, want %v", tt.keepEmpty, got, tt.toc)
+		}
+
+		chapters, words, err := f.ExtractChapters(path)
+		if err != nil {
+			t.Fatalf("ExtractChapters: %v", err)
+		}
+		if len(words) != 5 || !reflect.DeepEqual(chapters, tt.chapters) {
+			t.Errorf("KeepEmpty=%v: chapters = %+v (%d words), want %+v", tt.keepEmpty, chapters, len(words), tt.chapters)
+		}
+	}
+
+	// Zero-length chapters never become current.
+	r := NewReader("one two three four five", 300)
+	r.SetChapters(tests[1].chapters, nil)
+	r.JumpToChapter(3)
+	if r.CurrentChapterTitle() != "Two" {
+		t.Errorf("chapter at word 3 = %q, want Two", r.CurrentChapterTitle())
+	}
+}
@@ -0,0 +1,79 @@
+package reader
+
+import (
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// NoteMode controls what becomes of an EPUB's footnotes and endnotes,
+// which would otherwise be read in the middle of the text around them.
+type NoteMode int
+
+const (
+	// NotesSkip leaves out notes and the markers referring to them.
+	NotesSkip NoteMode = iota
+	// NotesAppendix leaves out the markers and gathers the notes into a
+	// final chapter.
+	NotesAppendix
+	// NotesInline reads notes and markers where they appear in the book.
+	NotesInline
+)
+
+var noteModeNames = []string{"skip", "appendix", "inline"}
+
+func (m NoteMode) String() string {
+	if m < 0 || int(m) >= len(noteModeNames) {
+		return "skip"
+	}
+	return noteModeNames[m]
+}
+
+// NoteModeNames lists the modes accepted by ParseNoteMode.
+func NoteModeNames() []string {
+	return noteModeNames
+}
+
+// ParseNoteMode parses "skip", "appendix" or "inline".
+func ParseNoteMode(s string) (NoteMode, bool) {
+	for i, name := range noteModeNames {
+		if strings.EqualFold(s, name) {
+			return NoteMode(i), true
+		}
+	}
+	return NotesSkip, false
+}
+
+// appendixTitle is the title of the chapter NotesAppendix gathers notes
+// into.
+const appendixTitle = "Notes"
+
+// isNoteLink reports whether n is a marker referring to a note, or a link
+// from a note back to its marker.
+func isNoteLink(n *html.Node) bool {
+	switch htmlAttr(n, "role") {
+	case "doc-noteref", "doc-backlink":
+		return true
+	}
+	return hasEPUBType(n, "noteref", "backlink")
+}
+
+// isNote reports whether n holds a note, or a list of them.
+func isNote(n *html.Node) bool {
+	switch htmlAttr(n, "role") {
+	case "doc-footnote", "doc-endnote", "doc-endnotes":
+		return true
+	}
+	return hasEPUBType(n, "footnote", "endnote", "rearnote", "note", "footnotes", "endnotes", "rearnotes")
+}
+
+// hasEPUBType reports whether n's epub:type names any of types.
+func hasEPUBType(n *html.Node, types ...string) bool {
+	for _, t := range strings.Fields(htmlAttr(n, "epub:type")) {
+		if slices.Contains(types, t) {
+			return true
+		}
+	}
+	return false
+}
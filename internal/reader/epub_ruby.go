@@ -0,0 +1,156 @@
+package reader
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+// RubyMode controls what becomes of ruby annotations, such as the
+// furigana printed beside the kanji of Japanese books to give their
+// readings.
+type RubyMode int
+
+const (
+	// RubySkip reads the annotated text alone, leaving out the readings.
+	RubySkip RubyMode = iota
+	// RubyInline reads each reading after its text, in brackets, as in
+	// 漢字（かんじ）.
+	RubyInline
+)
+
+var rubyModeNames = []string{"skip", "inline"}
+
+func (m RubyMode) String() string {
+	if m < 0 || int(m) >= len(rubyModeNames) {
+		return "skip"
+	}
+	return rubyModeNames[m]
+}
+
+// RubyModeNames lists the modes accepted by ParseRubyMode.
+func RubyModeNames() []string {
+	return rubyModeNames
+}
+
+// ParseRubyMode parses "skip" or "inline".
+func ParseRubyMode(s string) (RubyMode, bool) {
+	for i, name := range rubyModeNames {
+		if strings.EqualFold(s, name) {
+			return RubyMode(i), true
+		}
+	}
+	return RubySkip, false
+}
+
+// rubyText returns the text of a ruby element: the text it annotates,
+// followed with RubyInline by its readings in brackets. Both are joined
+// up without spaces, however the markup splits them.
+func rubyText(n *html.Node, mode RubyMode) string {
+	var base, reading strings.Builder
+	var walk func(*html.Node, *strings.Builder)
+	walk = func(n *html.Node, out *strings.Builder) {
+		switch {
+		case n.Type == html.TextNode:
+			out.WriteString(strings.TrimSpace(n.Data))
+			return
+		case n.Type == html.ElementNode && n.Data == "rp":
+			// Brackets for browsers without ruby support.
+			return
+		case n.Type == html.ElementNode && (n.Data == "rt" || n.Data == "rtc"):
+			out = &reading
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c, out)
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walk(c, &base)
+	}
+	if mode == RubyInline && reading.Len() > 0 {
+		return base.String() + "（" + reading.String() + "）"
+	}
+	return base.String()
+}
+
+// inlineElements are the elements that can split a run of text without
+// ending it, such as emphasis.
+var inlineElements = map[string]bool{
+	"a": true, "abbr": true, "b": true, "bdi": true, "bdo": true, "cite": true,
+	"code": true, "dfn": true, "em": true, "font": true, "i": true, "kbd": true,
+	"mark": true, "q": true, "ruby": true, "s": true, "samp": true, "small": true,
+	"span": true, "strong": true, "sub": true, "sup": true, "time": true,
+	"tt": true, "u": true, "var": true,
+}
+
+// sectionWriter collects the text of a section, a space after each piece.
+// Chinese and Japanese are written without spaces, so pieces of them split
+// only by inline markup, such as ruby or emphasis, are joined without one
+// to be broken into words as a whole.
+type sectionWriter struct {
+	b strings.Builder
+	// space is set when a space is due before the next piece.
+	space bool
+	// joinable is set while the text ends in Chinese or Japanese that the
+	// next piece may continue.
+	joinable bool
+}
+
+// write adds a piece of text.
+func (w *sectionWriter) write(t string) {
+	if t == "" {
+		return
+	}
+	first, _ := utf8.DecodeRuneInString(t)
+	if w.space && !(w.joinable && isWideCJK(first)) {
+		w.b.WriteByte(' ')
+	}
+	w.b.WriteString(t)
+	w.space = true
+	last, _ := utf8.DecodeLastRuneInString(t)
+	w.joinable = isWideCJK(last)
+}
+
+// element notes markup between pieces of text: any but inline markup ends
+// the run of text before it.
+func (w *sectionWriter) element(n *html.Node) {
+	if !inlineElements[n.Data] {
+		w.joinable = false
+	}
+}
+
+func (w *sectionWriter) String() string {
+	if w.space {
+		return w.b.String() + " "
+	}
+	return w.b.String()
+}
+
+// isWideCJK reports whether r is a Chinese or Japanese character or one
+// of their full-width punctuation marks.
+func isWideCJK(r rune) bool {
+	return isCJK(r) || (r >= 0x3000 && r <= 0x303f) || (r >= 0xff00 && r <= 0xffef)
+}
+
+// isVerticalWriting reports whether an EPUB's package document says the
+// book is typeset in vertical lines, as Japanese novels often are: with
+// the primary-writing-mode meta, or pages turning right to left in a
+// Chinese, Japanese or Korean book.
+func isVerticalWriting(opf epubPackage) bool {
+	for _, m := range opf.Metas {
+		if m.Name == "primary-writing-mode" {
+			return strings.HasPrefix(m.Content, "vertical")
+		}
+	}
+	if opf.Spine.Direction != "rtl" {
+		return false
+	}
+	for _, lang := range opf.Languages {
+		switch strings.ToLower(strings.SplitN(strings.TrimSpace(lang), "-", 2)[0]) {
+		case "ja", "zh", "ko":
+			return true
+		}
+	}
+	return false
+}
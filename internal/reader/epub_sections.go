@@ -0,0 +1,78 @@
+package reader
+
+import (
+	"context"
+	"runtime"
+	"sync"
+
+	"github.com/taylorskalyo/goreader/epub"
+)
+
+// epubSection is a spine item read and turned into text.
+type epubSection struct {
+	item *epub.Item
+	// index is the item's place in the spine.
+	index int
+	// matter is set for front and back matter left out with SkipMatter,
+	// which is not read.
+	matter      bool
+	text, notes string
+	err         error
+}
+
+// workers returns how many spine items to read at once.
+func (f *EPUBFormat) workers() int {
+	if f.Workers > 0 {
+		return f.Workers
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// readSpineSections reads the spine items and extracts their text, several at a
+// time, returning them in spine order. Items with no manifest entry are
+// left out. It stops early, returning ctx's error, when ctx is cancelled.
+// Errors reading an item are left on its section for the caller to skip
+// or fail on, so warnings are reported in spine order.
+func (f *EPUBFormat) readSpineSections(ctx context.Context, book *epub.Rootfile, matter map[string]bool) ([]epubSection, error) {
+	var sections []epubSection
+	for i, ref := range book.Spine.Itemrefs {
+		if ref.Item != nil {
+			sections = append(sections, epubSection{item: ref.Item, index: i, matter: isMatter(matter, ref.Item.HREF)})
+		}
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(f.workers(), len(sections)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				s := &sections[i]
+				data, err := readEPUBItem(s.item)
+				if err != nil {
+					s.err = err
+					continue
+				}
+				s.text, s.notes = extractSection(string(data), f.Figures, f.Notes, f.Ruby)
+			}
+		}()
+	}
+feed:
+	for i := range sections {
+		if sections[i].matter {
+			continue
+		}
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return sections, nil
+}
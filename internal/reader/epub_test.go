@@ -1,6 +1,10 @@
 package reader
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -35,3 +39,359 @@ func TestExtractTextFromHTML(t *testing.T) {
 		}
 	}
 }
+
+func TestExtractHTMLTextFigures(t *testing.T) {
+	htmlContent := `<html><body>
+		<p>See the diagram.</p>
+		<figure><img src="arch.png" alt="Request flow
+			through the proxy"><figcaption>Figure 2. The proxy.</figcaption></figure>
+		<img src="rule.png" alt="">
+	</body></html>`
+
+	if got := strings.Join(ParseText(extractHTMLText(htmlContent, false)), " "); got != "See the diagram. Figure 2. The proxy." {
+		t.Errorf("without figures got %q", got)
+	}
+	want := "See the diagram. [Figure: Request flow through the proxy] Figure 2. The proxy."
+	if got := strings.Join(ParseText(extractHTMLText(htmlContent, true)), " "); got != want {
+		t.Errorf("with figures got %q, want %q", got, want)
+	}
+}
+
+func TestExtractSectionRuby(t *testing.T) {
+	htmlContent := `<html><body>
+		<p>彼は<ruby>漢<rp>(</rp><rt>かん</rt><rp>)</rp>字<rp>(</rp><rt>じ</rt><rp>)</rp></ruby>を<em>読んだ</em>。</p>
+		<p>次の段落。</p>
+		<p>The <ruby>word<rt>reading</rt></ruby> stays apart.</p>
+	</body></html>`
+
+	tests := []struct {
+		mode RubyMode
+		want []string
+	}{
+		{RubySkip, []string{"彼は", "漢字を", "読んだ。", "次の", "段落。", "The", "word", "stays", "apart."}},
+		{RubyInline, []string{"彼は", "漢字", "（かんじ）を", "読んだ。", "次の", "段落。", "The", "word（reading）", "stays", "apart."}},
+	}
+	for _, tt := range tests {
+		text, _ := extractSection(htmlContent, false, NotesSkip, tt.mode)
+		if got := ParseText(text); strings.Join(got, " ") != strings.Join(tt.want, " ") {
+			t.Errorf("%v: words = %q, want %q", tt.mode, got, tt.want)
+		}
+	}
+	if m, ok := ParseRubyMode("Inline"); !ok || m != RubyInline {
+		t.Errorf("ParseRubyMode(Inline) = %v, %v", m, ok)
+	}
+}
+
+func TestEPUBUnreadableSections(t *testing.T) {
+	path := writeTestEPUB(t, map[string]string{
+		"OEBPS/content.opf": `<?xml version="1.0"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0">
+  <manifest>
+    <item id="c1" href="ch1.xhtml" media-type="application/xhtml+xml"/>
+    <item id="c2" href="missing.xhtml" media-type="application/xhtml+xml"/>
+  </manifest>
+  <spine><itemref idref="c1"/><itemref idref="c2"/></spine>
+</package>`,
+		"OEBPS/ch1.xhtml": `<html><body><p>Only this survives.</p></body></html>`,
+	})
+
+	var warnings Warnings
+	f := &EPUBFormat{Warnings: &warnings}
+	_, words, err := f.ExtractChapters(path)
+	if err != nil {
+		t.Fatalf("ExtractChapters: %v", err)
+	}
+	if _, err := f.Extract(path); err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	if strings.Join(words, " ") != "Only this survives." {
+		t.Errorf("words = %q", words)
+	}
+	if warnings.Summary() != "1 section could not be read" {
+		t.Errorf("Summary() = %q, warnings %v", warnings.Summary(), warnings)
+	}
+	var serr *SectionError
+	if !errors.As(warnings[0], &serr) || serr.Section != "missing.xhtml" {
+		t.Errorf("warning = %v, want SectionError for missing.xhtml", warnings[0])
+	}
+
+	strict := &EPUBFormat{Strict: true}
+	if _, _, err := strict.ExtractChapters(path); !errors.As(err, &serr) {
+		t.Errorf("strict ExtractChapters err = %v, want SectionError", err)
+	}
+	if _, err := strict.Extract(path); err == nil {
+		t.Error("strict Extract should fail")
+	}
+
+	// Read a chapter at a time, the section is skipped with a warning, or
+	// ends the text with an error when strict.
+	warnings = nil
+	chapters, err := f.OpenChapters(path)
+	if err != nil {
+		t.Fatalf("OpenChapters: %v", err)
+	}
+	defer chapters.Close()
+	if r := NewChapterReader(chapters, 300); r.Err() != nil || len(warnings) != 1 {
+		t.Errorf("lazily read: err %v, warnings %v", r.Err(), warnings)
+	}
+	strictChapters, err := strict.OpenChapters(path)
+	if err != nil {
+		t.Fatalf("strict OpenChapters: %v", err)
+	}
+	defer strictChapters.Close()
+	if r := NewChapterReader(strictChapters, 300); !errors.As(r.Err(), &serr) {
+		t.Errorf("strict lazily read err = %v, want SectionError", r.Err())
+	}
+}
+
+func TestEPUBNotes(t *testing.T) {
+	path := writeTestEPUB(t, map[string]string{
+		"OEBPS/content.opf":   navOPF,
+		"OEBPS/nav/toc.xhtml": navDocument,
+		"OEBPS/text/ch1.xhtml": `<html xmlns:epub="http://www.idpf.org/2007/ops"><body>
+			<p>one<a epub:type="noteref" href="#n1">1</a> two three</p>
+			<aside epub:type="footnote" id="n1"><p>Aside note.</p></aside></body></html>`,
+		"OEBPS/text/ch2.xhtml": `<html><body><p>four<a role="doc-noteref" href="#e1">2</a> five</p>
+			<section role="doc-endnotes"><ol><li id="e1"><p>End note. <a role="doc-backlink" href="#r1">↩</a></p></li></ol></section></body></html>`,
+	})
+
+	tests := []struct {
+		mode     NoteMode
+		words    string
+		chapters []string
+	}{
+		{NotesSkip, "one two three four five", []string{"Chapter One", "Chapter Two"}},
+		{NotesAppendix, "one two three four five Aside note. End note.", []string{"Chapter One", "Chapter Two", "Notes"}},
+		{NotesInline, "one 1 two three Aside note. four 2 five End note. ↩", []string{"Chapter One", "Chapter Two"}},
+	}
+	for _, tt := range tests {
+		f := &EPUBFormat{Notes: tt.mode}
+		chapters, words, err := f.ExtractChapters(path)
+		if err != nil {
+			t.Fatalf("%s: ExtractChapters: %v", tt.mode, err)
+		}
+		if got := strings.Join(words, " "); got != tt.words {
+			t.Errorf("%s: words = %q, want %q", tt.mode, got, tt.words)
+		}
+		var titles []string
+		for _, ch := range chapters {
+			titles = append(titles, ch.Title)
+		}
+		if strings.Join(titles, ", ") != strings.Join(tt.chapters, ", ") {
+			t.Errorf("%s: chapters = %q, want %q", tt.mode, titles, tt.chapters)
+		}
+		text, err := f.Extract(path)
+		if err != nil || strings.Join(ParseText(text), " ") != tt.words {
+			t.Errorf("%s: Extract = %q, %v; want %q", tt.mode, text, err, tt.words)
+		}
+	}
+
+	toc, err := (&EPUBFormat{Notes: NotesAppendix}).TOC(path)
+	if err != nil {
+		t.Fatalf("TOC: %v", err)
+	}
+	if last := toc[len(toc)-1]; last.Title != "Notes" || last.WordIndex != 5 || last.Preview != "Aside note. End note...." {
+		t.Errorf("last TOC entry = %+v, want Notes at word 5", last)
+	}
+}
+
+func TestEPUBSkipMatter(t *testing.T) {
+	path := writeTestEPUB(t, map[string]string{
+		"OEBPS/content.opf": `<?xml version="1.0"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0">
+  <manifest>
+    <item id="nav" href="nav/toc.xhtml" media-type="application/xhtml+xml" properties="nav"/>
+    <item id="cover" href="text/cover.xhtml" media-type="application/xhtml+xml"/>
+    <item id="copy" href="text/copy.xhtml" media-type="application/xhtml+xml"/>
+    <item id="c1" href="text/ch1.xhtml" media-type="application/xhtml+xml"/>
+    <item id="index" href="text/index.xhtml" media-type="application/xhtml+xml"/>
+  </manifest>
+  <spine><itemref idref="cover"/><itemref idref="copy"/><itemref idref="c1"/><itemref idref="index"/></spine>
+  <guide><reference type="cover" href="text/cover.xhtml"/></guide>
+</package>`,
+		"OEBPS/nav/toc.xhtml": `<html xmlns:epub="http://www.idpf.org/2007/ops"><body>
+  <nav epub:type="landmarks"><ol><li><a epub:type="index" href="../text/index.xhtml#i">Index</a></li></ol></nav>
+  <nav epub:type="toc"><ol>
+    <li><a href="../text/copy.xhtml">Copyright</a></li>
+    <li><a href="../text/ch1.xhtml">Chapter One</a></li>
+    <li><a href="../text/index.xhtml">Index</a></li>
+  </ol></nav></body></html>`,
+		"OEBPS/text/cover.xhtml": `<html><body><p>A Book</p></body></html>`,
+		"OEBPS/text/copy.xhtml":  `<html><body><p>All rights reserved.</p></body></html>`,
+		"OEBPS/text/ch1.xhtml":   `<html><body><p>one two three</p></body></html>`,
+		"OEBPS/text/index.xhtml": `<html><body><p>three, 1</p></body></html>`,
+	})
+
+	f := &EPUBFormat{SkipMatter: true}
+	chapters, words, err := f.ExtractChapters(path)
+	if err != nil {
+		t.Fatalf("ExtractChapters: %v", err)
+	}
+	if got := strings.Join(words, " "); got != "one two three" {
+		t.Errorf("words = %q, want the first chapter only", got)
+	}
+	if len(chapters) != 1 || chapters[0].Title != "Chapter One" || chapters[0].WordStart != 0 {
+		t.Errorf("chapters = %+v, want Chapter One at word 0", chapters)
+	}
+	text, err := f.Extract(path)
+	if err != nil || strings.Join(ParseText(text), " ") != "one two three" {
+		t.Errorf("Extract = %q, %v; want the first chapter only", text, err)
+	}
+	toc, err := f.TOC(path)
+	if err != nil {
+		t.Fatalf("TOC: %v", err)
+	}
+	if len(toc) != 1 || toc[0].Title != "Chapter One" || toc[0].WordIndex != 0 {
+		t.Errorf("TOC = %+v, want Chapter One at word 0", toc)
+	}
+
+	_, words, err = (&EPUBFormat{}).ExtractChapters(path)
+	if err != nil || len(words) != 10 {
+		t.Errorf("without SkipMatter: words = %q, %v; want all 10", words, err)
+	}
+}
+
+func TestEPUBMetadata(t *testing.T) {
+	path := writeTestEPUB(t, map[string]string{
+		"OEBPS/content.opf": `<?xml version="1.0"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:title>The Long
+      Title</dc:title>
+    <dc:creator>First Author</dc:creator>
+    <dc:creator>Second Author</dc:creator>
+  </metadata>
+  <manifest><item id="c1" href="ch1.xhtml" media-type="application/xhtml+xml"/></manifest>
+  <spine><itemref idref="c1"/></spine>
+</package>`,
+		"OEBPS/ch1.xhtml": `<html><body><p>one</p></body></html>`,
+	})
+
+	meta, err := (&EPUBFormat{}).Metadata(path)
+	if err != nil {
+		t.Fatalf("Metadata: %v", err)
+	}
+	if meta.Title != "The Long Title" || meta.Author != "First Author and Second Author" {
+		t.Errorf("Metadata = %+v, want The Long Title by First Author and Second Author", meta)
+	}
+	if got := meta.String(); got != "The Long Title by First Author and Second Author" {
+		t.Errorf("String() = %q", got)
+	}
+	if meta.Vertical {
+		t.Error("a horizontal book should not be vertical")
+	}
+	if got := (Metadata{Title: "Untitled"}).String(); got != "Untitled" {
+		t.Errorf("String() without author = %q, want the title", got)
+	}
+	if got := joinAuthors([]string{"A", "B", "C"}); got != "A, B and C" {
+		t.Errorf("joinAuthors = %q, want A, B and C", got)
+	}
+}
+
+func TestEPUBVerticalWriting(t *testing.T) {
+	tests := []struct {
+		metadata, spine string
+		want            bool
+	}{
+		{`<dc:language>ja</dc:language><meta name="primary-writing-mode" content="vertical-rl"/>`, `<spine>`, true},
+		{`<dc:language>ja</dc:language><meta name="primary-writing-mode" content="horizontal-lr"/>`, `<spine page-progression-direction="rtl">`, false},
+		{`<dc:language>ja-JP</dc:language>`, `<spine page-progression-direction="rtl">`, true},
+		{`<dc:language>ar</dc:language>`, `<spine page-progression-direction="rtl">`, false},
+	}
+	for _, tt := range tests {
+		path := writeTestEPUB(t, map[string]string{
+			"OEBPS/content.opf": `<?xml version="1.0"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/"><dc:title>本</dc:title>` + tt.metadata + `</metadata>
+  <manifest><item id="c1" href="ch1.xhtml" media-type="application/xhtml+xml"/></manifest>
+  ` + tt.spine + `<itemref idref="c1"/></spine>
+</package>`,
+			"OEBPS/ch1.xhtml": `<html><body><p>一</p></body></html>`,
+		})
+		meta, err := (&EPUBFormat{}).Metadata(path)
+		if err != nil || meta.Vertical != tt.want {
+			t.Errorf("%s %s: Vertical = %v, %v; want %v", tt.metadata, tt.spine, meta.Vertical, err, tt.want)
+		}
+	}
+}
+
+func TestEPUBOpenChapters(t *testing.T) {
+	path := writeTestEPUB(t, map[string]string{
+		"OEBPS/content.opf":   navOPF,
+		"OEBPS/nav/toc.xhtml": navDocument,
+		"OEBPS/text/ch1.xhtml": `<html xmlns:epub="http://www.idpf.org/2007/ops"><body>
+			<p>one<a epub:type="noteref" href="#n1">1</a> two three.</p>
+			<aside epub:type="footnote" id="n1"><p>Aside note.</p></aside></body></html>`,
+		"OEBPS/text/ch2.xhtml": `<html><body><p>four five</p></body></html>`,
+	})
+
+	f := &EPUBFormat{Notes: NotesAppendix}
+	chapters, err := f.OpenChapters(path)
+	if err != nil {
+		t.Fatalf("OpenChapters: %v", err)
+	}
+	defer chapters.Close()
+	if chapters.Len() != 3 || chapters.Size(0) == 0 {
+		t.Errorf("Len() = %d, Size(0) = %d; want two sections and the notes", chapters.Len(), chapters.Size(0))
+	}
+
+	r := NewChapterReader(chapters, 300)
+	wantChapters, wantWords, err := f.ExtractChapters(path)
+	if err != nil {
+		t.Fatalf("ExtractChapters: %v", err)
+	}
+	if strings.Join(r.Words, " ") != strings.Join(wantWords, " ") {
+		t.Errorf("words = %q, want %q as extracted whole", r.Words, wantWords)
+	}
+	if len(r.Chapters) != len(wantChapters) {
+		t.Fatalf("chapters = %+v, want %+v", r.Chapters, wantChapters)
+	}
+	if len(r.TOC) != 3 || r.TOC[0].Title != "Chapter One" || r.TOC[2].Title != "Notes" {
+		t.Errorf("TOC = %+v, want the sections and notes", r.TOC)
+	}
+	for i, ch := range r.Chapters {
+		if ch != wantChapters[i] {
+			t.Errorf("chapter %d = %+v, want %+v", i, ch, wantChapters[i])
+		}
+	}
+}
+
+func TestEPUBParallelSections(t *testing.T) {
+	files := map[string]string{}
+	var manifest, spine, want strings.Builder
+	for i := range 40 {
+		fmt.Fprintf(&manifest, `<item id="c%d" href="ch%d.xhtml" media-type="application/xhtml+xml"/>`, i, i)
+		fmt.Fprintf(&spine, `<itemref idref="c%d"/>`, i)
+		files[fmt.Sprintf("OEBPS/ch%d.xhtml", i)] = fmt.Sprintf(`<html><body><p>Chapter %d.</p></body></html>`, i)
+		fmt.Fprintf(&want, "Chapter %d. ", i)
+	}
+	files["OEBPS/content.opf"] = `<?xml version="1.0"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0">
+  <manifest>` + manifest.String() + `</manifest>
+  <spine>` + spine.String() + `</spine>
+</package>`
+	path := writeTestEPUB(t, files)
+
+	for _, workers := range []int{1, 8} {
+		f := &EPUBFormat{Workers: workers}
+		_, words, err := f.ExtractChapters(path)
+		if err != nil {
+			t.Fatalf("ExtractChapters with %d workers: %v", workers, err)
+		}
+		if got := strings.Join(words, " ") + " "; got != want.String() {
+			t.Errorf("%d workers read %q, want spine order", workers, got)
+		}
+	}
+
+	// A book without navigation still has its chapters.
+	toc, chapters, _, err := (&EPUBFormat{}).TOCAndChaptersContext(context.Background(), path)
+	if err != nil || toc != nil || len(chapters) != 40 {
+		t.Errorf("TOCAndChaptersContext = %+v, %d chapters, %v", toc, len(chapters), err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&EPUBFormat{}).ExtractContext(ctx, path); !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled ExtractContext err = %v", err)
+	}
+}
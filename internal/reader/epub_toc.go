@@ -2,6 +2,7 @@ package reader
 
 import (
 	"archive/zip"
+	"context"
 	"encoding/xml"
 	"fmt"
 	"io"
@@ -38,6 +39,11 @@ type navContent struct {
 
 // TOC extracts the table of contents from an EPUB file.
 func (f *EPUBFormat) TOC(filename string) ([]TOCEntry, error) {
+	return f.TOCContext(context.Background(), filename)
+}
+
+// TOCContext is TOC stopping between chapters when ctx is cancelled.
+func (f *EPUBFormat) TOCContext(ctx context.Context, filename string) ([]TOCEntry, error) {
 	rc, err := epub.OpenReader(filename)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open epub: %w", err)
@@ -50,24 +56,63 @@ func (f *EPUBFormat) TOC(filename string) ([]TOCEntry, error) {
 
 	book := rc.Rootfiles[0]
 
-	ncxData, err := findAndReadNCX(filename, book)
+	points, err := loadNavPoints(filename, book)
 	if err != nil {
 		return nil, err
 	}
-
-	var toc ncx
-	if err := xml.Unmarshal(ncxData, &toc); err != nil {
-		return nil, fmt.Errorf("failed to parse NCX: %w", err)
+	sections, err := f.readSpineSections(ctx, book, f.matter(filename, book))
+	if err != nil {
+		return nil, err
 	}
+	return f.tocEntries(points, sections)
+}
 
-	spineMap := buildAccurateSpineMap(filename, book)
-	entries := flattenNavPoints(toc.NavMap.NavPoints, spineMap, 0)
+// tocEntries builds the TOC entries for the navigation points from the
+// spine sections they point into.
+func (f *EPUBFormat) tocEntries(points []navPoint, sections []epubSection) ([]TOCEntry, error) {
+	spineMap, appendix, err := f.buildAccurateSpineMap(sections)
+	if err != nil {
+		return nil, err
+	}
+	entries := flattenNavPoints(points, spineMap, 0, f.KeepEmpty)
+	if appendix != nil {
+		entries = append(entries, TOCEntry{Title: appendixTitle, WordIndex: appendix.wordIndex, Preview: appendix.preview})
+	}
 
 	return entries, nil
 }
 
+// loadNavPoints reads the book's navigation from toc.ncx, falling back to
+// the EPUB3 nav document when there is no NCX or it is empty.
+func loadNavPoints(filename string, book *epub.Rootfile) ([]navPoint, error) {
+	ncxData, ncxErr := findAndReadNCX(filename, book)
+	if ncxErr == nil {
+		var toc ncx
+		if err := xml.Unmarshal(ncxData, &toc); err != nil {
+			ncxErr = fmt.Errorf("failed to parse NCX: %w", err)
+		} else if len(toc.NavMap.NavPoints) > 0 {
+			return toc.NavMap.NavPoints, nil
+		}
+	}
+
+	points, err := readNavDocument(filename, book)
+	if err != nil {
+		if ncxErr != nil {
+			return nil, ncxErr
+		}
+		return nil, err
+	}
+	return points, nil
+}
+
 // ExtractChapters extracts text with chapter boundaries preserved.
 func (f *EPUBFormat) ExtractChapters(filename string) ([]Chapter, []string, error) {
+	return f.ExtractChaptersContext(context.Background(), filename)
+}
+
+// ExtractChaptersContext is ExtractChapters stopping between chapters when
+// ctx is cancelled.
+func (f *EPUBFormat) ExtractChaptersContext(ctx context.Context, filename string) ([]Chapter, []string, error) {
 	rc, err := epub.OpenReader(filename)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to open epub: %w", err)
@@ -80,30 +125,74 @@ func (f *EPUBFormat) ExtractChapters(filename string) ([]Chapter, []string, erro
 
 	book := rc.Rootfiles[0]
 
-	tocByHref := buildTOCHrefMap(filename, book)
+	sections, err := f.readSpineSections(ctx, book, f.matter(filename, book))
+	if err != nil {
+		return nil, nil, err
+	}
+	return f.sectionChapters(buildTOCHrefMap(filename, book), sections)
+}
+
+// TOCAndChaptersContext reads both the table of contents and the chapters
+// of an EPUB from one pass over its spine, for callers that want both.
+// The TOC is nil when the book has no navigation.
+func (f *EPUBFormat) TOCAndChaptersContext(ctx context.Context, filename string) ([]TOCEntry, []Chapter, []string, error) {
+	rc, err := epub.OpenReader(filename)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to open epub: %w", err)
+	}
+	defer rc.Close()
+
+	if len(rc.Rootfiles) == 0 {
+		return nil, nil, nil, fmt.Errorf("no rootfiles found in epub")
+	}
+
+	book := rc.Rootfiles[0]
+
+	points, navErr := loadNavPoints(filename, book)
+	sections, err := f.readSpineSections(ctx, book, f.matter(filename, book))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	chapters, words, err := f.sectionChapters(tocHrefMap(points), sections)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if navErr != nil {
+		return nil, chapters, words, nil
+	}
+	toc, err := f.tocEntries(points, sections)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return toc, chapters, words, nil
+}
 
+// sectionChapters makes a chapter of each spine section, named from the
+// TOC, followed by the notes gathered by NotesAppendix.
+func (f *EPUBFormat) sectionChapters(tocByHref map[string]string, sections []epubSection) ([]Chapter, []string, error) {
 	var allWords []string
 	var chapters []Chapter
+	var notes strings.Builder
 
-	for i, ref := range book.Spine.Itemrefs {
-		if ref.Item == nil {
-			continue
-		}
-
-		r, err := ref.Item.Open()
-		if err != nil {
+	for _, s := range sections {
+		if s.matter {
 			continue
 		}
-		data, err := io.ReadAll(r)
-		r.Close()
-		if err != nil {
+		if s.err != nil {
+			if err := f.skip(s.item.HREF, s.err); err != nil {
+				return nil, nil, err
+			}
 			continue
 		}
 
-		text := extractTextFromHTML(string(data))
-		words := strings.Fields(text)
+		words := ParseText(s.text)
+		notes.WriteString(s.notes)
 
-		if len(words) == 0 {
+		title, titled := sectionTitle(tocByHref, s.item.HREF, s.index)
+
+		// An empty section is kept only on request, and only if the TOC
+		// names it; an untitled one would be a bare "Section N".
+		if len(words) == 0 && (!f.KeepEmpty || !titled) {
 			continue
 		}
 
@@ -111,15 +200,6 @@ func (f *EPUBFormat) ExtractChapters(filename string) ([]Chapter, []string, erro
 		allWords = append(allWords, words...)
 		wordEnd := len(allWords) - 1
 
-		title := fmt.Sprintf("Section %d", i+1)
-		if ref.Item.HREF != "" {
-			if t, ok := tocByHref[ref.Item.HREF]; ok {
-				title = t
-			} else if t, ok := tocByHref[path.Base(ref.Item.HREF)]; ok {
-				title = t
-			}
-		}
-
 		chapters = append(chapters, Chapter{
 			Title:     title,
 			WordStart: wordStart,
@@ -127,22 +207,44 @@ func (f *EPUBFormat) ExtractChapters(filename string) ([]Chapter, []string, erro
 		})
 	}
 
+	if words := ParseText(notes.String()); len(words) > 0 {
+		chapters = append(chapters, Chapter{
+			Title:     appendixTitle,
+			WordStart: len(allWords),
+			WordEnd:   len(allWords) + len(words) - 1,
+		})
+		allWords = append(allWords, words...)
+	}
+
 	return chapters, allWords, nil
 }
 
+// sectionTitle returns the TOC title of the spine item at href, reporting
+// whether it has one, or "Section N" after its place in the spine.
+func sectionTitle(tocByHref map[string]string, href string, i int) (string, bool) {
+	if href != "" {
+		if t, ok := tocByHref[href]; ok {
+			return t, true
+		}
+		if t, ok := tocByHref[path.Base(href)]; ok {
+			return t, true
+		}
+	}
+	return fmt.Sprintf("Section %d", i+1), false
+}
+
 // buildTOCHrefMap parses the NCX and returns a map of href to title
 func buildTOCHrefMap(filename string, book *epub.Rootfile) map[string]string {
-	result := make(map[string]string)
-
-	ncxData, err := findAndReadNCX(filename, book)
+	points, err := loadNavPoints(filename, book)
 	if err != nil {
-		return result
+		return map[string]string{}
 	}
+	return tocHrefMap(points)
+}
 
-	var toc ncx
-	if err := xml.Unmarshal(ncxData, &toc); err != nil {
-		return result
-	}
+// tocHrefMap maps the href of each navigation point to its title.
+func tocHrefMap(points []navPoint) map[string]string {
+	result := make(map[string]string)
 
 	var extract func(points []navPoint)
 	extract = func(points []navPoint) {
@@ -170,7 +272,7 @@ func buildTOCHrefMap(filename string, book *epub.Rootfile) map[string]string {
 			extract(np.Children)
 		}
 	}
-	extract(toc.NavMap.NavPoints)
+	extract(points)
 
 	return result
 }
@@ -219,79 +321,101 @@ func findAndReadNCX(filename string, book *epub.Rootfile) ([]byte, error) {
 type spineInfo struct {
 	wordIndex int
 	preview   string
+	// empty is set for sections with no text.
+	empty bool
+	// skipped is set for front and back matter left out with SkipMatter.
+	skipped bool
 }
 
-func buildAccurateSpineMap(filename string, book *epub.Rootfile) map[string]spineInfo {
+// buildAccurateSpineMap maps each spine item to where its words start in
+// the text, and returns where the notes gathered by NotesAppendix start,
+// if there are any.
+func (f *EPUBFormat) buildAccurateSpineMap(sections []epubSection) (map[string]spineInfo, *spineInfo, error) {
 	m := make(map[string]spineInfo)
 	wordCount := 0
-
-	for _, ref := range book.Spine.Itemrefs {
-		if ref.Item == nil {
+	var notes strings.Builder
+
+	for _, s := range sections {
+		href := s.item.HREF
+		if s.matter {
+			info := spineInfo{wordIndex: wordCount, skipped: true}
+			m[href] = info
+			m[path.Base(href)] = info
 			continue
 		}
-
-		r, err := ref.Item.Open()
-		if err != nil {
-			continue
-		}
-		data, err := io.ReadAll(r)
-		r.Close()
-		if err != nil {
+		if s.err != nil {
+			if err := f.skip(href, s.err); err != nil {
+				return nil, nil, err
+			}
 			continue
 		}
 
-		text := extractTextFromHTML(string(data))
-		words := strings.Fields(text)
-
-		preview := ""
-		if len(words) > 0 {
-			previewWords := words
-			if len(previewWords) > 10 {
-				previewWords = previewWords[:10]
-			}
-			preview = strings.Join(previewWords, " ") + "..."
-		}
+		words := ParseText(s.text)
+		notes.WriteString(s.notes)
 
-		if ref.Item.HREF != "" {
-			m[ref.Item.HREF] = spineInfo{wordIndex: wordCount, preview: preview}
-			m[path.Base(ref.Item.HREF)] = spineInfo{wordIndex: wordCount, preview: preview}
+		if href != "" {
+			info := spineInfo{wordIndex: wordCount, preview: sectionPreview(words), empty: len(words) == 0}
+			m[href] = info
+			m[path.Base(href)] = info
 		}
 
 		wordCount += len(words)
 	}
 
-	return m
+	// Notes gathered by NotesAppendix follow the last section.
+	var appendix *spineInfo
+	if words := ParseText(notes.String()); len(words) > 0 {
+		appendix = &spineInfo{wordIndex: wordCount, preview: sectionPreview(words)}
+	}
+	return m, appendix, nil
+}
+
+// sectionPreview returns the first words of a section for its TOC entry.
+func sectionPreview(words []string) string {
+	if len(words) == 0 {
+		return ""
+	}
+	if len(words) > 10 {
+		words = words[:10]
+	}
+	return strings.Join(words, " ") + "..."
 }
 
-func flattenNavPoints(points []navPoint, spineMap map[string]spineInfo, level int) []TOCEntry {
+// flattenNavPoints turns the navigation tree into TOC entries. Unless
+// keepEmpty is set, entries for sections with no text are dropped, as are
+// those for skipped front and back matter; those with children are kept as
+// headings for them.
+func flattenNavPoints(points []navPoint, spineMap map[string]spineInfo, level int, keepEmpty bool) []TOCEntry {
 	var entries []TOCEntry
 
 	for _, np := range points {
 		href := np.Content.Src
+		if href == "" && len(np.Children) > 0 {
+			// An unlinked heading starts where its first child does.
+			href = np.Children[0].Content.Src
+		}
 		baseHref := href
 		if idx := strings.Index(href, "#"); idx != -1 {
 			baseHref = href[:idx]
 		}
 
-		wordIndex := 0
-		preview := ""
-		if info, ok := spineMap[baseHref]; ok {
-			wordIndex = info.wordIndex
-			preview = info.preview
-		} else if info, ok := spineMap[path.Base(baseHref)]; ok {
-			wordIndex = info.wordIndex
-			preview = info.preview
+		info, ok := spineMap[baseHref]
+		if !ok {
+			info = spineMap[path.Base(baseHref)]
+		}
+		if (info.skipped || info.empty && !keepEmpty) && len(np.Children) == 0 {
+			continue
 		}
 
 		entry := TOCEntry{
 			Title:     strings.TrimSpace(np.Label.Text),
-			Preview:   preview,
-			WordIndex: wordIndex,
+			Preview:   info.preview,
+			WordIndex: info.wordIndex,
 			Level:     level,
 		}
 		entries = append(entries, entry)
 		if len(np.Children) > 0 {
-			children := flattenNavPoints(np.Children, spineMap, level+1)
+			children := flattenNavPoints(np.Children, spineMap, level+1, keepEmpty)
 			entries = append(entries, children...)
 		}
 	}
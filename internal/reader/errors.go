@@ -0,0 +1,42 @@
+package reader
+
+import "fmt"
+
+// SectionError reports a part of a document, such as an EPUB spine item,
+// that could not be read.
+type SectionError struct {
+	Section string
+	Err     error
+}
+
+func (e *SectionError) Error() string {
+	return fmt.Sprintf("section %s could not be read: %v", e.Section, e.Err)
+}
+
+func (e *SectionError) Unwrap() error { return e.Err }
+
+// Warnings collects errors that were skipped while loading a document, so
+// readers learn what is missing instead of getting a silently shorter book.
+type Warnings []error
+
+// Add records err once; the same section skipped by several passes over a
+// document is only reported once.
+func (w *Warnings) Add(err error) {
+	for _, e := range *w {
+		if e.Error() == err.Error() {
+			return
+		}
+	}
+	*w = append(*w, err)
+}
+
+// Summary describes the warnings in one line, or "" if there are none.
+func (w Warnings) Summary() string {
+	switch len(w) {
+	case 0:
+		return ""
+	case 1:
+		return "1 section could not be read"
+	}
+	return fmt.Sprintf("%d sections could not be read", len(w))
+}
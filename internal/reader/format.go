@@ -1,6 +1,7 @@
 package reader
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
@@ -13,6 +14,12 @@ type Format interface {
 	Extract(filename string) (string, error)
 }
 
+// ContextFormat is an optional interface for formats whose extraction can
+// be cancelled.
+type ContextFormat interface {
+	ExtractContext(ctx context.Context, filename string) (string, error)
+}
+
 var registry []Format
 
 // Register adds a format reader to the registry.
@@ -20,21 +27,44 @@ func Register(f Format) {
 	registry = append(registry, f)
 }
 
-// ExtractText extracts text from a file, using a registered format or plain text fallback.
-func ExtractText(filename string) (string, error) {
+// FormatFor returns the registered format handling filename's extension.
+func FormatFor(filename string) (Format, bool) {
 	ext := strings.ToLower(filepath.Ext(filename))
 	for _, f := range registry {
 		for _, e := range f.Extensions() {
 			if ext == e {
-				return f.Extract(filename)
+				return f, true
 			}
 		}
 	}
+	return nil, false
+}
+
+// ExtractText extracts text from a file, using a registered format or plain
+// text fallback. Plain text in UTF-16 or Windows-1252 is converted; see
+// DecodeText.
+func ExtractText(filename string) (string, error) {
+	return ExtractTextContext(context.Background(), filename)
+}
+
+// ExtractTextContext is ExtractText stopping early when ctx is cancelled.
+// Formats that don't implement ContextFormat are only checked before they
+// start.
+func ExtractTextContext(ctx context.Context, filename string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if f, ok := FormatFor(filename); ok {
+		if cf, ok := f.(ContextFormat); ok {
+			return cf.ExtractContext(ctx, filename)
+		}
+		return f.Extract(filename)
+	}
 	data, err := os.ReadFile(filename)
 	if err != nil {
 		return "", err
 	}
-	return string(data), nil
+	return DecodeText(data), nil
 }
 
 // SupportedFormats returns registered format names with their extensions.
@@ -45,3 +75,33 @@ func SupportedFormats() []string {
 	}
 	return out
 }
+
+// FormatInfo describes a registered format: the extensions it handles and
+// which of the optional interfaces it implements, for building file
+// filters and the like.
+type FormatInfo struct {
+	Name       string   `json:"name"`
+	Extensions []string `json:"extensions"`
+	// TOC is true for a TOCProvider.
+	TOC bool `json:"toc"`
+	// Chapters is true for a ChapterExtractor.
+	Chapters bool `json:"chapters"`
+	// Metadata is true for a MetadataProvider.
+	Metadata bool `json:"metadata"`
+	// Streaming is true for a ChapterOpener.
+	Streaming bool `json:"streaming"`
+}
+
+// Formats describes the registered formats in the order they were
+// registered.
+func Formats() []FormatInfo {
+	out := make([]FormatInfo, len(registry))
+	for i, f := range registry {
+		out[i] = FormatInfo{Name: f.Name(), Extensions: f.Extensions()}
+		_, out[i].TOC = f.(TOCProvider)
+		_, out[i].Chapters = f.(ChapterExtractor)
+		_, out[i].Metadata = f.(MetadataProvider)
+		_, out[i].Streaming = f.(ChapterOpener)
+	}
+	return out
+}
@@ -1,8 +1,11 @@
 package reader
 
 import (
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -71,3 +74,66 @@ func TestSupportedFormats(t *testing.T) {
 	}
 	t.Errorf("EPUB not registered: %v", formats)
 }
+
+func TestFormats(t *testing.T) {
+	var epub, markdown *FormatInfo
+	for _, f := range Formats() {
+		switch f.Name {
+		case "EPUB":
+			epub = &f
+		case "Markdown":
+			markdown = &f
+		}
+	}
+	if epub == nil || !epub.TOC || !epub.Chapters || !epub.Metadata || !epub.Streaming {
+		t.Errorf("EPUB = %+v, want every optional interface", epub)
+	}
+	if markdown == nil || !markdown.TOC || !markdown.Chapters || markdown.Streaming || len(markdown.Extensions) != 2 {
+		t.Errorf("Markdown = %+v", markdown)
+	}
+}
+
+func TestExtractCancelled(t *testing.T) {
+	path := writeTestEPUB(t, map[string]string{
+		"OEBPS/content.opf":    navOPF,
+		"OEBPS/nav/toc.xhtml":  navDocument,
+		"OEBPS/text/ch1.xhtml": `<html><body><p>One.</p></body></html>`,
+		"OEBPS/text/ch2.xhtml": `<html><body><p>Two.</p></body></html>`,
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := ExtractTextContext(ctx, path); !errors.Is(err, context.Canceled) {
+		t.Errorf("ExtractTextContext err = %v, want context.Canceled", err)
+	}
+	f := &EPUBFormat{}
+	if _, _, err := ExtractChaptersContext(ctx, f, path); !errors.Is(err, context.Canceled) {
+		t.Errorf("ExtractChaptersContext err = %v, want context.Canceled", err)
+	}
+	if _, err := f.TOCContext(ctx, path); !errors.Is(err, context.Canceled) {
+		t.Errorf("TOCContext err = %v, want context.Canceled", err)
+	}
+
+	// Formats without cancellation support are still checked up front.
+	if _, err := TOCContext(ctx, &MarkdownFormat{}, path); !errors.Is(err, context.Canceled) {
+		t.Errorf("TOCContext(markdown) err = %v, want context.Canceled", err)
+	}
+	if _, _, err := ExtractChaptersContext(context.Background(), f, path); err != nil {
+		t.Errorf("uncancelled ExtractChaptersContext: %v", err)
+	}
+}
+
+func TestHTMLFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.html")
+	os.WriteFile(path, []byte(`<html><body><nav>Home | About</nav>
+<article><h1>Title</h1><p>First paragraph of the article.</p><p>Second paragraph.</p></article>
+<footer>Copyright</footer></body></html>`), 0644)
+
+	got, err := ExtractText(path)
+	if err != nil {
+		t.Fatalf("ExtractText: %v", err)
+	}
+	if !strings.Contains(got, "First paragraph of the article.") || strings.Contains(got, "Copyright") {
+		t.Errorf("ExtractText = %q", got)
+	}
+}
@@ -0,0 +1,74 @@
+package reader
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFrequencies(t *testing.T) {
+	en, ok := Frequencies("en-GB")
+	if !ok || en["the"] != 0 || en["of"] != 1 {
+		t.Fatalf("unexpected English frequencies: %v", ok)
+	}
+	if _, ok := en["whale"]; ok {
+		t.Error("whale should not be among the commonest words")
+	}
+	if _, ok := Frequencies("xx"); ok {
+		t.Error("unknown languages should have no list")
+	}
+}
+
+func TestRarity(t *testing.T) {
+	r := NewReader("The houses walked 42 leviathan https://example.com", 300)
+	if got := r.Token(5).Rarity; got != 0 {
+		t.Errorf("without a frequency list rarity = %v, want 0", got)
+	}
+	r.Frequencies, _ = Frequencies("en")
+	for _, tt := range []struct {
+		idx      int
+		min, max float64
+	}{
+		{0, 0, 0},     // the commonest word
+		{1, 0.1, 0.5}, // house, with its ending cut
+		{2, 0.1, 0.5}, // walk
+		{3, 0, 0},     // numbers are never rare
+		{4, 1, 1},     // missing from the list
+		{5, 0, 0},     // links are never rare
+	} {
+		if got := r.Token(tt.idx).Rarity; got < tt.min || got > tt.max {
+			t.Errorf("rarity of %q = %v, want %v to %v", r.Words[tt.idx], got, tt.min, tt.max)
+		}
+	}
+}
+
+func TestDifficultyFactor(t *testing.T) {
+	r := NewReader("the antidisestablishmentarianism whale cat", 600)
+	r.Frequencies, _ = Frequencies("en")
+	if got := r.DifficultyFactor(1); got != 1 {
+		t.Errorf("with no strength factor = %v, want 1", got)
+	}
+	r.DifficultyStrength = 1
+	for _, tt := range []struct {
+		idx  int
+		want float64
+	}{
+		{0, 1},   // common and short
+		{1, 2},   // very long and rare
+		{2, 1.5}, // rare
+		{3, 1 + 0.5*r.rarity(3)},
+	} {
+		if got := r.DifficultyFactor(tt.idx); got != tt.want {
+			t.Errorf("factor of %q = %v, want %v", r.Words[tt.idx], got, tt.want)
+		}
+	}
+
+	r.ParagraphPause = 1
+	r.Advance()
+	if got, want := r.WordDelay(), 2*r.GetDelay(); got != want {
+		t.Errorf("WordDelay() = %v, want %v", got, want)
+	}
+	r.Pacer = LengthPacer{}
+	if got, want := r.WordDelay(), time.Duration(float64(LengthPacer{}.NextDelay(r, 1))*2); got != want {
+		t.Errorf("WordDelay() with length pacing = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,255 @@
+package reader
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// GitHubAPI is the base URL of the GitHub REST API.
+var GitHubAPI = "https://api.github.com"
+
+// GitHubRef identifies a repository, issue or pull request on github.com.
+type GitHubRef struct {
+	Owner  string
+	Repo   string
+	Kind   string // "repo", "issue" or "pull"
+	Number int
+}
+
+// ParseGitHubURL recognises github.com links to a repository, issue or pull
+// request.
+func ParseGitHubURL(rawURL string) (GitHubRef, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Host != "github.com" && u.Host != "www.github.com") {
+		return GitHubRef{}, false
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return GitHubRef{}, false
+	}
+	ref := GitHubRef{Owner: parts[0], Repo: strings.TrimSuffix(parts[1], ".git"), Kind: "repo"}
+	switch {
+	case len(parts) == 2:
+		return ref, true
+	case len(parts) >= 4 && (parts[2] == "issues" || parts[2] == "pull"):
+		n, err := strconv.Atoi(parts[3])
+		if err != nil {
+			return GitHubRef{}, false
+		}
+		ref.Kind = map[string]string{"issues": "issue", "pull": "pull"}[parts[2]]
+		ref.Number = n
+		return ref, true
+	}
+	return GitHubRef{}, false
+}
+
+type githubUser struct {
+	Login string `json:"login"`
+}
+
+type githubIssue struct {
+	Title string     `json:"title"`
+	Body  string     `json:"body"`
+	User  githubUser `json:"user"`
+}
+
+type githubComment struct {
+	Body string     `json:"body"`
+	User githubUser `json:"user"`
+}
+
+// ErrTooLarge is returned by FetchGitHubContext for a document larger than
+// its limit.
+var ErrTooLarge = errors.New("document is larger than the limit")
+
+// FetchGitHubContext fetches a README, or an issue or pull request with
+// one section for its description and one per comment, with requests
+// cancelled when ctx is. It returns the document's title and sections. It
+// fails with ErrTooLarge once the responses come to more than limit
+// bytes; 0 allows any size.
+func FetchGitHubContext(ctx context.Context, ref GitHubRef, limit int64) (string, []Section, error) {
+	var left *int64
+	if limit > 0 {
+		left = &limit
+	}
+	repo := "/repos/" + ref.Owner + "/" + ref.Repo
+	if ref.Kind == "repo" {
+		data, err := githubGet(ctx, repo+"/readme", "application/vnd.github.raw", left)
+		if err != nil {
+			return "", nil, err
+		}
+		title := ref.Owner + "/" + ref.Repo
+		return title, MarkdownSections(title, string(data)), nil
+	}
+
+	endpoint := repo + "/issues/"
+	if ref.Kind == "pull" {
+		endpoint = repo + "/pulls/"
+	}
+	var issue githubIssue
+	if _, err := githubGetPage(ctx, endpoint+strconv.Itoa(ref.Number), &issue, left); err != nil {
+		return "", nil, err
+	}
+	title := fmt.Sprintf("#%d %s", ref.Number, issue.Title)
+	sections := []Section{{Title: title, Text: CleanMarkdown(issue.Body), Level: 0}}
+
+	// Pull request conversation comments live on the issue endpoint.
+	path := repo + "/issues/" + strconv.Itoa(ref.Number) + "/comments?per_page=100"
+	for page := 0; path != "" && page < MaxArticlePages; page++ {
+		var comments []githubComment
+		next, err := githubGetPage(ctx, path, &comments, left)
+		if err != nil {
+			return "", nil, err
+		}
+		for _, c := range comments {
+			sections = append(sections, Section{
+				Title: "Comment by " + c.User.Login,
+				Text:  CleanMarkdown(c.Body),
+				Level: 1,
+			})
+		}
+		path = next
+	}
+	return title, sections, nil
+}
+
+type githubRelease struct {
+	Name        string `json:"name"`
+	TagName     string `json:"tag_name"`
+	Body        string `json:"body"`
+	Draft       bool   `json:"draft"`
+	PublishedAt string `json:"published_at"`
+}
+
+// FetchReleasesContext fetches the release notes of a repository's latest
+// count releases, newest first, one section per release, with requests
+// cancelled when ctx is. each, if not nil, is called with each release's
+// section as it is read.
+func FetchReleasesContext(ctx context.Context, owner, repo string, count int, each func(Section)) ([]Section, error) {
+	path := fmt.Sprintf("/repos/%s/%s/releases?per_page=%d", owner, repo, min(count, 100))
+	var sections []Section
+	for page := 0; path != "" && len(sections) < count && page < MaxArticlePages; page++ {
+		var releases []githubRelease
+		next, err := githubGetPage(ctx, path, &releases, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range releases {
+			if r.Draft || len(sections) == count {
+				continue
+			}
+			title := r.Name
+			if title == "" {
+				title = r.TagName
+			}
+			if date, _, ok := strings.Cut(r.PublishedAt, "T"); ok {
+				title += " (" + date + ")"
+			}
+			text := CleanMarkdown(r.Body)
+			if text == "" {
+				text = "No release notes."
+			}
+			sections = append(sections, Section{Title: title, Text: text})
+			if each != nil {
+				each(sections[len(sections)-1])
+			}
+		}
+		path = next
+	}
+	return sections, nil
+}
+
+// githubGetPage decodes the JSON response for an API path and returns the
+// path of the next page, if any. The response is counted against left as
+// githubGet counts it.
+func githubGetPage(ctx context.Context, path string, v any, left *int64) (string, error) {
+	resp, err := githubDo(ctx, path, "application/vnd.github+json")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	data, err := readGitHubBody(resp.Body, left)
+	if err != nil {
+		return "", err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return "", fmt.Errorf("invalid GitHub response: %w", err)
+	}
+	return nextLink(resp.Header.Get("Link")), nil
+}
+
+// githubGet returns the raw response body for an API path. When left is
+// set, the body is taken from the bytes left of a document's limit.
+func githubGet(ctx context.Context, path, accept string, left *int64) ([]byte, error) {
+	resp, err := githubDo(ctx, path, accept)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return readGitHubBody(resp.Body, left)
+}
+
+// readGitHubBody reads up to maxURLBytes of a response body, failing with
+// ErrTooLarge if it is more than left.
+func readGitHubBody(body io.Reader, left *int64) ([]byte, error) {
+	if left == nil {
+		return io.ReadAll(io.LimitReader(body, maxURLBytes))
+	}
+	data, err := io.ReadAll(io.LimitReader(body, min(*left, maxURLBytes)+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > *left {
+		return nil, ErrTooLarge
+	}
+	*left -= int64(len(data))
+	return data[:min(len(data), maxURLBytes)], nil
+}
+
+// githubDo performs an API request, authenticating with GITHUB_TOKEN when
+// it is set. path may also be an absolute URL from a Link header.
+func githubDo(ctx context.Context, path, accept string) (*http.Response, error) {
+	target := path
+	if !IsURL(path) {
+		target = GitHubAPI + path
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "brr (+https://github.com/metcalfc/brr)")
+	req.Header.Set("Accept", accept)
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := clientFor(ctx).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch from GitHub: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to fetch from GitHub: %s", resp.Status)
+	}
+	return resp, nil
+}
+
+// linkNextPattern matches the rel="next" entry of a Link header.
+var linkNextPattern = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// nextLink returns the rel="next" URL from a Link header, or "".
+func nextLink(header string) string {
+	if m := linkNextPattern.FindStringSubmatch(header); m != nil {
+		return m[1]
+	}
+	return ""
+}
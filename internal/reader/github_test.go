@@ -0,0 +1,157 @@
+package reader
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestParseGitHubURL(t *testing.T) {
+	tests := []struct {
+		in   string
+		want GitHubRef
+		ok   bool
+	}{
+		{"https://github.com/metcalfc/brr", GitHubRef{"metcalfc", "brr", "repo", 0}, true},
+		{"https://github.com/metcalfc/brr/issues/12", GitHubRef{"metcalfc", "brr", "issue", 12}, true},
+		{"https://github.com/metcalfc/brr/pull/7/files", GitHubRef{"metcalfc", "brr", "pull", 7}, true},
+		{"https://github.com/metcalfc/brr/blob/main/README.md", GitHubRef{}, false},
+		{"https://github.com/metcalfc", GitHubRef{}, false},
+		{"https://gitlab.com/metcalfc/brr", GitHubRef{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseGitHubURL(tt.in)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseGitHubURL(%q) = %+v, %v; want %+v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func githubServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path + "?" + r.URL.RawQuery {
+		case "/repos/o/r/readme?":
+			if r.Header.Get("Accept") != "application/vnd.github.raw" {
+				t.Errorf("readme Accept = %q", r.Header.Get("Accept"))
+			}
+			w.Write([]byte("# r\n\nA **tool**.\n\n## Usage\nRun `r`.\n"))
+		case "/repos/o/r/issues/5?":
+			w.Write([]byte(`{"title":"RFC: speed","body":"Proposal with [details](http://x).","user":{"login":"amy"}}`))
+		case "/repos/o/r/pulls/5?":
+			w.Write([]byte(`{"title":"Add speed","body":"Implements the RFC.","user":{"login":"amy"}}`))
+		case "/repos/o/r/issues/5/comments?per_page=100":
+			w.Header().Set("Link", `<`+srv.URL+`/repos/o/r/issues/5/comments?page=2>; rel="next", <x>; rel="last"`)
+			w.Write([]byte(`[{"body":"+1","user":{"login":"bo"}}]`))
+		case "/repos/o/r/issues/5/comments?page=2":
+			w.Write([]byte(`[{"body":"> +1\nAgreed.","user":{"login":"cy"}}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	return srv
+}
+
+func TestFetchGitHub(t *testing.T) {
+	srv := githubServer(t)
+	defer srv.Close()
+	orig := GitHubAPI
+	GitHubAPI = srv.URL
+	defer func() { GitHubAPI = orig }()
+
+	title, sections, err := FetchGitHubContext(context.Background(), GitHubRef{Owner: "o", Repo: "r", Kind: "issue", Number: 5}, 0)
+	if err != nil {
+		t.Fatalf("FetchGitHubContext: %v", err)
+	}
+	if title != "#5 RFC: speed" {
+		t.Errorf("title = %q", title)
+	}
+	want := []Section{
+		{Title: "#5 RFC: speed", Text: "Proposal with details.", Level: 0},
+		{Title: "Comment by bo", Text: "+1", Level: 1},
+		{Title: "Comment by cy", Text: "+1\nAgreed.", Level: 1},
+	}
+	if len(sections) != len(want) {
+		t.Fatalf("got %+v, want %+v", sections, want)
+	}
+	for i := range want {
+		if sections[i] != want[i] {
+			t.Errorf("section %d = %+v, want %+v", i, sections[i], want[i])
+		}
+	}
+
+	_, sections, err = FetchGitHubContext(context.Background(), GitHubRef{Owner: "o", Repo: "r", Kind: "pull", Number: 5}, 0)
+	if err != nil || sections[0].Text != "Implements the RFC." {
+		t.Errorf("pull request: %+v, %v", sections, err)
+	}
+
+	title, sections, err = FetchGitHubContext(context.Background(), GitHubRef{Owner: "o", Repo: "r", Kind: "repo"}, 0)
+	if err != nil {
+		t.Fatalf("FetchGitHubContext readme: %v", err)
+	}
+	if title != "o/r" || len(sections) != 2 || sections[0].Text != "A tool." || sections[1].Title != "Usage" {
+		t.Errorf("readme = %q %+v", title, sections)
+	}
+
+	if _, _, err := FetchGitHubContext(context.Background(), GitHubRef{Owner: "o", Repo: "missing", Kind: "repo"}, 0); err == nil {
+		t.Error("expected error for missing repository")
+	}
+
+	issue := GitHubRef{Owner: "o", Repo: "r", Kind: "issue", Number: 5}
+	if _, _, err := FetchGitHubContext(context.Background(), issue, 100); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("an issue over the limit should fail with ErrTooLarge, got %v", err)
+	}
+	if _, sections, err := FetchGitHubContext(context.Background(), issue, 1000); err != nil || len(sections) != 3 {
+		t.Errorf("an issue within the limit should be read whole, got %+v, %v", sections, err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := FetchGitHubContext(ctx, issue, 0); !errors.Is(err, context.Canceled) {
+		t.Errorf("a cancelled fetch should fail, got %v", err)
+	}
+}
+
+func TestFetchReleases(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/o/r/releases" || r.URL.Query().Get("per_page") != "2" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[
+			{"name":"","tag_name":"v3.0.0","draft":true,"body":"wip"},
+			{"name":"Big one","tag_name":"v2.0.0","published_at":"2024-05-01T10:00:00Z","body":"## Changes\n- **Breaking**: new API"},
+			{"name":"","tag_name":"v1.1.0","published_at":"2024-03-01T10:00:00Z","body":""},
+			{"name":"","tag_name":"v1.0.0","published_at":"2024-01-01T10:00:00Z","body":"First"}]`))
+	}))
+	defer srv.Close()
+	orig := GitHubAPI
+	GitHubAPI = srv.URL
+	defer func() { GitHubAPI = orig }()
+
+	var read []Section
+	sections, err := FetchReleasesContext(context.Background(), "o", "r", 2, func(s Section) { read = append(read, s) })
+	if err != nil {
+		t.Fatalf("FetchReleasesContext: %v", err)
+	}
+	want := []Section{
+		{Title: "Big one (2024-05-01)", Text: "Changes\nBreaking: new API"},
+		{Title: "v1.1.0 (2024-03-01)", Text: "No release notes."},
+	}
+	if len(sections) != len(want) {
+		t.Fatalf("got %+v, want %+v", sections, want)
+	}
+	for i := range want {
+		if sections[i] != want[i] {
+			t.Errorf("release %d = %+v, want %+v", i, sections[i], want[i])
+		}
+	}
+	if !slices.Equal(read, want) {
+		t.Errorf("each saw %+v, want %+v", read, want)
+	}
+}
@@ -0,0 +1,64 @@
+package reader
+
+// maxHistory bounds how many positions Back can return to.
+const maxHistory = 100
+
+// history holds the absolute positions left by jumps, for Back, and those
+// left by Back, for Forward.
+type history struct {
+	back, forward []int
+}
+
+// Jump moves to the word at idx as a navigation that Back can undo, such
+// as choosing a table of contents entry.
+func (r *Reader) Jump(idx int) {
+	if idx < 0 || idx >= len(r.Words) {
+		return
+	}
+	if idx != r.CurrentIndex {
+		r.remember()
+	}
+	r.JumpToChapter(idx)
+}
+
+// remember records the current position before a jump. Positions undone
+// with Back are forgotten, as a new jump starts a new path forward.
+func (r *Reader) remember() {
+	pos := r.Position()
+	if n := len(r.history.back); n == 0 || r.history.back[n-1] != pos {
+		r.history.back = append(r.history.back, pos)
+		if len(r.history.back) > maxHistory {
+			r.history.back = r.history.back[1:]
+		}
+	}
+	r.history.forward = nil
+}
+
+// Back returns to the position before the last jump, reporting whether
+// there was one to return to.
+func (r *Reader) Back() bool {
+	return r.retrace(&r.history.back, &r.history.forward)
+}
+
+// Forward redoes the last jump undone with Back, reporting whether there
+// was one.
+func (r *Reader) Forward() bool {
+	return r.retrace(&r.history.forward, &r.history.back)
+}
+
+// retrace moves to the newest position in from, recording the current one
+// in to. Positions no longer reachable, such as those trimmed from a
+// stream, are dropped.
+func (r *Reader) retrace(from, to *[]int) bool {
+	cur := r.Position()
+	for len(*from) > 0 {
+		pos := (*from)[len(*from)-1]
+		*from = (*from)[:len(*from)-1]
+		if pos == cur || !r.SeekTo(pos) {
+			continue
+		}
+		*to = append(*to, cur)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package reader
+
+import "testing"
+
+func TestHistory(t *testing.T) {
+	r := NewReader("One two. Three four. Five six. Seven eight.", 300)
+	if r.Back() || r.Forward() {
+		t.Fatal("Back or Forward moved with no history")
+	}
+
+	r.JumpToNextSentence() // 2
+	r.Jump(6)
+	r.Search("four") // 3
+	steps := []struct {
+		name string
+		step func() bool
+		ok   bool
+		want int
+	}{
+		{"back", r.Back, true, 6},
+		{"back", r.Back, true, 2},
+		{"back", r.Back, true, 0},
+		{"back at start", r.Back, false, 0},
+		{"forward", r.Forward, true, 2},
+		{"forward", r.Forward, true, 6},
+		{"forward", r.Forward, true, 3},
+		{"forward at end", r.Forward, false, 3},
+	}
+	for i, s := range steps {
+		if ok := s.step(); ok != s.ok || r.CurrentIndex != s.want {
+			t.Errorf("step %d (%s): ok=%v at word %d, want ok=%v at word %d", i, s.name, ok, r.CurrentIndex, s.ok, s.want)
+		}
+	}
+
+	// A new jump after going back forgets the way forward.
+	r.Back()
+	r.Back()
+	r.Jump(7)
+	if r.Forward() {
+		t.Errorf("Forward after a new jump moved to word %d", r.CurrentIndex)
+	}
+	if !r.Back() || r.CurrentIndex != 2 {
+		t.Errorf("Back after a new jump at word %d, want 2", r.CurrentIndex)
+	}
+}
+
+func TestHistoryIgnoresReading(t *testing.T) {
+	r := NewReader("One two. Three four. Five six.", 300)
+	r.RewindSentences = 1
+	r.Jump(4)
+	for r.Advance() {
+	}
+	r.Pause()
+	r.resume(r.pausedAt.Add(DefaultRewindAfter))
+	if !r.Back() || r.CurrentIndex != 0 {
+		t.Errorf("Back at word %d, want 0: reading and rewinding are not jumps", r.CurrentIndex)
+	}
+	if r.Back() {
+		t.Errorf("second Back moved to word %d", r.CurrentIndex)
+	}
+}
+
+func TestHistoryLimit(t *testing.T) {
+	r := NewReader("a b c d e f g h i j", 300)
+	for i := range maxHistory + 20 {
+		r.Jump((i + 1) % 10)
+	}
+	if n := len(r.history.back); n != maxHistory {
+		t.Errorf("history holds %d positions, want %d", n, maxHistory)
+	}
+}
@@ -0,0 +1,31 @@
+package reader
+
+import (
+	"os"
+	"strings"
+)
+
+// HTMLFormat implements Format for saved web pages, such as reference
+// manager snapshots, keeping the article text and dropping page chrome.
+type HTMLFormat struct{}
+
+func init() {
+	Register(&HTMLFormat{})
+}
+
+func (f *HTMLFormat) Name() string         { return "HTML" }
+func (f *HTMLFormat) Extensions() []string { return []string{".html", ".htm", ".xhtml"} }
+
+func (f *HTMLFormat) Extract(filename string) (string, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return "", err
+	}
+	return ExtractHTML(DecodeText(data)), nil
+}
+
+// ExtractHTML returns the article text of an HTML page, one paragraph per
+// block, without scripts, navigation and repeated boilerplate.
+func ExtractHTML(page string) string {
+	return strings.Join(DedupeBlocks(extractArticleBlocks(page)), "\n\n")
+}
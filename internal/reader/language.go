@@ -0,0 +1,139 @@
+package reader
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// sentenceTerminators end a sentence in every language: the full stop,
+// exclamation and question marks, their full-width Chinese and Japanese
+// forms, and the Arabic question mark, Devanagari danda and Ethiopic and
+// Armenian full stops.
+const sentenceTerminators = ".!?‼⁇⁈⁉。！？．｡؟।॥።։"
+
+// defaultORPFraction places the ORP of a long word a third of the way in,
+// as suits English and most languages written in Latin letters.
+const defaultORPFraction = 1.0 / 3
+
+// orpFraction returns how far into a long word starting with first its
+// ORP lies. Arabic, Persian and Hebrew join articles, prepositions and
+// conjunctions to the word after them, so the stem lies further in. Each
+// Hangul block is a syllable, so Korean words are short and dense, and
+// focused in the middle.
+func orpFraction(first rune) float64 {
+	switch {
+	case unicode.In(first, unicode.Arabic, unicode.Hebrew):
+		return 0.4
+	case unicode.Is(unicode.Hangul, first):
+		return 0.5
+	}
+	return defaultORPFraction
+}
+
+// sentenceOpeners begin a question or exclamation in Spanish, and join
+// the word after them when set apart from it by a space. Only Spanish
+// writes them, so they are joined whatever the language.
+const sentenceOpeners = "¿¡"
+
+// Language is how text in a language is read where it differs from
+// English. The zero Language reads text as English is read.
+type Language struct {
+	// terminators end a sentence in this language, besides
+	// sentenceTerminators.
+	terminators string
+}
+
+// languagesByCode holds the languages read differently from English, and
+// those that need nothing besides what their script brings: Chinese and
+// Japanese punctuation ends sentences whatever the language, Spanish
+// opening marks are joined to their words, and the ORP of Arabic, Hebrew
+// and Hangul words is placed by script; see GetORPPosition.
+var languagesByCode = map[string]Language{
+	"ar": {},
+	"fa": {},
+	"he": {},
+	// The Greek question mark is the semicolon.
+	"el": {terminators: ";"},
+	"es": {},
+	"ja": {},
+	"ko": {},
+	"zh": {},
+}
+
+// LanguageFor returns the Language of a code such as "es" or "pt-BR",
+// reporting whether the language is known. Unknown languages are read as
+// English is.
+func LanguageFor(code string) (Language, bool) {
+	base, _, _ := strings.Cut(strings.ToLower(code), "-")
+	l, ok := languagesByCode[base]
+	_, stopwords := stopwordLists[base]
+	return l, ok || stopwords
+}
+
+// Languages lists the language codes accepted by LanguageFor.
+func Languages() []string {
+	langs := StopwordLanguages()
+	for lang := range languagesByCode {
+		if !slices.Contains(langs, lang) {
+			langs = append(langs, lang)
+		}
+	}
+	slices.Sort(langs)
+	return langs
+}
+
+// SetLanguage reads the text as written in lang: its sentences end, and
+// are paced, at that language's punctuation. The sentences it ends in the
+// words already read are added, and words read later are parsed in it.
+func (r *Reader) SetLanguage(lang Language) {
+	r.language = lang
+	if lang.terminators == "" {
+		return
+	}
+	starts := slices.Clone(r.SentenceStarts)
+	for i := 1; i < len(r.Words); i++ {
+		if lang.sentenceBreak(r.Words[i-1], r.Words[i]) {
+			starts = append(starts, i)
+		}
+	}
+	slices.Sort(starts)
+	r.SentenceStarts = slices.Compact(starts)
+}
+
+// endsSentence reports whether word ends with sentence punctuation in any
+// language; see Language.endsSentence.
+func endsSentence(word string) bool {
+	return Language{}.endsSentence(word)
+}
+
+// endsSentence reports whether word ends with sentence punctuation, before
+// any closing quotes and brackets. Whether a sentence really ends there
+// depends on the word after it too; see sentenceBreak.
+func (l Language) endsSentence(word string) bool {
+	last, size := utf8.DecodeLastRuneInString(strings.TrimRight(word, sentenceClosers))
+	if size == 0 {
+		return false
+	}
+	return strings.ContainsRune(sentenceTerminators, last) || strings.ContainsRune(l.terminators, last)
+}
+
+// joinOpeners joins each field made only of opening marks, such as a
+// Spanish ¿ set apart by a space, to the field after it.
+func joinOpeners(fields []string) []string {
+	out := fields[:0]
+	pending := ""
+	for _, f := range fields {
+		if strings.Trim(f, sentenceOpeners) == "" {
+			pending += f
+			continue
+		}
+		out = append(out, pending+f)
+		pending = ""
+	}
+	if pending != "" {
+		out = append(out, pending)
+	}
+	return out
+}
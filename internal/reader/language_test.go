@@ -0,0 +1,140 @@
+package reader
+
+import (
+	"slices"
+	"testing"
+)
+
+// language returns the Language of code, failing the test if it is unknown.
+func language(t *testing.T, code string) Language {
+	t.Helper()
+	lang, ok := LanguageFor(code)
+	if !ok {
+		t.Fatalf("LanguageFor(%q) failed", code)
+	}
+	return lang
+}
+
+func TestLanguages(t *testing.T) {
+	langs := Languages()
+	for _, want := range []string{"de", "el", "en", "es", "ja"} {
+		if !slices.Contains(langs, want) {
+			t.Errorf("Languages() = %v, missing %s", langs, want)
+		}
+	}
+	if !slices.IsSorted(langs) {
+		t.Errorf("Languages() = %v, want them sorted", langs)
+	}
+	if _, ok := LanguageFor("de-AT"); !ok {
+		t.Error("regional codes should use the base language's rules")
+	}
+	if _, ok := LanguageFor("xx"); ok {
+		t.Error("unknown languages should be reported")
+	}
+}
+
+func TestJapaneseSentences(t *testing.T) {
+	r := NewReader("これはペンです。次の文！最後？終わり", 300)
+	want := []string{"これは", "ペンです。", "次の", "文！", "最後？", "終わり"}
+	if !slices.Equal(r.Words, want) {
+		t.Fatalf("Words = %q, want %q", r.Words, want)
+	}
+	if starts := []int{0, 2, 4, 5}; !slices.Equal(r.SentenceStarts, starts) {
+		t.Errorf("SentenceStarts = %v, want %v", r.SentenceStarts, starts)
+	}
+}
+
+func TestSpanishOpeners(t *testing.T) {
+	r := NewReader("Hola. ¿ Qué hora es? ¡Ya voy!", 300)
+	want := []string{"Hola.", "¿Qué", "hora", "es?", "¡Ya", "voy!"}
+	if !slices.Equal(r.Words, want) {
+		t.Fatalf("Words = %q, want %q", r.Words, want)
+	}
+	if starts := []int{0, 1, 4}; !slices.Equal(r.SentenceStarts, starts) {
+		t.Errorf("SentenceStarts = %v, want %v", r.SentenceStarts, starts)
+	}
+	if got := GetORPPosition("¿Qué"); got != 2 {
+		t.Errorf("GetORPPosition(¿Qué) = %d, want 2, past the opening mark", got)
+	}
+}
+
+func TestGreekQuestionMark(t *testing.T) {
+	text := "Τι ώρα είναι; Είναι αργά."
+	r := NewReader(text, 300)
+	r.Pacer = PunctuationPacer{}
+	if !slices.Equal(r.SentenceStarts, []int{0}) {
+		t.Errorf("in English a semicolon should not end a sentence, got starts %v", r.SentenceStarts)
+	}
+	if got, want := r.Pacer.NextDelay(r, 2), scaleDelay(r.GetDelay(), ClauseEndFactor); got != want {
+		t.Errorf("in English a semicolon should pause as a clause: %v, want %v", got, want)
+	}
+
+	r.SetLanguage(language(t, "el"))
+	if !slices.Equal(r.SentenceStarts, []int{0, 3}) {
+		t.Errorf("SentenceStarts = %v, want [0 3]", r.SentenceStarts)
+	}
+	if got, want := r.Pacer.NextDelay(r, 2), scaleDelay(r.GetDelay(), SentenceEndFactor); got != want {
+		t.Errorf("in Greek a semicolon should pause as a sentence end: %v, want %v", got, want)
+	}
+	r.Incomplete = true
+	r.Append("Τι; Τίποτα.", nil, nil)
+	if !slices.Equal(r.SentenceStarts, []int{0, 3, 5, 6}) {
+		t.Errorf("text added should be parsed in Greek, got starts %v", r.SentenceStarts)
+	}
+	if other := NewReader(text, 300); !slices.Equal(other.SentenceStarts, []int{0}) {
+		t.Errorf("another reader should still read English, got starts %v", other.SentenceStarts)
+	}
+}
+
+func TestScriptORP(t *testing.T) {
+	for _, tt := range []struct {
+		word string
+		want int
+	}{
+		{"recognition", 3},
+		{"והמשפחותיהם", 4}, // and the families: two prefixes before the stem
+		{"والمستشفيات", 4}, // and the hospitals
+		{"대한민국사람", 3},
+	} {
+		if got := GetORPPosition(tt.word); got != tt.want {
+			t.Errorf("GetORPPosition(%s) = %d, want %d", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestGetORPPosition(t *testing.T) {
+	tests := []struct {
+		name     string
+		word     string
+		expected int
+	}{
+		{"single char", "a", 0},
+		{"two chars", "ab", 1},
+		{"three chars", "abc", 1},
+		{"four chars", "abcd", 1},
+		{"five chars", "abcde", 1},
+		{"six chars", "abcdef", 2},
+		{"nine chars", "abcdefghi", 3},
+		{"twelve chars", "abcdefghijkl", 4},
+		{"trailing comma", "said,", 1},
+		{"quoted", "\"hello\"", 2},
+		{"parenthesized quote", "(\"hello\")", 3},
+		{"long word with period", "recognition.", 3},
+		{"inner apostrophe", "don't", 1},
+		{"punctuation only", "...", 1},
+		{"single ideograph", "日", 0},
+		{"two ideographs", "東京", 0},
+		{"three ideographs", "日本語", 1},
+		{"ideographs with kana", "勉強します。", 2},
+		{"empty string", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GetORPPosition(tt.word)
+			if result != tt.expected {
+				t.Errorf("GetORPPosition(%q) = %v, want %v", tt.word, result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package reader
+
+// ChapterProvider supplies a document's words a chapter at a time, so a
+// Reader can parse chapters as it reaches them rather than all up front.
+type ChapterProvider interface {
+	// Len returns the number of chapters.
+	Len() int
+	// Chapter returns the title and words of chapter i.
+	Chapter(i int) (title string, words []string, err error)
+	// Size returns the size of chapter i in bytes before it is parsed,
+	// for estimating the document's length.
+	Size(i int) int64
+}
+
+// ChapterTitler is implemented by chapter providers that know the titles
+// of their chapters before parsing them, giving a Reader over them a table
+// of contents from the start.
+type ChapterTitler interface {
+	// Title returns the title of chapter i.
+	Title(i int) string
+}
+
+// ChapterOpener is an optional interface for formats that can be read a
+// chapter at a time, for documents too large to extract whole up front.
+type ChapterOpener interface {
+	// OpenChapterProvider opens filename for NewChapterReader. Close the
+	// result when done with it if it is an io.Closer.
+	OpenChapterProvider(filename string) (ChapterProvider, error)
+}
+
+// NewChapterReader creates a Reader that parses p's chapters on demand:
+// the current one, the next as the reader nears its end, and the one
+// before, kept for backward sentence jumps. Chapters further behind are
+// dropped from memory, and read again if JumpToTOC goes back to them. A
+// chapter that fails to parse ends the text, and Err reports why.
+//
+// As with NewStreamReader, the total word count is an estimate until the
+// last chapter is parsed. If p is a ChapterTitler, the table of contents
+// lists every chapter; an entry's WordIndex is -1 until its chapter is
+// parsed, so go to entries with JumpToTOC.
+func NewChapterReader(p ChapterProvider, wpm int) *Reader {
+	r := &Reader{WPM: wpm, chapters: p, chapterStarts: make([]int, p.Len())}
+	titles, _ := p.(ChapterTitler)
+	for i := range p.Len() {
+		r.sizeHint += p.Size(i)
+		r.chapterStarts[i] = -1
+		if titles != nil {
+			r.TOC = append(r.TOC, TOCEntry{Title: titles.Title(i), WordIndex: -1})
+		}
+	}
+	r.fill()
+	r.updateCurrentChapter()
+	return r
+}
+
+// fillChapters parses chapters until at least streamChunk words follow the
+// current word.
+func (r *Reader) fillChapters() {
+	for r.nextChapter < r.chapters.Len() && len(r.Words)-r.CurrentIndex <= streamChunk {
+		i := r.nextChapter
+		r.nextChapter++
+		title, words, err := r.chapters.Chapter(i)
+		if err != nil {
+			r.err = err
+			r.nextChapter = r.chapters.Len()
+			return
+		}
+		if i >= r.parsed {
+			// A chapter read again is counted once.
+			r.parsedBytes += r.chapters.Size(i)
+			r.parsedWords += len(words)
+			r.parsed = i + 1
+		}
+		start := len(r.Words)
+		r.chapterStarts[i] = r.Base + start
+		if i < len(r.TOC) {
+			r.TOC[i].WordIndex = r.Base + start
+		}
+		if len(words) == 0 {
+			continue
+		}
+		if start > 0 && !r.language.sentenceBreak(r.Words[start-1], words[0]) {
+			// A chapter starts a sentence, however the last one ended.
+			r.SentenceStarts = append(r.SentenceStarts, start)
+		}
+		r.Chapters = append(r.Chapters, Chapter{Title: title, WordStart: start, WordEnd: start + len(words) - 1})
+		r.appendWords(words)
+	}
+}
+
+// Err returns the error that ended a document read lazily before its end,
+// or nil.
+func (r *Reader) Err() error {
+	if r.stream != nil {
+		return r.stream.Err()
+	}
+	return r.err
+}
+
+// JumpToTOC jumps to where TOC entry i starts, recording the jump for
+// Back, and reports whether it could. A document read a chapter at a time
+// is parsed as far as the entry's chapter first.
+func (r *Reader) JumpToTOC(i int) bool {
+	if i < 0 || i >= len(r.TOC) {
+		return false
+	}
+	if r.chapters == nil {
+		idx := r.TOC[i].WordIndex
+		if idx < 0 || idx >= len(r.Words) {
+			return false
+		}
+		r.Jump(idx)
+		return true
+	}
+	if r.chapterStarts[i] != r.Position() {
+		r.remember()
+	}
+	return r.seekChapter(i)
+}
+
+// SampleTOC returns up to n words from where TOC entry i starts, within
+// its chapter, without moving. A chapter of a document read a chapter at
+// a time that is not in memory is parsed for it.
+func (r *Reader) SampleTOC(i, n int) []string {
+	if i < 0 || i >= len(r.TOC) {
+		return nil
+	}
+	if r.chapters == nil {
+		return r.Sample(r.TOC[i].WordIndex, n)
+	}
+	if pos := r.chapterStarts[i]; pos >= r.Base && pos-r.Base < len(r.Words) {
+		return r.Sample(pos-r.Base, n)
+	}
+	_, words, err := r.chapters.Chapter(i)
+	if err != nil {
+		return nil
+	}
+	return words[:min(n, len(words))]
+}
+
+// seekChapter moves to the first word of chapter i, parsing ahead to it,
+// or reading again from it if it has been dropped from memory.
+func (r *Reader) seekChapter(i int) bool {
+	for r.chapterStarts[i] < 0 && !r.sourceDone() {
+		r.CurrentIndex = max(len(r.Words)-1, 0)
+		r.fill()
+		r.trim()
+	}
+	pos := r.chapterStarts[i]
+	if pos < 0 {
+		return false
+	}
+	if pos < r.Base {
+		r.rewind(i)
+	}
+	return r.SeekTo(pos)
+}
+
+// rewind drops the words in memory and reads again from chapter i, which
+// has been parsed before. The chapters before it are kept, so they are
+// still counted and named.
+func (r *Reader) rewind(i int) {
+	pos := r.chapterStarts[i]
+	var kept []Chapter
+	for _, ch := range r.Chapters {
+		if r.Base+ch.WordStart >= pos {
+			break
+		}
+		ch.WordStart += r.Base - pos
+		ch.WordEnd += r.Base - pos
+		kept = append(kept, ch)
+	}
+	r.Chapters = kept
+	r.Words, r.SentenceStarts = nil, nil
+	r.Base, r.CurrentIndex, r.frame = pos, 0, 0
+	r.nextChapter = i
+	r.fill()
+	r.updateCurrentChapter()
+}
+
+// lazy reports whether words are read as the reader reaches them, from a
+// stream or a chapter at a time.
+func (r *Reader) lazy() bool {
+	return r.stream != nil || r.chapters != nil
+}
+
+// sourceDone reports whether every word has been read into the window.
+func (r *Reader) sourceDone() bool {
+	switch {
+	case r.stream != nil:
+		return r.stream.Done()
+	case r.chapters != nil:
+		return r.nextChapter >= r.chapters.Len()
+	}
+	return true
+}
@@ -0,0 +1,136 @@
+package reader
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// testChapters provides chapters of numbered words, recording which were
+// parsed.
+type testChapters struct {
+	texts  []string
+	parsed []int
+	fail   int
+}
+
+func (c *testChapters) Len() int         { return len(c.texts) }
+func (c *testChapters) Size(i int) int64 { return int64(len(c.texts[i])) }
+func (c *testChapters) Chapter(i int) (string, []string, error) {
+	if i == c.fail {
+		return "", nil, errors.New("unreadable")
+	}
+	c.parsed = append(c.parsed, i)
+	return fmt.Sprintf("Chapter %d", i+1), ParseText(c.texts[i]), nil
+}
+
+func TestChapterReader(t *testing.T) {
+	const per = 5000
+	c := &testChapters{fail: -1}
+	for range 4 {
+		c.texts = append(c.texts, numberedText(per))
+	}
+	r := NewChapterReader(c, 300)
+	if len(c.parsed) != 1 || !r.Streaming() {
+		t.Fatalf("opening should parse only the first chapter, parsed %v", c.parsed)
+	}
+	if _, total := r.Progress(); total != 4*per {
+		t.Errorf("estimated total = %d, want %d", total, 4*per)
+	}
+
+	if !r.SeekTo(per - 100) {
+		t.Fatal("SeekTo the end of chapter 1 failed")
+	}
+	r.Advance()
+	if len(c.parsed) != 2 {
+		t.Errorf("nearing the end of a chapter should parse the next, parsed %v", c.parsed)
+	}
+
+	if !r.SeekTo(2*per + 10) {
+		t.Fatal("SeekTo chapter 3 failed")
+	}
+	if r.Base != per || r.CurrentChapterTitle() != "Chapter 3" {
+		t.Errorf("in chapter 3, window starts at %d in %q; want chapter 2 kept from %d", r.Base, r.CurrentChapterTitle(), per)
+	}
+	if cur, n := r.ChapterProgress(); cur != 11 || n != per {
+		t.Errorf("ChapterProgress() = %d/%d, want 11/%d", cur, n, per)
+	}
+	r.JumpToChapter(r.Chapters[2].WordStart)
+	r.JumpToPrevSentence()
+	if r.Position() != 2*per-10 {
+		t.Errorf("previous sentence at %d, want %d in chapter 2", r.Position(), 2*per-10)
+	}
+
+	for r.Advance() {
+	}
+	if !r.AtEnd() || r.Position() != 4*per-1 || len(c.parsed) != 4 {
+		t.Errorf("at the end: position %d, parsed %v", r.Position(), c.parsed)
+	}
+	if r.SeekTo(0) {
+		t.Error("SeekTo a dropped chapter should fail")
+	}
+}
+
+// titledChapters is testChapters knowing its titles up front.
+type titledChapters struct{ testChapters }
+
+func (c *titledChapters) Title(i int) string { return fmt.Sprintf("Chapter %d", i+1) }
+
+func TestChapterReaderTOC(t *testing.T) {
+	const per = 5000
+	c := &titledChapters{testChapters{fail: -1}}
+	for range 4 {
+		c.texts = append(c.texts, numberedText(per))
+	}
+	r := NewChapterReader(c, 300)
+	if len(r.TOC) != 4 || r.TOC[3].Title != "Chapter 4" || r.TOC[3].WordIndex != -1 {
+		t.Fatalf("TOC = %+v, want every chapter listed before it is parsed", r.TOC)
+	}
+	if got := r.SampleTOC(2, 3); strings.Join(got, " ") != "w00000 w00001 w00002" {
+		t.Errorf("SampleTOC(2) = %q", got)
+	}
+	if !r.JumpToTOC(2) || r.Position() != 2*per || r.CurrentChapterTitle() != "Chapter 3" {
+		t.Fatalf("JumpToTOC(2) at %d in %q", r.Position(), r.CurrentChapterTitle())
+	}
+	if r.TOC[2].WordIndex != 2*per {
+		t.Errorf("parsed entry WordIndex = %d, want %d", r.TOC[2].WordIndex, 2*per)
+	}
+	if !r.JumpToTOC(3) || r.Base <= 0 {
+		t.Fatalf("JumpToTOC(3) should drop the first chapters, window at %d", r.Base)
+	}
+
+	// Going back to a dropped chapter reads it again.
+	parsed := len(c.parsed)
+	if !r.JumpToTOC(0) || r.Position() != 0 || r.CurrentChapterTitle() != "Chapter 1" || len(c.parsed) == parsed {
+		t.Fatalf("JumpToTOC(0) at %d in %q", r.Position(), r.CurrentChapterTitle())
+	}
+	if _, total := r.Progress(); total != 4*per {
+		t.Errorf("total after reading chapters again = %d, want %d", total, 4*per)
+	}
+	if !r.Back() || r.Position() != 3*per {
+		t.Errorf("Back should return to chapter 4, at %d", r.Position())
+	}
+	if len(r.Chapters) != 4 || r.Chapters[3].Title != "Chapter 4" {
+		t.Errorf("chapters = %+v", r.Chapters)
+	}
+}
+
+func TestChapterReaderStartsSentences(t *testing.T) {
+	c := &testChapters{texts: []string{"no full stop", "next chapter.", "lost", "unread"}, fail: 2}
+	r := NewChapterReader(c, 300)
+	if strings.Join(r.Words, " ") != "no full stop next chapter." {
+		t.Errorf("words = %q, want to stop at the unreadable chapter", r.Words)
+	}
+	if r.Err() == nil {
+		t.Error("Err should report the unreadable chapter")
+	}
+	if len(r.SentenceStarts) != 2 || r.SentenceStarts[1] != 3 {
+		t.Errorf("sentence starts = %v, want the second chapter to start one", r.SentenceStarts)
+	}
+	for r.Advance() {
+	}
+	if !r.AtEnd() || r.CurrentWord() != "chapter." {
+		t.Errorf("an unreadable chapter should end the text, at %q", r.CurrentWord())
+	}
+}
@@ -0,0 +1,127 @@
+package reader
+
+import (
+	"net/url"
+	"strings"
+)
+
+// LinkKind tells URLs and email addresses apart from ordinary words.
+type LinkKind int
+
+const (
+	// LinkNone is an ordinary word.
+	LinkNone LinkKind = iota
+	// LinkURL is a web address such as https://example.com/page.
+	LinkURL
+	// LinkEmail is an email address.
+	LinkEmail
+)
+
+// LinkPolicy controls how URLs and email addresses are shown.
+type LinkPolicy int
+
+const (
+	// LinksFull shows links whole, for longer than other words.
+	LinksFull LinkPolicy = iota
+	// LinksDomain shows only a link's domain.
+	LinksDomain
+	// LinksPlaceholder shows [link] or [email] in place of a link.
+	LinksPlaceholder
+	// LinksSkip leaves links out.
+	LinksSkip
+)
+
+var linkPolicyNames = []string{"full", "domain", "placeholder", "skip"}
+
+// LinkDelayFactor multiplies the delay of links shown with LinksFull.
+const LinkDelayFactor = 2
+
+func (p LinkPolicy) String() string {
+	if p < 0 || int(p) >= len(linkPolicyNames) {
+		return "full"
+	}
+	return linkPolicyNames[p]
+}
+
+// LinkPolicyNames lists the policies accepted by ParseLinkPolicy.
+func LinkPolicyNames() []string {
+	return linkPolicyNames
+}
+
+// ParseLinkPolicy parses "full", "domain", "placeholder" or "skip".
+func ParseLinkPolicy(s string) (LinkPolicy, bool) {
+	for i, name := range linkPolicyNames {
+		if strings.EqualFold(s, name) {
+			return LinkPolicy(i), true
+		}
+	}
+	return LinksFull, false
+}
+
+// linkKind classifies a word's core as a URL, an email address or neither.
+func linkKind(core string) LinkKind {
+	lower := strings.ToLower(core)
+	for _, prefix := range []string{"http://", "https://", "ftp://", "www."} {
+		if strings.HasPrefix(lower, prefix) && len(lower) > len(prefix) {
+			return LinkURL
+		}
+	}
+	if strings.HasPrefix(lower, "mailto:") {
+		return LinkEmail
+	}
+	local, domain, ok := strings.Cut(core, "@")
+	if ok && local != "" && !strings.ContainsAny(domain, "@/") && strings.Contains(strings.Trim(domain, "."), ".") {
+		return LinkEmail
+	}
+	return LinkNone
+}
+
+// linkDomain returns the domain of a URL or email address, without a
+// leading www.
+func linkDomain(core string, kind LinkKind) string {
+	if kind == LinkEmail {
+		_, domain, _ := strings.Cut(strings.TrimPrefix(core, "mailto:"), "@")
+		return domain
+	}
+	raw := core
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+	host := core
+	if u, err := url.Parse(raw); err == nil && u.Hostname() != "" {
+		host = u.Hostname()
+	}
+	return strings.TrimPrefix(strings.ToLower(host), "www.")
+}
+
+// linkWord returns word as shown under policy p: whole, reduced to its
+// domain, or replaced by a placeholder, keeping surrounding punctuation.
+func linkWord(word string, p LinkPolicy) string {
+	runes := []rune(word)
+	start, end := CoreBounds(word)
+	core := string(runes[start:end])
+	kind := linkKind(core)
+	if kind == LinkNone {
+		return word
+	}
+	switch p {
+	case LinksDomain:
+		core = linkDomain(core, kind)
+	case LinksPlaceholder:
+		core = "[link]"
+		if kind == LinkEmail {
+			core = "[email]"
+		}
+	}
+	return string(runes[:start]) + core + string(runes[end:])
+}
+
+// isSkippedLink reports whether the word at idx is a link left out by the
+// reader's LinkPolicy.
+func (r *Reader) isSkippedLink(idx int) bool {
+	if r.LinkPolicy != LinksSkip || idx < 0 || idx >= len(r.Words) {
+		return false
+	}
+	start, end := CoreBounds(r.Words[idx])
+	return linkKind(string([]rune(r.Words[idx])[start:end])) != LinkNone
+}
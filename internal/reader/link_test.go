@@ -0,0 +1,74 @@
+package reader
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLinkWord(t *testing.T) {
+	tests := []struct {
+		word   string
+		kind   LinkKind
+		domain string
+		holder string
+	}{
+		{"word", LinkNone, "word", "word"},
+		{"https://www.Example.com/docs?q=1", LinkURL, "example.com", "[link]"},
+		{"(www.example.org/page).", LinkURL, "(example.org).", "([link])."},
+		{"ann@mail.example.com,", LinkEmail, "mail.example.com,", "[email],"},
+		{"mailto:bo@example.net", LinkEmail, "example.net", "[email]"},
+		{"@handle", LinkNone, "@handle", "@handle"},
+		{"a@b", LinkNone, "a@b", "a@b"},
+	}
+	for _, tt := range tests {
+		if got := NewToken(tt.word).Link; got != tt.kind {
+			t.Errorf("NewToken(%q).Link = %v, want %v", tt.word, got, tt.kind)
+		}
+		if got := linkWord(tt.word, LinksDomain); got != tt.domain {
+			t.Errorf("linkWord(%q, domain) = %q, want %q", tt.word, got, tt.domain)
+		}
+		if got := linkWord(tt.word, LinksPlaceholder); got != tt.holder {
+			t.Errorf("linkWord(%q, placeholder) = %q, want %q", tt.word, got, tt.holder)
+		}
+		if got := linkWord(tt.word, LinksFull); got != tt.word {
+			t.Errorf("linkWord(%q, full) = %q", tt.word, got)
+		}
+	}
+}
+
+func TestReaderLinkPolicy(t *testing.T) {
+	text := "see https://example.com/a and mail ann@example.com today"
+	tests := []struct {
+		policy LinkPolicy
+		want   []string
+	}{
+		{LinksFull, []string{"see", "https://example.com/a", "and", "mail", "ann@example.com", "today"}},
+		{LinksDomain, []string{"see", "example.com", "and", "mail", "example.com", "today"}},
+		{LinksPlaceholder, []string{"see", "[link]", "and", "mail", "[email]", "today"}},
+		{LinksSkip, []string{"see", "and", "mail", "today"}},
+	}
+	for _, tt := range tests {
+		r := NewReader(text, 300)
+		r.LinkPolicy = tt.policy
+		var shown []string
+		for {
+			shown = append(shown, r.CurrentWord())
+			if !r.Advance() {
+				break
+			}
+		}
+		if !reflect.DeepEqual(shown, tt.want) {
+			t.Errorf("%v: shown %q, want %q", tt.policy, shown, tt.want)
+		}
+	}
+
+	r := NewReader(text, 300)
+	r.JumpToChapter(1)
+	if got, want := r.WordDelay(), r.GetDelay()*LinkDelayFactor; got != want {
+		t.Errorf("WordDelay for a full link = %v, want %v", got, want)
+	}
+	r.LinkPolicy = LinksDomain
+	if got := r.WordDelay(); got != r.GetDelay() {
+		t.Errorf("WordDelay for a shortened link = %v, want %v", got, r.GetDelay())
+	}
+}
@@ -22,7 +22,7 @@ func (f *MarkdownFormat) Extract(filename string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return string(data), nil
+	return DecodeText(data), nil
 }
 
 var headerRegex = regexp.MustCompile(`^(#{1,6})\s+(.+)$`)
@@ -53,7 +53,7 @@ func (f *MarkdownFormat) TOC(filename string) ([]TOCEntry, error) {
 			})
 		}
 
-		words := strings.Fields(line)
+		words := ParseText(line)
 		wordCount += len(words)
 	}
 
@@ -91,7 +91,7 @@ func (f *MarkdownFormat) ExtractChapters(filename string) ([]Chapter, []string,
 			currentWords = nil
 		}
 
-		words := strings.Fields(line)
+		words := ParseText(line)
 		allWords = append(allWords, words...)
 		currentWords = append(currentWords, words...)
 	}
@@ -111,3 +111,86 @@ func (f *MarkdownFormat) ExtractChapters(filename string) ([]Chapter, []string,
 
 	return chapters, allWords, scanner.Err()
 }
+
+var (
+	mdImageRegex    = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	mdLinkRegex     = regexp.MustCompile(`\[([^\]]+)\]\([^)]*\)`)
+	mdRefLinkRegex  = regexp.MustCompile(`\[([^\]]+)\]\[[^\]]*\]`)
+	mdRefDefRegex   = regexp.MustCompile(`^\s*\[[^\]]+\]:\s+\S+`)
+	mdCommentRegex  = regexp.MustCompile(`(?s)<!--.*?-->`)
+	mdTagRegex      = regexp.MustCompile(`</?[a-zA-Z][^>]*>`)
+	mdListRegex     = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+(?:\[[ xX]\]\s+)?`)
+	mdRuleRegex     = regexp.MustCompile(`^\s*(?:[-*_]\s*){3,}$`)
+	mdEmphasisRegex = regexp.MustCompile("(\\*+|__|~~|`)")
+)
+
+// CleanMarkdown strips Markdown syntax that reads badly one word at a time:
+// code blocks, link targets, images, HTML, emphasis markers, list bullets
+// and table pipes. Line structure is kept.
+func CleanMarkdown(md string) string {
+	md = mdCommentRegex.ReplaceAllString(md, "")
+	var out []string
+	inFence := false
+	for _, line := range strings.Split(md, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence || mdRefDefRegex.MatchString(line) || mdRuleRegex.MatchString(line) {
+			continue
+		}
+		if match := headerRegex.FindStringSubmatch(trimmed); match != nil {
+			trimmed = match[2]
+		}
+		for strings.HasPrefix(trimmed, ">") {
+			trimmed = strings.TrimSpace(strings.TrimPrefix(trimmed, ">"))
+		}
+		trimmed = mdListRegex.ReplaceAllString(trimmed, "")
+		trimmed = mdImageRegex.ReplaceAllString(trimmed, "$1")
+		trimmed = mdLinkRegex.ReplaceAllString(trimmed, "$1")
+		trimmed = mdRefLinkRegex.ReplaceAllString(trimmed, "$1")
+		trimmed = mdTagRegex.ReplaceAllString(trimmed, "")
+		trimmed = mdEmphasisRegex.ReplaceAllString(trimmed, "")
+		if strings.HasPrefix(trimmed, "|") {
+			if strings.Trim(trimmed, "|-: ") == "" {
+				continue
+			}
+			trimmed = strings.Join(strings.Fields(strings.ReplaceAll(trimmed, "|", " ")), " ")
+		}
+		out = append(out, trimmed)
+	}
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
+
+// MarkdownSections splits a Markdown document into sections at its headers,
+// cleaning each with CleanMarkdown. Text before the first header becomes a
+// section titled title.
+func MarkdownSections(title, md string) []Section {
+	var sections []Section
+	cur := Section{Title: title}
+	var body []string
+	inFence := false
+	flush := func() {
+		cur.Text = CleanMarkdown(strings.Join(body, "\n"))
+		if strings.TrimSpace(cur.Text) != "" {
+			sections = append(sections, cur)
+		}
+		body = nil
+	}
+
+	for _, line := range strings.Split(md, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+		}
+		if match := headerRegex.FindStringSubmatch(line); match != nil && !inFence {
+			flush()
+			cur = Section{Title: CleanMarkdown(match[2]), Level: len(match[1]) - 1}
+			continue
+		}
+		body = append(body, line)
+	}
+	flush()
+	return sections
+}
@@ -157,3 +157,47 @@ Just paragraphs.
 		t.Error("Expected non-empty words")
 	}
 }
+
+func TestCleanMarkdown(t *testing.T) {
+	input := "# Title\n\n" +
+		"Some **bold** and *em* text with `code` and a [link](https://x.y/z).\n" +
+		"![logo](logo.png)\n" +
+		"<!-- hidden -->\n" +
+		"- [ ] task one\n" +
+		"1. first\n" +
+		"> quoted <b>html</b>\n\n" +
+		"```go\nfmt.Println(\"skip\")\n```\n" +
+		"| a | b |\n|---|---|\n| 1 | 2 |\n" +
+		"---\n" +
+		"[ref]: https://example.com\n"
+	want := "Title\n\n" +
+		"Some bold and em text with code and a link.\n" +
+		"logo\n\n" +
+		"task one\n" +
+		"first\n" +
+		"quoted html\n\n" +
+		"a b\n" +
+		"1 2"
+	if got := CleanMarkdown(input); got != want {
+		t.Errorf("CleanMarkdown() =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestMarkdownSections(t *testing.T) {
+	input := "Intro text.\n\n# Install\nRun it.\n\n```sh\n# not a header\n```\n## Options\nUse **flags**.\n# Empty\n"
+	sections := MarkdownSections("README", input)
+
+	want := []Section{
+		{Title: "README", Text: "Intro text.", Level: 0},
+		{Title: "Install", Text: "Run it.", Level: 0},
+		{Title: "Options", Text: "Use flags.", Level: 1},
+	}
+	if len(sections) != len(want) {
+		t.Fatalf("got %+v, want %+v", sections, want)
+	}
+	for i := range want {
+		if sections[i] != want[i] {
+			t.Errorf("section %d = %+v, want %+v", i, sections[i], want[i])
+		}
+	}
+}
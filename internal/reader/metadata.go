@@ -0,0 +1,40 @@
+package reader
+
+import "strings"
+
+// Metadata is the title and author a document records about itself.
+type Metadata struct {
+	Title  string
+	Author string
+	// Vertical is set for books typeset in vertical lines, such as
+	// Japanese novels.
+	Vertical bool
+}
+
+// MetadataProvider is an optional interface for formats that record their
+// title and author
+type MetadataProvider interface {
+	Metadata(filename string) (Metadata, error)
+}
+
+// String returns "Title by Author", or just the title when the author is
+// unknown.
+func (m Metadata) String() string {
+	if m.Author == "" {
+		return m.Title
+	}
+	return m.Title + " by " + m.Author
+}
+
+// cleanMetadata collapses the whitespace a metadata field was wrapped with.
+func cleanMetadata(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// joinAuthors lists authors as "A", "A and B" or "A, B and C".
+func joinAuthors(authors []string) string {
+	if len(authors) < 2 {
+		return strings.Join(authors, "")
+	}
+	return strings.Join(authors[:len(authors)-1], ", ") + " and " + authors[len(authors)-1]
+}
@@ -0,0 +1,79 @@
+package reader
+
+import (
+	"html"
+	"strings"
+)
+
+// ligatures maps typographic ligatures, common in text extracted from
+// PDFs and typeset EPUBs, to their letters.
+var ligatures = map[rune]string{
+	'ﬀ': "ff",
+	'ﬁ': "fi",
+	'ﬂ': "fl",
+	'ﬃ': "ffi",
+	'ﬄ': "ffl",
+	'ﬅ': "st",
+	'ﬆ': "st",
+}
+
+// smartQuotes maps typographic quotes to their plain forms.
+var smartQuotes = map[rune]rune{
+	'‘': '\'',
+	'’': '\'',
+	'‚': '\'',
+	'‛': '\'',
+	'“': '"',
+	'”': '"',
+	'„': '"',
+	'‟': '"',
+}
+
+// Normalize converts text to the forms words are shown in: HTML entities
+// left in the text are decoded, typographic quotes are made plain, soft
+// hyphens are dropped, ligatures are spelled out, and an em-dash between
+// words ends the first so the second is shown on its own. Quotes around
+// Chinese and Japanese are kept, as word breaking there relies on them.
+//
+// All text passes through Normalize as it is split into words, whatever
+// format it was extracted from.
+func Normalize(text string) string {
+	if strings.Contains(text, "&") {
+		text = html.UnescapeString(text)
+	}
+	if !strings.ContainsFunc(text, needsNormalizing) {
+		return text
+	}
+	runes := []rune(text)
+	var b strings.Builder
+	b.Grow(len(text))
+	for i, r := range runes {
+		switch {
+		case r == '\u00ad':
+			// A soft hyphen only marks where a word may break.
+		case ligatures[r] != "":
+			b.WriteString(ligatures[r])
+		case r == '—':
+			b.WriteRune(r)
+			if i+1 < len(runes) && isWordRune(runes[i+1]) && !isCJK(runes[i+1]) {
+				b.WriteByte(' ')
+			}
+		case smartQuotes[r] != 0 && !nextToCJK(runes, i):
+			b.WriteRune(smartQuotes[r])
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// needsNormalizing reports whether Normalize may change r.
+func needsNormalizing(r rune) bool {
+	return r == '\u00ad' || r == '—' || ligatures[r] != "" || smartQuotes[r] != 0
+}
+
+// nextToCJK reports whether the rune at i is beside a Chinese or Japanese
+// character.
+func nextToCJK(runes []rune, i int) bool {
+	return (i > 0 && isCJK(runes[i-1])) || (i+1 < len(runes) && isCJK(runes[i+1]))
+}
@@ -0,0 +1,39 @@
+package reader
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"plain", "Nothing to do here.", "Nothing to do here."},
+		{"entities", "Fish &amp; chips &mdash; &#8220;cheap&#8221;", `Fish & chips — "cheap"`},
+		{"quotes", "“It’s late,” she said. ‘Very.’", `"It's late," she said. 'Very.'`},
+		{"soft hyphens", "extra­ordinary", "extraordinary"},
+		{"ligatures", "ﬁrst ﬂoor eﬀect", "first floor effect"},
+		{"em-dash between words", "wait—what", "wait— what"},
+		{"em-dash before a quote", `going to—"`, `going to—"`},
+		{"CJK quotes kept", "他说“你好”。", "他说“你好”。"},
+		{"CJK em-dash", "等等——你好", "等等——你好"},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.in); got != tt.want {
+			t.Errorf("%s: Normalize(%q) = %q, want %q", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizedWords(t *testing.T) {
+	want := []string{"The", `"final"`, "word—", "really"}
+	if got := ParseText("The “ﬁnal” word—really"); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseText = %q, want %q", got, want)
+	}
+	r := NewStreamReader(strings.NewReader("The “ﬁnal” word—really"), 0, 300)
+	if !reflect.DeepEqual(r.Words, want) {
+		t.Errorf("streamed words = %q, want %q", r.Words, want)
+	}
+}
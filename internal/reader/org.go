@@ -0,0 +1,147 @@
+package reader
+
+import (
+	"regexp"
+	"strings"
+)
+
+// OrgFormat implements Format for Emacs Org-mode files, with a chapter for
+// each heading.
+type OrgFormat struct{}
+
+func init() {
+	Register(&OrgFormat{})
+}
+
+func (f *OrgFormat) Name() string         { return "Org" }
+func (f *OrgFormat) Extensions() []string { return []string{".org"} }
+
+func (f *OrgFormat) Extract(filename string) (string, error) {
+	sections, err := readSections(filename, OrgSections)
+	if err != nil {
+		return "", err
+	}
+	return sectionsText(sections), nil
+}
+
+// TOC lists the file's headings.
+func (f *OrgFormat) TOC(filename string) ([]TOCEntry, error) {
+	sections, err := readSections(filename, OrgSections)
+	if err != nil {
+		return nil, err
+	}
+	return SectionsTOC(sections), nil
+}
+
+// ExtractChapters extracts the text with a chapter per heading.
+func (f *OrgFormat) ExtractChapters(filename string) ([]Chapter, []string, error) {
+	sections, err := readSections(filename, OrgSections)
+	if err != nil {
+		return nil, nil, err
+	}
+	chapters, _, words := AssembleSections(sections)
+	return chapters, words, nil
+}
+
+var (
+	orgHeadingRegex  = regexp.MustCompile(`^(\*+)\s+(.*)$`)
+	orgKeywordRegex  = regexp.MustCompile(`^(?:TODO|DONE|NEXT|WAITING|HOLD|CANCELL?ED)\s+`)
+	orgPriorityRegex = regexp.MustCompile(`^\[#[A-Za-z0-9]\]\s*`)
+	orgTagsRegex     = regexp.MustCompile(`\s+:[\w@#%:]+:\s*$`)
+	orgTitleRegex    = regexp.MustCompile(`(?i)^#\+title:\s*(.+)$`)
+	orgDrawerRegex   = regexp.MustCompile(`^:[A-Za-z]+:$`)
+	orgLinkRegex     = regexp.MustCompile(`\[\[([^\]]+)\](?:\[([^\]]+)\])?\]`)
+	orgListRegex     = regexp.MustCompile(`^\s*(?:[-+]|\d+[.)])\s+(?:\[[ xX-]\]\s+)?`)
+	orgEmphasis      []*regexp.Regexp
+)
+
+func init() {
+	// Go regexps lack backreferences, so each marker gets its own pattern.
+	for _, marker := range []string{`\*`, `/`, `=`, `~`, `\+`, `_`} {
+		orgEmphasis = append(orgEmphasis, regexp.MustCompile(
+			`(^|[\s("'])`+marker+`([^\s`+marker+`](?:[^`+marker+`]*[^\s`+marker+`])?)`+marker+`([\s)"'.,;:!?]|$)`))
+	}
+}
+
+// OrgSections splits an Org document into sections at its headings, with
+// markup, blocks, drawers and keyword lines removed. TODO keywords,
+// priorities and tags are dropped from heading titles. Text before the
+// first heading becomes a section titled by #+TITLE, or title.
+func OrgSections(title, org string) []Section {
+	var sections []Section
+	cur := Section{Title: title}
+	var body []string
+	flush := func(keepEmpty bool) {
+		cur.Text = strings.Join(body, "\n")
+		if keepEmpty || strings.TrimSpace(cur.Text) != "" {
+			sections = append(sections, cur)
+		}
+		body = nil
+	}
+
+	inBlock, inDrawer, headed := false, false, false
+	for _, line := range strings.Split(org, "\n") {
+		trimmed := strings.TrimSpace(line)
+		upper := strings.ToUpper(trimmed)
+		switch {
+		case strings.HasPrefix(upper, "#+BEGIN"):
+			inBlock = !strings.HasPrefix(upper, "#+BEGIN_QUOTE") && !strings.HasPrefix(upper, "#+BEGIN_VERSE")
+			continue
+		case strings.HasPrefix(upper, "#+END"):
+			inBlock = false
+			continue
+		case inBlock:
+			continue
+		case inDrawer:
+			inDrawer = upper != ":END:"
+			continue
+		case orgDrawerRegex.MatchString(trimmed) && upper != ":END:":
+			inDrawer = true
+			continue
+		}
+
+		if m := orgHeadingRegex.FindStringSubmatch(line); m != nil {
+			flush(headed)
+			heading := orgKeywordRegex.ReplaceAllString(m[2], "")
+			heading = orgPriorityRegex.ReplaceAllString(heading, "")
+			heading = orgTagsRegex.ReplaceAllString(heading, "")
+			cur = Section{Title: cleanOrgLine(heading), Level: len(m[1]) - 1}
+			headed = true
+			continue
+		}
+		if m := orgTitleRegex.FindStringSubmatch(trimmed); m != nil && !headed {
+			cur.Title = cleanOrgLine(m[1])
+			continue
+		}
+		if strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "CLOSED:") ||
+			strings.HasPrefix(trimmed, "SCHEDULED:") || strings.HasPrefix(trimmed, "DEADLINE:") {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "|") {
+			if strings.Trim(trimmed, "|-+: ") == "" {
+				continue
+			}
+			trimmed = strings.Join(strings.Fields(strings.ReplaceAll(trimmed, "|", " ")), " ")
+		}
+		body = append(body, cleanOrgLine(orgListRegex.ReplaceAllString(trimmed, "")))
+	}
+	flush(headed)
+	return sections
+}
+
+// cleanOrgLine replaces links with their descriptions and strips emphasis
+// markers.
+func cleanOrgLine(s string) string {
+	s = orgLinkRegex.ReplaceAllStringFunc(s, func(link string) string {
+		m := orgLinkRegex.FindStringSubmatch(link)
+		if m[2] != "" {
+			return m[2]
+		}
+		return strings.TrimPrefix(m[1], "file:")
+	})
+	for _, re := range orgEmphasis {
+		// Adjacent spans share the space between them, so run twice.
+		s = re.ReplaceAllString(re.ReplaceAllString(s, "$1$2$3"), "$1$2$3")
+	}
+	return strings.TrimSpace(s)
+}
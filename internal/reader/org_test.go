@@ -0,0 +1,88 @@
+package reader
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOrgSections(t *testing.T) {
+	input := `#+TITLE: My *Notes*
+#+STARTUP: overview
+Preamble with a [[https://example.com][link]] and [[file:other.org]].
+
+* TODO [#A] Reading list                                     :books:
+:PROPERTIES:
+:ID: 1234
+:END:
+- [ ] Read /Dune/ again
+- Finish =brr= docs
+** Empty heading
+*** Deep
+Some ~code~ and +struck+ text.
+#+BEGIN_SRC go
+fmt.Println("skipped")
+#+END_SRC
+# a comment
+#+begin_quote
+Quoted words.
+#+end_quote
+| a | b |
+|---+---|
+| 1 | 2 |
+`
+	sections := OrgSections("notes", input)
+	want := []Section{
+		{Title: "My Notes", Text: "Preamble with a link and other.org.\n", Level: 0},
+		{Title: "Reading list", Text: "Read Dune again\nFinish brr docs", Level: 0},
+		{Title: "Empty heading", Text: "", Level: 1},
+		{Title: "Deep", Text: "Some code and struck text.\nQuoted words.\na b\n1 2\n", Level: 2},
+	}
+	if len(sections) != len(want) {
+		t.Fatalf("got %+v, want %d sections", sections, len(want))
+	}
+	for i := range want {
+		if sections[i] != want[i] {
+			t.Errorf("section %d = %+v, want %+v", i, sections[i], want[i])
+		}
+	}
+}
+
+func TestOrgFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.org")
+	content := "* Part One\n** Chapter 1\nFirst words here.\n** Chapter 2\nSecond words.\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	f := &OrgFormat{}
+	toc, err := f.TOC(path)
+	if err != nil {
+		t.Fatalf("TOC: %v", err)
+	}
+	wantTOC := []struct {
+		title string
+		index int
+		level int
+	}{{"Part One", 0, 0}, {"Chapter 1", 0, 1}, {"Chapter 2", 3, 1}}
+	if len(toc) != len(wantTOC) {
+		t.Fatalf("got %d TOC entries, want %d: %+v", len(toc), len(wantTOC), toc)
+	}
+	for i, w := range wantTOC {
+		if toc[i].Title != w.title || toc[i].WordIndex != w.index || toc[i].Level != w.level {
+			t.Errorf("entry %d = %+v, want %+v", i, toc[i], w)
+		}
+	}
+
+	chapters, words, err := f.ExtractChapters(path)
+	if err != nil {
+		t.Fatalf("ExtractChapters: %v", err)
+	}
+	if len(words) != 5 || len(chapters) != 2 || chapters[1].Title != "Chapter 2" || chapters[1].WordStart != 3 {
+		t.Errorf("got chapters %+v, words %q", chapters, words)
+	}
+
+	if ff, ok := FormatFor("notes.ORG"); !ok || ff.Name() != "Org" {
+		t.Error("FormatFor should find the Org format")
+	}
+}
@@ -0,0 +1,187 @@
+package reader
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Pacer decides how long each word is shown, before the pauses at
+// paragraph and chapter starts and the other adjustments made by
+// WordDelay. New pacing strategies implement it rather than changing the
+// Reader.
+type Pacer interface {
+	// NextDelay returns how long to show the word at idx in r.Words.
+	NextDelay(r *Reader, idx int) time.Duration
+}
+
+// Pacing multipliers used by the built-in pacers.
+const (
+	// SentenceEndFactor lengthens words ending a sentence.
+	SentenceEndFactor = 2.0
+	// ClauseEndFactor lengthens words ending in a comma, colon, semicolon
+	// or dash.
+	ClauseEndFactor = 1.5
+)
+
+// FixedPacer shows every word for the same time, 60s / WPM.
+type FixedPacer struct{}
+
+func (FixedPacer) NextDelay(r *Reader, idx int) time.Duration {
+	return r.GetDelay()
+}
+
+// PunctuationPacer lengthens words ending a sentence or clause, giving
+// time for what was read to sink in.
+type PunctuationPacer struct{}
+
+func (PunctuationPacer) NextDelay(r *Reader, idx int) time.Duration {
+	return scaleDelay(r.GetDelay(), punctuationFactor(r.language, r.wordAt(idx)))
+}
+
+// LengthPacer shows words for longer the more letters they have, a five
+// letter word taking 60s / WPM.
+type LengthPacer struct{}
+
+func (LengthPacer) NextDelay(r *Reader, idx int) time.Duration {
+	return scaleDelay(r.GetDelay(), lengthFactor(r.wordAt(idx)))
+}
+
+// SyllablePacer shows words for longer the more syllables they have, a
+// two-syllable word taking 60s / WPM.
+type SyllablePacer struct{}
+
+func (SyllablePacer) NextDelay(r *Reader, idx int) time.Duration {
+	return scaleDelay(r.GetDelay(), syllableFactor(r.wordAt(idx)))
+}
+
+// adaptiveWindow is how many words either side of the current one
+// AdaptivePacer averages over.
+const adaptiveWindow = 100
+
+// AdaptivePacer weighs each word by its syllables and punctuation, then
+// scales the weights so that the nearby text averages the set WPM: hard
+// words borrow time from easy ones rather than slowing the reading down.
+type AdaptivePacer struct{}
+
+func (AdaptivePacer) NextDelay(r *Reader, idx int) time.Duration {
+	lo, hi := max(idx-adaptiveWindow, 0), min(idx+adaptiveWindow+1, len(r.Words))
+	var total float64
+	for i := lo; i < hi; i++ {
+		total += adaptiveWeight(r.language, r.Words[i])
+	}
+	if total == 0 {
+		return r.GetDelay()
+	}
+	mean := total / float64(hi-lo)
+	return scaleDelay(r.GetDelay(), adaptiveWeight(r.language, r.wordAt(idx))/mean)
+}
+
+func adaptiveWeight(lang Language, word string) float64 {
+	return syllableFactor(word) * punctuationFactor(lang, word)
+}
+
+var pacers = []struct {
+	name  string
+	pacer Pacer
+}{
+	{"fixed", FixedPacer{}},
+	{"punctuation", PunctuationPacer{}},
+	{"length", LengthPacer{}},
+	{"syllable", SyllablePacer{}},
+	{"adaptive", AdaptivePacer{}},
+}
+
+// PacerNames lists the pacers accepted by ParsePacer.
+func PacerNames() []string {
+	names := make([]string, len(pacers))
+	for i, p := range pacers {
+		names[i] = p.name
+	}
+	return names
+}
+
+// ParsePacer returns the built-in pacer called name.
+func ParsePacer(name string) (Pacer, bool) {
+	for _, p := range pacers {
+		if strings.EqualFold(name, p.name) {
+			return p.pacer, true
+		}
+	}
+	return nil, false
+}
+
+// PacerName returns the name ParsePacer accepts for a built-in pacer, or
+// "" for any other.
+func PacerName(p Pacer) string {
+	for _, b := range pacers {
+		if p == b.pacer {
+			return b.name
+		}
+	}
+	return ""
+}
+
+// wordAt returns the word at idx, or "" if there is none.
+func (r *Reader) wordAt(idx int) string {
+	if idx < 0 || idx >= len(r.Words) {
+		return ""
+	}
+	return r.Words[idx]
+}
+
+func scaleDelay(d time.Duration, factor float64) time.Duration {
+	return time.Duration(float64(d) * factor)
+}
+
+// punctuationFactor returns SentenceEndFactor or ClauseEndFactor for a word
+// ending a sentence or clause in lang, otherwise 1.
+func punctuationFactor(lang Language, word string) float64 {
+	word = strings.TrimRight(word, `"')]”’»`)
+	switch {
+	case lang.endsSentence(word), strings.HasSuffix(word, "…"):
+		return SentenceEndFactor
+	case strings.HasSuffix(word, ","), strings.HasSuffix(word, ";"), strings.HasSuffix(word, ":"),
+		strings.HasSuffix(word, "—"), strings.HasSuffix(word, "–"):
+		return ClauseEndFactor
+	}
+	return 1
+}
+
+// lengthFactor is 0.6 plus 0.08 for each letter of a word, at most 2.
+func lengthFactor(word string) float64 {
+	start, end := CoreBounds(word)
+	n := end - start
+	return min(0.6+0.08*float64(n), 2)
+}
+
+// syllableFactor scales with the syllables in a word, from 0.85 for one
+// syllable to at most 2.
+func syllableFactor(word string) float64 {
+	return min(0.7+0.15*float64(max(syllables(word), 1)), 2)
+}
+
+// syllables estimates the syllables in a word by counting groups of
+// vowels, not counting a silent final e. Each Chinese or Japanese
+// character is a syllable.
+func syllables(word string) int {
+	count := 0
+	inVowel := false
+	runes := []rune(strings.ToLower(word))
+	for i, r := range runes {
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) {
+			count++
+			inVowel = false
+			continue
+		}
+		vowel := strings.ContainsRune("aeiouyàáâäèéêëìíîïòóôöùúûü", r)
+		if vowel && !inVowel {
+			silentE := r == 'e' && count > 0 && (i == len(runes)-1 || !unicode.IsLetter(runes[i+1]))
+			if !silentE {
+				count++
+			}
+		}
+		inVowel = vowel
+	}
+	return count
+}
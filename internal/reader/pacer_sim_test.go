@@ -0,0 +1,173 @@
+package reader
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+)
+
+// pacingCorpus is prose with the mix of short and long words, clauses,
+// sentence ends, quotes and paragraphs that pacers react to.
+const pacingCorpus = `It was the best of times, it was the worst of times; it was the age of
+wisdom, it was the age of foolishness. Everything — absolutely everything —
+seemed possible.
+
+"Where are you going?" she asked. He didn't answer: the incomprehensibility
+of the question, its overwhelming institutional bureaucratization, left him
+speechless… Then, quietly, he walked on.
+
+Photosynthesis converts light energy into chemical energy. Chlorophyll, a
+green pigment, absorbs mostly blue and red wavelengths; the rest is
+reflected. 東京 is a city. A cat sat on a mat.`
+
+// simulationCorpus returns pacingCorpus followed by words generated from a
+// fixed seed, so every run paces the same text.
+func simulationCorpus() string {
+	vocabulary := []string{"a", "an", "the", "of", "cat", "ran", "quickly", "towards", "extraordinary",
+		"misunderstanding", "idea", "through", "beautiful", "rhythm", "strengths", "I", "queue"}
+	endings := []string{"", "", "", "", "", ",", ";", ".", "!", "?", ":"}
+	rng := rand.New(rand.NewSource(42))
+	var sb strings.Builder
+	sb.WriteString(pacingCorpus)
+	for i := range 2000 {
+		if i%60 == 0 {
+			sb.WriteString("\n\n")
+		} else {
+			sb.WriteString(" ")
+		}
+		sb.WriteString(vocabulary[rng.Intn(len(vocabulary))])
+		sb.WriteString(endings[rng.Intn(len(endings))])
+	}
+	return sb.String()
+}
+
+// simulation summarises pacing a reader from its first word to its last.
+type simulation struct {
+	words    int
+	total    time.Duration
+	min, max time.Duration
+	delays   []time.Duration
+}
+
+// effectiveWPM is the words shown per minute over the whole simulation.
+func (s simulation) effectiveWPM() float64 {
+	return float64(s.words) / s.total.Minutes()
+}
+
+// simulate reads r to the end the way the front ends do, showing each
+// word for WordDelay and then advancing, without waiting in real time.
+func simulate(r *Reader) simulation {
+	var s simulation
+	for {
+		d := r.WordDelay()
+		s.delays = append(s.delays, d)
+		s.total += d
+		s.words++
+		if s.min == 0 || d < s.min {
+			s.min = d
+		}
+		s.max = max(s.max, d)
+		if !r.Advance() {
+			return s
+		}
+	}
+}
+
+func newSimReader(pacer Pacer, wpm int) *Reader {
+	r := NewReader(simulationCorpus(), wpm)
+	r.Pacer = pacer
+	return r
+}
+
+func TestPacingSimulationWPM(t *testing.T) {
+	const wpm = 400
+	// The corpus ends more words with punctuation than most prose, so the
+	// punctuation pacer is allowed to read well below the set speed.
+	tests := []struct {
+		name     string
+		min, max float64 // bounds on the effective WPM
+	}{
+		{"fixed", wpm, wpm},
+		{"punctuation", wpm * 0.65, wpm * 0.85},
+		{"length", wpm * 0.8, wpm * 1.25},
+		{"syllable", wpm * 0.8, wpm * 1.25},
+		{"adaptive", wpm * 0.98, wpm * 1.02},
+	}
+	for _, tt := range tests {
+		pacer, _ := ParsePacer(tt.name)
+		s := simulate(newSimReader(pacer, wpm))
+		if got := s.effectiveWPM(); got < tt.min-0.5 || got > tt.max+0.5 {
+			t.Errorf("%s: effective WPM = %.1f, want %.0f-%.0f", tt.name, got, tt.min, tt.max)
+		}
+	}
+}
+
+func TestPacingSimulationFrameBounds(t *testing.T) {
+	const wpm = 400
+	base := NewReader("", wpm).GetDelay()
+	tests := []struct {
+		name     string
+		min, max time.Duration
+	}{
+		{"fixed", base, base},
+		{"punctuation", base, base * 2},
+		{"length", base * 6 / 10, base * 2},
+		{"syllable", base * 85 / 100, base * 2},
+		{"adaptive", base / 4, base * 4},
+	}
+	for _, tt := range tests {
+		pacer, _ := ParsePacer(tt.name)
+		s := simulate(newSimReader(pacer, wpm))
+		if s.min < tt.min || s.max > tt.max {
+			t.Errorf("%s: frame times %v-%v, want within %v-%v", tt.name, s.min, s.max, tt.min, tt.max)
+		}
+	}
+
+	// Paragraph and chapter pauses stretch frames by at most their factor.
+	r := newSimReader(PunctuationPacer{}, wpm)
+	r.ParagraphPause, r.ChapterPause = DefaultParagraphPause, DefaultChapterPause
+	if s := simulate(r); s.max > time.Duration(float64(base*2)*DefaultParagraphPause) {
+		t.Errorf("punctuation with paragraph pauses: longest frame %v", s.max)
+	}
+}
+
+func TestPacingSimulationMonotonic(t *testing.T) {
+	for _, name := range PacerNames() {
+		pacer, _ := ParsePacer(name)
+		var prev simulation
+		for i, wpm := range []int{100, 250, 400, 700, 1000, 1500} {
+			s := simulate(newSimReader(pacer, wpm))
+			if i > 0 {
+				if s.total >= prev.total {
+					t.Errorf("%s: reading at %d WPM took %v, no less than %v at a slower speed", name, wpm, s.total, prev.total)
+				}
+				for idx, d := range s.delays {
+					if d > prev.delays[idx] {
+						t.Errorf("%s: word %d takes %v at %d WPM, longer than %v at a slower speed", name, idx, d, wpm, prev.delays[idx])
+						break
+					}
+				}
+			}
+			prev = s
+		}
+	}
+
+	// Harder words never get less time than easier ones in the same spot.
+	r := NewReader("cat, encyclopedia, cat. encyclopedia.", 300)
+	for _, pacer := range []Pacer{LengthPacer{}, SyllablePacer{}} {
+		if pacer.NextDelay(r, 1) < pacer.NextDelay(r, 0) || pacer.NextDelay(r, 3) < pacer.NextDelay(r, 2) {
+			t.Errorf("%T gives a long word less time than a short one", pacer)
+		}
+	}
+}
+
+func TestPacingSimulationDeterministic(t *testing.T) {
+	for _, name := range PacerNames() {
+		pacer, _ := ParsePacer(name)
+		a, b := simulate(newSimReader(pacer, 300)), simulate(newSimReader(pacer, 300))
+		if a.total != b.total || a.words != b.words {
+			t.Errorf("%s: two runs differ: %v over %d words, then %v over %d", name, a.total, a.words, b.total, b.words)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package reader
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSyllables(t *testing.T) {
+	tests := []struct {
+		word string
+		want int
+	}{
+		{"cat", 1},
+		{"make", 1},
+		{"the", 1},
+		{"reading", 2},
+		{"beautiful,", 3},
+		{"Encyclopedia", 5},
+		{"東京", 2},
+		{"42", 0},
+	}
+	for _, tt := range tests {
+		if got := syllables(tt.word); got != tt.want {
+			t.Errorf("syllables(%q) = %d, want %d", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestPacers(t *testing.T) {
+	r := NewReader("A dog, the encyclopedia said. Fine", 600)
+	base := r.GetDelay()
+	scaled := func(f float64) time.Duration { return time.Duration(float64(base) * f) }
+
+	tests := []struct {
+		name string
+		want []time.Duration
+	}{
+		{"fixed", []time.Duration{base, base, base, base, base, base}},
+		{"punctuation", []time.Duration{base, scaled(1.5), base, base, scaled(2), base}},
+		{"length", []time.Duration{scaled(0.68), scaled(0.84), scaled(0.84), scaled(1.56), scaled(0.92), scaled(0.92)}},
+		{"syllable", []time.Duration{scaled(0.85), scaled(0.85), scaled(0.85), scaled(1.45), scaled(0.85), scaled(0.85)}},
+	}
+	for _, tt := range tests {
+		pacer, ok := ParsePacer(tt.name)
+		if !ok {
+			t.Fatalf("ParsePacer(%q) failed", tt.name)
+		}
+		for idx, want := range tt.want {
+			if got := pacer.NextDelay(r, idx); got != want {
+				t.Errorf("%s NextDelay(%d) = %v, want %v", tt.name, idx, got, want)
+			}
+		}
+	}
+	if _, ok := ParsePacer("random"); ok {
+		t.Error("ParsePacer should reject unknown names")
+	}
+
+	// The adaptive pacer keeps the average pace at the set WPM.
+	var adaptive AdaptivePacer
+	var total time.Duration
+	for idx := range r.Words {
+		total += adaptive.NextDelay(r, idx)
+	}
+	if avg := total / time.Duration(len(r.Words)); avg < base-time.Millisecond || avg > base+time.Millisecond {
+		t.Errorf("adaptive average delay = %v, want %v", avg, base)
+	}
+	if adaptive.NextDelay(r, 3) <= adaptive.NextDelay(r, 2) {
+		t.Error("adaptive pacing should give encyclopedia longer than the")
+	}
+
+	r.Pacer = PunctuationPacer{}
+	r.ParagraphPause = 2.5
+	r.JumpToChapter(4)
+	if got := r.WordDelay(); got != scaled(2) {
+		t.Errorf("WordDelay with a pacer = %v, want %v", got, scaled(2))
+	}
+}
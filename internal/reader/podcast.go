@@ -0,0 +1,246 @@
+package reader
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Episode is a podcast episode from an RSS feed.
+type Episode struct {
+	Title string
+	Link  string
+	// Notes holds the show notes, possibly HTML.
+	Notes       string
+	Transcripts []TranscriptLink
+}
+
+// TranscriptLink is a podcast:transcript reference.
+type TranscriptLink struct {
+	URL  string
+	Type string
+}
+
+type rssFeed struct {
+	Items []rssItem `xml:"channel>item"`
+}
+
+type rssItem struct {
+	Title       string           `xml:"title"`
+	Link        string           `xml:"link"`
+	Description string           `xml:"description"`
+	Content     string           `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	Transcripts []TranscriptLink `xml:"https://podcastindex.org/namespace/1.0 transcript"`
+}
+
+// UnmarshalXML reads the url and type attributes of a transcript tag.
+func (t *TranscriptLink) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for _, a := range start.Attr {
+		switch a.Name.Local {
+		case "url":
+			t.URL = a.Value
+		case "type":
+			t.Type = a.Value
+		}
+	}
+	return d.Skip()
+}
+
+// ParseFeed parses the episodes of a podcast RSS feed, newest first as
+// published.
+func ParseFeed(data []byte) ([]Episode, error) {
+	var feed rssFeed
+	if err := xml.Unmarshal(data, &feed); err != nil {
+		return nil, fmt.Errorf("invalid podcast feed: %w", err)
+	}
+	episodes := make([]Episode, 0, len(feed.Items))
+	for _, item := range feed.Items {
+		notes := item.Content
+		if notes == "" {
+			notes = item.Description
+		}
+		episodes = append(episodes, Episode{
+			Title:       strings.TrimSpace(item.Title),
+			Link:        strings.TrimSpace(item.Link),
+			Notes:       notes,
+			Transcripts: item.Transcripts,
+		})
+	}
+	return episodes, nil
+}
+
+// FetchEpisodeContext finds a podcast episode, with requests cancelled
+// when ctx is. rawURL is either a feed, in which case the index'th episode
+// (1 is the newest) is returned, or an episode page that links to its feed.
+func FetchEpisodeContext(ctx context.Context, rawURL string, index int) (Episode, error) {
+	data, contentType, err := fetchURLContext(ctx, rawURL)
+	if err != nil {
+		return Episode{}, err
+	}
+
+	pageURL := ""
+	if strings.Contains(contentType, "html") {
+		feedURL := feedLink(string(data), rawURL)
+		if feedURL == "" {
+			return Episode{}, errors.New("no podcast feed linked from page")
+		}
+		pageURL = rawURL
+		if data, _, err = fetchURLContext(ctx, feedURL); err != nil {
+			return Episode{}, err
+		}
+	}
+
+	episodes, err := ParseFeed(data)
+	if err != nil {
+		return Episode{}, err
+	}
+	if pageURL != "" {
+		for _, ep := range episodes {
+			if sameURL(ep.Link, pageURL) {
+				return ep, nil
+			}
+		}
+		return Episode{}, errors.New("episode not found in podcast feed")
+	}
+	if index < 1 || index > len(episodes) {
+		return Episode{}, fmt.Errorf("episode %d not found (feed has %d)", index, len(episodes))
+	}
+	return episodes[index-1], nil
+}
+
+// transcriptPreference orders transcript types from most to least readable.
+var transcriptPreference = []string{"text/plain", "application/json", "text/vtt", "application/x-subrip", "application/srt", "text/srt", "text/html"}
+
+// EpisodeTranscriptContext fetches the episode's most readable transcript
+// and returns it as plain text, or "" if it has no usable transcript.
+// Requests are cancelled when ctx is.
+func EpisodeTranscriptContext(ctx context.Context, ep Episode) string {
+	for _, typ := range transcriptPreference {
+		for _, t := range ep.Transcripts {
+			if t.Type != typ {
+				continue
+			}
+			data, _, err := fetchURLContext(ctx, t.URL)
+			if err != nil {
+				continue
+			}
+			if text := transcriptText(data, typ); strings.TrimSpace(text) != "" {
+				return text
+			}
+		}
+	}
+	return ""
+}
+
+// NotesText returns the episode's show notes as plain text.
+func (e Episode) NotesText() string {
+	return strings.Join(extractArticleBlocks(e.Notes), "\n\n")
+}
+
+// voicePattern matches a WebVTT voice span such as "<v Alice>".
+var voicePattern = regexp.MustCompile(`<v(?:\.[\w.-]+)?\s+([^>]+)>`)
+
+// cueTagPattern matches any other WebVTT cue markup.
+var cueTagPattern = regexp.MustCompile(`</?[^>]+>`)
+
+// transcriptText converts a transcript of the given MIME type to text with
+// one "Speaker: words" line per cue where speakers are known. Subtitle
+// timings are left for the transcript sanitizer.
+func transcriptText(data []byte, typ string) string {
+	switch typ {
+	case "application/json":
+		var doc struct {
+			Segments []struct {
+				Speaker string `json:"speaker"`
+				Body    string `json:"body"`
+			} `json:"segments"`
+		}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return ""
+		}
+		var lines []string
+		for _, s := range doc.Segments {
+			line := strings.TrimSpace(s.Body)
+			if s.Speaker != "" {
+				line = s.Speaker + ": " + line
+			}
+			lines = append(lines, line)
+		}
+		return strings.Join(lines, "\n")
+
+	case "text/html":
+		return strings.Join(extractArticleBlocks(string(data)), "\n")
+
+	case "text/vtt":
+		text := voicePattern.ReplaceAllString(string(data), "$1: ")
+		return cueTagPattern.ReplaceAllString(text, "")
+	}
+	return string(data)
+}
+
+// feedLink returns the absolute URL of the RSS feed a page advertises with
+// <link rel="alternate" type="application/rss+xml">, or "".
+func feedLink(page, pageURL string) string {
+	doc, err := html.Parse(strings.NewReader(page))
+	if err != nil {
+		return ""
+	}
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return ""
+	}
+
+	var href string
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if href != "" {
+			return
+		}
+		if n.Type == html.ElementNode && n.Data == "link" {
+			var rel, typ, h string
+			for _, a := range n.Attr {
+				switch a.Key {
+				case "rel":
+					rel = strings.ToLower(a.Val)
+				case "type":
+					typ = strings.ToLower(a.Val)
+				case "href":
+					h = a.Val
+				}
+			}
+			if strings.Contains(rel, "alternate") && strings.Contains(typ, "rss") && h != "" {
+				href = h
+				return
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	if href == "" {
+		return ""
+	}
+	ref, err := url.Parse(href)
+	if err != nil {
+		return ""
+	}
+	return base.ResolveReference(ref).String()
+}
+
+// sameURL compares two URLs ignoring scheme and a trailing slash.
+func sameURL(a, b string) bool {
+	norm := func(s string) string {
+		s = strings.TrimPrefix(strings.TrimPrefix(s, "https://"), "http://")
+		return strings.TrimSuffix(s, "/")
+	}
+	return a != "" && norm(a) == norm(b)
+}
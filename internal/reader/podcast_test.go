@@ -0,0 +1,101 @@
+package reader
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func podcastServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/feed.xml":
+			w.Header().Set("Content-Type", "application/rss+xml")
+			w.Write([]byte(`<?xml version="1.0"?>
+<rss version="2.0" xmlns:podcast="https://podcastindex.org/namespace/1.0"
+     xmlns:content="http://purl.org/rss/1.0/modules/content/">
+<channel><title>Show</title>
+<item><title>Episode 2</title><link>` + srv.URL + `/ep2/</link>
+  <description>Plain notes.</description>
+  <podcast:transcript url="` + srv.URL + `/ep2.vtt" type="text/vtt"/>
+  <podcast:transcript url="` + srv.URL + `/ep2.json" type="application/json"/>
+</item>
+<item><title>Episode 1</title><link>` + srv.URL + `/ep1</link>
+  <description>Short.</description>
+  <content:encoded><![CDATA[<p>Welcome to episode one.</p><p>Links below.</p>]]></content:encoded>
+</item>
+</channel></rss>`))
+		case "/ep2.json":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"version":"1.0.0","segments":[
+				{"speaker":"Alice","startTime":0,"body":"Hi there"},
+				{"speaker":"Bob","startTime":1.5,"body":"Hello"}]}`))
+		case "/ep2.vtt":
+			w.Header().Set("Content-Type", "text/vtt")
+			w.Write([]byte("WEBVTT\n\n00:00.000 --> 00:01.000\n<v Alice>Hi there</v>\n"))
+		case "/ep1":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<html><head><link rel="alternate" type="application/rss+xml" href="/feed.xml"></head>
+				<body><p>Episode page</p></body></html>`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	return srv
+}
+
+func TestFetchEpisodeFromFeed(t *testing.T) {
+	srv := podcastServer(t)
+	defer srv.Close()
+
+	ep, err := FetchEpisodeContext(context.Background(), srv.URL+"/feed.xml", 1)
+	if err != nil {
+		t.Fatalf("FetchEpisodeContext: %v", err)
+	}
+	if ep.Title != "Episode 2" || len(ep.Transcripts) != 2 {
+		t.Fatalf("got %q with %d transcripts", ep.Title, len(ep.Transcripts))
+	}
+
+	if text := EpisodeTranscriptContext(context.Background(), ep); text != "Alice: Hi there\nBob: Hello" {
+		t.Errorf("EpisodeTranscriptContext() = %q", text)
+	}
+
+	if _, err := FetchEpisodeContext(context.Background(), srv.URL+"/feed.xml", 3); err == nil {
+		t.Error("expected error for missing episode")
+	}
+}
+
+func TestFetchEpisodeFromPage(t *testing.T) {
+	srv := podcastServer(t)
+	defer srv.Close()
+
+	ep, err := FetchEpisodeContext(context.Background(), srv.URL+"/ep1", 1)
+	if err != nil {
+		t.Fatalf("FetchEpisodeContext: %v", err)
+	}
+	if ep.Title != "Episode 1" {
+		t.Fatalf("got episode %q, want Episode 1", ep.Title)
+	}
+
+	if text := EpisodeTranscriptContext(context.Background(), ep); text != "" {
+		t.Errorf("EpisodeTranscriptContext() = %q, want none", text)
+	}
+	if text := ep.NotesText(); text != "Welcome to episode one.\n\nLinks below." {
+		t.Errorf("NotesText() = %q", text)
+	}
+}
+
+func TestTranscriptTextVTT(t *testing.T) {
+	got := transcriptText([]byte("WEBVTT\n\n1\n00:00.000 --> 00:02.000\n<v Alice>Hi <b>there</b></v>\n"), "text/vtt")
+	if !strings.Contains(got, "Alice: Hi there") {
+		t.Errorf("transcriptText() = %q", got)
+	}
+	clean := NewTranscript(DefaultFillers).Sanitize(got)
+	if clean != "Alice: Hi there." {
+		t.Errorf("sanitized = %q", clean)
+	}
+}
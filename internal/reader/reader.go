@@ -2,55 +2,154 @@
 package reader
 
 import (
+	"sort"
 	"strings"
 	"time"
-	"unicode/utf8"
+	"unicode"
 )
 
 // Reader holds the state for an RSVP speed reading session.
 type Reader struct {
-	Words          []string
-	SentenceStarts []int
-	CurrentIndex   int
-	WPM            int
-	Paused         bool
-	LastArrowPress time.Time
+	Words           []string
+	SentenceStarts  []int
+	ParagraphStarts []int
+	CurrentIndex    int
+	WPM             int
+	Paused          bool
+	LastArrowPress  time.Time
 
 	// Chapter support
 	Chapters       []Chapter
 	TOC            []TOCEntry
 	CurrentChapter int
+	// sequence is the chapters to read in order; see SetSequence.
+	sequence []int
+	// OnChapter, if set, is called by Advance when it moves into a new
+	// chapter, with the indices of the chapters left and entered.
+	OnChapter func(from, to int)
+
+	// Search support
+	SearchQuery string
+
+	// history holds the positions left by jumps; see Back and Forward.
+	history history
+
+	// ParagraphPause and ChapterPause multiply the delay of the first word
+	// of a paragraph or chapter; values of 1 or less add no pause.
+	ParagraphPause float64
+	ChapterPause   float64
+
+	// language ends sentences and paces them; see SetLanguage.
+	language Language
+
+	// Stopwords are the function words that StopwordMode shortens or skips.
+	Stopwords    map[string]bool
+	StopwordMode StopwordMode
+
+	// Frequencies ranks the commonest words of the text's language, for
+	// telling rare words; see Frequencies. DifficultyStrength lengthens
+	// rare and long words by up to that fraction; see DifficultyFactor.
+	// Zero disables it.
+	Frequencies        map[string]int
+	DifficultyStrength float64
+
+	// RewindSentences is how many sentences ResumeWithRewind backs up
+	// after a pause of at least RewindAfter. Zero disables rewinding.
+	RewindSentences int
+	RewindAfter     time.Duration
+	// pausedAt and pausedPos record when and where Pause was called.
+	pausedAt  time.Time
+	pausedPos int
+
+	// LinkPolicy controls how URLs and email addresses are shown.
+	LinkPolicy LinkPolicy
+
+	// Pacer sets how long each word is shown; nil shows every word for
+	// GetDelay, as FixedPacer does.
+	Pacer Pacer
+
+	// MaxWordLength splits longer words into several frames; see
+	// SplitLongWord. Zero shows every word whole.
+	MaxWordLength int
+	// frame is the frame shown of the word at absolute index framePos.
+	frame    int
+	framePos int
+
+	// Incomplete marks a document still loading, whose rest Append adds.
+	// At its last word it is Waiting rather than at its end.
+	Incomplete bool
+
+	// Streaming support: Words holds a window starting at global index Base.
+	Base     int
+	stream   *WordStream
+	sizeHint int64
+
+	// chapters supplies the words of a document parsed a chapter at a
+	// time; see NewChapterReader. nextChapter is the next one to parse,
+	// parsed counts those parsed at least once, and parsedBytes and
+	// parsedWords measure them.
+	chapters    ChapterProvider
+	nextChapter int
+	parsed      int
+	parsedBytes int64
+	parsedWords int
+	// chapterStarts holds the absolute index of each chapter's first word
+	// once it is parsed, or -1.
+	chapterStarts []int
+	// err is the error that ended the chapters early.
+	err error
 }
 
 // NewReader creates a new Reader from the given text and words-per-minute setting.
 func NewReader(text string, wpm int) *Reader {
-	words := ParseText(text)
+	tokens := ParseTokens(text)
+	words := make([]string, len(tokens))
+	sentences := []int{0}
+	var paragraphs []int
+	for i, t := range tokens {
+		words[i] = t.Text
+		if t.SentenceStart && i > 0 {
+			sentences = append(sentences, i)
+		}
+		if t.ParagraphStart {
+			paragraphs = append(paragraphs, i)
+		}
+	}
 	return &Reader{
-		Words:          words,
-		SentenceStarts: FindSentenceStarts(words),
-		CurrentIndex:   0,
-		WPM:            wpm,
-		Paused:         false,
-		LastArrowPress: time.Time{},
+		Words:           words,
+		SentenceStarts:  sentences,
+		ParagraphStarts: paragraphs,
+		CurrentIndex:    0,
+		WPM:             wpm,
+		Paused:          false,
+		LastArrowPress:  time.Time{},
 	}
 }
 
-// ParseText splits text into words.
+// Default pause multipliers at paragraph and chapter starts.
+const (
+	DefaultParagraphPause = 2.5
+	DefaultChapterPause   = 4.0
+)
+
+// DefaultRewindAfter is the shortest pause after which resuming rewinds.
+const DefaultRewindAfter = 5 * time.Second
+
+// ParseText splits text into words. Chinese and Japanese, written without
+// spaces, are broken into short words.
 func ParseText(text string) []string {
-	return strings.Fields(text)
+	return splitWords(text)
 }
 
-// FindSentenceStarts returns indices of words that start sentences.
+// FindSentenceStarts returns indices of words that start sentences. A
+// full stop after an abbreviation such as "Dr." or an initial, or followed
+// by a lowercase word, does not end a sentence, and closing quotes and
+// brackets may follow the mark that does.
 func FindSentenceStarts(words []string) []int {
 	starts := []int{0}
-	for i, word := range words {
-		if len(word) > 0 {
-			last := word[len(word)-1]
-			if last == '.' || last == '!' || last == '?' {
-				if i+1 < len(words) {
-					starts = append(starts, i+1)
-				}
-			}
+	for i := 1; i < len(words); i++ {
+		if (Language{}).sentenceBreak(words[i-1], words[i]) {
+			starts = append(starts, i)
 		}
 	}
 	return starts
@@ -58,18 +157,68 @@ func FindSentenceStarts(words []string) []int {
 
 // GetORPPosition returns the Optimal Recognition Point index for a word.
 // This is the character (rune) position where the eye should focus for fastest recognition.
+// Surrounding punctuation and quotes are not counted, so "said," and
+// ("hello") focus on the same letter as said and hello. CJK words focus
+// on their middle character, and the letter focused in other long words
+// depends on their script; see orpFraction.
 func GetORPPosition(word string) int {
-	length := utf8.RuneCountInString(word)
+	start, end := CoreBounds(word)
+	length := end - start
+	if length == 0 {
+		return start
+	}
+	first := []rune(word)[start]
+	if isCJK(first) {
+		// Each ideograph carries meaning, so focus on the middle of short
+		// CJK words rather than near their start.
+		return start + (length-1)/2
+	}
+	orp := start + int(float64(length)*orpFraction(first))
 	if length <= 1 {
-		return 0
+		orp = start
 	} else if length <= 5 {
-		return 1
+		orp = start + 1
+	}
+	// Focus on a letter, not an accent or other mark combined with it.
+	runes := []rune(word)
+	for orp > start && orp < len(runes) && unicode.IsMark(runes[orp]) {
+		orp--
+	}
+	return orp
+}
+
+// CoreBounds returns the rune range [start, end) of a word's core, without
+// leading and trailing punctuation. A word with no letters or digits is all
+// core.
+func CoreBounds(word string) (start, end int) {
+	runes := []rune(word)
+	start, end = 0, len(runes)
+	for start < end && !isWordRune(runes[start]) {
+		start++
 	}
-	return length / 3
+	for end > start && !isWordRune(runes[end-1]) {
+		end--
+	}
+	if start == end {
+		return 0, len(runes)
+	}
+	return start, end
 }
 
-// JumpToPrevSentence moves to the start of the previous sentence.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+}
+
+// JumpToPrevSentence moves to the start of the previous sentence, as a
+// jump that Back can undo.
 func (r *Reader) JumpToPrevSentence() {
+	r.remember()
+	r.prevSentence()
+}
+
+// prevSentence moves to the start of the previous sentence.
+func (r *Reader) prevSentence() {
+	r.frame = 0
 	for i := len(r.SentenceStarts) - 1; i >= 0; i-- {
 		if r.SentenceStarts[i] < r.CurrentIndex {
 			r.CurrentIndex = r.SentenceStarts[i]
@@ -79,8 +228,13 @@ func (r *Reader) JumpToPrevSentence() {
 	r.CurrentIndex = 0
 }
 
-// JumpToNextSentence moves to the start of the next sentence.
+// JumpToNextSentence moves to the start of the next sentence, as a jump
+// that Back can undo.
 func (r *Reader) JumpToNextSentence() {
+	r.remember()
+	r.frame = 0
+	r.fill()
+	defer r.trim()
 	for i := 0; i < len(r.SentenceStarts); i++ {
 		if r.SentenceStarts[i] > r.CurrentIndex {
 			r.CurrentIndex = r.SentenceStarts[i]
@@ -92,55 +246,407 @@ func (r *Reader) JumpToNextSentence() {
 	}
 }
 
+// SkipSentence skips the rest of the current sentence, moving to the start
+// of the next as a jump that Back can undo, and returns how many words it
+// skipped. Unlike JumpToNextSentence it is meant to be used while reading
+// goes on.
+func (r *Reader) SkipSentence() int {
+	return r.skipTo(func() []int { return r.SentenceStarts })
+}
+
+// SkipParagraph is SkipSentence for the rest of the current paragraph.
+func (r *Reader) SkipParagraph() int {
+	return r.skipTo(func() []int { return r.ParagraphStarts })
+}
+
+// skipTo moves to the first of starts after the current word, or to the
+// last word if there is none, returning how many words it skipped. starts
+// is called after reading ahead, which adds to the reader's starts.
+func (r *Reader) skipTo(starts func() []int) int {
+	if len(r.Words) == 0 {
+		return 0
+	}
+	from := r.Position()
+	r.remember()
+	r.frame = 0
+	r.fill()
+	defer r.trim()
+	next := starts()
+	if i := sort.SearchInts(next, r.CurrentIndex+1); i < len(next) {
+		r.CurrentIndex = next[i]
+	} else {
+		r.CurrentIndex = len(r.Words) - 1
+	}
+	return r.Position() - from
+}
+
+// IsSentenceStart reports whether the word at idx begins a sentence.
+func (r *Reader) IsSentenceStart(idx int) bool {
+	i := sort.SearchInts(r.SentenceStarts, idx)
+	return i < len(r.SentenceStarts) && r.SentenceStarts[i] == idx
+}
+
+// IsParagraphStart reports whether the word at idx begins a paragraph.
+func (r *Reader) IsParagraphStart(idx int) bool {
+	i := sort.SearchInts(r.ParagraphStarts, idx)
+	return i < len(r.ParagraphStarts) && r.ParagraphStarts[i] == idx
+}
+
+// SentenceStart returns the index of the first word of the sentence
+// containing idx.
+func (r *Reader) SentenceStart(idx int) int {
+	i := sort.SearchInts(r.SentenceStarts, idx+1)
+	if i == 0 {
+		return 0
+	}
+	return r.SentenceStarts[i-1]
+}
+
+// SentenceEnd returns the index of the last word of the sentence containing idx.
+func (r *Reader) SentenceEnd(idx int) int {
+	i := sort.SearchInts(r.SentenceStarts, idx+1)
+	if i < len(r.SentenceStarts) {
+		return r.SentenceStarts[i] - 1
+	}
+	return len(r.Words) - 1
+}
+
+// SentenceIndex returns the number of the sentence containing idx, counting
+// from 0.
+func (r *Reader) SentenceIndex(idx int) int {
+	return max(sort.SearchInts(r.SentenceStarts, idx+1)-1, 0)
+}
+
+// SentenceFrom returns the text from idx to the end of its sentence.
+func (r *Reader) SentenceFrom(idx int) string {
+	if idx < 0 || idx >= len(r.Words) {
+		return ""
+	}
+	return strings.Join(r.Words[idx:r.SentenceEnd(idx)+1], " ")
+}
+
 // GetDelay returns the duration to display each word based on WPM.
 func (r *Reader) GetDelay() time.Duration {
 	return time.Duration(60.0/float64(r.WPM)*1000) * time.Millisecond
 }
 
-// CurrentWord returns the word at the current index.
+// WordDelay returns how long to show the current word: the Pacer's delay
+// times its DifficultyFactor, lengthened by ChapterPause at the start of a
+// chapter after the first word or ParagraphPause at the start of a
+// paragraph, or halved for a function word in StopwordsHalf mode. Links
+// shown in full take GetDelay times LinkDelayFactor, and the later frames
+// of a split word GetDelay.
+func (r *Reader) WordDelay() time.Duration {
+	d := r.GetDelay()
+	if r.LinkPolicy == LinksFull && r.CurrentToken().Link != LinkNone {
+		return d * LinkDelayFactor
+	}
+	if r.Frame() > 0 {
+		return d
+	}
+	if r.Pacer != nil {
+		d = r.Pacer.NextDelay(r, r.CurrentIndex)
+	}
+	d = scaleDelay(d, r.DifficultyFactor(r.CurrentIndex))
+	t := r.CurrentToken()
+	switch {
+	case r.ChapterPause > 1 && t.ChapterStart && r.Position() > 0:
+		return time.Duration(float64(d) * r.ChapterPause)
+	case r.ParagraphPause > 1 && t.ParagraphStart:
+		return time.Duration(float64(d) * r.ParagraphPause)
+	case r.StopwordMode == StopwordsHalf && t.Stopword:
+		return d / 2
+	}
+	return d
+}
+
+// WordsAround returns up to before words preceding the current one and up
+// to after words following it, in reading order.
+func (r *Reader) WordsAround(before, after int) (prev, next []string) {
+	if r.CurrentIndex < 0 || r.CurrentIndex >= len(r.Words) {
+		return nil, nil
+	}
+	prev = r.Words[max(0, r.CurrentIndex-before):r.CurrentIndex]
+	next = r.Words[r.CurrentIndex+1 : min(len(r.Words), r.CurrentIndex+1+after)]
+	return prev, next
+}
+
+// Sample returns up to n words from idx, stopping at the end of the
+// chapter holding it, for a look at a chapter without moving to it.
+func (r *Reader) Sample(idx, n int) []string {
+	if idx < 0 || idx >= len(r.Words) {
+		return nil
+	}
+	end := min(len(r.Words), idx+n)
+	if len(r.Chapters) > 0 {
+		if ch := r.Chapters[r.ChapterIndex(idx)]; ch.WordEnd >= idx {
+			end = min(end, ch.WordEnd+1)
+		}
+	}
+	return r.Words[idx:end]
+}
+
+// CurrentWord returns the word at the current index, or the frame of it
+// being shown if it is split.
 func (r *Reader) CurrentWord() string {
-	if r.CurrentIndex >= 0 && r.CurrentIndex < len(r.Words) {
-		return r.Words[r.CurrentIndex]
+	if frames := r.frames(); len(frames) > 0 {
+		return frames[min(r.Frame(), len(frames)-1)]
 	}
 	return ""
 }
 
-// Progress returns the current position and total word count.
+// Progress returns the current position and total word count. While
+// streaming, the total is an estimate.
 func (r *Reader) Progress() (current, total int) {
+	if r.lazy() {
+		return r.Base + r.CurrentIndex + 1, r.estimatedTotal()
+	}
+	if current, total, ok := r.sequenceProgress(); ok {
+		return current, total
+	}
 	return r.CurrentIndex + 1, len(r.Words)
 }
 
-// Advance moves to the next word. Returns true if there are more words.
+// Position returns the absolute index of the current word, for saving.
+func (r *Reader) Position() int {
+	return r.Base + r.CurrentIndex
+}
+
+// SeekTo moves to an absolute word index, reading ahead when streaming.
+// Returns false if the document is shorter than pos.
+func (r *Reader) SeekTo(pos int) bool {
+	if pos < r.Base {
+		return false
+	}
+	for r.lazy() && pos-r.Base >= len(r.Words) && !r.sourceDone() {
+		r.CurrentIndex = len(r.Words) - 1
+		r.fill()
+		r.trim()
+		if r.stream != nil && r.stream.AtEOF() && pos-r.Base >= len(r.Words) {
+			break
+		}
+	}
+	if pos-r.Base >= len(r.Words) {
+		return false
+	}
+	r.JumpToChapter(pos - r.Base)
+	r.trim()
+	return true
+}
+
+// Advance moves to the next word, or the next frame of a split word.
+// Returns true if there are more words. In StopwordsSkip mode it moves past
+// function words, and with LinksSkip past links, except one that starts a
+// chapter or ends the text.
 func (r *Reader) Advance() bool {
+	if r.nextFrame() {
+		return true
+	}
+	r.frame = 0
+	from := r.CurrentChapter
+	if !r.advance() {
+		return false
+	}
+	for (r.StopwordMode == StopwordsSkip && r.isStopword(r.CurrentIndex) || r.isSkippedLink(r.CurrentIndex)) && !r.AtChapterStart() {
+		if !r.advance() {
+			break
+		}
+	}
+	if r.OnChapter != nil && r.CurrentChapter != from {
+		r.OnChapter(from, r.CurrentChapter)
+	}
+	return true
+}
+
+// advance moves to the next word without skipping any.
+func (r *Reader) advance() bool {
+	if len(r.Words) == 0 {
+		// A follow-mode stream can start empty; its first word is the
+		// one to show, not skip.
+		r.fill()
+		return len(r.Words) > 0
+	}
+	r.fill()
+	if atEnd, moved := r.nextInSequence(); atEnd {
+		return moved
+	}
 	if r.CurrentIndex < len(r.Words)-1 {
 		r.CurrentIndex++
+		for next := r.CurrentChapter + 1; next < len(r.Chapters) && r.CurrentIndex >= r.Chapters[next].WordStart; next++ {
+			r.CurrentChapter = next
+		}
+		r.trim()
 		return true
 	}
 	return false
 }
 
+// AtChapterStart reports whether the current word opens a chapter after the
+// first, i.e. whether Advance has just crossed a chapter boundary.
+func (r *Reader) AtChapterStart() bool {
+	if r.Frame() != 0 || r.CurrentChapter >= len(r.Chapters) || r.CurrentIndex != r.Chapters[r.CurrentChapter].WordStart {
+		return false
+	}
+	// In a sequence, the first chapter read is the start.
+	if p := r.sequenceIndex(r.CurrentChapter); p >= 0 {
+		return p > 0
+	}
+	return r.CurrentChapter > 0
+}
+
+// Pause stops reading and remembers when and where, for ResumeWithRewind.
+func (r *Reader) Pause() {
+	r.Paused = true
+	r.pausedAt = time.Now()
+	r.pausedPos = r.Position()
+}
+
+// ResumeWithRewind resumes reading after Pause. If the pause lasted at
+// least RewindAfter, it first backs up RewindSentences sentences, the first
+// to the start of the current one, to restore context, but not past the
+// start of the chapter. A position changed while paused, by seeking or
+// searching, is kept as is. It reports whether it rewound.
+func (r *Reader) ResumeWithRewind() bool {
+	return r.resume(time.Now())
+}
+
+func (r *Reader) resume(now time.Time) bool {
+	r.Paused = false
+	pausedAt := r.pausedAt
+	r.pausedAt = time.Time{}
+	if r.RewindSentences <= 0 || pausedAt.IsZero() || r.Position() != r.pausedPos || now.Sub(pausedAt) < r.RewindAfter {
+		return false
+	}
+	from, start := r.CurrentIndex, 0
+	if r.CurrentChapter < len(r.Chapters) {
+		start = r.Chapters[r.CurrentChapter].WordStart
+	}
+	for range r.RewindSentences {
+		r.prevSentence()
+	}
+	r.CurrentIndex = max(r.CurrentIndex, min(start, from))
+	r.updateCurrentChapter()
+	return r.CurrentIndex != from
+}
+
 // AtEnd returns true if the reader is at the last word.
 func (r *Reader) AtEnd() bool {
-	return r.CurrentIndex >= len(r.Words)-1
+	if !r.sourceDone() || r.Incomplete {
+		return false
+	}
+	if p := r.sequenceIndex(r.CurrentChapter); p >= 0 {
+		return p == len(r.sequence)-1 && r.CurrentIndex >= r.chapterEnd(r.CurrentChapter) && r.Frame() >= len(r.frames())-1
+	}
+	return r.CurrentIndex >= len(r.Words)-1 && r.Frame() >= len(r.frames())-1
 }
 
 // JumpToChapter jumps to the specified word index and updates current chapter.
 func (r *Reader) JumpToChapter(wordIndex int) {
 	if wordIndex >= 0 && wordIndex < len(r.Words) {
 		r.CurrentIndex = wordIndex
+		r.frame = 0
 		r.updateCurrentChapter()
 	}
 }
 
 // updateCurrentChapter sets CurrentChapter based on CurrentIndex.
 func (r *Reader) updateCurrentChapter() {
+	r.CurrentChapter = r.ChapterIndex(r.CurrentIndex)
+}
+
+// ChapterIndex returns the index of the chapter containing the word at idx,
+// or 0 if there are no chapters.
+func (r *Reader) ChapterIndex(idx int) int {
 	for i := len(r.Chapters) - 1; i >= 0; i-- {
-		if r.CurrentIndex >= r.Chapters[i].WordStart {
-			r.CurrentChapter = i
-			return
+		if idx >= r.Chapters[i].WordStart {
+			return i
+		}
+	}
+	return 0
+}
+
+// ChapterProgress returns the position of the current word within its
+// chapter and the chapter's length in words, or zeros if the text has no
+// chapters.
+func (r *Reader) ChapterProgress() (current, total int) {
+	if r.CurrentChapter < 0 || r.CurrentChapter >= len(r.Chapters) {
+		return 0, 0
+	}
+	ch := r.Chapters[r.CurrentChapter]
+	return r.CurrentIndex - ch.WordStart + 1, max(ch.WordEnd-ch.WordStart+1, 0)
+}
+
+// Truncate drops every word from the nth on, along with the sentences,
+// paragraphs, chapters and TOC entries that start there. It does nothing
+// to a document read lazily.
+func (r *Reader) Truncate(n int) {
+	if r.lazy() || n < 0 || n >= len(r.Words) {
+		return
+	}
+	r.Words = r.Words[:n]
+	r.SentenceStarts = cutStarts(r.SentenceStarts, n)
+	r.ParagraphStarts = cutStarts(r.ParagraphStarts, n)
+	for i, ch := range r.Chapters {
+		if ch.WordStart >= n {
+			r.Chapters = r.Chapters[:i]
+			break
+		}
+		r.Chapters[i].WordEnd = min(ch.WordEnd, n-1)
+	}
+	r.sequence = r.validSequence(r.sequence)
+	for i, e := range r.TOC {
+		if e.WordIndex >= n {
+			r.TOC = r.TOC[:i]
+			break
 		}
 	}
-	r.CurrentChapter = 0
+	if r.CurrentIndex >= n {
+		r.CurrentIndex = max(n-1, 0)
+		r.frame = 0
+		r.updateCurrentChapter()
+	}
+}
+
+// Append adds text to the end of an incomplete document, with its TOC
+// entries and chapters counted from its first word. The text starts a
+// paragraph. It does nothing to a document read lazily.
+func (r *Reader) Append(text string, toc []TOCEntry, chapters []Chapter) {
+	if r.lazy() {
+		return
+	}
+	start := len(r.Words)
+	for i, t := range parseTokens(text, r.language) {
+		idx := start + i
+		if i == 0 || t.SentenceStart {
+			if n := len(r.SentenceStarts); n == 0 || r.SentenceStarts[n-1] != idx {
+				r.SentenceStarts = append(r.SentenceStarts, idx)
+			}
+		}
+		if i == 0 || t.ParagraphStart {
+			r.ParagraphStarts = append(r.ParagraphStarts, idx)
+		}
+		r.Words = append(r.Words, t.Text)
+	}
+	for _, c := range chapters {
+		c.WordStart += start
+		c.WordEnd += start
+		r.Chapters = append(r.Chapters, c)
+	}
+	for _, e := range toc {
+		e.WordIndex += start
+		r.TOC = append(r.TOC, e)
+	}
+	r.updateCurrentChapter()
+}
+
+// cutStarts returns the positions in sorted starts before n.
+func cutStarts(starts []int, n int) []int {
+	for i, s := range starts {
+		if s >= n {
+			return starts[:i]
+		}
+	}
+	return starts
 }
 
 // CurrentChapterTitle returns the title of the current chapter.
@@ -0,0 +1,367 @@
+package reader
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSentenceHelpers(t *testing.T) {
+	r := NewReader("One two. Three four five. Six", 300)
+
+	if !r.IsSentenceStart(2) || r.IsSentenceStart(3) {
+		t.Error("IsSentenceStart mismatch")
+	}
+	if got := r.SentenceEnd(3); got != 4 {
+		t.Errorf("SentenceEnd(3) = %d, want 4", got)
+	}
+	if got := r.SentenceEnd(5); got != 5 {
+		t.Errorf("SentenceEnd(5) = %d, want 5", got)
+	}
+	for idx, want := range []int{0, 0, 1, 1, 1, 2} {
+		if got := r.SentenceIndex(idx); got != want {
+			t.Errorf("SentenceIndex(%d) = %d, want %d", idx, got, want)
+		}
+	}
+	for idx, want := range []int{0, 0, 2, 2, 2, 5} {
+		if got := r.SentenceStart(idx); got != want {
+			t.Errorf("SentenceStart(%d) = %d, want %d", idx, got, want)
+		}
+	}
+	if got := r.SentenceFrom(3); got != "four five." {
+		t.Errorf("SentenceFrom(3) = %q", got)
+	}
+}
+
+func TestSkipSentenceAndParagraph(t *testing.T) {
+	r := NewReader("One two. Three four five. Six\n\nSeven eight. Nine", 300)
+	r.SeekTo(3)
+	if n := r.SkipSentence(); n != 2 || r.CurrentIndex != 5 {
+		t.Errorf("SkipSentence skipped %d words to %d, want 2 to 5", n, r.CurrentIndex)
+	}
+	if r.Paused {
+		t.Error("skipping should not pause")
+	}
+	if !r.Back() || r.CurrentIndex != 3 {
+		t.Errorf("Back should return to where the skip began, at %d", r.CurrentIndex)
+	}
+	if n := r.SkipParagraph(); n != 3 || r.CurrentIndex != 6 {
+		t.Errorf("SkipParagraph skipped %d words to %d, want 3 to 6", n, r.CurrentIndex)
+	}
+	if n := r.SkipParagraph(); n != 2 || r.CurrentIndex != 8 {
+		t.Errorf("SkipParagraph in the last paragraph skipped %d words to %d, want 2 to the last word", n, r.CurrentIndex)
+	}
+	if n := r.SkipSentence(); n != 0 {
+		t.Errorf("SkipSentence at the last word skipped %d words", n)
+	}
+}
+
+func TestChapterIndex(t *testing.T) {
+	r := NewReader("a b c d e f", 300)
+	r.SetChapters([]Chapter{{Title: "One", WordStart: 0}, {Title: "Two", WordStart: 3}}, nil)
+
+	for idx, want := range []int{0, 0, 0, 1, 1, 1} {
+		if got := r.ChapterIndex(idx); got != want {
+			t.Errorf("ChapterIndex(%d) = %d, want %d", idx, got, want)
+		}
+	}
+	if got := NewReader("a b", 300).ChapterIndex(1); got != 0 {
+		t.Errorf("ChapterIndex without chapters = %d, want 0", got)
+	}
+}
+
+func TestAdvanceTracksChapter(t *testing.T) {
+	r := NewReader("a b c d e f", 300)
+	r.SetChapters([]Chapter{{Title: "One", WordStart: 0, WordEnd: 2}, {Title: "Two", WordStart: 3, WordEnd: 5}}, nil)
+
+	var starts []int
+	for r.Advance() {
+		if r.AtChapterStart() {
+			starts = append(starts, r.CurrentIndex)
+		}
+		if want := r.ChapterIndex(r.CurrentIndex); r.CurrentChapter != want {
+			t.Errorf("at word %d CurrentChapter = %d, want %d", r.CurrentIndex, r.CurrentChapter, want)
+		}
+	}
+	if len(starts) != 1 || starts[0] != 3 {
+		t.Errorf("chapter starts = %v, want [3]", starts)
+	}
+}
+
+func TestChapterProgress(t *testing.T) {
+	r := NewReader("a b c d e f", 300)
+	if cur, total := r.ChapterProgress(); cur != 0 || total != 0 {
+		t.Errorf("without chapters ChapterProgress = %d/%d, want 0/0", cur, total)
+	}
+	r.SetChapters([]Chapter{{Title: "One", WordStart: 0, WordEnd: 1}, {Title: "Two", WordStart: 2, WordEnd: 5}}, nil)
+	for idx, want := range [][2]int{{1, 2}, {2, 2}, {1, 4}, {2, 4}, {3, 4}, {4, 4}} {
+		r.JumpToChapter(idx)
+		if cur, total := r.ChapterProgress(); cur != want[0] || total != want[1] {
+			t.Errorf("at word %d ChapterProgress = %d/%d, want %d/%d", idx, cur, total, want[0], want[1])
+		}
+	}
+}
+
+func TestOnChapter(t *testing.T) {
+	r := NewReader("a b c the d e", 300)
+	r.SetChapters([]Chapter{{WordStart: 0, WordEnd: 1}, {WordStart: 2, WordEnd: 2}, {WordStart: 3, WordEnd: 5}}, nil)
+	r.Stopwords = map[string]bool{"the": true}
+	r.StopwordMode = StopwordsSkip
+
+	var crossed [][2]int
+	r.OnChapter = func(from, to int) {
+		crossed = append(crossed, [2]int{from, to})
+		r.Pause()
+	}
+	for r.Advance() {
+	}
+	if want := [][2]int{{0, 1}, {1, 2}}; !reflect.DeepEqual(crossed, want) {
+		t.Errorf("OnChapter calls = %v, want %v", crossed, want)
+	}
+	if !r.Paused {
+		t.Error("pausing in OnChapter did not pause the reader")
+	}
+}
+
+func TestResumeRewindStopsAtChapter(t *testing.T) {
+	r := NewReader("One two. Three four. Five six.", 300)
+	r.SetChapters([]Chapter{{WordStart: 0, WordEnd: 1}, {WordStart: 2, WordEnd: 5}}, nil)
+	r.RewindSentences = 2
+	r.RewindAfter = DefaultRewindAfter
+
+	r.JumpToChapter(4)
+	r.Pause()
+	if rewound := r.resume(r.pausedAt.Add(time.Minute)); !rewound || r.CurrentIndex != 2 {
+		t.Errorf("rewind from word 4: rewound=%v at word %d, want word 2", rewound, r.CurrentIndex)
+	}
+
+	// Paused at a chapter start, as when pausing at the end of each
+	// chapter, resuming stays put rather than re-reading the last one.
+	r.Pause()
+	if rewound := r.resume(r.pausedAt.Add(time.Minute)); rewound || r.CurrentIndex != 2 || r.CurrentChapter != 1 {
+		t.Errorf("rewind from chapter start: rewound=%v at word %d in chapter %d, want word 2 in chapter 1", rewound, r.CurrentIndex, r.CurrentChapter)
+	}
+}
+
+func TestWordDelay(t *testing.T) {
+	r := NewReader("a b\n\nc d e f", 600)
+	r.SetChapters([]Chapter{{Title: "One", WordStart: 0}, {Title: "Two", WordStart: 4}}, nil)
+	base := r.GetDelay()
+
+	for idx, want := range []time.Duration{base, base, base, base, base, base} {
+		r.JumpToChapter(idx)
+		if got := r.WordDelay(); got != want {
+			t.Errorf("without pauses WordDelay at %d = %v, want %v", idx, got, want)
+		}
+	}
+
+	r.ParagraphPause = 2.5
+	r.ChapterPause = 4
+	para, chapter := base*5/2, base*4
+	for idx, want := range []time.Duration{para, base, para, base, chapter, base} {
+		r.JumpToChapter(idx)
+		if got := r.WordDelay(); got != want {
+			t.Errorf("WordDelay at %d = %v, want %v", idx, got, want)
+		}
+	}
+}
+
+func TestWordsAround(t *testing.T) {
+	r := NewReader("a b c d e", 300)
+	tests := []struct {
+		idx        int
+		prev, next string
+	}{
+		{0, "", "b c"},
+		{2, "a b", "d e"},
+		{4, "c d", ""},
+	}
+	for _, tt := range tests {
+		r.JumpToChapter(tt.idx)
+		prev, next := r.WordsAround(2, 2)
+		if got := strings.Join(prev, " "); got != tt.prev {
+			t.Errorf("at %d prev = %q, want %q", tt.idx, got, tt.prev)
+		}
+		if got := strings.Join(next, " "); got != tt.next {
+			t.Errorf("at %d next = %q, want %q", tt.idx, got, tt.next)
+		}
+	}
+}
+
+func TestSample(t *testing.T) {
+	r := NewReader("a b c d e f g", 300)
+	r.SetChapters([]Chapter{{Title: "One", WordStart: 0, WordEnd: 3}, {Title: "Two", WordStart: 4, WordEnd: 6}}, nil)
+	tests := []struct {
+		idx, n int
+		want   string
+	}{
+		{0, 2, "a b"},
+		{1, 10, "b c d"},
+		{4, 10, "e f g"},
+		{7, 10, ""},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(r.Sample(tt.idx, tt.n), " "); got != tt.want {
+			t.Errorf("Sample(%d, %d) = %q, want %q", tt.idx, tt.n, got, tt.want)
+		}
+	}
+	if r.CurrentIndex != 0 {
+		t.Errorf("Sample moved the reader to %d", r.CurrentIndex)
+	}
+}
+
+func TestResumeWithRewind(t *testing.T) {
+	tests := []struct {
+		name    string
+		rewind  int
+		paused  time.Duration
+		moved   bool
+		wantPos int
+	}{
+		{"short pause", 1, 2 * time.Second, false, 4},
+		{"long pause", 1, 10 * time.Second, false, 2},
+		{"two sentences", 2, 10 * time.Second, false, 0},
+		{"disabled", 0, 10 * time.Second, false, 4},
+		{"moved while paused", 1, 10 * time.Second, true, 5},
+	}
+	for _, tt := range tests {
+		r := NewReader("One two. Three four five. Six", 300)
+		r.RewindSentences = tt.rewind
+		r.RewindAfter = DefaultRewindAfter
+		r.JumpToChapter(4)
+		r.Pause()
+		if tt.moved {
+			r.JumpToChapter(5)
+		}
+		start := r.pausedAt
+		rewound := r.resume(start.Add(tt.paused))
+		if r.Paused || r.Position() != tt.wantPos || rewound != (tt.wantPos < 4) {
+			t.Errorf("%s: paused=%v pos=%d rewound=%v, want pos %d", tt.name, r.Paused, r.Position(), rewound, tt.wantPos)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	r := NewReader("One two. Three four.\n\nFive six. Seven eight.", 300)
+	r.SetChapters([]Chapter{{Title: "A", WordStart: 0, WordEnd: 3}, {Title: "B", WordStart: 4, WordEnd: 7}},
+		[]TOCEntry{{Title: "A", WordIndex: 0}, {Title: "B", WordIndex: 4}})
+	r.JumpToChapter(6)
+	r.Truncate(3)
+	if want := []string{"One", "two.", "Three"}; !reflect.DeepEqual(r.Words, want) {
+		t.Errorf("Words = %v, want %v", r.Words, want)
+	}
+	if want := []int{0, 2}; !reflect.DeepEqual(r.SentenceStarts, want) {
+		t.Errorf("SentenceStarts = %v, want %v", r.SentenceStarts, want)
+	}
+	if len(r.Chapters) != 1 || r.Chapters[0].WordEnd != 2 || len(r.TOC) != 1 {
+		t.Errorf("Chapters = %+v, TOC = %+v; want only A, ending at word 2", r.Chapters, r.TOC)
+	}
+	if r.CurrentIndex != 2 || r.CurrentChapter != 0 {
+		t.Errorf("at word %d in chapter %d, want word 2 in chapter 0", r.CurrentIndex, r.CurrentChapter)
+	}
+	r.Truncate(10)
+	if len(r.Words) != 3 {
+		t.Errorf("Truncate past the end left %d words, want 3", len(r.Words))
+	}
+}
+
+func TestAppend(t *testing.T) {
+	r := NewReader("One two. Three", 300)
+	r.SetChapters([]Chapter{{Title: "A", WordStart: 0, WordEnd: 2}}, []TOCEntry{{Title: "A"}})
+	r.Incomplete = true
+	r.JumpToChapter(2)
+	if r.Advance() || !r.Waiting() || r.AtEnd() {
+		t.Fatal("an incomplete document should wait at its last word")
+	}
+	r.Append("Four five. Six", []TOCEntry{{Title: "B"}}, []Chapter{{Title: "B", WordEnd: 2}})
+	if want := []string{"One", "two.", "Three", "Four", "five.", "Six"}; !reflect.DeepEqual(r.Words, want) {
+		t.Errorf("Words = %v, want %v", r.Words, want)
+	}
+	if want := []int{0, 2, 3, 5}; !reflect.DeepEqual(r.SentenceStarts, want) {
+		t.Errorf("SentenceStarts = %v, want %v", r.SentenceStarts, want)
+	}
+	if !r.IsParagraphStart(3) || len(r.Chapters) != 2 || r.Chapters[1].WordStart != 3 || r.Chapters[1].WordEnd != 5 || r.TOC[1].WordIndex != 3 {
+		t.Errorf("Chapters = %+v, TOC = %+v; want B from word 3 to 5", r.Chapters, r.TOC)
+	}
+	if !r.Advance() || r.CurrentChapter != 1 {
+		t.Errorf("reading on should enter B, at chapter %d", r.CurrentChapter)
+	}
+	r.Incomplete = false
+	r.JumpToChapter(5)
+	if r.Waiting() || !r.AtEnd() {
+		t.Error("a complete document should end at its last word")
+	}
+}
+
+func TestParseText(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name:     "simple sentence",
+			input:    "Hello world this is a test",
+			expected: []string{"Hello", "world", "this", "is", "a", "test"},
+		},
+		{
+			name:     "multiple spaces",
+			input:    "Hello    world     test",
+			expected: []string{"Hello", "world", "test"},
+		},
+		{
+			name:     "newlines and tabs",
+			input:    "Hello\nworld\ttest",
+			expected: []string{"Hello", "world", "test"},
+		},
+		{
+			name:     "empty string",
+			input:    "",
+			expected: []string{},
+		},
+		{
+			name:     "single word",
+			input:    "Hello",
+			expected: []string{"Hello"},
+		},
+		{
+			name:     "punctuation",
+			input:    "Hello, world! How are you?",
+			expected: []string{"Hello,", "world!", "How", "are", "you?"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ParseText(tt.input)
+			if len(result) != len(tt.expected) {
+				t.Errorf("ParseText() length = %v, want %v", len(result), len(tt.expected))
+				return
+			}
+			for i := range result {
+				if result[i] != tt.expected[i] {
+					t.Errorf("ParseText()[%d] = %v, want %v", i, result[i], tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkParseText(b *testing.B) {
+	text := strings.Repeat("Hello world this is a test sentence with multiple words. ", 100)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ParseText(text)
+	}
+}
+
+func BenchmarkGetORPPosition(b *testing.B) {
+	words := []string{"a", "hello", "testing", "extraordinary", "supercalifragilisticexpialidocious"}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, word := range words {
+			GetORPPosition(word)
+		}
+	}
+}
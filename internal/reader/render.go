@@ -0,0 +1,283 @@
+package reader
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// SegmentKind identifies how a piece of a word is emphasized on screen.
+type SegmentKind int
+
+const (
+	// SegmentPlain is regular word text.
+	SegmentPlain SegmentKind = iota
+	// SegmentFocus is the highlighted ORP character.
+	SegmentFocus
+	// SegmentBold is emphasized text, as in bionic reading.
+	SegmentBold
+	// SegmentPunct is punctuation around a word, drawn dimmed.
+	SegmentPunct
+)
+
+// Segment is a run of characters sharing one emphasis.
+type Segment struct {
+	Text string
+	Kind SegmentKind
+}
+
+// WordRenderer splits a word into styled segments. Both the terminal and
+// GUI front ends draw words through a WordRenderer, so display modes only
+// need to be written once.
+type WordRenderer interface {
+	Name() string
+	Segments(word string) []Segment
+}
+
+// ORPRenderer highlights the single Optimal Recognition Point character.
+type ORPRenderer struct{}
+
+func (ORPRenderer) Name() string { return "orp" }
+
+func (ORPRenderer) Segments(word string) []Segment {
+	runes := []rune(word)
+	if len(runes) == 0 {
+		return nil
+	}
+	start, end := CoreBounds(word)
+	orp := GetORPPosition(word)
+	if orp >= len(runes) {
+		orp = len(runes) - 1
+	}
+	// The focus letter keeps its accents and other combining marks.
+	focusEnd := max(clusterEnd(runes, orp), orp+1)
+	end = max(end, focusEnd)
+	return compactSegments([]Segment{
+		{Text: string(runes[:start]), Kind: SegmentPunct},
+		{Text: string(runes[start:orp]), Kind: SegmentPlain},
+		{Text: string(runes[orp:focusEnd]), Kind: SegmentFocus},
+		{Text: string(runes[focusEnd:end]), Kind: SegmentPlain},
+		{Text: string(runes[end:]), Kind: SegmentPunct},
+	})
+}
+
+// BionicRenderer bolds the leading fraction of each word instead of
+// highlighting one character.
+type BionicRenderer struct {
+	Fraction float64
+}
+
+func (BionicRenderer) Name() string { return "bionic" }
+
+func (b BionicRenderer) Segments(word string) []Segment {
+	runes := []rune(word)
+	if len(runes) == 0 {
+		return nil
+	}
+	start, end := CoreBounds(word)
+	core := end - start
+	n := int(math.Ceil(float64(core) * b.Fraction))
+	if n < 1 {
+		n = 1
+	}
+	if n > core {
+		n = core
+	}
+	return compactSegments([]Segment{
+		{Text: string(runes[:start]), Kind: SegmentPunct},
+		{Text: string(runes[start : start+n]), Kind: SegmentBold},
+		{Text: string(runes[start+n : end]), Kind: SegmentPlain},
+		{Text: string(runes[end:]), Kind: SegmentPunct},
+	})
+}
+
+// ContextRenderer is a WordRenderer that also shows the words around the
+// current one, in a column above and below it.
+type ContextRenderer interface {
+	WordRenderer
+	Context() (before, after int)
+}
+
+// VerticalRenderer presents words down a fixed column, one per line,
+// scrolling up as reading advances. The current word is highlighted at its
+// ORP between dimmed neighbours, for readers who find a single flashing
+// point tiring.
+type VerticalRenderer struct {
+	Before, After int
+}
+
+func (VerticalRenderer) Name() string { return "vertical" }
+
+func (VerticalRenderer) Segments(word string) []Segment {
+	return ORPRenderer{}.Segments(word)
+}
+
+func (v VerticalRenderer) Context() (before, after int) {
+	return v.Before, v.After
+}
+
+// StripRenderer is a WordRenderer that also shows the words around the
+// current one, on the same line to its left and right.
+type StripRenderer interface {
+	WordRenderer
+	Strip() (before, after int)
+}
+
+// PreviewRenderer shows the previous and next words as dimmed ghosts on
+// either side of the current word, which is highlighted at its ORP, so the
+// eye has a glimpse of the flow of the sentence.
+type PreviewRenderer struct {
+	Before, After int
+}
+
+func (PreviewRenderer) Name() string { return "preview" }
+
+func (PreviewRenderer) Segments(word string) []Segment {
+	return ORPRenderer{}.Segments(word)
+}
+
+func (p PreviewRenderer) Strip() (before, after int) {
+	return p.Before, p.After
+}
+
+// ParallelRenderer highlights the word at its ORP, as ORPRenderer does,
+// with the translation of its sentence from a ParallelText shown below it,
+// for language learners reading a text alongside its translation.
+type ParallelRenderer struct{}
+
+func (ParallelRenderer) Name() string { return "parallel" }
+
+func (ParallelRenderer) Segments(word string) []Segment {
+	return ORPRenderer{}.Segments(word)
+}
+
+// Renderers lists the established display modes.
+var Renderers = []WordRenderer{
+	ORPRenderer{},
+	BionicRenderer{Fraction: 0.4},
+}
+
+// ExperimentalRenderers are the newer display modes, which follow the
+// established ones in the cycling order.
+var ExperimentalRenderers = []WordRenderer{
+	VerticalRenderer{Before: 3, After: 3},
+	PreviewRenderer{Before: 1, After: 1},
+	ParallelRenderer{},
+}
+
+func allRenderers() []WordRenderer {
+	return append(append([]WordRenderer(nil), Renderers...), ExperimentalRenderers...)
+}
+
+// RendererByName returns the display mode with the given name.
+func RendererByName(name string) (WordRenderer, bool) {
+	for _, r := range allRenderers() {
+		if strings.EqualFold(r.Name(), name) {
+			return r, true
+		}
+	}
+	return nil, false
+}
+
+// NextRenderer returns the display mode after cur, wrapping around.
+func NextRenderer(cur WordRenderer) WordRenderer {
+	all := allRenderers()
+	for i, r := range all {
+		if cur != nil && r.Name() == cur.Name() {
+			return all[(i+1)%len(all)]
+		}
+	}
+	return all[0]
+}
+
+// RendererNames lists the names of all display modes, experimental ones
+// last.
+func RendererNames() []string {
+	var names []string
+	for _, r := range allRenderers() {
+		names = append(names, r.Name())
+	}
+	return names
+}
+
+// IsRTL reports whether a word is written right to left: whether its first
+// letter is Hebrew, Arabic or another right-to-left script.
+func IsRTL(word string) bool {
+	for _, r := range word {
+		if unicode.IsLetter(r) {
+			return unicode.In(r, unicode.Hebrew, unicode.Arabic, unicode.Syriac, unicode.Thaana, unicode.Nko)
+		}
+	}
+	return false
+}
+
+// VisualSegments puts a word's segments in display order, left to right.
+// A right-to-left word's segments are reversed, in order and in their
+// text, so it reads correctly where text is only laid out left to right.
+func VisualSegments(word string, segs []Segment) []Segment {
+	if !IsRTL(word) {
+		return segs
+	}
+	out := make([]Segment, len(segs))
+	for i, seg := range segs {
+		out[len(segs)-1-i] = Segment{Text: string(reverseClusters([]rune(seg.Text))), Kind: seg.Kind}
+	}
+	return out
+}
+
+// VisualText returns word in display order, left to right: reversed if it
+// is right to left, as VisualSegments does.
+func VisualText(word string) string {
+	if !IsRTL(word) {
+		return word
+	}
+	return string(reverseClusters([]rune(word)))
+}
+
+// reverseClusters reverses runes a letter at a time, keeping each letter's
+// combining marks, such as Hebrew points and Arabic vowel marks, after it.
+func reverseClusters(runes []rune) []rune {
+	out := make([]rune, 0, len(runes))
+	for end := len(runes); end > 0; {
+		start := end - 1
+		for start > 0 && unicode.IsMark(runes[start]) {
+			start--
+		}
+		out = append(out, runes[start:end]...)
+		end = start
+	}
+	return out
+}
+
+// clusterEnd returns the index just past the letter at i and the combining
+// marks that follow it.
+func clusterEnd(runes []rune, i int) int {
+	end := min(i+1, len(runes))
+	for end < len(runes) && unicode.IsMark(runes[end]) {
+		end++
+	}
+	return end
+}
+
+// VisualORP returns the rune index of a word's ORP counted from the left
+// of the word as displayed. The ORP itself is counted in reading order, so
+// for a right-to-left word it is mirrored: a third of the way in from the
+// right.
+func VisualORP(word string) int {
+	orp := GetORPPosition(word)
+	if IsRTL(word) {
+		runes := []rune(word)
+		return max(len(runes)-clusterEnd(runes, orp), 0)
+	}
+	return orp
+}
+
+func compactSegments(segs []Segment) []Segment {
+	out := segs[:0]
+	for _, s := range segs {
+		if s.Text != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
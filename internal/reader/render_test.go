@@ -0,0 +1,138 @@
+package reader
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestORPRendererSegments(t *testing.T) {
+	tests := []struct {
+		word     string
+		expected []Segment
+	}{
+		{"a", []Segment{{"a", SegmentFocus}}},
+		{"hello", []Segment{{"h", SegmentPlain}, {"e", SegmentFocus}, {"llo", SegmentPlain}}},
+		{"said,", []Segment{{"s", SegmentPlain}, {"a", SegmentFocus}, {"id", SegmentPlain}, {",", SegmentPunct}}},
+		{`("hello")`, []Segment{{`("`, SegmentPunct}, {"h", SegmentPlain}, {"e", SegmentFocus}, {"llo", SegmentPlain}, {`")`, SegmentPunct}}},
+		{"—", []Segment{{"—", SegmentFocus}}},
+		{"nai\u0308ve", []Segment{{"na", SegmentPlain}, {"i\u0308", SegmentFocus}, {"ve", SegmentPlain}}},
+		{"o\u0308l", []Segment{{"o\u0308", SegmentFocus}, {"l", SegmentPlain}}},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		got := ORPRenderer{}.Segments(tt.word)
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("Segments(%q) = %v, want %v", tt.word, got, tt.expected)
+		}
+	}
+}
+
+func TestBionicRendererSegments(t *testing.T) {
+	b := BionicRenderer{Fraction: 0.4}
+
+	tests := []struct {
+		word     string
+		expected []Segment
+	}{
+		{"a", []Segment{{"a", SegmentBold}}},
+		{"reading", []Segment{{"rea", SegmentBold}, {"ding", SegmentPlain}}},
+		{"über", []Segment{{"üb", SegmentBold}, {"er", SegmentPlain}}},
+		{"'reading.'", []Segment{{"'", SegmentPunct}, {"rea", SegmentBold}, {"ding", SegmentPlain}, {".'", SegmentPunct}}},
+	}
+
+	for _, tt := range tests {
+		got := b.Segments(tt.word)
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("Segments(%q) = %v, want %v", tt.word, got, tt.expected)
+		}
+	}
+}
+
+func TestNextRenderer(t *testing.T) {
+	r := NextRenderer(ORPRenderer{})
+	if r.Name() != "bionic" {
+		t.Errorf("NextRenderer(orp) = %s, want bionic", r.Name())
+	}
+	if NextRenderer(ParallelRenderer{}).Name() != "orp" {
+		t.Error("NextRenderer should wrap around")
+	}
+	if _, ok := RendererByName("BIONIC"); !ok {
+		t.Error("RendererByName should be case insensitive")
+	}
+}
+
+func TestVerticalRenderer(t *testing.T) {
+	r, ok := RendererByName("vertical")
+	if !ok {
+		t.Fatal("RendererByName should find experimental modes")
+	}
+	cr, ok := r.(ContextRenderer)
+	if !ok {
+		t.Fatal("vertical mode should show context")
+	}
+	if before, after := cr.Context(); before != 3 || after != 3 {
+		t.Errorf("Context() = %d, %d, want 3, 3", before, after)
+	}
+	if !reflect.DeepEqual(r.Segments("hello"), ORPRenderer{}.Segments("hello")) {
+		t.Error("vertical mode should highlight the ORP like orp mode")
+	}
+	if NextRenderer(BionicRenderer{}).Name() != "vertical" || NextRenderer(r).Name() != "preview" {
+		t.Error("cycling should go on from bionic through the experimental modes")
+	}
+	if names := RendererNames(); !reflect.DeepEqual(names[len(Renderers):], []string{"vertical", "preview", "parallel"}) {
+		t.Errorf("RendererNames() = %v, want the experimental modes last", names)
+	}
+}
+
+func TestPreviewRenderer(t *testing.T) {
+	r, ok := RendererByName("preview")
+	if !ok {
+		t.Fatal("RendererByName should find the preview mode")
+	}
+	sr, ok := r.(StripRenderer)
+	if !ok {
+		t.Fatal("preview mode should show a strip of neighbours")
+	}
+	if before, after := sr.Strip(); before != 1 || after != 1 {
+		t.Errorf("Strip() = %d, %d, want 1, 1", before, after)
+	}
+	if !reflect.DeepEqual(r.Segments("hello"), ORPRenderer{}.Segments("hello")) {
+		t.Error("preview mode should highlight the ORP like orp mode")
+	}
+}
+
+func TestRTLSegments(t *testing.T) {
+	tests := []struct {
+		word string
+		rtl  bool
+		orp  int
+		want []Segment
+	}{
+		{"hello", false, 1, []Segment{{"h", SegmentPlain}, {"e", SegmentFocus}, {"llo", SegmentPlain}}},
+		{"שלום", true, 2, []Segment{{"םו", SegmentPlain}, {"ל", SegmentFocus}, {"ש", SegmentPlain}}},
+		{"(مرحبا)", true, 4, []Segment{{")", SegmentPunct}, {"ابح", SegmentPlain}, {"ر", SegmentFocus}, {"م", SegmentPlain}, {"(", SegmentPunct}}},
+		{"123", false, 1, []Segment{{"1", SegmentPlain}, {"2", SegmentFocus}, {"3", SegmentPlain}}},
+		// Points stay with their letters.
+		{"שָׁלוֹם", true, 4, []Segment{{"םוֹל", SegmentPlain}, {"שָׁ", SegmentFocus}}},
+	}
+	for _, tt := range tests {
+		if got := IsRTL(tt.word); got != tt.rtl {
+			t.Errorf("IsRTL(%q) = %v, want %v", tt.word, got, tt.rtl)
+		}
+		if got := VisualORP(tt.word); got != tt.orp {
+			t.Errorf("VisualORP(%q) = %d, want %d", tt.word, got, tt.orp)
+		}
+		got := VisualSegments(tt.word, ORPRenderer{}.Segments(tt.word))
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("VisualSegments(%q) = %v, want %v", tt.word, got, tt.want)
+		}
+		var visual string
+		for _, seg := range tt.want {
+			visual += seg.Text
+		}
+		if got := VisualText(tt.word); got != visual {
+			t.Errorf("VisualText(%q) = %q, want %q", tt.word, got, visual)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package reader
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// RSTFormat implements Format for reStructuredText files, with a chapter
+// for each section title.
+type RSTFormat struct{}
+
+func init() {
+	Register(&RSTFormat{})
+}
+
+func (f *RSTFormat) Name() string         { return "reStructuredText" }
+func (f *RSTFormat) Extensions() []string { return []string{".rst", ".rest"} }
+
+func (f *RSTFormat) Extract(filename string) (string, error) {
+	sections, err := readSections(filename, RSTSections)
+	if err != nil {
+		return "", err
+	}
+	return sectionsText(sections), nil
+}
+
+// TOC lists the file's section titles.
+func (f *RSTFormat) TOC(filename string) ([]TOCEntry, error) {
+	sections, err := readSections(filename, RSTSections)
+	if err != nil {
+		return nil, err
+	}
+	return SectionsTOC(sections), nil
+}
+
+// ExtractChapters extracts the text with a chapter per section title.
+func (f *RSTFormat) ExtractChapters(filename string) ([]Chapter, []string, error) {
+	sections, err := readSections(filename, RSTSections)
+	if err != nil {
+		return nil, nil, err
+	}
+	chapters, _, words := AssembleSections(sections)
+	return chapters, words, nil
+}
+
+var (
+	rstDirectiveRegex = regexp.MustCompile(`^\.\.(?:\s+|$)(?:([\w:-]+)::|\[[^\]]*\]|_[^:]*:|\|[^|]*\|)?`)
+	rstRoleRegex      = regexp.MustCompile("(?::[\\w:+-]+:)?`([^`<]*?)\\s*(?:<[^>]*>)?`(?::[\\w:+-]+:)?_{0,2}")
+	rstLiteralRegex   = regexp.MustCompile("``([^`]+)``")
+	rstStrongRegex    = regexp.MustCompile(`\*\*([^*]+)\*\*|\*([^*\s][^*]*)\*`)
+	rstRefRegex       = regexp.MustCompile(`(^|[\s(])([A-Za-z0-9][\w-]*?)__?([\s).,;:!?]|$)`)
+	rstListRegex      = regexp.MustCompile(`^\s*(?:[-*+•]|#\.|\d+[.)]|\(\d+\))\s+`)
+)
+
+// rstTextDirectives keep their body text; every other directive's block,
+// such as code, images and raw HTML, is skipped.
+var rstTextDirectives = map[string]bool{
+	"note": true, "tip": true, "hint": true, "important": true, "warning": true,
+	"caution": true, "danger": true, "attention": true, "error": true, "admonition": true,
+	"seealso": true, "versionadded": true, "versionchanged": true, "deprecated": true,
+	"epigraph": true, "topic": true, "sidebar": true, "rubric": true, "highlights": true,
+}
+
+// RSTSections splits a reStructuredText document into sections at its
+// titles, with markup, literal blocks, comments and code directives
+// removed. Title levels follow the order in which underline styles first
+// appear, as in docutils. Text before the first title becomes a section
+// titled title.
+func RSTSections(title, rst string) []Section {
+	lines := strings.Split(strings.ReplaceAll(rst, "\t", "    "), "\n")
+	var sections []Section
+	cur := Section{Title: title}
+	var body []string
+	headed := false
+	flush := func() {
+		cur.Text = strings.Join(body, "\n")
+		if headed || strings.TrimSpace(cur.Text) != "" {
+			sections = append(sections, cur)
+		}
+		body = nil
+	}
+
+	var styles []string // adornment styles in order of first use
+	level := func(style string) int {
+		for i, s := range styles {
+			if s == style {
+				return i
+			}
+		}
+		styles = append(styles, style)
+		return len(styles) - 1
+	}
+
+	// skipIndent is the indentation a skipped block's lines must exceed;
+	// -1 when not skipping.
+	skipIndent := -1
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " ")
+		trimmed := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		if skipIndent >= 0 {
+			if trimmed == "" || indent > skipIndent {
+				continue
+			}
+			skipIndent = -1
+		}
+
+		// Overlined title: ====, Title, ====.
+		if isRSTAdornment(trimmed) && i+2 < len(lines) {
+			text := strings.TrimSpace(lines[i+1])
+			under := strings.TrimSpace(lines[i+2])
+			if text != "" && under == trimmed && !isRSTAdornment(text) {
+				flush()
+				cur = Section{Title: cleanRSTLine(text), Level: level("over" + trimmed[:1])}
+				headed = true
+				i += 2
+				continue
+			}
+		}
+		// Underlined title: Title, ====.
+		if trimmed != "" && indent == 0 && i+1 < len(lines) && !isRSTAdornment(trimmed) {
+			under := strings.TrimSpace(lines[i+1])
+			if isRSTAdornment(under) && utf8.RuneCountInString(under) >= min(utf8.RuneCountInString(trimmed), 3) {
+				flush()
+				cur = Section{Title: cleanRSTLine(trimmed), Level: level(under[:1])}
+				headed = true
+				i++
+				continue
+			}
+		}
+		if isRSTAdornment(trimmed) {
+			continue // transition
+		}
+
+		if m := rstDirectiveRegex.FindStringSubmatch(trimmed); m != nil {
+			name := strings.TrimPrefix(m[1], "rst:")
+			if rstTextDirectives[name] {
+				// Keep the argument (e.g. an admonition title) and body.
+				if rest := strings.TrimSpace(trimmed[len(m[0]):]); rest != "" {
+					body = append(body, cleanRSTLine(rest))
+				}
+				continue
+			}
+			skipIndent = indent
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, ":") && strings.Count(trimmed, ":") >= 2 && indent > 0 {
+			continue // directive option
+		}
+
+		if strings.HasSuffix(trimmed, "::") {
+			// A paragraph ending in "::" introduces a literal block.
+			trimmed = strings.TrimSuffix(trimmed, ":")
+			if trimmed == ":" {
+				trimmed = ""
+			}
+			skipIndent = indent
+		}
+
+		if strings.HasPrefix(trimmed, "+-") || strings.HasPrefix(trimmed, "|") {
+			if strings.Trim(trimmed, "+-=|: ") == "" {
+				continue
+			}
+			trimmed = strings.Join(strings.Fields(strings.ReplaceAll(trimmed, "|", " ")), " ")
+		}
+		body = append(body, cleanRSTLine(rstListRegex.ReplaceAllString(trimmed, "")))
+	}
+	flush()
+	return sections
+}
+
+// isRSTAdornment reports whether s is a title underline or transition: at
+// least three of the same punctuation character.
+func isRSTAdornment(s string) bool {
+	if len(s) < 3 || !strings.ContainsRune("=-`:'\".~^_*+#<>", rune(s[0])) {
+		return false
+	}
+	return strings.Count(s, s[:1]) == len(s)
+}
+
+// cleanRSTLine strips inline markup: roles, interpreted text, literals,
+// emphasis, hyperlink targets and reference underscores.
+func cleanRSTLine(s string) string {
+	s = rstLiteralRegex.ReplaceAllString(s, "$1")
+	s = rstRoleRegex.ReplaceAllString(s, "$1")
+	s = rstStrongRegex.ReplaceAllString(s, "$1$2")
+	s = rstRefRegex.ReplaceAllString(s, "$1$2$3")
+	return strings.TrimSpace(s)
+}
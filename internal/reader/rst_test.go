@@ -0,0 +1,101 @@
+package reader
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRSTSections(t *testing.T) {
+	input := `Intro with ` + "``code``" + ` and a reference_.
+
+==========
+ Document
+==========
+
+Some *emphasis*, **strong** and :ref:` + "`a role <target>`" + ` text.
+
+Section One
+-----------
+
+- First item
+- Calls ` + "`__init__`" + ` here
+
+.. code-block:: python
+
+   print("skipped")
+
+.. note:: Remember this.
+
+   Indented note body.
+
+.. comment that is skipped
+   over two lines
+
+Example::
+
+    literal block skipped
+
+----
+
+Sub
+~~~
+
+Empty
+-----
+
+Section Two
+-----------
+
++---+---+
+| a | b |
++---+---+
+`
+	sections := RSTSections("doc", input)
+	want := []Section{
+		{Title: "doc", Text: "Intro with code and a reference.\n", Level: 0},
+		{Title: "Document", Text: "\nSome emphasis, strong and a role text.\n", Level: 0},
+		{Title: "Section One", Text: "\nFirst item\nCalls __init__ here\n\nRemember this.\n\nIndented note body.\n\nExample:\n", Level: 1},
+		{Title: "Sub", Text: "", Level: 2},
+		{Title: "Empty", Text: "", Level: 1},
+		{Title: "Section Two", Text: "\na b\n", Level: 1},
+	}
+	if len(sections) != len(want) {
+		t.Fatalf("got %q, want %d sections", sections, len(want))
+	}
+	for i := range want {
+		if sections[i] != want[i] {
+			t.Errorf("section %d = %q, want %q", i, sections[i], want[i])
+		}
+	}
+}
+
+func TestRSTFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "guide.rst")
+	content := "Guide\n=====\n\nStart here.\n\nInstall\n-------\n\nRun the installer.\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	f := &RSTFormat{}
+	toc, err := f.TOC(path)
+	if err != nil {
+		t.Fatalf("TOC: %v", err)
+	}
+	if len(toc) != 2 || toc[0].Title != "Guide" || toc[1].Title != "Install" ||
+		toc[1].WordIndex != 2 || toc[1].Level != 1 {
+		t.Errorf("got TOC %+v", toc)
+	}
+
+	chapters, words, err := f.ExtractChapters(path)
+	if err != nil {
+		t.Fatalf("ExtractChapters: %v", err)
+	}
+	if len(words) != 5 || len(chapters) != 2 || chapters[1].WordStart != 2 {
+		t.Errorf("got chapters %+v, words %q", chapters, words)
+	}
+
+	if ff, ok := FormatFor("guide.rest"); !ok || ff.Name() != "reStructuredText" {
+		t.Error("FormatFor should find the reStructuredText format")
+	}
+}
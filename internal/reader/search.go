@@ -0,0 +1,86 @@
+package reader
+
+import "strings"
+
+// FindNext returns the index of the next match for query strictly after from,
+// wrapping around to the start. Returns -1 if the query does not occur.
+func (r *Reader) FindNext(query string, from int) int {
+	terms := searchTerms(query)
+	if len(terms) == 0 || len(r.Words) == 0 {
+		return -1
+	}
+	n := len(r.Words)
+	for step := 1; step <= n; step++ {
+		i := (from + step) % n
+		if r.matchAt(terms, i) {
+			return i
+		}
+	}
+	return -1
+}
+
+// FindPrev returns the index of the previous match for query strictly before
+// from, wrapping around to the end. Returns -1 if the query does not occur.
+func (r *Reader) FindPrev(query string, from int) int {
+	terms := searchTerms(query)
+	if len(terms) == 0 || len(r.Words) == 0 {
+		return -1
+	}
+	n := len(r.Words)
+	for step := 1; step <= n; step++ {
+		i := ((from-step)%n + n) % n
+		if r.matchAt(terms, i) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Search sets the active query and jumps to its first match at or after the
+// current word. Returns false if there is no match.
+func (r *Reader) Search(query string) bool {
+	r.SearchQuery = query
+	idx := r.FindNext(query, r.CurrentIndex-1)
+	if idx < 0 {
+		return false
+	}
+	r.Jump(idx)
+	return true
+}
+
+// SearchNext jumps to the next match of the active query.
+func (r *Reader) SearchNext() bool {
+	idx := r.FindNext(r.SearchQuery, r.CurrentIndex)
+	if idx < 0 {
+		return false
+	}
+	r.Jump(idx)
+	return true
+}
+
+// SearchPrev jumps to the previous match of the active query.
+func (r *Reader) SearchPrev() bool {
+	idx := r.FindPrev(r.SearchQuery, r.CurrentIndex)
+	if idx < 0 {
+		return false
+	}
+	r.Jump(idx)
+	return true
+}
+
+// matchAt reports whether consecutive words starting at i contain the terms.
+func (r *Reader) matchAt(terms []string, i int) bool {
+	if i+len(terms) > len(r.Words) {
+		return false
+	}
+	for j, term := range terms {
+		if !strings.Contains(strings.ToLower(r.Words[i+j]), term) {
+			return false
+		}
+	}
+	return true
+}
+
+func searchTerms(query string) []string {
+	return strings.Fields(strings.ToLower(query))
+}
@@ -0,0 +1,64 @@
+package reader
+
+import "testing"
+
+func TestFindNext(t *testing.T) {
+	r := NewReader("The cat sat. The Cat ran away. A dog sat down.", 300)
+
+	tests := []struct {
+		name     string
+		query    string
+		from     int
+		expected int
+	}{
+		{"first match", "cat", -1, 1},
+		{"case insensitive", "cat", 1, 4},
+		{"wraps around", "cat", 4, 1},
+		{"phrase", "dog sat", -1, 8},
+		{"substring", "awa", -1, 6},
+		{"no match", "bird", -1, -1},
+		{"empty query", "  ", -1, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.FindNext(tt.query, tt.from); got != tt.expected {
+				t.Errorf("FindNext(%q, %d) = %d, want %d", tt.query, tt.from, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFindPrev(t *testing.T) {
+	r := NewReader("The cat sat. The Cat ran away.", 300)
+
+	if got := r.FindPrev("cat", 4); got != 1 {
+		t.Errorf("FindPrev = %d, want 1", got)
+	}
+	if got := r.FindPrev("cat", 1); got != 4 {
+		t.Errorf("FindPrev should wrap, got %d, want 4", got)
+	}
+}
+
+func TestSearchNavigation(t *testing.T) {
+	r := NewReader("one two one three one", 300)
+
+	if !r.Search("one") || r.CurrentIndex != 0 {
+		t.Fatalf("Search should land on current word, got %d", r.CurrentIndex)
+	}
+	if !r.SearchNext() || r.CurrentIndex != 2 {
+		t.Errorf("SearchNext = %d, want 2", r.CurrentIndex)
+	}
+	if !r.SearchNext() || r.CurrentIndex != 4 {
+		t.Errorf("SearchNext = %d, want 4", r.CurrentIndex)
+	}
+	if !r.SearchPrev() || r.CurrentIndex != 2 {
+		t.Errorf("SearchPrev = %d, want 2", r.CurrentIndex)
+	}
+	if r.Search("missing") {
+		t.Error("Search should fail for missing query")
+	}
+	if r.CurrentIndex != 2 {
+		t.Errorf("failed search should not move, got %d", r.CurrentIndex)
+	}
+}
@@ -0,0 +1,96 @@
+package reader
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Section is a titled block of text that becomes one chapter when several
+// pieces (pages, files, issue comments) are read as a single document.
+type Section struct {
+	Title string
+	Text  string
+	Level int
+}
+
+// AssembleSections joins sections into a single word list, returning a
+// chapter and TOC entry for every section that has words.
+func AssembleSections(sections []Section) ([]Chapter, []TOCEntry, []string) {
+	var words []string
+	var chapters []Chapter
+	var toc []TOCEntry
+
+	for _, s := range sections {
+		sw := ParseText(s.Text)
+		if len(sw) == 0 {
+			continue
+		}
+		start := len(words)
+		words = append(words, sw...)
+		chapters = append(chapters, Chapter{
+			Title:     s.Title,
+			WordStart: start,
+			WordEnd:   len(words) - 1,
+		})
+		toc = append(toc, TOCEntry{
+			Title:     s.Title,
+			Preview:   previewWords(sw),
+			WordIndex: start,
+			Level:     s.Level,
+		})
+	}
+
+	return chapters, toc, words
+}
+
+// previewWords returns the first few words of a chapter for TOC display.
+func previewWords(words []string) string {
+	if len(words) == 0 {
+		return ""
+	}
+	if len(words) > 10 {
+		words = words[:10]
+	}
+	return strings.Join(words, " ") + "..."
+}
+
+// SectionsTOC lists every section, including headings with no text of their
+// own, which point at the next section's first word.
+func SectionsTOC(sections []Section) []TOCEntry {
+	toc := make([]TOCEntry, 0, len(sections))
+	words := 0
+	for _, s := range sections {
+		sw := ParseText(s.Text)
+		toc = append(toc, TOCEntry{
+			Title:     s.Title,
+			Preview:   previewWords(sw),
+			WordIndex: words,
+			Level:     s.Level,
+		})
+		words += len(sw)
+	}
+	return toc
+}
+
+// sectionsText joins the sections' text in reading order.
+func sectionsText(sections []Section) string {
+	texts := make([]string, 0, len(sections))
+	for _, s := range sections {
+		if t := strings.TrimSpace(s.Text); t != "" {
+			texts = append(texts, t)
+		}
+	}
+	return strings.Join(texts, "\n\n")
+}
+
+// readSections parses a file into sections with parse, passing the file's
+// base name as the title for text before the first heading.
+func readSections(filename string, parse func(title, text string) []Section) ([]Section, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	title := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	return parse(title, DecodeText(data)), nil
+}
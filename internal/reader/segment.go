@@ -0,0 +1,129 @@
+package reader
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxCJKChunk is how many ideographs or katakana a CJK word may hold before
+// it is split. Without a dictionary, short chunks keep each flash readable.
+const maxCJKChunk = 3
+
+// cjkClass groups runes for segmenting text written without spaces.
+type cjkClass int
+
+const (
+	cjkNone cjkClass = iota
+	cjkHan
+	cjkHiragana
+	cjkKatakana
+	cjkOpen  // opening brackets and quotes, which join the next word
+	cjkClose // closing brackets, quotes and punctuation, which join the previous word
+)
+
+func classifyCJK(r rune) cjkClass {
+	switch {
+	case unicode.Is(unicode.Han, r):
+		return cjkHan
+	case unicode.Is(unicode.Hiragana, r):
+		return cjkHiragana
+	case unicode.Is(unicode.Katakana, r) || r == 'ー':
+		return cjkKatakana
+	case strings.ContainsRune("「『（【〈《〔［｛“‘([{", r):
+		return cjkOpen
+	case strings.ContainsRune("」』）】〉》〕］｝”’。、，．！？：；・…〜)]}.,!?;:'\"", r):
+		return cjkClose
+	}
+	return cjkNone
+}
+
+// isCJK reports whether r is an ideograph or kana.
+func isCJK(r rune) bool {
+	c := classifyCJK(r)
+	return c == cjkHan || c == cjkHiragana || c == cjkKatakana
+}
+
+// splitWords normalizes text and splits it into display words at
+// whitespace, then breaks runs of Chinese and Japanese, which are written
+// without spaces, into short words.
+func splitWords(text string) []string {
+	fields := joinOpeners(strings.Fields(Normalize(text)))
+	var words []string
+	for _, f := range fields {
+		words = append(words, segmentCJK(f)...)
+	}
+	return words
+}
+
+// segmentCJK breaks a whitespace-free field into words. Runs of ideographs
+// and katakana are cut every maxCJKChunk runes; hiragana (particles and
+// verb endings) stays with the word before it; non-CJK text such as Latin
+// words and numbers is kept whole; punctuation joins the neighbouring word
+// it belongs to, and a full-width full stop, exclamation or question mark
+// ends its word.
+func segmentCJK(field string) []string {
+	if !strings.ContainsFunc(field, isCJK) {
+		return []string{field}
+	}
+
+	var words []string
+	var cur []rune
+	var prev cjkClass     // class of the last non-punctuation rune in cur
+	content, kana := 0, 0 // ideographs/katakana and hiragana in cur
+	open := false         // cur holds only opening punctuation so far
+	ended := false        // cur ends a sentence
+	flush := func() {
+		if len(cur) > 0 {
+			words = append(words, string(cur))
+		}
+		cur, prev, content, kana, open, ended = nil, cjkNone, 0, 0, false, false
+	}
+
+	for _, r := range field {
+		c := classifyCJK(r)
+		switch c {
+		case cjkClose:
+			cur = append(cur, r)
+			ended = ended || (r >= utf8.RuneSelf && endsSentence(string(r)))
+			continue
+		case cjkOpen:
+			if !open {
+				flush()
+			}
+			cur = append(cur, r)
+			open = true
+			continue
+		}
+
+		if !open && len(cur) > 0 && (ended || breaksBefore(c, prev, content, kana)) {
+			flush()
+		}
+		cur = append(cur, r)
+		open = false
+		prev = c
+		switch c {
+		case cjkHan, cjkKatakana:
+			content++
+		case cjkHiragana:
+			kana++
+		}
+	}
+	flush()
+	return words
+}
+
+// breaksBefore reports whether a rune of class c starts a new word after a
+// word whose last letter has class prev and which holds content ideographs
+// or katakana and kana hiragana.
+func breaksBefore(c, prev cjkClass, content, kana int) bool {
+	switch c {
+	case cjkHiragana:
+		// Particles and endings stay with the word before them, but a run
+		// of hiragana alone is cut like ideographs.
+		return prev == cjkNone || (content == 0 && kana >= 2*maxCJKChunk)
+	case cjkHan, cjkKatakana:
+		return c != prev || content >= maxCJKChunk
+	}
+	return prev != cjkNone
+}
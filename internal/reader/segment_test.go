@@ -0,0 +1,37 @@
+package reader
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseTextCJK(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"plain English words", []string{"plain", "English", "words"}},
+		{"日本語を勉強します。", []string{"日本語を", "勉強します。"}},
+		{"我们今天去公园。", []string{"我们今", "天去公", "园。"}},
+		{"「こんにちは」と言った。", []string{"「こんにちは」と", "言った。"}},
+		{"コンピューターで計算", []string{"コンピ", "ュータ", "ーで", "計算"}},
+		{"iPhoneを買った", []string{"iPhone", "を", "買った"}},
+		{"2024年に東京へ", []string{"2024", "年に", "東京へ"}},
+		{"ありがとうございました", []string{"ありがとうご", "ざいました"}},
+		{"(東京)に行く", []string{"(東京)に", "行く"}},
+		{"Hello 世界!", []string{"Hello", "世界!"}},
+	}
+	for _, tt := range tests {
+		if got := ParseText(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseText(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestWordStreamCJK(t *testing.T) {
+	s := NewWordStream(strings.NewReader("日本語を勉強します。 done"))
+	if got, want := s.Next(10), []string{"日本語を", "勉強します。", "done"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Next = %q, want %q", got, want)
+	}
+}
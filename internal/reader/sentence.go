@@ -0,0 +1,70 @@
+package reader
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// sentenceClosers are the quotes and brackets that may follow the mark
+// ending a sentence, as in "Stop." or (see below.).
+const sentenceClosers = `"')]}’”»›」』）】`
+
+// abbreviations are words that end in a full stop without ending the
+// sentence, compared lowercased and without the stop. Those that often end
+// a sentence too, such as "etc.", are left out: the word after them tells.
+var abbreviations = map[string]bool{
+	"mr": true, "mrs": true, "ms": true, "dr": true, "prof": true, "rev": true,
+	"hon": true, "st": true, "mt": true, "gen": true, "col": true, "lt": true,
+	"sgt": true, "capt": true, "gov": true, "sen": true, "rep": true,
+	"e.g": true, "i.e": true, "cf": true, "vs": true, "viz": true,
+	"approx": true, "fig": true, "figs": true, "vol": true, "vols": true,
+	"ch": true, "ed": true, "eds": true, "jan": true, "feb": true, "mar": true,
+	"apr": true, "jun": true, "jul": true, "aug": true, "sep": true,
+	"sept": true, "oct": true, "nov": true, "dec": true, "ca": true,
+}
+
+// numberedAbbreviations are abbreviations only when a number follows, as
+// in "No. 5", since alone they are words that can end a sentence.
+var numberedAbbreviations = map[string]bool{"no": true, "nos": true, "p": true, "pp": true}
+
+// sentenceBreak reports whether a sentence ends after word when next
+// follows it, next being "" if it is unknown. Besides ending in sentence
+// punctuation, before any closing quotes and brackets, word must not be
+// an abbreviation such as "Dr." or "e.g." or an initial such as the "J."
+// of "J. Smith". A question, exclamation or trailing off followed by a
+// lowercase word goes on, as in "Why?" she asked.
+func (l Language) sentenceBreak(word, next string) bool {
+	if !l.endsSentence(word) {
+		return false
+	}
+	trimmed := strings.TrimRight(word, sentenceClosers)
+	if !strings.HasSuffix(trimmed, ".") || strings.HasSuffix(trimmed, "..") {
+		return !startsLowercase(next)
+	}
+	core := strings.TrimLeft(strings.TrimSuffix(trimmed, "."), `"'([{‘“«‹`)
+	// An initial: a single capital letter.
+	if r, size := utf8.DecodeRuneInString(core); size == len(core) && unicode.IsUpper(r) {
+		return false
+	}
+	core = strings.ToLower(core)
+	if numberedAbbreviations[core] {
+		start, end := CoreBounds(next)
+		return !isNumber([]rune(next)[start:end])
+	}
+	return !abbreviations[core]
+}
+
+// startsLowercase reports whether word's first letter, after any opening
+// punctuation, is lowercase.
+func startsLowercase(word string) bool {
+	for _, r := range word {
+		if unicode.IsLetter(r) {
+			return unicode.IsLower(r)
+		}
+		if unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,35 @@
+package reader
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFindSentenceStarts(t *testing.T) {
+	tests := []struct {
+		text string
+		want []int
+	}{
+		{"One two. Three four. Five", []int{0, 2, 4}},
+		{"Dr. Watson arrived. He sat.", []int{0, 3}},
+		{"Use tools, e.g. Hammers. Or not.", []int{0, 4}},
+		{"Pi is 3.14 or so. Next.", []int{0, 5}},
+		{"See No. 5 now. No. Never.", []int{0, 4, 5}},
+		{"J. R. R. Tolkien wrote it. Then", []int{0, 6}},
+		{`He said "Stop." Then left.`, []int{0, 3}},
+		{"(It was late.) We slept.", []int{0, 3}},
+		{`"Why?" she asked. "Now!" Go.`, []int{0, 3, 4}},
+		{"Wait... and see. Done", []int{0, 3}},
+		{"It ended... Then more.", []int{0, 2}},
+	}
+	for _, tt := range tests {
+		words := strings.Fields(tt.text)
+		if got := FindSentenceStarts(words); !slices.Equal(got, tt.want) {
+			t.Errorf("FindSentenceStarts(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+		if got := NewReader(tt.text, 300).SentenceStarts; !slices.Equal(got, tt.want) {
+			t.Errorf("NewReader(%q).SentenceStarts = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package reader
+
+// SetSequence sets the chapters to read, by index, in the order to read
+// them. Advance moves from the end of each to the start of the next, and
+// Progress and AtEnd count only them. Chapters out of range or repeated
+// are dropped, and an empty sequence reads every chapter in document
+// order. A reader at the start of the document, or not in one of the
+// chapters, moves to the start of the first. Streamed readers, which do
+// not know their chapters ahead, always read in document order.
+func (r *Reader) SetSequence(sequence []int) {
+	r.sequence = r.validSequence(sequence)
+	if len(r.sequence) > 0 && (r.CurrentIndex == 0 || r.sequenceIndex(r.CurrentChapter) < 0) {
+		r.CurrentIndex = r.Chapters[r.sequence[0]].WordStart
+		r.frame = 0
+		r.updateCurrentChapter()
+	}
+}
+
+// Sequence returns the chapters being read in order, or nil when reading
+// every chapter in document order.
+func (r *Reader) Sequence() []int {
+	return r.sequence
+}
+
+// Start returns the position reading starts from: the first chapter of the
+// sequence, or the start of the document.
+func (r *Reader) Start() int {
+	if len(r.sequence) > 0 {
+		return r.Chapters[r.sequence[0]].WordStart
+	}
+	return 0
+}
+
+// validSequence returns the chapters of sequence that r has, once each.
+func (r *Reader) validSequence(sequence []int) []int {
+	if r.lazy() {
+		return nil
+	}
+	var valid []int
+	seen := make(map[int]bool)
+	for _, c := range sequence {
+		if c >= 0 && c < len(r.Chapters) && !seen[c] {
+			seen[c] = true
+			valid = append(valid, c)
+		}
+	}
+	return valid
+}
+
+// sequenceIndex returns the place of chapter in the sequence, or -1 if it
+// is not in it or there is none.
+func (r *Reader) sequenceIndex(chapter int) int {
+	for i, c := range r.sequence {
+		if c == chapter {
+			return i
+		}
+	}
+	return -1
+}
+
+// chapterEnd returns the index of the last word of chapter, which runs
+// until the next one starts.
+func (r *Reader) chapterEnd(chapter int) int {
+	if chapter+1 < len(r.Chapters) {
+		return r.Chapters[chapter+1].WordStart - 1
+	}
+	return len(r.Words) - 1
+}
+
+// nextInSequence moves from the last word of a chapter in the sequence to
+// the start of the next one. It reports whether the current word ends a
+// chapter in the sequence, and whether there was a next one to move to.
+func (r *Reader) nextInSequence() (atEnd, moved bool) {
+	p := r.sequenceIndex(r.CurrentChapter)
+	if p < 0 || r.CurrentIndex < r.chapterEnd(r.CurrentChapter) {
+		return false, false
+	}
+	if p+1 == len(r.sequence) {
+		return true, false
+	}
+	r.CurrentChapter = r.sequence[p+1]
+	r.CurrentIndex = r.Chapters[r.CurrentChapter].WordStart
+	return true, true
+}
+
+// sequenceProgress returns Progress counted over the chapters of the
+// sequence, reporting false when the current chapter is not in it.
+func (r *Reader) sequenceProgress() (current, total int, ok bool) {
+	p := r.sequenceIndex(r.CurrentChapter)
+	if p < 0 {
+		return 0, 0, false
+	}
+	for i, c := range r.sequence {
+		n := r.chapterEnd(c) - r.Chapters[c].WordStart + 1
+		if i < p {
+			current += n
+		}
+		total += n
+	}
+	current += r.CurrentIndex - r.Chapters[r.CurrentChapter].WordStart + 1
+	return current, total, true
+}
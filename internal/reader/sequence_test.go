@@ -0,0 +1,46 @@
+package reader
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSequence(t *testing.T) {
+	r := NewReader("a1 a2. b1 b2. c1 c2.", 300)
+	r.SetChapters([]Chapter{{Title: "A", WordStart: 0, WordEnd: 1}, {Title: "B", WordStart: 2, WordEnd: 3}, {Title: "C", WordStart: 4, WordEnd: 5}}, nil)
+
+	r.SetSequence([]int{2, 0, 7, 2})
+	if !slices.Equal(r.Sequence(), []int{2, 0}) {
+		t.Fatalf("Sequence() = %v, want [2 0]", r.Sequence())
+	}
+	if r.CurrentWord() != "c1" || r.AtChapterStart() {
+		t.Errorf("SetSequence should move to the first chapter read, at %q", r.CurrentWord())
+	}
+	var read []string
+	for {
+		read = append(read, r.CurrentWord())
+		if cur, total := r.Progress(); cur != len(read) || total != 4 {
+			t.Errorf("Progress() at %q = %d/%d, want %d/4", r.CurrentWord(), cur, total, len(read))
+		}
+		if !r.Advance() {
+			break
+		}
+		if r.CurrentWord() == "a1" && !r.AtChapterStart() {
+			t.Error("moving to the next chapter read should start a chapter")
+		}
+	}
+	if !slices.Equal(read, []string{"c1", "c2.", "a1", "a2."}) || !r.AtEnd() {
+		t.Errorf("read %q, at end %v; want C then A", read, r.AtEnd())
+	}
+
+	// A chapter left out is read in document order when jumped to.
+	r.JumpToChapter(r.Chapters[1].WordStart)
+	if r.Advance(); r.CurrentWord() != "b2." || !r.Advance() || r.CurrentWord() != "c1" {
+		t.Errorf("reading on from B reached %q, want c1", r.CurrentWord())
+	}
+
+	r.SetSequence(nil)
+	if r.Sequence() != nil || !r.Advance() || r.CurrentWord() != "c2." {
+		t.Errorf("without a sequence at %q, want c2.", r.CurrentWord())
+	}
+}
@@ -0,0 +1,74 @@
+package reader
+
+import "strings"
+
+// DefaultMaxWordLength is the longest word, in runes, shown in one frame.
+const DefaultMaxWordLength = 20
+
+// wordBreaks are the characters a long word is preferably split after, such
+// as those separating the parts of URLs, paths and hyphenated compounds.
+const wordBreaks = "-/._?&=#:"
+
+// SplitLongWord splits a word longer than max runes into frames of at most
+// max runes, each but the last ending in a hyphen. Splits fall after a
+// separator such as / or - where one is near the limit; otherwise the
+// word is cut into frames of about equal length, so no frame is a stub.
+// A max below 2 leaves the word whole.
+func SplitLongWord(word string, max int) []string {
+	runes := []rune(word)
+	if max < 2 || len(runes) <= max {
+		return []string{word}
+	}
+
+	var frames []string
+	for len(runes) > max {
+		// Leave room for the hyphen on every frame but the last.
+		limit := max - 1
+		cut := 0
+		for i := limit; i >= limit/2 && i > 0; i-- {
+			if strings.ContainsRune(wordBreaks, runes[i-1]) {
+				cut = i
+				break
+			}
+		}
+		if cut == 0 {
+			parts := (len(runes) + limit - 1) / limit
+			cut = (len(runes) + parts - 1) / parts
+		}
+		frame := string(runes[:cut])
+		if runes[cut-1] != '-' {
+			frame += "-"
+		}
+		frames = append(frames, frame)
+		runes = runes[cut:]
+	}
+	return append(frames, string(runes))
+}
+
+// Frame returns which frame of the current word is shown, counting from 0.
+// Only words longer than MaxWordLength have more than one frame.
+func (r *Reader) Frame() int {
+	if r.framePos != r.Position() {
+		return 0
+	}
+	return r.frame
+}
+
+// frames returns the frames of the current word.
+func (r *Reader) frames() []string {
+	if r.CurrentIndex < 0 || r.CurrentIndex >= len(r.Words) {
+		return nil
+	}
+	return SplitLongWord(linkWord(r.Words[r.CurrentIndex], r.LinkPolicy), r.MaxWordLength)
+}
+
+// nextFrame moves to the next frame of the current word, reporting false
+// if the current frame is its last.
+func (r *Reader) nextFrame() bool {
+	f := r.Frame()
+	if f+1 >= len(r.frames()) {
+		return false
+	}
+	r.frame, r.framePos = f+1, r.Position()
+	return true
+}
@@ -0,0 +1,67 @@
+package reader
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitLongWord(t *testing.T) {
+	tests := []struct {
+		word string
+		max  int
+		want []string
+	}{
+		{"short", 20, []string{"short"}},
+		{"Donaudampfschifffahrtsgesellschaft", 0, []string{"Donaudampfschifffahrtsgesellschaft"}},
+		{"Donaudampfschifffahrtsgesellschaft", 20, []string{"Donaudampfschifff-", "ahrtsgesellschaft"}},
+		{"https://example.com/docs/reader", 20, []string{"https://example.-", "com/docs/reader"}},
+		{"well-known-self-contained", 12, []string{"well-known-", "self-", "contained"}},
+		{"abcdefghij", 4, []string{"abc-", "def-", "ghij"}},
+	}
+	for _, tt := range tests {
+		got := SplitLongWord(tt.word, tt.max)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitLongWord(%q, %d) = %q, want %q", tt.word, tt.max, got, tt.want)
+		}
+		for _, f := range got {
+			if tt.max > 1 && len([]rune(f)) > tt.max {
+				t.Errorf("SplitLongWord(%q, %d): frame %q is too long", tt.word, tt.max, f)
+			}
+		}
+	}
+}
+
+func TestReaderFrames(t *testing.T) {
+	r := NewReader("see abcdefghij now", 300)
+	r.MaxWordLength = 4
+	var shown []string
+	for {
+		shown = append(shown, r.CurrentWord())
+		if !r.Advance() {
+			break
+		}
+	}
+	want := []string{"see", "abc-", "def-", "ghij", "now"}
+	if !reflect.DeepEqual(shown, want) {
+		t.Errorf("shown %q, want %q", shown, want)
+	}
+	if r.Position() != 2 {
+		t.Errorf("Position() = %d, want 2", r.Position())
+	}
+
+	r.JumpToChapter(1)
+	r.Advance()
+	if r.CurrentWord() != "def-" || r.Frame() != 1 || r.AtEnd() {
+		t.Errorf("after one advance: word %q frame %d", r.CurrentWord(), r.Frame())
+	}
+	r.JumpToChapter(1)
+	if r.CurrentWord() != "abc-" {
+		t.Errorf("jumping back should show the first frame, got %q", r.CurrentWord())
+	}
+
+	last := NewReader("abcdefghij", 300)
+	last.MaxWordLength = 4
+	if last.AtEnd() {
+		t.Error("AtEnd should be false while frames of the last word remain")
+	}
+}
@@ -0,0 +1,95 @@
+package reader
+
+import (
+	"sort"
+	"strings"
+)
+
+// StopwordMode controls how common function words are shown.
+type StopwordMode int
+
+const (
+	// StopwordsShow shows function words like any other word.
+	StopwordsShow StopwordMode = iota
+	// StopwordsHalf shows function words for half the usual time.
+	StopwordsHalf
+	// StopwordsSkip leaves function words out entirely.
+	StopwordsSkip
+)
+
+var stopwordModeNames = []string{"off", "half", "skip"}
+
+func (m StopwordMode) String() string {
+	if m < 0 || int(m) >= len(stopwordModeNames) {
+		return "off"
+	}
+	return stopwordModeNames[m]
+}
+
+// Next returns the mode after m, wrapping around.
+func (m StopwordMode) Next() StopwordMode {
+	return (m + 1) % StopwordMode(len(stopwordModeNames))
+}
+
+// ParseStopwordMode parses "off", "half" or "skip".
+func ParseStopwordMode(s string) (StopwordMode, bool) {
+	for i, name := range stopwordModeNames {
+		if strings.EqualFold(s, name) {
+			return StopwordMode(i), true
+		}
+	}
+	return StopwordsShow, false
+}
+
+// stopwordLists holds the most common function words of each language.
+var stopwordLists = map[string]string{
+	"en": "a an the of to in on at by for from with and or but nor so as if than that this these those " +
+		"is are was were be been am it its he she they we you i me my his her their our your him them us " +
+		"not no do does did has have had will would shall should can could may might must " +
+		"into onto upon about over under then there here which who whom what when where while",
+	"de": "der die das den dem des ein eine einer eines einem einen und oder aber denn als wie wenn dass " +
+		"zu zum zur in im an am auf aus bei mit nach von vom vor für um über unter durch " +
+		"ist sind war waren sein hat haben hatte es er sie wir ihr ich du man nicht auch so noch nur",
+	"fr": "le la les un une des du de au aux et ou mais donc or ni car que qui quoi dont où " +
+		"à en dans par pour sur sous avec sans chez vers est sont était être a ont avait il elle ils elles " +
+		"on nous vous je tu me te se ce cette ces son sa ses leur leurs ne pas plus y",
+	"es": "el la los las lo un una unos unas y o u e pero ni que quien cual cuando donde como " +
+		"de del a al en con por para sin sobre entre hasta desde es son era fue ser ha han había " +
+		"se me te le les nos os su sus mi mis tu tus no ya muy más",
+}
+
+// StopwordLanguages lists the languages with a built-in stopword list.
+func StopwordLanguages() []string {
+	langs := make([]string, 0, len(stopwordLists))
+	for lang := range stopwordLists {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Stopwords returns the function word set for a language code such as
+// "en" or "de-AT".
+func Stopwords(lang string) (map[string]bool, bool) {
+	base, _, _ := strings.Cut(strings.ToLower(lang), "-")
+	list, ok := stopwordLists[base]
+	if !ok {
+		return nil, false
+	}
+	set := make(map[string]bool)
+	for _, w := range strings.Fields(list) {
+		set[w] = true
+	}
+	return set, true
+}
+
+// isStopword reports whether the word at idx is in the reader's stopword
+// set, ignoring case and surrounding punctuation.
+func (r *Reader) isStopword(idx int) bool {
+	if len(r.Stopwords) == 0 || idx < 0 || idx >= len(r.Words) {
+		return false
+	}
+	runes := []rune(r.Words[idx])
+	start, end := CoreBounds(r.Words[idx])
+	return r.Stopwords[strings.ToLower(string(runes[start:end]))]
+}
@@ -0,0 +1,82 @@
+package reader
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseStopwordMode(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want StopwordMode
+		ok   bool
+	}{
+		{"off", StopwordsShow, true},
+		{"HALF", StopwordsHalf, true},
+		{"skip", StopwordsSkip, true},
+		{"some", StopwordsShow, false},
+	} {
+		got, ok := ParseStopwordMode(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseStopwordMode(%q) = %v, %v, want %v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+	if StopwordsSkip.Next() != StopwordsShow {
+		t.Error("Next should wrap around to off")
+	}
+}
+
+func TestStopwords(t *testing.T) {
+	en, ok := Stopwords("en")
+	if !ok || !en["the"] || en["reading"] {
+		t.Errorf("unexpected English stopwords: %v", ok)
+	}
+	if de, ok := Stopwords("de-AT"); !ok || !de["und"] {
+		t.Error("regional codes should use the base language list")
+	}
+	if _, ok := Stopwords("xx"); ok {
+		t.Error("unknown languages should have no list")
+	}
+}
+
+func TestStopwordModes(t *testing.T) {
+	newReader := func(mode StopwordMode) *Reader {
+		r := NewReader("The cat sat on the mat. Of", 600)
+		r.Stopwords, _ = Stopwords("en")
+		r.StopwordMode = mode
+		return r
+	}
+	read := func(r *Reader) string {
+		words := []string{r.CurrentWord()}
+		for r.Advance() {
+			words = append(words, r.CurrentWord())
+		}
+		return strings.Join(words, " ")
+	}
+
+	if got := read(newReader(StopwordsShow)); got != "The cat sat on the mat. Of" {
+		t.Errorf("off mode read %q", got)
+	}
+	if got := read(newReader(StopwordsSkip)); got != "The cat sat mat. Of" {
+		t.Errorf("skip mode read %q, want function words skipped except the first and last", got)
+	}
+
+	r := newReader(StopwordsSkip)
+	r.SetChapters([]Chapter{{Title: "One", WordStart: 0}, {Title: "Two", WordStart: 4}}, nil)
+	if got := read(r); got != "The cat sat the mat. Of" {
+		t.Errorf("skip mode read %q, want a chapter's first word kept", got)
+	}
+
+	r = newReader(StopwordsHalf)
+	r.JumpToChapter(3)
+	if got, want := r.WordDelay(), r.GetDelay()/2; got != want {
+		t.Errorf("half mode delay for %q = %v, want %v", r.CurrentWord(), got, want)
+	}
+	if !r.CurrentToken().Stopword || r.Token(1).Stopword {
+		t.Error("Token should flag function words")
+	}
+	r.JumpToChapter(1)
+	if got := r.WordDelay(); got != r.GetDelay() {
+		t.Errorf("half mode delay for %q = %v", r.CurrentWord(), got)
+	}
+}
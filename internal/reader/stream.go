@@ -0,0 +1,270 @@
+package reader
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode"
+)
+
+const (
+	// streamChunk is how many words are read ahead of the current position.
+	streamChunk = 2048
+	// streamWindow is how many already-read words are kept behind the
+	// current position for backward sentence jumps.
+	streamWindow = 8192
+)
+
+// WordStream reads whitespace-separated words lazily from an io.Reader.
+type WordStream struct {
+	r         *bufio.Reader
+	partial   strings.Builder
+	eof       bool
+	err       error
+	bytesRead int64
+	wordsRead int
+
+	// Follow keeps the stream open at end of input so words appended later
+	// (a growing log file, a slow pipe) are still read.
+	Follow bool
+}
+
+// NewWordStream creates a word stream over r.
+func NewWordStream(r io.Reader) *WordStream {
+	return &WordStream{r: bufio.NewReaderSize(r, 64*1024)}
+}
+
+// Next reads about n words: a few more when the last field splits into
+// several CJK words, and fewer when input is exhausted for now. In follow
+// mode a later call may return more.
+func (s *WordStream) Next(n int) []string {
+	var words []string
+	if s.err != nil {
+		return nil
+	}
+	s.eof = false
+	for len(words) < n {
+		ch, size, err := s.r.ReadRune()
+		if err != nil {
+			if err != io.EOF {
+				s.err = err
+			}
+			s.eof = true
+			if !s.Follow && s.partial.Len() > 0 {
+				words = append(words, splitWords(s.partial.String())...)
+				s.partial.Reset()
+			}
+			break
+		}
+		s.bytesRead += int64(size)
+		if unicode.IsSpace(ch) {
+			if s.partial.Len() > 0 {
+				words = append(words, splitWords(s.partial.String())...)
+				s.partial.Reset()
+			}
+			continue
+		}
+		s.partial.WriteRune(ch)
+	}
+	s.wordsRead += len(words)
+	return words
+}
+
+// Done reports whether the stream has no more words and never will.
+func (s *WordStream) Done() bool {
+	return s.err != nil || (s.eof && !s.Follow)
+}
+
+// AtEOF reports whether the last read reached the current end of input.
+func (s *WordStream) AtEOF() bool {
+	return s.eof
+}
+
+// Err returns the first non-EOF read error.
+func (s *WordStream) Err() error {
+	return s.err
+}
+
+// NewStreamReader creates a Reader that pulls words lazily from src,
+// keeping only a sliding window in memory. sizeHint is the input size in
+// bytes, used to estimate the total word count; pass 0 if unknown.
+func NewStreamReader(src io.Reader, sizeHint int64, wpm int) *Reader {
+	return NewFollowReader(NewWordStream(DecodeReader(src)), sizeHint, wpm)
+}
+
+// NewTailReader creates a streaming Reader that keeps following src after
+// reaching the end of input, as for a growing log file. src should not block
+// at end of input; wrap pipes with NewPollReader.
+func NewTailReader(src io.Reader, wpm int) *Reader {
+	stream := NewWordStream(src)
+	stream.Follow = true
+	return NewFollowReader(stream, 0, wpm)
+}
+
+// NewFollowReader creates a streaming Reader over an existing word stream.
+func NewFollowReader(stream *WordStream, sizeHint int64, wpm int) *Reader {
+	r := &Reader{
+		WPM:      wpm,
+		stream:   stream,
+		sizeHint: sizeHint,
+	}
+	r.fill()
+	return r
+}
+
+// Streaming reports whether words are being read lazily, from a stream or
+// a chapter at a time.
+func (r *Reader) Streaming() bool {
+	return r.lazy()
+}
+
+// Waiting reports whether a follow-mode reader, or one over an incomplete
+// document, has shown every word read so far and is waiting for more.
+func (r *Reader) Waiting() bool {
+	following := r.stream != nil && r.stream.Follow && !r.stream.Done()
+	return (following || r.Incomplete) && r.CurrentIndex >= len(r.Words)-1
+}
+
+// fill reads ahead so at least streamChunk words follow the current word.
+func (r *Reader) fill() {
+	if r.chapters != nil {
+		r.fillChapters()
+		return
+	}
+	if r.stream == nil {
+		return
+	}
+	for !r.stream.Done() && len(r.Words)-r.CurrentIndex <= streamChunk {
+		words := r.stream.Next(streamChunk)
+		if len(words) == 0 {
+			return
+		}
+		r.appendWords(words)
+		if r.stream.AtEOF() {
+			return
+		}
+	}
+}
+
+// appendWords adds streamed words to the window, extending sentence starts.
+func (r *Reader) appendWords(words []string) {
+	start := len(r.Words)
+	for i := range words {
+		idx := start + i
+		if idx == 0 {
+			r.SentenceStarts = append(r.SentenceStarts, 0)
+		} else {
+			var prev string
+			if i > 0 {
+				prev = words[i-1]
+			} else {
+				prev = r.Words[idx-1]
+			}
+			if r.language.sentenceBreak(prev, words[i]) {
+				r.SentenceStarts = append(r.SentenceStarts, idx)
+			}
+		}
+	}
+	r.Words = append(r.Words, words...)
+}
+
+// trim drops words far behind the current position from the window: for
+// a stream, all but streamWindow of them, and for chapters, those before
+// the previous chapter.
+func (r *Reader) trim() {
+	var drop int
+	switch {
+	case r.stream != nil:
+		if r.CurrentIndex < 2*streamWindow {
+			return
+		}
+		drop = r.CurrentIndex - streamWindow
+	case r.chapters != nil:
+		prev := r.ChapterIndex(r.CurrentIndex) - 1
+		if prev < 0 || r.Chapters[prev].WordStart <= 0 {
+			return
+		}
+		drop = r.Chapters[prev].WordStart
+	default:
+		return
+	}
+	r.Words = append([]string(nil), r.Words[drop:]...)
+	r.CurrentIndex -= drop
+	r.Base += drop
+
+	starts := r.SentenceStarts[:0]
+	for _, s := range r.SentenceStarts {
+		if s >= drop {
+			starts = append(starts, s-drop)
+		}
+	}
+	r.SentenceStarts = starts
+	// Chapters are kept, before the window if need be, so they are still
+	// counted and named.
+	for i := range r.Chapters {
+		r.Chapters[i].WordStart -= drop
+		r.Chapters[i].WordEnd -= drop
+	}
+}
+
+// estimatedTotal guesses the total word count of a document read lazily
+// from the bytes consumed so far.
+func (r *Reader) estimatedTotal() int {
+	loaded := r.Base + len(r.Words)
+	words, read := r.parsedWords, r.parsedBytes
+	if r.stream != nil {
+		words, read = r.stream.wordsRead, r.stream.bytesRead
+	}
+	if r.sourceDone() || r.sizeHint <= 0 || read == 0 {
+		return loaded
+	}
+	est := int(float64(r.sizeHint) * float64(words) / float64(read))
+	if est < loaded {
+		return loaded
+	}
+	return est
+}
+
+// pollReader moves reads from a blocking source onto a goroutine so that
+// Read never blocks: it reports io.EOF whenever no data has arrived yet.
+type pollReader struct {
+	data chan []byte
+	buf  []byte
+}
+
+// NewPollReader wraps a blocking source such as a pipe for use with
+// NewTailReader.
+func NewPollReader(src io.Reader) io.Reader {
+	p := &pollReader{data: make(chan []byte, 16)}
+	go func() {
+		defer close(p.data)
+		for {
+			buf := make([]byte, 32*1024)
+			n, err := src.Read(buf)
+			if n > 0 {
+				p.data <- buf[:n]
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return p
+}
+
+func (p *pollReader) Read(b []byte) (int, error) {
+	if len(p.buf) == 0 {
+		select {
+		case chunk, ok := <-p.data:
+			if !ok {
+				return 0, io.EOF
+			}
+			p.buf = chunk
+		default:
+			return 0, io.EOF
+		}
+	}
+	n := copy(b, p.buf)
+	p.buf = p.buf[n:]
+	return n, nil
+}
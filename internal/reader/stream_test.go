@@ -0,0 +1,169 @@
+package reader
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// numberedText returns n words "w00000 w00001 ..." with a sentence end every 10 words.
+func numberedText(n int) string {
+	var sb strings.Builder
+	for i := 0; i < n; i++ {
+		sb.WriteString(fmt.Sprintf("w%05d", i))
+		if i%10 == 9 {
+			sb.WriteString(".")
+		}
+		sb.WriteString(" ")
+	}
+	return sb.String()
+}
+
+func TestWordStream(t *testing.T) {
+	s := NewWordStream(strings.NewReader("  one two\tthree\nfour"))
+	got := s.Next(3)
+	if strings.Join(got, ",") != "one,two,three" {
+		t.Errorf("Next(3) = %v", got)
+	}
+	got = s.Next(3)
+	if strings.Join(got, ",") != "four" {
+		t.Errorf("Next(3) = %v", got)
+	}
+	if !s.Done() {
+		t.Error("stream should be done at EOF")
+	}
+}
+
+func TestStreamReaderMatchesInMemory(t *testing.T) {
+	text := numberedText(50)
+	mem := NewReader(text, 300)
+	r := NewStreamReader(strings.NewReader(text), int64(len(text)), 300)
+
+	if len(r.SentenceStarts) != len(mem.SentenceStarts) {
+		t.Fatalf("sentence starts = %v, want %v", r.SentenceStarts, mem.SentenceStarts)
+	}
+	for i := range mem.Words {
+		if r.CurrentWord() != mem.Words[i] {
+			t.Fatalf("word %d = %q, want %q", i, r.CurrentWord(), mem.Words[i])
+		}
+		r.Advance()
+	}
+	if !r.AtEnd() {
+		t.Error("stream reader should be at end")
+	}
+}
+
+func TestStreamReaderSlidingWindow(t *testing.T) {
+	const total = 40000
+	text := numberedText(total)
+	r := NewStreamReader(strings.NewReader(text), int64(len(text)), 300)
+
+	_, est := r.Progress()
+	if est < total*9/10 || est > total*11/10 {
+		t.Errorf("estimated total %d, want about %d", est, total)
+	}
+
+	for r.Advance() {
+		if len(r.Words) > 2*streamWindow+2*streamChunk {
+			t.Fatalf("window grew to %d words", len(r.Words))
+		}
+	}
+
+	current, got := r.Progress()
+	if current != total || got != total {
+		t.Errorf("Progress() = %d/%d, want %d/%d", current, got, total, total)
+	}
+	if r.CurrentWord() != fmt.Sprintf("w%05d.", total-1) {
+		t.Errorf("last word = %q", r.CurrentWord())
+	}
+
+	r.JumpToPrevSentence()
+	if r.CurrentWord() != fmt.Sprintf("w%05d", total-10) {
+		t.Errorf("prev sentence = %q, want w%d", r.CurrentWord(), total-10)
+	}
+}
+
+func TestStreamReaderSeekTo(t *testing.T) {
+	text := numberedText(30000)
+	r := NewStreamReader(strings.NewReader(text), int64(len(text)), 300)
+
+	if !r.SeekTo(25000) {
+		t.Fatal("SeekTo(25000) failed")
+	}
+	if r.Position() != 25000 || r.CurrentWord() != "w25000" {
+		t.Errorf("Position() = %d, word %q", r.Position(), r.CurrentWord())
+	}
+	if r.SeekTo(40000) {
+		t.Error("SeekTo past the end should fail")
+	}
+}
+
+func TestTailReaderFollowsGrowingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer f.Close()
+
+	r := NewTailReader(f, 300)
+	if !r.Waiting() || r.AtEnd() {
+		t.Fatal("empty tail reader should be waiting, not at end")
+	}
+
+	appendText := func(s string) {
+		t.Helper()
+		w, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+		if err != nil {
+			t.Fatalf("OpenFile: %v", err)
+		}
+		w.WriteString(s)
+		w.Close()
+	}
+
+	appendText("one two")
+	if !r.Advance() || r.CurrentWord() != "one" {
+		t.Fatalf("first word = %q, want one", r.CurrentWord())
+	}
+	// "two" may still be growing, so it is held back.
+	if r.Advance() || !r.Waiting() {
+		t.Fatal("partial trailing word should not be shown")
+	}
+
+	appendText("\nthree\n")
+	for _, want := range []string{"two", "three"} {
+		if !r.Advance() || r.CurrentWord() != want {
+			t.Fatalf("word = %q, want %q", r.CurrentWord(), want)
+		}
+	}
+	if !r.Waiting() {
+		t.Error("reader should wait for more input")
+	}
+}
+
+func TestPollReader(t *testing.T) {
+	pr, pw := io.Pipe()
+	r := NewPollReader(pr)
+	buf := make([]byte, 16)
+	if n, err := r.Read(buf); n != 0 || err != io.EOF {
+		t.Fatalf("Read() on empty pipe = %d, %v; want 0, EOF", n, err)
+	}
+
+	pw.Write([]byte("hello"))
+	var got []byte
+	for deadline := time.Now().Add(time.Second); len(got) < 5 && time.Now().Before(deadline); {
+		n, _ := r.Read(buf)
+		got = append(got, buf[:n]...)
+	}
+	if string(got) != "hello" {
+		t.Errorf("Read() = %q, want hello", got)
+	}
+	pw.Close()
+}
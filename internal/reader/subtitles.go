@@ -0,0 +1,83 @@
+package reader
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+)
+
+// subtitleContext is how many words either side of the current word a cue
+// shows, within its sentence.
+const subtitleContext = 6
+
+// SubtitleFormats lists the formats WriteSubtitles supports.
+var SubtitleFormats = []string{"srt", "vtt"}
+
+// WriteSubtitles writes one cue per word shown, from the start of the text,
+// each lasting as long as the reader shows the word (see WordDelay), so a
+// narration recorded at the same pace can be subtitled word by word. Each
+// cue shows the word in bold among its neighbours in the sentence. format
+// is "srt" or "vtt".
+func WriteSubtitles(w io.Writer, r *Reader, format string) error {
+	if format != "srt" && format != "vtt" {
+		return fmt.Errorf("unknown subtitle format %q", format)
+	}
+	bw := bufio.NewWriter(w)
+	if format == "vtt" {
+		bw.WriteString("WEBVTT\n\n")
+	}
+	if !r.SeekTo(0) {
+		return bw.Flush()
+	}
+
+	cue, idx := 1, r.CurrentIndex
+	var start, end time.Duration
+	for {
+		end += r.WordDelay()
+		more := r.Advance()
+		// The frames of a split word share its cue.
+		if more && r.Frame() > 0 {
+			continue
+		}
+		if format == "srt" {
+			fmt.Fprintf(bw, "%d\n", cue)
+		}
+		fmt.Fprintf(bw, "%s --> %s\n%s\n\n",
+			subtitleTime(start, format), subtitleTime(end, format), r.subtitleCue(idx))
+		if !more {
+			break
+		}
+		cue, idx, start = cue+1, r.CurrentIndex, end
+	}
+	return bw.Flush()
+}
+
+// subtitleCue returns the words around idx in its sentence with idx bold.
+func (r *Reader) subtitleCue(idx int) string {
+	first := max(r.SentenceStarts[r.SentenceIndex(idx)], idx-subtitleContext)
+	last := min(r.SentenceEnd(idx), idx+subtitleContext)
+
+	parts := make([]string, 0, last-first+1)
+	for i := first; i <= last; i++ {
+		word := html.EscapeString(r.Words[i])
+		if i == idx {
+			word = "<b>" + word + "</b>"
+		}
+		parts = append(parts, word)
+	}
+	return strings.Join(parts, " ")
+}
+
+// subtitleTime formats d as an SRT (00:00:01,500) or WebVTT (00:00:01.500)
+// timestamp.
+func subtitleTime(d time.Duration, format string) string {
+	sep := ","
+	if format == "vtt" {
+		sep = "."
+	}
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", ms/3600000, ms/60000%60, ms/1000%60, sep, ms%1000)
+}
@@ -0,0 +1,74 @@
+package reader
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteSubtitles(t *testing.T) {
+	r := NewReader("Hi <there>. Bye now.", 120) // 500ms per word
+
+	var srt strings.Builder
+	if err := WriteSubtitles(&srt, r, "srt"); err != nil {
+		t.Fatalf("WriteSubtitles srt: %v", err)
+	}
+	wantSRT := "1\n00:00:00,000 --> 00:00:00,500\n<b>Hi</b> &lt;there&gt;.\n\n" +
+		"2\n00:00:00,500 --> 00:00:01,000\nHi <b>&lt;there&gt;.</b>\n\n" +
+		"3\n00:00:01,000 --> 00:00:01,500\n<b>Bye</b> now.\n\n" +
+		"4\n00:00:01,500 --> 00:00:02,000\nBye <b>now.</b>\n\n"
+	if srt.String() != wantSRT {
+		t.Errorf("srt =\n%s\nwant\n%s", srt.String(), wantSRT)
+	}
+
+	var vtt strings.Builder
+	if err := WriteSubtitles(&vtt, r, "vtt"); err != nil {
+		t.Fatalf("WriteSubtitles vtt: %v", err)
+	}
+	if !strings.HasPrefix(vtt.String(), "WEBVTT\n\n00:00:00.000 --> 00:00:00.500\n<b>Hi</b>") {
+		t.Errorf("vtt = %q", vtt.String())
+	}
+
+	if err := WriteSubtitles(&vtt, r, "ass"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestWriteSubtitlesPacing(t *testing.T) {
+	r := NewReader("Hi there.\n\nExtraordinarily long.", 120)
+	r.ParagraphPause = 2
+	r.MaxWordLength = 8
+
+	var srt strings.Builder
+	if err := WriteSubtitles(&srt, r, "srt"); err != nil {
+		t.Fatalf("WriteSubtitles: %v", err)
+	}
+	// Paragraph starts are shown twice as long, and the frames of a split
+	// word share one cue.
+	want := "1\n00:00:00,000 --> 00:00:01,000\n<b>Hi</b> there.\n\n" +
+		"2\n00:00:01,000 --> 00:00:01,500\nHi <b>there.</b>\n\n" +
+		"3\n00:00:01,500 --> 00:00:03,500\n<b>Extraordinarily</b> long.\n\n" +
+		"4\n00:00:03,500 --> 00:00:04,000\nExtraordinarily <b>long.</b>\n\n"
+	if srt.String() != want {
+		t.Errorf("srt =\n%s\nwant\n%s", srt.String(), want)
+	}
+}
+
+func TestSubtitleCueContext(t *testing.T) {
+	r := NewReader(numberedText(30), 300)
+	cue := r.subtitleCue(15)
+	// Word 15 is in the sentence w00010..w00019.
+	if cue != "w00010 w00011 w00012 w00013 w00014 <b>w00015</b> w00016 w00017 w00018 w00019." {
+		t.Errorf("cue = %q", cue)
+	}
+
+	long := NewReader(strings.Repeat("word ", 40), 300)
+	if n := len(strings.Fields(long.subtitleCue(20))); n != 2*subtitleContext+1 {
+		t.Errorf("long sentence cue has %d words, want %d", n, 2*subtitleContext+1)
+	}
+}
+
+func TestSubtitleTime(t *testing.T) {
+	if got := subtitleTime(3723500*1e6, "srt"); got != "01:02:03,500" {
+		t.Errorf("subtitleTime() = %q", got)
+	}
+}
@@ -1,5 +1,7 @@
 package reader
 
+import "context"
+
 // TOCEntry represents a single entry in a table of contents
 type TOCEntry struct {
 	Title     string
@@ -24,3 +26,38 @@ type TOCProvider interface {
 type ChapterExtractor interface {
 	ExtractChapters(filename string) ([]Chapter, []string, error)
 }
+
+// ContextTOCProvider is implemented by TOC providers that can be cancelled.
+type ContextTOCProvider interface {
+	TOCContext(ctx context.Context, filename string) ([]TOCEntry, error)
+}
+
+// ContextChapterExtractor is implemented by chapter extractors that can be
+// cancelled.
+type ContextChapterExtractor interface {
+	ExtractChaptersContext(ctx context.Context, filename string) ([]Chapter, []string, error)
+}
+
+// TOCContext reads p's table of contents, stopping early when ctx is
+// cancelled if p supports it.
+func TOCContext(ctx context.Context, p TOCProvider, filename string) ([]TOCEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if cp, ok := p.(ContextTOCProvider); ok {
+		return cp.TOCContext(ctx, filename)
+	}
+	return p.TOC(filename)
+}
+
+// ExtractChaptersContext extracts chapters with e, stopping early when ctx
+// is cancelled if e supports it.
+func ExtractChaptersContext(ctx context.Context, e ChapterExtractor, filename string) ([]Chapter, []string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	if ce, ok := e.(ContextChapterExtractor); ok {
+		return ce.ExtractChaptersContext(ctx, filename)
+	}
+	return e.ExtractChapters(filename)
+}
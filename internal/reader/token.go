@@ -0,0 +1,127 @@
+package reader
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// LengthClass buckets a word by the length of its core, for features that
+// treat short and long words differently.
+type LengthClass int
+
+const (
+	// LengthShort is a core of up to 3 runes.
+	LengthShort LengthClass = iota
+	// LengthMedium is a core of 4 to 8 runes.
+	LengthMedium
+	// LengthLong is a core of 9 to 13 runes.
+	LengthLong
+	// LengthVeryLong is a core of more than 13 runes.
+	LengthVeryLong
+)
+
+// Token is a word and its place in the structure of the text, so pacing,
+// skipping and highlighting can ask about a word without re-parsing it.
+type Token struct {
+	Text           string
+	SentenceStart  bool
+	ParagraphStart bool
+	ChapterStart   bool
+	Number         bool
+	Stopword       bool
+	Link           LinkKind
+	Length         LengthClass
+	// Rarity is how unusual the word is, from 0 for the commonest to 1
+	// for a word missing from the reader's frequency list.
+	Rarity float64
+}
+
+// NewToken describes a single word. Structural flags are left for the
+// caller to set.
+func NewToken(word string) Token {
+	start, end := CoreBounds(word)
+	core := []rune(word)[start:end]
+	return Token{
+		Text:   word,
+		Number: isNumber(core),
+		Link:   linkKind(string(core)),
+		Length: lengthClass(end - start),
+	}
+}
+
+// ParseTokens splits text into tokens, marking the first word of each
+// sentence and paragraph. Paragraphs are separated by blank lines.
+func ParseTokens(text string) []Token {
+	return parseTokens(text, Language{})
+}
+
+// parseTokens is ParseTokens ending sentences as lang does.
+func parseTokens(text string, lang Language) []Token {
+	var tokens []Token
+	paragraph := true
+	for _, line := range strings.Split(text, "\n") {
+		words := splitWords(line)
+		if len(words) == 0 {
+			paragraph = true
+			continue
+		}
+		for _, w := range words {
+			t := NewToken(w)
+			t.ParagraphStart = paragraph
+			t.SentenceStart = len(tokens) == 0 || lang.sentenceBreak(tokens[len(tokens)-1].Text, w)
+			tokens = append(tokens, t)
+			paragraph = false
+		}
+	}
+	return tokens
+}
+
+// Token returns the token for the word at idx, with its sentence,
+// paragraph, chapter and stopword flags and rarity taken from the reader's
+// current structure and settings.
+func (r *Reader) Token(idx int) Token {
+	if idx < 0 || idx >= len(r.Words) {
+		return Token{}
+	}
+	t := NewToken(r.Words[idx])
+	t.SentenceStart = r.IsSentenceStart(idx)
+	t.ParagraphStart = r.IsParagraphStart(idx)
+	i := sort.Search(len(r.Chapters), func(i int) bool { return r.Chapters[i].WordStart >= idx })
+	t.ChapterStart = i < len(r.Chapters) && r.Chapters[i].WordStart == idx
+	t.Stopword = r.isStopword(idx)
+	t.Rarity = r.rarity(idx)
+	return t
+}
+
+// CurrentToken returns the token for the current word.
+func (r *Reader) CurrentToken() Token {
+	return r.Token(r.CurrentIndex)
+}
+
+func lengthClass(n int) LengthClass {
+	switch {
+	case n <= 3:
+		return LengthShort
+	case n <= 8:
+		return LengthMedium
+	case n <= 13:
+		return LengthLong
+	}
+	return LengthVeryLong
+}
+
+// isNumber reports whether a word's core is a number such as 42, 3.14,
+// 1,000 or 2024-01-31.
+func isNumber(core []rune) bool {
+	digits := false
+	for _, r := range core {
+		switch {
+		case unicode.IsDigit(r):
+			digits = true
+		case !strings.ContainsRune(".,:/-%", r):
+			return false
+		}
+	}
+	return digits
+}
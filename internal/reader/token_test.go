@@ -0,0 +1,77 @@
+package reader
+
+import "testing"
+
+func TestParseTokens(t *testing.T) {
+	tokens := ParseTokens("One two\nthree.\n\n  \nFour 3.14\n\n\tSix")
+	want := []Token{
+		{Text: "One", SentenceStart: true, ParagraphStart: true, Length: LengthShort},
+		{Text: "two", Length: LengthShort},
+		{Text: "three.", Length: LengthMedium},
+		{Text: "Four", SentenceStart: true, ParagraphStart: true, Length: LengthMedium},
+		{Text: "3.14", Number: true, Length: LengthMedium},
+		{Text: "Six", ParagraphStart: true, Length: LengthShort},
+	}
+	if len(tokens) != len(want) {
+		t.Fatalf("got %d tokens, want %d: %+v", len(tokens), len(want), tokens)
+	}
+	for i := range want {
+		if tokens[i] != want[i] {
+			t.Errorf("token %d = %+v, want %+v", i, tokens[i], want[i])
+		}
+	}
+}
+
+func TestNewToken(t *testing.T) {
+	tests := []struct {
+		word   string
+		number bool
+		length LengthClass
+	}{
+		{"a", false, LengthShort},
+		{"(1,000)", true, LengthMedium},
+		{"2024-01-31.", true, LengthLong},
+		{"1st", false, LengthShort},
+		{"—", false, LengthShort},
+		{"\"recognition\"", false, LengthLong},
+		{"Donaudampfschifffahrt", false, LengthVeryLong},
+	}
+	for _, tt := range tests {
+		got := NewToken(tt.word)
+		if got.Text != tt.word || got.Number != tt.number || got.Length != tt.length {
+			t.Errorf("NewToken(%q) = %+v, want number %v length %v", tt.word, got, tt.number, tt.length)
+		}
+	}
+}
+
+func TestReaderToken(t *testing.T) {
+	r := NewReader("Hello there. General\n\nKenobi 42", 300)
+	r.SetChapters([]Chapter{{Title: "One", WordStart: 0}, {Title: "Two", WordStart: 3}}, nil)
+
+	tests := []struct {
+		idx                          int
+		sentence, paragraph, chapter bool
+	}{
+		{0, true, true, true},
+		{1, false, false, false},
+		{2, true, false, false},
+		{3, false, true, true},
+		{4, false, false, false},
+	}
+	for _, tt := range tests {
+		tok := r.Token(tt.idx)
+		if tok.SentenceStart != tt.sentence || tok.ParagraphStart != tt.paragraph || tok.ChapterStart != tt.chapter {
+			t.Errorf("Token(%d) = %+v", tt.idx, tok)
+		}
+	}
+	if !r.Token(4).Number {
+		t.Error("Token(4) should be a number")
+	}
+	r.JumpToChapter(3)
+	if r.CurrentToken().Text != "Kenobi" {
+		t.Errorf("CurrentToken() = %+v", r.CurrentToken())
+	}
+	if (r.Token(-1) != Token{}) || (r.Token(5) != Token{}) {
+		t.Error("out of range tokens should be empty")
+	}
+}
@@ -0,0 +1,164 @@
+package reader
+
+import (
+	"bufio"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// DefaultFillers are the filler words dropped from transcripts when no
+// custom word list is given.
+var DefaultFillers = []string{"um", "umm", "uh", "uhh", "uhm", "erm", "er", "ah", "hmm", "mm", "mhm"}
+
+var (
+	// timestampPattern matches a leading cue time such as "00:01:23",
+	// "[1:02:03.500]" or "(12:34)".
+	timestampPattern = regexp.MustCompile(`^[\[(]?\d{1,2}:\d{2}(?::\d{2})?(?:[.,]\d+)?[\])]?\s*`)
+	// speakerPattern matches a leading speaker label such as "Alice:" or
+	// "SPEAKER 2:".
+	speakerPattern = regexp.MustCompile(`^([A-Z][\w.'-]*(?: [A-Z0-9][\w.'-]*){0,3}):\s+`)
+	// cuePattern matches bracketed non-speech cues such as "[laughter]".
+	cuePattern = regexp.MustCompile(`\[[^\]]*\]`)
+)
+
+// Transcript cleans meeting and podcast transcripts for reading: it drops
+// filler words, bracketed cues and timestamps, and ends a sentence whenever
+// the speaker changes.
+type Transcript struct {
+	// fillers holds lowercase filler phrases split into words.
+	fillers [][]string
+}
+
+// NewTranscript returns a sanitizer dropping the given filler words or
+// phrases (e.g. "you know"). Matching ignores case and punctuation.
+func NewTranscript(fillers []string) *Transcript {
+	t := &Transcript{}
+	for _, f := range fillers {
+		if words := strings.Fields(strings.ToLower(f)); len(words) > 0 {
+			t.fillers = append(t.fillers, words)
+		}
+	}
+	return t
+}
+
+// LoadFillerList reads a filler word list with one word or phrase per line.
+// Blank lines and lines starting with # are ignored.
+func LoadFillerList(filename string) ([]string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var fillers []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fillers = append(fillers, line)
+	}
+	return fillers, scanner.Err()
+}
+
+// Sanitize returns text with fillers, cues and timestamps removed. Each
+// speaker turn starts on its own line with the speaker's label, and the
+// previous turn is closed with a period if it had no sentence punctuation.
+func (t *Transcript) Sanitize(text string) string {
+	var out []string
+	var turn []string
+	speaker := ""
+
+	flush := func() {
+		if len(turn) == 0 {
+			return
+		}
+		if last := turn[len(turn)-1]; !endsSentence(last) {
+			turn[len(turn)-1] = strings.TrimRight(last, ",;:-") + "."
+		}
+		out = append(out, strings.Join(turn, " "))
+		turn = nil
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if isCueMetadata(line) {
+			continue
+		}
+		line = timestampPattern.ReplaceAllString(line, "")
+		if m := speakerPattern.FindStringSubmatch(line); m != nil {
+			line = line[len(m[0]):]
+			if m[1] != speaker {
+				flush()
+				speaker = m[1]
+				turn = append(turn, speaker+":")
+			}
+		}
+		line = cuePattern.ReplaceAllString(line, " ")
+		turn = append(turn, t.dropFillers(strings.Fields(line))...)
+	}
+	flush()
+	return strings.Join(out, "\n")
+}
+
+// dropFillers removes filler phrases from words. Sentence punctuation on a
+// dropped filler moves to the preceding word.
+func (t *Transcript) dropFillers(words []string) []string {
+	kept := make([]string, 0, len(words))
+	for i := 0; i < len(words); {
+		n := t.fillerAt(words[i:])
+		if n == 0 {
+			kept = append(kept, words[i])
+			i++
+			continue
+		}
+		last := words[i+n-1]
+		if endsSentence(last) && len(kept) > 0 && !endsSentence(kept[len(kept)-1]) {
+			prev := strings.TrimRight(kept[len(kept)-1], ",;:-")
+			kept[len(kept)-1] = prev + last[len(last)-1:]
+		}
+		i += n
+	}
+	return kept
+}
+
+// fillerAt returns the number of words at the start of words that form a
+// filler phrase, or 0 if none does.
+func (t *Transcript) fillerAt(words []string) int {
+	best := 0
+	for _, phrase := range t.fillers {
+		if len(phrase) > len(words) || len(phrase) <= best {
+			continue
+		}
+		match := true
+		for j, w := range phrase {
+			if normalizeFiller(words[j]) != w {
+				match = false
+				break
+			}
+		}
+		if match {
+			best = len(phrase)
+		}
+	}
+	return best
+}
+
+// normalizeFiller lowercases a word and strips surrounding punctuation.
+func normalizeFiller(word string) string {
+	return strings.ToLower(strings.Trim(word, ".,;:!?…-—\"'()"))
+}
+
+// isCueMetadata reports whether a line is subtitle structure rather than
+// speech: a WEBVTT header, a cue number or a cue timing line.
+func isCueMetadata(line string) bool {
+	switch {
+	case line == "":
+		return false
+	case strings.HasPrefix(line, "WEBVTT"), strings.Contains(line, "-->"):
+		return true
+	}
+	return strings.Trim(line, "0123456789") == ""
+}
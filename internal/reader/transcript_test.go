@@ -0,0 +1,72 @@
+package reader
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestTranscriptSanitize(t *testing.T) {
+	tests := []struct {
+		name    string
+		fillers []string
+		input   string
+		want    string
+	}{
+		{
+			name:    "fillers and cues",
+			fillers: DefaultFillers,
+			input:   "Um, so I think, uh, we should ship it [laughter] today.",
+			want:    "so I think, we should ship it today.",
+		},
+		{
+			name:    "speaker changes end sentences",
+			fillers: DefaultFillers,
+			input:   "Alice: we could try that\nBob: sure let's do it\nBob: right now",
+			want:    "Alice: we could try that.\nBob: sure let's do it right now.",
+		},
+		{
+			name:    "timestamps and speaker labels",
+			fillers: DefaultFillers,
+			input:   "[00:01:02] SPEAKER 1: hello there\n(00:01:09) SPEAKER 2: hi, um.",
+			want:    "SPEAKER 1: hello there.\nSPEAKER 2: hi.",
+		},
+		{
+			name:    "webvtt structure",
+			fillers: DefaultFillers,
+			input:   "WEBVTT\n\n1\n00:00:01.000 --> 00:00:03.000\nWelcome back\n\n2\n00:00:03.500 --> 00:00:05.000\nto the show",
+			want:    "Welcome back to the show.",
+		},
+		{
+			name:    "custom phrases",
+			fillers: []string{"you know", "like"},
+			input:   "It was, you know, like really um good.",
+			want:    "It was, really um good.",
+		},
+	}
+
+	for _, tt := range tests {
+		got := NewTranscript(tt.fillers).Sanitize(tt.input)
+		if got != tt.want {
+			t.Errorf("%s: Sanitize() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLoadFillerList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fillers.txt")
+	content := "# podcast fillers\num\n\n  you know  \nsort of\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	got, err := LoadFillerList(path)
+	if err != nil {
+		t.Fatalf("LoadFillerList: %v", err)
+	}
+	want := []string{"um", "you know", "sort of"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadFillerList() = %q, want %q", got, want)
+	}
+}
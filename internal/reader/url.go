@@ -0,0 +1,332 @@
+package reader
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+const maxURLBytes = 10 << 20 // 10MB cap on fetched pages
+
+// URLClient is the HTTP client used to fetch web articles.
+var URLClient = &http.Client{Timeout: 30 * time.Second}
+
+// clientKey is the context key of the client set by WithClient.
+type clientKey struct{}
+
+// WithClient returns a copy of ctx whose requests are made with client in
+// place of URLClient.
+func WithClient(ctx context.Context, client *http.Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
+
+// clientFor returns the client for requests made under ctx.
+func clientFor(ctx context.Context) *http.Client {
+	if client, ok := ctx.Value(clientKey{}).(*http.Client); ok && client != nil {
+		return client
+	}
+	return URLClient
+}
+
+// IsURL reports whether s looks like an http(s) URL rather than a file path.
+func IsURL(s string) bool {
+	lower := strings.ToLower(s)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+// MaxArticlePages bounds how many rel="next" pages are stitched into one
+// article.
+var MaxArticlePages = 10
+
+// ExtractTextFromURL fetches a web page and extracts its readable text,
+// dropping repeated blocks and boilerplate. Articles split across pages
+// with rel="next" links are stitched into one document.
+func ExtractTextFromURL(rawURL string) (string, error) {
+	return ExtractTextFromURLContext(context.Background(), rawURL)
+}
+
+// ExtractTextFromURLContext is ExtractTextFromURL with requests cancelled
+// when ctx is.
+func ExtractTextFromURLContext(ctx context.Context, rawURL string) (string, error) {
+	data, contentType, err := fetchURLContext(ctx, rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	if !strings.Contains(contentType, "html") {
+		return string(data), nil
+	}
+
+	blocks := extractArticleBlocks(string(data))
+	seen := map[string]bool{rawURL: true}
+	pageURL := rawURL
+	for pages := 1; pages < MaxArticlePages; pages++ {
+		next := nextPageURL(string(data), pageURL)
+		if next == "" || seen[next] {
+			break
+		}
+		seen[next] = true
+
+		data, contentType, err = fetchURLContext(ctx, next)
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		if err != nil || !strings.Contains(contentType, "html") {
+			break
+		}
+		blocks = append(blocks, extractArticleBlocks(string(data))...)
+		pageURL = next
+	}
+
+	return strings.Join(DedupeBlocks(blocks), "\n\n"), nil
+}
+
+// nextPageURL returns the absolute rel="next" link of a page on the same
+// host, or "" if there is none.
+func nextPageURL(page, pageURL string) string {
+	doc, err := html.Parse(strings.NewReader(page))
+	if err != nil {
+		return ""
+	}
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return ""
+	}
+
+	var href string
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if href != "" {
+			return
+		}
+		if n.Type == html.ElementNode && (n.Data == "link" || n.Data == "a") {
+			var rel, h string
+			for _, a := range n.Attr {
+				switch a.Key {
+				case "rel":
+					rel = a.Val
+				case "href":
+					h = a.Val
+				}
+			}
+			for _, r := range strings.Fields(strings.ToLower(rel)) {
+				if r == "next" && h != "" {
+					href = h
+					return
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	if href == "" {
+		return ""
+	}
+	ref, err := url.Parse(href)
+	if err != nil {
+		return ""
+	}
+	next := base.ResolveReference(ref)
+	next.Fragment = ""
+	if next.Host != base.Host {
+		return ""
+	}
+	return next.String()
+}
+
+// fetchURL GETs rawURL and returns the body and content type.
+func fetchURL(rawURL string) ([]byte, string, error) {
+	return fetchURLContext(context.Background(), rawURL)
+}
+
+// fetchURLContext is fetchURL with the request cancelled when ctx is.
+func fetchURLContext(ctx context.Context, rawURL string) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("User-Agent", "brr (+https://github.com/metcalfc/brr)")
+
+	resp, err := clientFor(ctx).Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch url: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("failed to fetch url: %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxURLBytes))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read response: %w", err)
+	}
+	return data, resp.Header.Get("Content-Type"), nil
+}
+
+// skippedElements never contain article text.
+var skippedElements = map[string]bool{
+	"head": true, "script": true, "style": true, "noscript": true,
+	"nav": true, "footer": true, "aside": true, "form": true,
+	"button": true, "svg": true, "iframe": true, "template": true,
+}
+
+// blockElements end the current text block.
+var blockElements = map[string]bool{
+	"p": true, "div": true, "article": true, "section": true, "main": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"li": true, "ul": true, "ol": true, "blockquote": true, "pre": true,
+	"figcaption": true, "td": true, "tr": true, "br": true, "header": true,
+}
+
+// extractArticleBlocks splits an HTML page into paragraph-level text blocks,
+// skipping scripts, navigation and other page chrome.
+func extractArticleBlocks(s string) []string {
+	doc, err := html.Parse(strings.NewReader(s))
+	if err != nil {
+		return nil
+	}
+
+	var blocks []string
+	var cur strings.Builder
+	flush := func() {
+		if t := strings.Join(strings.Fields(cur.String()), " "); t != "" {
+			blocks = append(blocks, t)
+		}
+		cur.Reset()
+	}
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && (skippedElements[n.Data] || isPaginationLink(n)) {
+			return
+		}
+		if n.Type == html.TextNode {
+			cur.WriteString(n.Data)
+			cur.WriteString(" ")
+		}
+		block := n.Type == html.ElementNode && blockElements[n.Data]
+		if block {
+			flush()
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+		if block {
+			flush()
+		}
+	}
+	walk(doc)
+	flush()
+	return blocks
+}
+
+// isPaginationLink reports whether n is a rel="next"/"prev" anchor, whose
+// text ("Next page") is navigation rather than content.
+func isPaginationLink(n *html.Node) bool {
+	if n.Data != "a" {
+		return false
+	}
+	for _, a := range n.Attr {
+		if a.Key != "rel" {
+			continue
+		}
+		for _, r := range strings.Fields(strings.ToLower(a.Val)) {
+			if r == "next" || r == "prev" || r == "previous" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// boilerplatePhrases mark short blocks that are page chrome rather than content.
+var boilerplatePhrases = []string{
+	"share this article",
+	"share this story",
+	"share on facebook",
+	"share on twitter",
+	"share on linkedin",
+	"copy link",
+	"sign up for our newsletter",
+	"subscribe to our newsletter",
+	"click here to subscribe",
+	"follow us on",
+	"related articles",
+	"recommended for you",
+	"advertisement",
+	"all rights reserved",
+	"accept cookies",
+	"we use cookies",
+}
+
+// maxBoilerplateWords bounds how long a block may be and still be treated
+// as boilerplate; longer blocks are assumed to be real content.
+const maxBoilerplateWords = 12
+
+// minDedupeLength is the shortest block, in letters and digits, that is
+// dropped when repeated. Shorter ones, such as a reply of "No." or a scene
+// break of "* * *", are as likely to recur in the text itself.
+const minDedupeLength = 8
+
+// DedupeBlocks removes repeated blocks and short boilerplate blocks, keeping
+// the first occurrence of each block in order.
+func DedupeBlocks(blocks []string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, b := range blocks {
+		if strings.TrimSpace(b) == "" {
+			continue
+		}
+		key := normalizeBlock(b)
+		if utf8.RuneCountInString(key) >= minDedupeLength {
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+		if isBoilerplate(key) {
+			continue
+		}
+		out = append(out, b)
+	}
+	return out
+}
+
+func isBoilerplate(key string) bool {
+	if len(strings.Fields(key)) > maxBoilerplateWords {
+		return false
+	}
+	for _, p := range boilerplatePhrases {
+		if strings.Contains(key, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeBlock lowercases a block and strips punctuation so trivially
+// different copies compare equal.
+func normalizeBlock(s string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r):
+			sb.WriteRune(r)
+		case unicode.IsSpace(r):
+			sb.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
@@ -0,0 +1,157 @@
+package reader
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestIsURL(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"https://example.com/article", true},
+		{"HTTP://example.com", true},
+		{"book.epub", false},
+		{"/tmp/http.txt", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsURL(tt.input); got != tt.expected {
+			t.Errorf("IsURL(%q) = %v, want %v", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestDedupeBlocks(t *testing.T) {
+	blocks := []string{
+		"The Title",
+		"By Jane Doe",
+		"Share this article",
+		"First paragraph of the story.",
+		"By Jane Doe",
+		"THE TITLE!",
+		"Second paragraph of the story.",
+		"Sign up for our newsletter",
+	}
+
+	got := DedupeBlocks(blocks)
+	expected := []string{
+		"The Title",
+		"By Jane Doe",
+		"First paragraph of the story.",
+		"Second paragraph of the story.",
+	}
+
+	if len(got) != len(expected) {
+		t.Fatalf("DedupeBlocks() = %q, want %q", got, expected)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("block %d: got %q, want %q", i, got[i], expected[i])
+		}
+	}
+}
+
+func TestDedupeBlocksKeepsShortBlocks(t *testing.T) {
+	blocks := []string{"Are you coming?", "No.", "* * *", "Morning came.", "Are you coming?", "No.", "* * *"}
+	got := DedupeBlocks(blocks)
+	want := []string{"Are you coming?", "No.", "* * *", "Morning came.", "No.", "* * *"}
+	if !slices.Equal(got, want) {
+		t.Errorf("DedupeBlocks() = %q, want %q", got, want)
+	}
+}
+
+func TestDedupeBlocksKeepsLongContent(t *testing.T) {
+	long := "We use cookies in this long paragraph that actually talks about baking cookies at home with the family on weekends."
+	got := DedupeBlocks([]string{long})
+	if len(got) != 1 {
+		t.Errorf("long content block should be kept, got %q", got)
+	}
+}
+
+func TestExtractTextFromURL(t *testing.T) {
+	page := `<html><head><title>Site | The Title</title><script>var x = 1;</script></head>
+	<body>
+		<nav>Home About</nav>
+		<h1>The Title</h1>
+		<p>By Jane Doe</p>
+		<div class="share">Share this article</div>
+		<p>Body text here.</p>
+		<p>By Jane Doe</p>
+		<footer>All rights reserved</footer>
+	</body></html>`
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(page))
+	}))
+	defer srv.Close()
+
+	text, err := ExtractTextFromURL(srv.URL)
+	if err != nil {
+		t.Fatalf("ExtractTextFromURL: %v", err)
+	}
+
+	words := strings.Join(ParseText(text), " ")
+	expected := "The Title By Jane Doe Body text here."
+	if words != expected {
+		t.Errorf("got %q, want %q", words, expected)
+	}
+}
+
+func TestExtractTextFromURLError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	if _, err := ExtractTextFromURL(srv.URL); err == nil {
+		t.Error("expected error for 404 response")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ExtractTextFromURLContext(ctx, srv.URL); !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled fetch err = %v, want context.Canceled", err)
+	}
+}
+
+func TestExtractTextFromURLPagination(t *testing.T) {
+	pages := map[string]string{
+		"/story":   `<html><head><link rel="next" href="/story/2"></head><body><p>By Jane Doe</p><p>Page one.</p></body></html>`,
+		"/story/2": `<html><body><p>By Jane Doe</p><p>Page two.</p><a rel="next nofollow" href="/story/3">Next</a></body></html>`,
+		"/story/3": `<html><body><p>Page three.</p><a rel="next" href="/story">Loop</a></body></html>`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	text, err := ExtractTextFromURL(srv.URL + "/story")
+	if err != nil {
+		t.Fatalf("ExtractTextFromURL: %v", err)
+	}
+
+	words := strings.Join(ParseText(text), " ")
+	expected := "By Jane Doe Page one. Page two. Page three."
+	if words != expected {
+		t.Errorf("got %q, want %q", words, expected)
+	}
+}
+
+func TestNextPageURLIgnoresOtherHosts(t *testing.T) {
+	page := `<html><head><link rel="next" href="https://other.example/2"></head></html>`
+	if got := nextPageURL(page, "https://example.com/1"); got != "" {
+		t.Errorf("nextPageURL should ignore other hosts, got %q", got)
+	}
+}
@@ -0,0 +1,261 @@
+package reader
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Video is a YouTube video's captions split into sections at its chapter
+// markers.
+type Video struct {
+	Title    string
+	Sections []Section
+	// Language is the language code of the captions read.
+	Language string
+	// Fallback is set when the video has no captions in the language asked
+	// for, so those in Language were read instead.
+	Fallback bool
+	// Languages lists the language codes the video has captions in.
+	Languages []string
+}
+
+// Caption is one timed caption cue.
+type Caption struct {
+	Start float64
+	Text  string
+}
+
+// VideoChapter is a chapter marker from a video description.
+type VideoChapter struct {
+	Start float64
+	Title string
+}
+
+type captionTrack struct {
+	BaseURL      string `json:"baseUrl"`
+	LanguageCode string `json:"languageCode"`
+	Kind         string `json:"kind"`
+}
+
+type playerResponse struct {
+	VideoDetails struct {
+		Title            string `json:"title"`
+		ShortDescription string `json:"shortDescription"`
+	} `json:"videoDetails"`
+	Captions struct {
+		Renderer struct {
+			Tracks []captionTrack `json:"captionTracks"`
+		} `json:"playerCaptionsTracklistRenderer"`
+	} `json:"captions"`
+}
+
+// FetchYouTubeVideoContext fetches the caption track of a YouTube video in
+// the given language, preferring uploaded captions over automatic ones,
+// with requests cancelled when ctx is.
+func FetchYouTubeVideoContext(ctx context.Context, videoURL, lang string) (Video, error) {
+	page, _, err := fetchURLContext(ctx, videoURL)
+	if err != nil {
+		return Video{}, err
+	}
+	player, err := parsePlayerResponse(page)
+	if err != nil {
+		return Video{}, err
+	}
+
+	track, ok := pickCaptionTrack(player.Captions.Renderer.Tracks, lang)
+	if !ok {
+		return Video{}, errors.New("video has no captions")
+	}
+	data, _, err := fetchURLContext(ctx, track.BaseURL)
+	if err != nil {
+		return Video{}, err
+	}
+	captions, err := ParseTimedText(data)
+	if err != nil {
+		return Video{}, err
+	}
+
+	title := player.VideoDetails.Title
+	chapters := ParseVideoChapters(player.VideoDetails.ShortDescription)
+	video := Video{
+		Title:    title,
+		Sections: CaptionSections(title, captions, chapters),
+		Language: track.LanguageCode,
+		Fallback: !captionLanguage(track, lang),
+	}
+	for _, t := range player.Captions.Renderer.Tracks {
+		if !slices.Contains(video.Languages, t.LanguageCode) {
+			video.Languages = append(video.Languages, t.LanguageCode)
+		}
+	}
+	return video, nil
+}
+
+// parsePlayerResponse extracts the ytInitialPlayerResponse JSON embedded in
+// a watch page.
+func parsePlayerResponse(page []byte) (playerResponse, error) {
+	var player playerResponse
+	const marker = "ytInitialPlayerResponse"
+	i := bytes.Index(page, []byte(marker))
+	if i < 0 {
+		return player, errors.New("no player data in page")
+	}
+	j := bytes.IndexByte(page[i:], '{')
+	if j < 0 {
+		return player, errors.New("no player data in page")
+	}
+	// The decoder stops at the end of the object, ignoring the script after it.
+	if err := json.NewDecoder(bytes.NewReader(page[i+j:])).Decode(&player); err != nil {
+		return player, fmt.Errorf("invalid player data: %w", err)
+	}
+	return player, nil
+}
+
+// pickCaptionTrack chooses the track for lang, preferring uploaded captions
+// to automatic ("asr") ones, then falling back to any track.
+func pickCaptionTrack(tracks []captionTrack, lang string) (captionTrack, bool) {
+	var auto *captionTrack
+	for i, t := range tracks {
+		if !captionLanguage(t, lang) {
+			continue
+		}
+		if t.Kind != "asr" {
+			return t, true
+		}
+		if auto == nil {
+			auto = &tracks[i]
+		}
+	}
+	if auto != nil {
+		return *auto, true
+	}
+	if len(tracks) > 0 {
+		return tracks[0], true
+	}
+	return captionTrack{}, false
+}
+
+// captionLanguage reports whether t is in lang or a regional variant of it,
+// as en-GB is of en.
+func captionLanguage(t captionTrack, lang string) bool {
+	return strings.EqualFold(t.LanguageCode, lang) || strings.HasPrefix(strings.ToLower(t.LanguageCode), strings.ToLower(lang)+"-")
+}
+
+// ParseTimedText parses a YouTube timedtext caption track in either the
+// classic <transcript><text start=".."> form or format 3 (<p t="ms">).
+func ParseTimedText(data []byte) ([]Caption, error) {
+	var doc struct {
+		Texts []struct {
+			Start string `xml:"start,attr"`
+			Body  string `xml:",chardata"`
+		} `xml:"text"`
+		Paragraphs []struct {
+			T    string `xml:"t,attr"`
+			Body string `xml:",innerxml"`
+		} `xml:"body>p"`
+	}
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid caption track: %w", err)
+	}
+
+	var captions []Caption
+	for _, t := range doc.Texts {
+		start, _ := strconv.ParseFloat(t.Start, 64)
+		captions = append(captions, Caption{Start: start, Text: cleanCaption(t.Body)})
+	}
+	for _, p := range doc.Paragraphs {
+		ms, _ := strconv.ParseFloat(p.T, 64)
+		body := cueTagPattern.ReplaceAllString(p.Body, "")
+		captions = append(captions, Caption{Start: ms / 1000, Text: cleanCaption(body)})
+	}
+	return captions, nil
+}
+
+// cleanCaption unescapes a caption's text, which YouTube often escapes twice,
+// and folds it onto one line.
+func cleanCaption(s string) string {
+	return strings.Join(strings.Fields(html.UnescapeString(html.UnescapeString(s))), " ")
+}
+
+// chapterLinePattern matches a description line such as "1:02:03 Title" or
+// "(4:05) - Title".
+var chapterLinePattern = regexp.MustCompile(`^\(?((?:\d{1,2}:)?\d{1,2}:\d{2})\)?\s*[-–—:]?\s+(.+)$`)
+
+// ParseVideoChapters reads chapter markers from a video description. Like
+// YouTube, it only recognises them when the first marker is at 0:00.
+func ParseVideoChapters(description string) []VideoChapter {
+	var chapters []VideoChapter
+	for _, line := range strings.Split(description, "\n") {
+		m := chapterLinePattern.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		chapters = append(chapters, VideoChapter{Start: parseClock(m[1]), Title: strings.TrimSpace(m[2])})
+	}
+	if len(chapters) == 0 || chapters[0].Start != 0 {
+		return nil
+	}
+	return chapters
+}
+
+// parseClock converts "h:mm:ss" or "m:ss" to seconds.
+func parseClock(s string) float64 {
+	var secs float64
+	for _, part := range strings.Split(s, ":") {
+		n, _ := strconv.Atoi(part)
+		secs = secs*60 + float64(n)
+	}
+	return secs
+}
+
+// CaptionSections groups captions into one section per chapter, or a single
+// section titled title when there are no chapters.
+func CaptionSections(title string, captions []Caption, chapters []VideoChapter) []Section {
+	if len(chapters) == 0 {
+		chapters = []VideoChapter{{Title: title}}
+	}
+	sections := make([]Section, len(chapters))
+	for i, c := range chapters {
+		sections[i] = Section{Title: c.Title, Level: 1}
+	}
+
+	texts := make([]strings.Builder, len(chapters))
+	ch := 0
+	for _, c := range captions {
+		for ch+1 < len(chapters) && c.Start >= chapters[ch+1].Start {
+			ch++
+		}
+		texts[ch].WriteString(c.Text)
+		texts[ch].WriteString("\n")
+	}
+	for i := range sections {
+		sections[i].Text = texts[i].String()
+	}
+	return sections
+}
+
+// IsYouTubeURL reports whether rawURL is a YouTube video link.
+func IsYouTubeURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	switch host {
+	case "youtu.be":
+		return len(u.Path) > 1
+	case "youtube.com", "m.youtube.com", "music.youtube.com":
+		return u.Path == "/watch" && u.Query().Get("v") != "" || strings.HasPrefix(u.Path, "/live/") || strings.HasPrefix(u.Path, "/shorts/")
+	}
+	return false
+}
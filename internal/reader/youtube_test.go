@@ -0,0 +1,147 @@
+package reader
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseTimedText(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []Caption
+	}{
+		{
+			name: "classic",
+			input: `<?xml version="1.0" encoding="utf-8" ?><transcript>
+				<text start="0.5" dur="2.1">it&amp;#39;s a
+				test</text>
+				<text start="2.6" dur="1">[Music]</text></transcript>`,
+			want: []Caption{{0.5, "it's a test"}, {2.6, "[Music]"}},
+		},
+		{
+			name: "format 3",
+			input: `<?xml version="1.0" encoding="utf-8" ?><timedtext format="3"><body>
+				<p t="1200" d="900">hello <s>there</s></p><p t="65000" d="500">again</p></body></timedtext>`,
+			want: []Caption{{1.2, "hello there"}, {65, "again"}},
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseTimedText([]byte(tt.input))
+		if err != nil {
+			t.Errorf("%s: ParseTimedText: %v", tt.name, err)
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: caption %d = %+v, want %+v", tt.name, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestParseVideoChapters(t *testing.T) {
+	desc := "Great talk.\n\n0:00 Intro\n(1:30) - The problem\n1:02:03 Q&A\nsee 4:00 for more"
+	got := ParseVideoChapters(desc)
+	want := []VideoChapter{{0, "Intro"}, {90, "The problem"}, {3723, "Q&A"}}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("chapter %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if got := ParseVideoChapters("1:00 Not from the start\n2:00 Later"); got != nil {
+		t.Errorf("chapters not starting at 0:00 should be ignored, got %v", got)
+	}
+}
+
+func TestCaptionSections(t *testing.T) {
+	captions := []Caption{{0, "welcome"}, {50, "so"}, {95, "the problem is"}, {120, "hard"}}
+	chapters := []VideoChapter{{0, "Intro"}, {90, "The problem"}}
+
+	sections := CaptionSections("Talk", captions, chapters)
+	if len(sections) != 2 {
+		t.Fatalf("got %d sections, want 2", len(sections))
+	}
+	if sections[0].Title != "Intro" || strings.Fields(sections[0].Text)[1] != "so" {
+		t.Errorf("section 0 = %+v", sections[0])
+	}
+	if sections[1].Text != "the problem is\nhard\n" {
+		t.Errorf("section 1 text = %q", sections[1].Text)
+	}
+
+	single := CaptionSections("Talk", captions, nil)
+	if len(single) != 1 || single[0].Title != "Talk" {
+		t.Errorf("without chapters got %+v", single)
+	}
+}
+
+func TestFetchYouTubeVideo(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/watch":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<html><script>var ytInitialPlayerResponse = {"videoDetails":{"title":"A Talk",
+				"shortDescription":"0:00 Start\n0:05 End"},"captions":{"playerCaptionsTracklistRenderer":{"captionTracks":[
+				{"baseUrl":"` + srv.URL + `/asr","languageCode":"en","kind":"asr"},
+				{"baseUrl":"` + srv.URL + `/manual","languageCode":"en-GB"}]}}};var meta = {};</script></html>`))
+		case "/manual":
+			w.Write([]byte(`<transcript><text start="1">first words</text><text start="6">last words</text></transcript>`))
+		case "/asr":
+			w.Write([]byte(`<transcript><text start="1">automatic</text></transcript>`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	video, err := FetchYouTubeVideoContext(context.Background(), srv.URL+"/watch?v=abc", "en")
+	if err != nil {
+		t.Fatalf("FetchYouTubeVideoContext: %v", err)
+	}
+	if video.Title != "A Talk" || len(video.Sections) != 2 {
+		t.Fatalf("got %+v", video)
+	}
+	if video.Sections[0].Text != "first words\n" || video.Sections[1].Title != "End" {
+		t.Errorf("sections = %+v", video.Sections)
+	}
+	if video.Language != "en-GB" || video.Fallback {
+		t.Errorf("English captions should be read as asked, got %q, fallback %v", video.Language, video.Fallback)
+	}
+
+	video, err = FetchYouTubeVideoContext(context.Background(), srv.URL+"/watch?v=abc", "de")
+	if err != nil {
+		t.Fatalf("FetchYouTubeVideoContext: %v", err)
+	}
+	if !video.Fallback || video.Language != "en" || !slices.Equal(video.Languages, []string{"en", "en-GB"}) {
+		t.Errorf("with no German captions the fallback should be reported, got %q, %v, %q", video.Language, video.Fallback, video.Languages)
+	}
+}
+
+func TestIsYouTubeURL(t *testing.T) {
+	tests := map[string]bool{
+		"https://www.youtube.com/watch?v=dQw4w9WgXcQ": true,
+		"https://youtu.be/dQw4w9WgXcQ":                true,
+		"https://m.youtube.com/shorts/abc":            true,
+		"https://www.youtube.com/@channel":            false,
+		"https://example.com/watch?v=abc":             false,
+	}
+	for in, want := range tests {
+		if got := IsYouTubeURL(in); got != want {
+			t.Errorf("IsYouTubeURL(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package reader
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ZoteroAPI is the base URL of the Zotero Web API, used to write notes.
+var ZoteroAPI = "https://api.zotero.org"
+
+// ZoteroItem is a library item with the files attached to it.
+type ZoteroItem struct {
+	Key         string
+	CitationKey string
+	Title       string
+	Creators    string
+	Year        string
+	Attachments []string
+}
+
+// zoteroLibrary is the subset of a Better BibTeX JSON export that brr reads.
+type zoteroLibrary struct {
+	Items []struct {
+		ItemKey     string `json:"itemKey"`
+		Key         string `json:"key"`
+		CitationKey string `json:"citationKey"`
+		ItemType    string `json:"itemType"`
+		Title       string `json:"title"`
+		Date        string `json:"date"`
+		Creators    []struct {
+			FirstName string `json:"firstName"`
+			LastName  string `json:"lastName"`
+			Name      string `json:"name"`
+		} `json:"creators"`
+		Attachments []struct {
+			Path string `json:"path"`
+		} `json:"attachments"`
+	} `json:"items"`
+}
+
+// LoadZoteroLibrary reads the items of a Better BibTeX JSON export, which
+// Better BibTeX can keep up to date automatically. Notes and standalone
+// attachments are left out.
+func LoadZoteroLibrary(path string) ([]ZoteroItem, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var lib zoteroLibrary
+	if err := json.Unmarshal(data, &lib); err != nil {
+		return nil, fmt.Errorf("invalid Better BibTeX JSON: %w", err)
+	}
+
+	var items []ZoteroItem
+	for _, it := range lib.Items {
+		if it.ItemType == "note" || it.ItemType == "attachment" {
+			continue
+		}
+		item := ZoteroItem{Key: it.ItemKey, CitationKey: it.CitationKey, Title: it.Title}
+		if item.Key == "" {
+			item.Key = it.Key
+		}
+		if len(it.Date) >= 4 {
+			item.Year = it.Date[:4]
+		}
+		var names []string
+		for _, c := range it.Creators {
+			if c.LastName != "" {
+				names = append(names, c.LastName)
+			} else if c.Name != "" {
+				names = append(names, c.Name)
+			}
+		}
+		switch {
+		case len(names) > 2:
+			item.Creators = names[0] + " et al."
+		default:
+			item.Creators = strings.Join(names, " & ")
+		}
+		for _, a := range it.Attachments {
+			if a.Path != "" {
+				item.Attachments = append(item.Attachments, a.Path)
+			}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// FindZoteroItems returns the items whose key or citation key is query, or
+// failing that, whose title contains it, ignoring case.
+func FindZoteroItems(items []ZoteroItem, query string) []ZoteroItem {
+	for _, it := range items {
+		if strings.EqualFold(it.Key, query) || strings.EqualFold(it.CitationKey, query) {
+			return []ZoteroItem{it}
+		}
+	}
+	var matches []ZoteroItem
+	q := strings.ToLower(query)
+	for _, it := range items {
+		if strings.Contains(strings.ToLower(it.Title), q) {
+			matches = append(matches, it)
+		}
+	}
+	return matches
+}
+
+// ReadableAttachment returns the item's first attached file that brr can
+// read, preferring EPUB over HTML snapshots and other registered formats.
+func (it ZoteroItem) ReadableAttachment() (string, bool) {
+	best, rank := "", 0
+	for _, path := range it.Attachments {
+		ext := strings.ToLower(filepath.Ext(path))
+		r := 0
+		switch {
+		case ext == ".epub":
+			r = 3
+		case ext == ".html" || ext == ".htm":
+			r = 2
+		case ext == ".txt":
+			r = 1
+		default:
+			if _, ok := FormatFor(path); ok {
+				r = 1
+			}
+		}
+		if r > rank {
+			best, rank = path, r
+		}
+	}
+	return best, rank > 0
+}
+
+// String describes the item for listings: key, authors, year and title.
+func (it ZoteroItem) String() string {
+	var byline []string
+	if it.Creators != "" {
+		byline = append(byline, it.Creators)
+	}
+	if it.Year != "" {
+		byline = append(byline, it.Year)
+	}
+	s := it.Key
+	if it.CitationKey != "" {
+		s += " [" + it.CitationKey + "]"
+	}
+	if len(byline) > 0 {
+		s += " " + strings.Join(byline, ", ") + "."
+	}
+	return s + " " + it.Title
+}
+
+// AddZoteroNote attaches an HTML note to an item through the Zotero Web
+// API, authenticating with an API key that has write access.
+func AddZoteroNote(userID, apiKey, itemKey, note string) error {
+	body, err := json.Marshal([]map[string]any{{
+		"itemType":   "note",
+		"parentItem": itemKey,
+		"note":       note,
+		"tags":       []map[string]string{{"tag": "brr"}},
+	}})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, ZoteroAPI+"/users/"+userID+"/items", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Zotero-API-Key", apiKey)
+	req.Header.Set("Zotero-API-Version", "3")
+
+	resp, err := URLClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to write Zotero note: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to write Zotero note: %s", resp.Status)
+	}
+
+	// The write API reports per-object failures with a 200 status.
+	var result struct {
+		Failed map[string]struct {
+			Message string `json:"message"`
+		} `json:"failed"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err == nil {
+		for _, f := range result.Failed {
+			return fmt.Errorf("failed to write Zotero note: %s", f.Message)
+		}
+	}
+	return nil
+}
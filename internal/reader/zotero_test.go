@@ -0,0 +1,100 @@
+package reader
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const zoteroExport = `{"items": [
+  {"itemKey": "ABCD1234", "citationKey": "smith2020", "itemType": "journalArticle",
+   "title": "Reading at Speed", "date": "2020-05-01",
+   "creators": [{"firstName": "Ann", "lastName": "Smith"}, {"firstName": "Bo", "lastName": "Jones"}],
+   "attachments": [{"path": "/lib/smith.pdf"}, {"path": "/lib/smith.html"}, {"path": "/lib/smith.epub"}]},
+  {"itemKey": "EFGH5678", "itemType": "book", "title": "Speed and Memory", "date": "1999",
+   "creators": [{"lastName": "A"}, {"lastName": "B"}, {"lastName": "C"}],
+   "attachments": [{"path": "/lib/memory.pdf"}]},
+  {"itemKey": "NOTE0001", "itemType": "note", "title": "Speed notes"}
+]}`
+
+func TestLoadZoteroLibrary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "library.json")
+	os.WriteFile(path, []byte(zoteroExport), 0644)
+
+	items, err := LoadZoteroLibrary(path)
+	if err != nil {
+		t.Fatalf("LoadZoteroLibrary: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("got %d items, want 2 (notes skipped)", len(items))
+	}
+	if got, want := items[0].String(), "ABCD1234 [smith2020] Smith & Jones, 2020. Reading at Speed"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if got := items[1].Creators; got != "A et al." {
+		t.Errorf("Creators = %q, want A et al.", got)
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"smith2020", []string{"ABCD1234"}},
+		{"efgh5678", []string{"EFGH5678"}},
+		{"speed", []string{"ABCD1234", "EFGH5678"}},
+		{"memory", []string{"EFGH5678"}},
+		{"nothing", nil},
+	}
+	for _, tt := range tests {
+		var keys []string
+		for _, it := range FindZoteroItems(items, tt.query) {
+			keys = append(keys, it.Key)
+		}
+		if !reflect.DeepEqual(keys, tt.want) {
+			t.Errorf("FindZoteroItems(%q) = %v, want %v", tt.query, keys, tt.want)
+		}
+	}
+
+	if got, ok := items[0].ReadableAttachment(); !ok || got != "/lib/smith.epub" {
+		t.Errorf("ReadableAttachment() = %q, %v, want the EPUB", got, ok)
+	}
+	if got, ok := items[1].ReadableAttachment(); ok {
+		t.Errorf("ReadableAttachment() = %q for a PDF-only item", got)
+	}
+}
+
+func TestAddZoteroNote(t *testing.T) {
+	var got []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/users/42/items" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("Zotero-API-Key") != "secret" {
+			t.Errorf("Zotero-API-Key = %q", r.Header.Get("Zotero-API-Key"))
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		if got[0]["parentItem"] == "BAD" {
+			w.Write([]byte(`{"success": {}, "failed": {"0": {"code": 400, "message": "Parent item not found"}}}`))
+			return
+		}
+		w.Write([]byte(`{"success": {"0": "NEWKEY"}, "failed": {}}`))
+	}))
+	defer srv.Close()
+	orig := ZoteroAPI
+	ZoteroAPI = srv.URL
+	defer func() { ZoteroAPI = orig }()
+
+	if err := AddZoteroNote("42", "secret", "ABCD1234", "<p>Read.</p>"); err != nil {
+		t.Fatalf("AddZoteroNote: %v", err)
+	}
+	if len(got) != 1 || got[0]["itemType"] != "note" || got[0]["note"] != "<p>Read.</p>" {
+		t.Errorf("posted %v", got)
+	}
+	if err := AddZoteroNote("42", "secret", "BAD", "<p>Read.</p>"); err == nil {
+		t.Error("expected an error for a failed write")
+	}
+}
@@ -0,0 +1,156 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	backupDirName    = "backups"
+	backupPrefix     = "reading_positions-"
+	backupTimeFormat = "20060102-150405.000"
+
+	// BackupInterval is how often the state file is backed up
+	BackupInterval = 24 * time.Hour
+	// MaxBackups is how many backups are kept; older ones are deleted
+	MaxBackups = 7
+)
+
+// Backup is a timestamped copy of the state file
+type Backup struct {
+	Path  string
+	Taken time.Time
+	// Books counts the files with saved state in the backup
+	Books int
+}
+
+// BackupIfDue backs up the state file unless the newest backup is less
+// than BackupInterval old
+func (s *StateStore) BackupIfDue(now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	backups := s.backups()
+	if len(backups) > 0 && now.Sub(backups[0].Taken) < BackupInterval {
+		return nil
+	}
+	return s.backup(now)
+}
+
+// Backup backs up the state file now
+func (s *StateStore) Backup(now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.backup(now)
+}
+
+// Backups returns the backups of the state file, newest first
+func (s *StateStore) Backups() []Backup {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	backups := s.backups()
+	for i := range backups {
+		backups[i].Books = countBooks(backups[i].Path)
+	}
+	return backups
+}
+
+// Restore replaces the saved state with backup b, first backing up the
+// current state so the restore can be undone
+func (s *StateStore) Restore(b Backup, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	raw, err := os.ReadFile(b.Path)
+	if err != nil {
+		return err
+	}
+	data := make(map[string]ReadingState)
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return fmt.Errorf("backup %s is damaged: %w", filepath.Base(b.Path), err)
+	}
+	if err := s.backup(now); err != nil {
+		return err
+	}
+	s.data = data
+	return s.save()
+}
+
+func (s *StateStore) backupDir() string {
+	return filepath.Join(filepath.Dir(s.path), backupDirName)
+}
+
+// backup copies the state file into the backup directory, deleting the
+// oldest backups beyond MaxBackups
+func (s *StateStore) backup(now time.Time) error {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	dir := s.backupDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	name := backupPrefix + now.Format(backupTimeFormat) + ".json"
+	if err := writeFileAtomic(filepath.Join(dir, name), data, 0644); err != nil {
+		return err
+	}
+	backups := s.backups()
+	for i := MaxBackups; i < len(backups); i++ {
+		if err := os.Remove(backups[i].Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backups returns the backups of the state file, newest first, with only
+// what their names tell
+func (s *StateStore) backups() []Backup {
+	paths, _ := filepath.Glob(filepath.Join(s.backupDir(), backupPrefix+"*.json"))
+	var out []Backup
+	for _, path := range paths {
+		stamp := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), backupPrefix), ".json")
+		taken, err := time.ParseInLocation(backupTimeFormat, stamp, time.Local)
+		if err != nil {
+			continue
+		}
+		out = append(out, Backup{Path: path, Taken: taken})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Taken.After(out[j].Taken)
+	})
+	return out
+}
+
+// countBooks counts the entries of a backup without decoding them, or
+// returns 0 if it can't be read
+func countBooks(path string) int {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+	dec := json.NewDecoder(f)
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return 0
+	}
+	n := 0
+	for dec.More() {
+		if _, err := dec.Token(); err != nil {
+			return 0
+		}
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return 0
+		}
+		n++
+	}
+	return n
+}
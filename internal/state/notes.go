@@ -0,0 +1,42 @@
+package state
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const notesDirName = "notes"
+
+// NotesPath returns the file holding the scratch notes for file, kept next
+// to the state file so they travel with it
+func (s *StateStore) NotesPath(hash string) string {
+	return filepath.Join(filepath.Dir(s.path), notesDirName, hash+".md")
+}
+
+// Notes returns the scratch notes for file, or "" if there are none
+func (s *StateStore) Notes(hash string) (string, error) {
+	data, err := os.ReadFile(s.NotesPath(hash))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	return string(data), err
+}
+
+// SetNotes saves the scratch notes for file, removing the notes file when
+// they are blank
+func (s *StateStore) SetNotes(hash, notes string) error {
+	path := s.NotesPath(hash)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if strings.TrimSpace(notes) == "" {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	return writeFileAtomic(path, []byte(notes), 0644)
+}
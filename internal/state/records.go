@@ -0,0 +1,139 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	recordsFileName = "records.json"
+
+	// SustainedWords is the fewest words a session must read for its
+	// speed to count toward FastestWPM
+	SustainedWords = 1000
+	// MaxRegressionRate is the most backward jumps per word read a
+	// session may make for its speed to count toward FastestWPM
+	MaxRegressionRate = 0.01
+)
+
+// Record is a personal best and when it was set
+type Record struct {
+	Value int       `json:"value"`
+	Set   time.Time `json:"set"`
+}
+
+// Records are the reader's personal bests across every book
+type Records struct {
+	// LongestSession is the most seconds spent reading in one session,
+	// not counting time paused
+	LongestSession Record `json:"longest_session"`
+	// FastestWPM is the fastest effective speed of a session reading at
+	// least SustainedWords with few regressions
+	FastestWPM Record `json:"fastest_wpm"`
+	// LongestStreak is the most consecutive days read on
+	LongestStreak Record `json:"longest_streak"`
+	// Streak is the current run of days read on, the last of which was
+	// LastDay
+	Streak  int    `json:"streak"`
+	LastDay string `json:"last_day,omitempty"`
+}
+
+// SessionSummary describes a finished reading session for Records
+type SessionSummary struct {
+	// Reading is the time spent reading, not counting time paused
+	Reading time.Duration
+	Words   int
+	WPM     int
+	// Regressions counts jumps back to reread
+	Regressions int
+}
+
+// Record names the personal bests Records keeps, as reported by Add
+const (
+	RecordLongestSession = "longest session"
+	RecordFastestWPM     = "fastest sustained speed"
+	RecordLongestStreak  = "longest streak"
+)
+
+// Add counts a session that ended at now toward the records, returning the
+// names of those it broke. A session that read nothing counts for nothing.
+func (r *Records) Add(s SessionSummary, now time.Time) []string {
+	if s.Words <= 0 {
+		return nil
+	}
+	var broken []string
+	if secs := int(s.Reading.Seconds()); secs > r.LongestSession.Value {
+		r.LongestSession = Record{Value: secs, Set: now}
+		broken = append(broken, RecordLongestSession)
+	}
+	if s.Words >= SustainedWords && float64(s.Regressions) <= MaxRegressionRate*float64(s.Words) && s.WPM > r.FastestWPM.Value {
+		r.FastestWPM = Record{Value: s.WPM, Set: now}
+		broken = append(broken, RecordFastestWPM)
+	}
+
+	today := now.Format(time.DateOnly)
+	switch r.LastDay {
+	case today:
+	case now.AddDate(0, 0, -1).Format(time.DateOnly):
+		r.Streak++
+	default:
+		r.Streak = 1
+	}
+	r.LastDay = today
+	if r.Streak > r.LongestStreak.Value {
+		// A first day is hardly a record to announce.
+		if r.LongestStreak.Value > 0 {
+			broken = append(broken, RecordLongestStreak)
+		}
+		r.LongestStreak = Record{Value: r.Streak, Set: now}
+	}
+	return broken
+}
+
+// CurrentStreak returns the run of days read on that includes today or
+// yesterday, or 0 if it has lapsed
+func (r *Records) CurrentStreak(now time.Time) int {
+	if r.LastDay == now.Format(time.DateOnly) || r.LastDay == now.AddDate(0, 0, -1).Format(time.DateOnly) {
+		return r.Streak
+	}
+	return 0
+}
+
+// recordsPath returns the file holding the records, next to the state file
+func (s *StateStore) recordsPath() string {
+	return filepath.Join(filepath.Dir(s.path), recordsFileName)
+}
+
+// Records returns the saved personal records, or none if there are none
+func (s *StateStore) Records() (Records, error) {
+	var r Records
+	data, err := os.ReadFile(s.recordsPath())
+	if errors.Is(err, fs.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return r, err
+	}
+	return r, json.Unmarshal(data, &r)
+}
+
+// AddSession counts a session that ended at now toward the saved records,
+// returning the names of those it broke
+func (s *StateStore) AddSession(sum SessionSummary, now time.Time) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, err := s.Records()
+	if err != nil {
+		return nil, err
+	}
+	broken := r.Add(sum, now)
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return broken, writeFileAtomic(s.recordsPath(), data, 0644)
+}
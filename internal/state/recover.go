@@ -0,0 +1,112 @@
+package state
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// damagedSuffix names the copy of a state file that could not be read
+// whole, kept for inspection after its entries are salvaged
+const damagedSuffix = ".damaged"
+
+// writeFileAtomic writes data to a temporary file beside path and renames
+// it over path, so a crash mid-write leaves the old file whole
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Chmod(tmp, perm); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// recoverState salvages what it can of a state file that is not valid
+// JSON: every entry before the damage that still reads as a ReadingState,
+// filled in with the entries lost from it that are in the newest backup
+// that can be read. The damaged file is kept beside it, and Recovered
+// describes what was done.
+func (s *StateStore) recoverState(damaged []byte) {
+	s.data = salvageStates(damaged)
+	salvaged := len(s.data)
+	for _, b := range s.backups() {
+		raw, err := os.ReadFile(b.Path)
+		if err != nil {
+			continue
+		}
+		var backup map[string]ReadingState
+		if json.Unmarshal(raw, &backup) != nil {
+			continue
+		}
+		for hash, st := range backup {
+			if _, ok := s.data[hash]; !ok {
+				s.data[hash] = st
+			}
+		}
+		break
+	}
+	os.WriteFile(s.path+damagedSuffix, damaged, 0644)
+	s.recovered = fmt.Sprintf("The state file was damaged; recovered %d books (%d from it, %d from its last backup). The damaged file is kept as %s",
+		len(s.data), salvaged, len(s.data)-salvaged, filepath.Base(s.path)+damagedSuffix)
+}
+
+// salvageStates returns the entries of a damaged state file that can still
+// be read, stopping where the file stops making sense
+func salvageStates(data []byte) map[string]ReadingState {
+	states := make(map[string]ReadingState)
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return states
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			break
+		}
+		hash, ok := tok.(string)
+		if !ok {
+			break
+		}
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			break
+		}
+		var st ReadingState
+		if json.Unmarshal(raw, &st) == nil {
+			states[hash] = st
+		}
+	}
+	return states
+}
+
+// Recovered describes how a damaged state file was recovered when the
+// store was loaded, or returns "" if it was read whole
+func (s *StateStore) Recovered() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.recovered
+}
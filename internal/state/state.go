@@ -7,17 +7,101 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"sync"
+	"time"
 )
 
 const (
 	stateFileName = "reading_positions.json"
 	hashBytes     = 8192 // First 8KB for content hash
+
+	// conflictPattern matches the copies of the state file left by sync
+	// tools such as Syncthing, Dropbox and Nextcloud when two devices
+	// change it at once
+	conflictPattern = "reading_positions*conflict*.json"
 )
 
-// ReadingState stores position for a single file
+// DeviceName identifies this device in saved positions
+var DeviceName, _ = os.Hostname()
+
+// ReadingState stores position and library metadata for a single file
 type ReadingState struct {
-	WordIndex int `json:"word_index"`
+	WordIndex  int       `json:"word_index"`
+	Filename   string    `json:"filename,omitempty"`
+	Title      string    `json:"title,omitempty"`
+	Author     string    `json:"author,omitempty"`
+	TotalWords int       `json:"total_words,omitempty"`
+	LastRead   time.Time `json:"last_read"`
+	// Device is the device that saved WordIndex
+	Device string `json:"device,omitempty"`
+	// GoalsReached counts reading sessions that met their goal
+	GoalsReached int `json:"goals_reached,omitempty"`
+	// Finished is when the book was first read far enough to count as
+	// finished
+	Finished time.Time `json:"finished,omitzero"`
+	// Bookmark places WordIndex in the document's structure, when known
+	Bookmark *Bookmark `json:"bookmark,omitempty"`
+	// Marks are the sentences marked while reading, in the order marked
+	Marks []Mark `json:"marks,omitempty"`
+	// Annotations are the notes attached to positions, in reading order
+	Annotations []Annotation `json:"annotations,omitempty"`
+	// Sessions are further positions kept under a name, such as one read
+	// on another device
+	Sessions map[string]Session `json:"sessions,omitempty"`
+	// Sequence is the chapters to read, by index, in the order to read
+	// them, when not all of them in document order
+	Sequence []int `json:"sequence,omitempty"`
+	Settings
+}
+
+// Session is a named reading position
+type Session struct {
+	WordIndex int       `json:"word_index"`
+	Bookmark  *Bookmark `json:"bookmark,omitempty"`
+	LastRead  time.Time `json:"last_read"`
+}
+
+// Bookmark records the chapter and sentence enclosing a saved position, so
+// it can be described without re-extracting the document
+type Bookmark struct {
+	Chapter  int `json:"chapter"`
+	Sentence int `json:"sentence"`
+}
+
+// Mark is a sentence marked while reading
+type Mark struct {
+	// WordIndex is the position of the sentence's first word
+	WordIndex int       `json:"word_index"`
+	Quote     string    `json:"quote"`
+	Chapter   string    `json:"chapter,omitempty"`
+	Created   time.Time `json:"created"`
+}
+
+// Annotation is a note attached to a position while reading
+type Annotation struct {
+	// WordIndex is the position of the word the note was written at
+	WordIndex int       `json:"word_index"`
+	Text      string    `json:"text"`
+	Chapter   string    `json:"chapter,omitempty"`
+	Created   time.Time `json:"created"`
+}
+
+// Settings stores the reader settings last used for a file
+type Settings struct {
+	WPM     int    `json:"wpm,omitempty"`
+	Display string `json:"display,omitempty"`
+	// Pacing names the pacer, as accepted by --pacing
+	Pacing string `json:"pacing,omitempty"`
+	// Stopwords is the function word mode, as accepted by --stopwords
+	Stopwords string `json:"stopwords,omitempty"`
+}
+
+// HistoryEntry pairs a file hash with its saved state
+type HistoryEntry struct {
+	Hash string
+	ReadingState
 }
 
 // StateStore manages persistent reading state
@@ -25,9 +109,14 @@ type StateStore struct {
 	path string
 	data map[string]ReadingState
 	mu   sync.RWMutex
+	// recovered describes the recovery of a damaged state file; see
+	// Recovered
+	recovered string
 }
 
-// NewStateStore creates or loads state from XDG_STATE_HOME/brr/
+// NewStateStore creates or loads state from XDG_STATE_HOME/brr/, backing
+// it up if no backup was made in the last BackupInterval and it was read
+// whole
 func NewStateStore() (*StateStore, error) {
 	dir := getStateDir()
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -41,6 +130,10 @@ func NewStateStore() (*StateStore, error) {
 	if err := store.load(); err != nil {
 		store.data = make(map[string]ReadingState)
 	}
+	// A damaged state file would push a good backup out of the ring.
+	if store.recovered == "" {
+		store.BackupIfDue(time.Now())
+	}
 	return store, nil
 }
 
@@ -71,6 +164,20 @@ func ComputeHash(filename string) (string, error) {
 	return hex.EncodeToString(hash[:16]), nil // First 16 bytes = 32 hex chars
 }
 
+// ComputeSetHash generates content hash for a set of files read together
+// as one book, in the order given
+func ComputeSetHash(filenames []string) (string, error) {
+	h := sha256.New()
+	for _, name := range filenames {
+		hash, err := ComputeHash(name)
+		if err != nil {
+			return "", err
+		}
+		io.WriteString(h, hash)
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16]), nil
+}
+
 // GetPosition returns saved position for file, or 0 if not found
 func (s *StateStore) GetPosition(hash string) int {
 	s.mu.RLock()
@@ -81,14 +188,314 @@ func (s *StateStore) GetPosition(hash string) int {
 	return 0
 }
 
-// SetPosition saves position for file
+// SetPosition saves position for file, keeping any recorded metadata
 func (s *StateStore) SetPosition(hash string, wordIndex int) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.data[hash] = ReadingState{WordIndex: wordIndex}
+	st := s.data[hash]
+	st.setPosition(wordIndex)
+	s.data[hash] = st
+	return s.save()
+}
+
+func (st *ReadingState) setPosition(wordIndex int) {
+	st.WordIndex = wordIndex
+	st.LastRead = time.Now()
+	st.Device = DeviceName
+}
+
+// GetSession returns the named session for file
+func (s *StateStore) GetSession(hash, name string) (Session, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	session, ok := s.data[hash].Sessions[name]
+	return session, ok
+}
+
+// SetSession saves the named session for file
+func (s *StateStore) SetSession(hash, name string, session Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.data[hash]
+	st.setSession(name, session)
+	s.data[hash] = st
+	return s.save()
+}
+
+func (st *ReadingState) setSession(name string, session Session) {
+	if st.Sessions == nil {
+		st.Sessions = make(map[string]Session)
+	}
+	session.LastRead = time.Now()
+	st.Sessions[name] = session
+}
+
+// Conflict returns file's state from a sync conflict copy of the state
+// file whose position differs from the local one, preferring the most
+// recently read
+func (s *StateStore) Conflict(hash string) (ReadingState, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	local := s.data[hash]
+	var other ReadingState
+	found := false
+	for _, c := range s.conflictCopies() {
+		st, ok := c.data[hash]
+		if !ok || st.WordIndex == local.WordIndex {
+			continue
+		}
+		if !found || st.LastRead.After(other.LastRead) {
+			other, found = st, true
+		}
+	}
+	return other, found
+}
+
+// ResolveConflict makes keep's position file's own and removes file from
+// the sync conflict copies, deleting copies left with nothing that
+// differs from the local state
+func (s *StateStore) ResolveConflict(hash string, keep ReadingState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.data[hash]
+	st.WordIndex = keep.WordIndex
+	st.Bookmark = keep.Bookmark
+	st.Device = keep.Device
+	st.LastRead = time.Now()
+	s.data[hash] = st
+	if err := s.save(); err != nil {
+		return err
+	}
+
+	for _, c := range s.conflictCopies() {
+		delete(c.data, hash)
+		for h, other := range c.data {
+			if local, ok := s.data[h]; ok && local.WordIndex == other.WordIndex {
+				delete(c.data, h)
+			}
+		}
+		var err error
+		if len(c.data) == 0 {
+			err = os.Remove(c.path)
+		} else {
+			err = writeState(c.path, c.data)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// conflictCopy is a sync conflict copy of the state file
+type conflictCopy struct {
+	path string
+	data map[string]ReadingState
+}
+
+// conflictCopies reads the sync conflict copies of the state file,
+// skipping any that cannot be read
+func (s *StateStore) conflictCopies() []conflictCopy {
+	paths, _ := filepath.Glob(filepath.Join(filepath.Dir(s.path), conflictPattern))
+	var copies []conflictCopy
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		c := conflictCopy{path: path}
+		if json.Unmarshal(data, &c.data) == nil {
+			copies = append(copies, c)
+		}
+	}
+	return copies
+}
+
+// SetInfo records library metadata for file without touching its position
+func (s *StateStore) SetInfo(hash, filename, title, author string, totalWords int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.data[hash]
+	st.Filename = filename
+	st.Title = title
+	st.Author = author
+	st.TotalWords = totalWords
+	st.LastRead = time.Now()
+	s.data[hash] = st
+	return s.save()
+}
+
+// SetSettings saves the reader settings for file
+func (s *StateStore) SetSettings(hash string, settings Settings) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.data[hash]
+	st.Settings = settings
+	s.data[hash] = st
 	return s.save()
 }
 
+// GetSettings returns saved reader settings for file, or zero values
+func (s *StateStore) GetSettings(hash string) Settings {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.data[hash].Settings
+}
+
+// SetSequence saves the chapters of file to read and their order, or
+// clears them when sequence is empty
+func (s *StateStore) SetSequence(hash string, sequence []int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.data[hash]
+	st.Sequence = sequence
+	s.data[hash] = st
+	return s.save()
+}
+
+// GetSequence returns the chapters of file to read in order, or nil to
+// read them all in document order
+func (s *StateStore) GetSequence(hash string) []int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.data[hash].Sequence
+}
+
+// SetBookmark saves the chapter and sentence of file's saved position
+func (s *StateStore) SetBookmark(hash string, bookmark Bookmark) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.data[hash]
+	st.Bookmark = &bookmark
+	s.data[hash] = st
+	return s.save()
+}
+
+// RecordGoal counts a reading goal met while reading file
+func (s *StateStore) RecordGoal(hash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.data[hash]
+	st.GoalsReached++
+	s.data[hash] = st
+	return s.save()
+}
+
+// SetFinished records file as finished at t, unless it already was
+func (s *StateStore) SetFinished(hash string, t time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.data[hash]
+	if !st.Finished.IsZero() {
+		return nil
+	}
+	st.Finished = t
+	s.data[hash] = st
+	return s.save()
+}
+
+// AddMark saves a marked sentence for file. It reports false, saving
+// nothing, if the sentence is already marked.
+func (s *StateStore) AddMark(hash string, mark Mark) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.data[hash]
+	for _, m := range st.Marks {
+		if m.WordIndex == mark.WordIndex {
+			return false, nil
+		}
+	}
+	st.Marks = append(st.Marks, mark)
+	s.data[hash] = st
+	return true, s.save()
+}
+
+// AddAnnotation saves a note at a position in file, keeping the notes in
+// reading order
+func (s *StateStore) AddAnnotation(hash string, annotation Annotation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.data[hash]
+	i := sort.Search(len(st.Annotations), func(i int) bool {
+		return st.Annotations[i].WordIndex > annotation.WordIndex
+	})
+	st.Annotations = slices.Insert(st.Annotations, i, annotation)
+	s.data[hash] = st
+	return s.save()
+}
+
+// Annotations returns the notes saved at positions in file, in reading
+// order
+func (s *StateStore) Annotations(hash string) []Annotation {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.Clone(s.data[hash].Annotations)
+}
+
+// Progress is what is saved of a file when reading stops
+type Progress struct {
+	Settings  Settings
+	WordIndex int
+	// Bookmark places WordIndex in the document's structure, when known
+	Bookmark *Bookmark
+	// Session, if set, names the session WordIndex is saved to in place of
+	// the file's own position
+	Session string
+	// Finished, if set, is when the file was read far enough to count as
+	// finished; an earlier finish is kept
+	Finished time.Time
+}
+
+// SaveProgress saves the settings, position and finish of file together,
+// writing the state file once. A position saved without a bookmark clears
+// the old one, which described another position.
+func (s *StateStore) SaveProgress(hash string, p Progress) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.data[hash]
+	st.Settings = p.Settings
+	if st.Finished.IsZero() {
+		st.Finished = p.Finished
+	}
+	if p.Session != "" {
+		st.setSession(p.Session, Session{WordIndex: p.WordIndex, Bookmark: p.Bookmark})
+	} else {
+		st.setPosition(p.WordIndex)
+		st.Bookmark = nil
+		if p.Bookmark != nil {
+			bookmark := *p.Bookmark
+			st.Bookmark = &bookmark
+		}
+	}
+	s.data[hash] = st
+	return s.save()
+}
+
+// Get returns the full saved state for file
+func (s *StateStore) Get(hash string) (ReadingState, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	st, ok := s.data[hash]
+	return st, ok
+}
+
+// History returns files with a recorded filename, most recently read first
+func (s *StateStore) History() []HistoryEntry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var out []HistoryEntry
+	for hash, st := range s.data {
+		if st.Filename == "" {
+			continue
+		}
+		out = append(out, HistoryEntry{Hash: hash, ReadingState: st})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].LastRead.After(out[j].LastRead)
+	})
+	return out
+}
+
 // Clear removes saved position for file
 func (s *StateStore) Clear(hash string) error {
 	s.mu.Lock()
@@ -105,13 +512,20 @@ func (s *StateStore) load() error {
 	if err != nil {
 		return err
 	}
-	return json.Unmarshal(data, &s.data)
+	if json.Unmarshal(data, &s.data) != nil {
+		s.recoverState(data)
+	}
+	return nil
 }
 
 func (s *StateStore) save() error {
-	data, err := json.MarshalIndent(s.data, "", "  ")
+	return writeState(s.path, s.data)
+}
+
+func writeState(path string, states map[string]ReadingState) error {
+	data, err := json.MarshalIndent(states, "", "  ")
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(s.path, data, 0644)
+	return writeFileAtomic(path, data, 0644)
 }
@@ -1,9 +1,14 @@
 package state
 
 import (
+	"bytes"
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestComputeHash(t *testing.T) {
@@ -63,6 +68,34 @@ func TestComputeHashSmallFile(t *testing.T) {
 	}
 }
 
+func TestComputeSetHash(t *testing.T) {
+	tmpDir := t.TempDir()
+	file1 := filepath.Join(tmpDir, "ch1.md")
+	file2 := filepath.Join(tmpDir, "ch2.md")
+	os.WriteFile(file1, []byte("Chapter one"), 0644)
+	os.WriteFile(file2, []byte("Chapter two"), 0644)
+
+	hash, err := ComputeSetHash([]string{file1, file2})
+	if err != nil {
+		t.Fatalf("ComputeSetHash failed: %v", err)
+	}
+	if len(hash) != 32 {
+		t.Errorf("Hash should be 32 chars, got %d", len(hash))
+	}
+	if again, _ := ComputeSetHash([]string{file1, file2}); again != hash {
+		t.Errorf("Same files should produce same hash: %s != %s", hash, again)
+	}
+	if reordered, _ := ComputeSetHash([]string{file2, file1}); reordered == hash {
+		t.Error("Files in another order should produce a different hash")
+	}
+	if single, _ := ComputeHash(file1); single == hash {
+		t.Error("A set should not share a hash with its first file")
+	}
+	if _, err := ComputeSetHash([]string{file1, filepath.Join(tmpDir, "missing.md")}); err == nil {
+		t.Error("A missing file should fail")
+	}
+}
+
 func TestStateStore(t *testing.T) {
 	// Use temp directory for state
 	tmpDir := t.TempDir()
@@ -128,3 +161,415 @@ func TestStateStorePersistence(t *testing.T) {
 		t.Errorf("Expected 5678 from persisted state, got %d", pos)
 	}
 }
+
+func TestStateStoreHistory(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", tmpDir)
+
+	store, err := NewStateStore()
+	if err != nil {
+		t.Fatalf("NewStateStore failed: %v", err)
+	}
+
+	store.SetInfo("hash1", "/books/one.txt", "one", "", 100)
+	store.SetPosition("hash1", 42)
+	store.SetPosition("anonymous", 7)
+	store.SetInfo("hash2", "/books/two.epub", "Two", "A. Author", 200)
+
+	history := store.History()
+	if len(history) != 2 {
+		t.Fatalf("Expected 2 history entries, got %d", len(history))
+	}
+	if history[0].Hash != "hash2" {
+		t.Errorf("Expected most recent entry first, got %s", history[0].Hash)
+	}
+
+	st, ok := store.Get("hash1")
+	if !ok {
+		t.Fatal("Expected state for hash1")
+	}
+	if st.WordIndex != 42 || st.Filename != "/books/one.txt" || st.TotalWords != 100 {
+		t.Errorf("SetPosition should keep metadata, got %+v", st)
+	}
+	if st.LastRead.IsZero() {
+		t.Error("Expected LastRead to be set")
+	}
+	if history[0].Title != "Two" || history[0].Author != "A. Author" {
+		t.Errorf("Expected title and author in history, got %+v", history[0])
+	}
+}
+
+func TestStateStoreSettings(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", tmpDir)
+
+	testHash := "abcdef1234567890abcdef1234567890"
+
+	store1, err := NewStateStore()
+	if err != nil {
+		t.Fatalf("NewStateStore failed: %v", err)
+	}
+	store1.SetPosition(testHash, 42)
+	store1.SetSettings(testHash, Settings{WPM: 550, Display: "bionic", Pacing: "syllable", Stopwords: "half"})
+	store1.SetPosition(testHash, 43)
+
+	store2, err := NewStateStore()
+	if err != nil {
+		t.Fatalf("NewStateStore failed: %v", err)
+	}
+	got := store2.GetSettings(testHash)
+	if got != (Settings{WPM: 550, Display: "bionic", Pacing: "syllable", Stopwords: "half"}) {
+		t.Errorf("Expected saved settings, got %+v", got)
+	}
+	if pos := store2.GetPosition(testHash); pos != 43 {
+		t.Errorf("Expected position 43, got %d", pos)
+	}
+	store2.RecordGoal(testHash)
+	store2.RecordGoal(testHash)
+	if st, _ := store2.Get(testHash); st.GoalsReached != 2 || st.WordIndex != 43 {
+		t.Errorf("Expected 2 goals at position 43, got %+v", st)
+	}
+	if st, _ := store2.Get(testHash); st.Bookmark != nil {
+		t.Errorf("Expected no bookmark, got %+v", st.Bookmark)
+	}
+	store2.SetBookmark(testHash, Bookmark{Chapter: 0, Sentence: 7})
+	store3, _ := NewStateStore()
+	if st, _ := store3.Get(testHash); st.Bookmark == nil || *st.Bookmark != (Bookmark{Sentence: 7}) {
+		t.Errorf("Expected bookmark at sentence 7, got %+v", st.Bookmark)
+	}
+
+	if got := store2.GetSettings("unknown"); got != (Settings{}) {
+		t.Errorf("Expected zero settings for unknown file, got %+v", got)
+	}
+}
+
+func TestStateStoreMarks(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	testHash := "abcdef1234567890abcdef1234567890"
+
+	store1, err := NewStateStore()
+	if err != nil {
+		t.Fatalf("NewStateStore failed: %v", err)
+	}
+	if added, err := store1.AddMark(testHash, Mark{WordIndex: 10, Quote: "First."}); !added || err != nil {
+		t.Fatalf("AddMark = %v, %v", added, err)
+	}
+	if added, _ := store1.AddMark(testHash, Mark{WordIndex: 10, Quote: "First."}); added {
+		t.Error("marking the same sentence twice should be ignored")
+	}
+	store1.AddMark(testHash, Mark{WordIndex: 3, Quote: "Earlier.", Chapter: "One"})
+	store1.SetPosition(testHash, 20)
+
+	store2, _ := NewStateStore()
+	st, _ := store2.Get(testHash)
+	if len(st.Marks) != 2 || st.Marks[0].Quote != "First." || st.Marks[1].Chapter != "One" {
+		t.Errorf("Expected two saved marks in order, got %+v", st.Marks)
+	}
+}
+
+func TestStateStoreNotes(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	testHash := "abcdef1234567890abcdef1234567890"
+
+	store, err := NewStateStore()
+	if err != nil {
+		t.Fatalf("NewStateStore failed: %v", err)
+	}
+	if notes, err := store.Notes(testHash); notes != "" || err != nil {
+		t.Errorf("Notes before any were saved = %q, %v", notes, err)
+	}
+	if err := store.SetNotes(testHash, "Who is the narrator?\n"); err != nil {
+		t.Fatalf("SetNotes failed: %v", err)
+	}
+	store2, _ := NewStateStore()
+	if notes, _ := store2.Notes(testHash); notes != "Who is the narrator?\n" {
+		t.Errorf("Notes = %q, want them saved", notes)
+	}
+	if err := store2.SetNotes(testHash, "  \n"); err != nil {
+		t.Fatalf("SetNotes blank failed: %v", err)
+	}
+	if _, err := os.Stat(store2.NotesPath(testHash)); !os.IsNotExist(err) {
+		t.Errorf("blank notes should remove the notes file, got %v", err)
+	}
+}
+
+func TestStateStoreAnnotations(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	testHash := "abcdef1234567890abcdef1234567890"
+
+	store, err := NewStateStore()
+	if err != nil {
+		t.Fatalf("NewStateStore failed: %v", err)
+	}
+	for _, a := range []Annotation{
+		{WordIndex: 40, Text: "Callback to chapter one"},
+		{WordIndex: 12, Text: "Unreliable narrator?", Chapter: "One"},
+		{WordIndex: 40, Text: "Same word, later note"},
+	} {
+		if err := store.AddAnnotation(testHash, a); err != nil {
+			t.Fatalf("AddAnnotation failed: %v", err)
+		}
+	}
+
+	store2, _ := NewStateStore()
+	got := store2.Annotations(testHash)
+	var texts []string
+	for _, a := range got {
+		texts = append(texts, a.Text)
+	}
+	if want := []string{"Unreliable narrator?", "Callback to chapter one", "Same word, later note"}; !slices.Equal(texts, want) {
+		t.Errorf("Annotations = %q, want %q in reading order", texts, want)
+	}
+	if got[0].Chapter != "One" {
+		t.Errorf("the chapter was not saved: %+v", got[0])
+	}
+}
+
+func TestStateStoreSyncConflict(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", tmpDir)
+	book, other := "abcdef1234567890abcdef1234567890", "0123456789abcdef0123456789abcdef"
+
+	store, err := NewStateStore()
+	if err != nil {
+		t.Fatalf("NewStateStore failed: %v", err)
+	}
+	defer func(name string) { DeviceName = name }(DeviceName)
+	DeviceName = "laptop"
+	store.SetPosition(book, 100)
+	store.SetPosition(other, 7)
+	if _, ok := store.Conflict(book); ok {
+		t.Error("Expected no conflict without conflict copies")
+	}
+
+	conflicted := filepath.Join(tmpDir, "brr", "reading_positions.sync-conflict-20260101-120000-ABCDEFG.json")
+	err = os.WriteFile(conflicted, []byte(`{
+		"`+book+`": {"word_index": 250, "device": "phone"},
+		"`+other+`": {"word_index": 7}
+	}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, ok := store.Conflict(book)
+	if !ok || st.WordIndex != 250 || st.Device != "phone" {
+		t.Fatalf("Conflict = %+v, %v; want phone's position 250", st, ok)
+	}
+	if _, ok := store.Conflict(other); ok {
+		t.Error("Expected no conflict for matching positions")
+	}
+
+	if err := store.SetSession(book, "laptop", Session{WordIndex: 100}); err != nil {
+		t.Fatalf("SetSession failed: %v", err)
+	}
+	if err := store.ResolveConflict(book, st); err != nil {
+		t.Fatalf("ResolveConflict failed: %v", err)
+	}
+	if pos := store.GetPosition(book); pos != 250 {
+		t.Errorf("Expected the kept position 250, got %d", pos)
+	}
+	if session, ok := store.GetSession(book, "laptop"); !ok || session.WordIndex != 100 {
+		t.Errorf("Expected the laptop session at 100, got %+v, %v", session, ok)
+	}
+	if _, err := os.Stat(conflicted); !os.IsNotExist(err) {
+		t.Errorf("Expected the resolved conflict copy to be removed, got %v", err)
+	}
+}
+
+func TestStateStoreBackups(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	testHash := "abcdef1234567890abcdef1234567890"
+	day := time.Date(2026, 3, 1, 9, 0, 0, 0, time.Local)
+
+	store, err := NewStateStore()
+	if err != nil {
+		t.Fatalf("NewStateStore failed: %v", err)
+	}
+	if err := store.BackupIfDue(day); err != nil || len(store.Backups()) != 0 {
+		t.Fatalf("Expected no backup before anything is saved, got %v, %v", store.Backups(), err)
+	}
+
+	store.SetPosition(testHash, 100)
+	for i := range MaxBackups + 3 {
+		store.BackupIfDue(day.Add(time.Duration(i) * BackupInterval))
+		store.BackupIfDue(day.Add(time.Duration(i)*BackupInterval + time.Hour))
+	}
+	backups := store.Backups()
+	if len(backups) != MaxBackups {
+		t.Fatalf("Expected %d backups, got %d", MaxBackups, len(backups))
+	}
+	if newest := day.Add((MaxBackups + 2) * BackupInterval); !backups[0].Taken.Equal(newest) || backups[0].Books != 1 {
+		t.Errorf("Expected the newest backup first, taken %v with 1 book, got %+v", newest, backups[0])
+	}
+
+	store.SetPosition(testHash, 900)
+	if err := store.Restore(backups[0], day.Add(30*BackupInterval)); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if pos := store.GetPosition(testHash); pos != 100 {
+		t.Errorf("Expected restored position 100, got %d", pos)
+	}
+	backups = store.Backups()
+	var undo map[string]ReadingState
+	raw, _ := os.ReadFile(backups[0].Path)
+	if json.Unmarshal(raw, &undo); undo[testHash].WordIndex != 900 {
+		t.Errorf("Expected restoring to back up the replaced position 900, got %+v", undo[testHash])
+	}
+	reloaded, _ := NewStateStore()
+	if pos := reloaded.GetPosition(testHash); pos != 100 {
+		t.Errorf("Expected restored position 100 after reloading, got %d", pos)
+	}
+}
+
+func TestRecords(t *testing.T) {
+	day := time.Date(2026, 10, 1, 21, 0, 0, 0, time.Local)
+	var r Records
+	broken := r.Add(SessionSummary{Reading: 10 * time.Minute, Words: 3000, WPM: 300, Regressions: 5}, day)
+	if strings.Join(broken, ", ") != "longest session, fastest sustained speed" || r.Streak != 1 || r.LongestStreak.Value != 1 {
+		t.Errorf("first session broke %q with streak %d, want session and speed records", broken, r.Streak)
+	}
+	if broken := r.Add(SessionSummary{Reading: time.Minute, Words: 400, WPM: 600}, day); len(broken) != 0 {
+		t.Errorf("a short fast session should break nothing, broke %q", broken)
+	}
+	if broken := r.Add(SessionSummary{Reading: time.Minute, Words: 2000, WPM: 500, Regressions: 30}, day.AddDate(0, 0, 1)); strings.Join(broken, ", ") != "longest streak" {
+		t.Errorf("a session rereading often the next day broke %q, want only the streak", broken)
+	}
+	if r.Streak != 2 || r.CurrentStreak(day.AddDate(0, 0, 2)) != 2 || r.CurrentStreak(day.AddDate(0, 0, 3)) != 0 {
+		t.Errorf("streak %d, want 2 until it lapses", r.Streak)
+	}
+	r.Add(SessionSummary{Reading: time.Minute, Words: 10, WPM: 300}, day.AddDate(0, 0, 5))
+	if r.Streak != 1 || r.LongestStreak.Value != 2 {
+		t.Errorf("after a gap streak %d, longest %d; want 1, 2", r.Streak, r.LongestStreak.Value)
+	}
+	if broken := r.Add(SessionSummary{}, day.AddDate(0, 0, 6)); broken != nil || r.LastDay != day.AddDate(0, 0, 5).Format(time.DateOnly) {
+		t.Error("a session reading nothing should not count")
+	}
+}
+
+func TestStateStoreRecords(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	store, _ := NewStateStore()
+	if r, err := store.Records(); err != nil || r.LongestSession.Value != 0 {
+		t.Errorf("Records before any session = %+v, %v", r, err)
+	}
+	broken, err := store.AddSession(SessionSummary{Reading: time.Minute, Words: 200, WPM: 200}, time.Now())
+	if err != nil || len(broken) != 1 {
+		t.Fatalf("AddSession = %q, %v", broken, err)
+	}
+	store2, _ := NewStateStore()
+	if r, _ := store2.Records(); r.LongestSession.Value != 60 || r.Streak != 1 {
+		t.Errorf("saved records = %+v", r)
+	}
+}
+
+func TestStateStoreRecovery(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	store, _ := NewStateStore()
+	store.SetPosition("aaaa", 1)
+	store.SetPosition("bbbb", 2)
+	store.SetPosition("cccc", 3)
+	if store.Recovered() != "" {
+		t.Errorf("a fresh store should need no recovery, got %q", store.Recovered())
+	}
+	dir := filepath.Dir(store.path)
+	if matches, _ := filepath.Glob(filepath.Join(dir, ".*tmp*")); len(matches) > 0 {
+		t.Errorf("temporary files left behind: %v", matches)
+	}
+
+	// Cut the file off partway through the third entry; the newest backup
+	// holds only the first two, and an older one is not used.
+	data, _ := os.ReadFile(store.path)
+	damaged := data[:bytes.Index(data, []byte(`"cccc"`))+20]
+	os.WriteFile(store.path, damaged, 0644)
+	os.MkdirAll(store.backupDir(), 0755)
+	os.WriteFile(filepath.Join(store.backupDir(), backupPrefix+"20260301-090000.000.json"), []byte(`{"eeee": {"word_index": 5}}`), 0644)
+	os.WriteFile(filepath.Join(store.backupDir(), backupPrefix+"20260302-090000.000.json"), []byte(`{"aaaa": {"word_index": 9}, "dddd": {"word_index": 4}}`), 0644)
+
+	recovered, _ := NewStateStore()
+	for hash, want := range map[string]int{"aaaa": 1, "bbbb": 2, "dddd": 4, "eeee": 0} {
+		if got := recovered.GetPosition(hash); got != want {
+			t.Errorf("recovered position of %s = %d, want %d", hash, got, want)
+		}
+	}
+	if !strings.Contains(recovered.Recovered(), "recovered 3 books (2 from it, 1 from its last backup)") {
+		t.Errorf("Recovered() = %q", recovered.Recovered())
+	}
+	if kept, _ := os.ReadFile(store.path + damagedSuffix); !bytes.Equal(kept, damaged) {
+		t.Error("the damaged file should be kept")
+	}
+	if backups := recovered.Backups(); len(backups) != 2 || backups[0].Books != 2 {
+		t.Errorf("a damaged file should never be backed up, got %+v", backups)
+	}
+	recovered.SetPosition("bbbb", 5)
+	if again, _ := NewStateStore(); again.Recovered() != "" || again.GetPosition("bbbb") != 5 {
+		t.Error("once saved, the recovered state should load whole")
+	}
+}
+
+func TestStateStoreFinished(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	testHash := "abcdef1234567890abcdef1234567890"
+	first := time.Date(2026, 10, 1, 21, 0, 0, 0, time.UTC)
+
+	store, err := NewStateStore()
+	if err != nil {
+		t.Fatalf("NewStateStore failed: %v", err)
+	}
+	store.SetPosition(testHash, 10)
+	if st, _ := store.Get(testHash); !st.Finished.IsZero() {
+		t.Fatalf("a book just started should not be finished: %v", st.Finished)
+	}
+	store.SetFinished(testHash, first)
+	store.SetFinished(testHash, first.AddDate(0, 1, 0))
+
+	store2, _ := NewStateStore()
+	if st, _ := store2.Get(testHash); !st.Finished.Equal(first) {
+		t.Errorf("Finished = %v, want the first time, %v", st.Finished, first)
+	}
+}
+
+func TestStateStoreSaveProgress(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	testHash := "abcdef1234567890abcdef1234567890"
+	finished := time.Date(2026, 10, 1, 21, 0, 0, 0, time.UTC)
+
+	store, err := NewStateStore()
+	if err != nil {
+		t.Fatalf("NewStateStore failed: %v", err)
+	}
+	store.SaveProgress(testHash, Progress{
+		Settings:  Settings{WPM: 450},
+		WordIndex: 120,
+		Bookmark:  &Bookmark{Chapter: 2, Sentence: 5},
+		Finished:  finished,
+	})
+
+	store2, _ := NewStateStore()
+	st, _ := store2.Get(testHash)
+	if st.WordIndex != 120 || st.Bookmark == nil || *st.Bookmark != (Bookmark{Chapter: 2, Sentence: 5}) {
+		t.Errorf("the position and its bookmark should be saved, got %+v", st)
+	}
+
+	// Streamed reading saves no bookmark, and the old one no longer fits.
+	store2.SaveProgress(testHash, Progress{Settings: Settings{WPM: 500}, WordIndex: 80})
+	store3, _ := NewStateStore()
+	st, _ = store3.Get(testHash)
+	if st.WordIndex != 80 || st.Bookmark != nil {
+		t.Errorf("a position without a bookmark should clear the old one, got %+v", st)
+	}
+
+	store3.SaveProgress(testHash, Progress{Settings: Settings{WPM: 500}, WordIndex: 40, Session: "phone", Finished: finished.AddDate(0, 1, 0)})
+	store4, _ := NewStateStore()
+	st, _ = store4.Get(testHash)
+	if st.WordIndex != 80 || st.Device != DeviceName {
+		t.Errorf("the file's own position should be kept, got %+v", st)
+	}
+	if s, ok := st.Sessions["phone"]; !ok || s.WordIndex != 40 {
+		t.Errorf("a named session should be saved apart, got %+v", st.Sessions)
+	}
+	if !st.Finished.Equal(finished) {
+		t.Errorf("Finished = %v, want the first time, %v", st.Finished, finished)
+	}
+	if st.Settings != (Settings{WPM: 500}) {
+		t.Errorf("the latest settings should be saved, got %+v", st.Settings)
+	}
+}
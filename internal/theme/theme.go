@@ -0,0 +1,150 @@
+// Package theme provides the named color schemes shared by brr and grr.
+package theme
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// Theme is a named color scheme. Colors are "#RRGGBB" hex strings.
+// Background is only applied by the GUI; an empty Background keeps the
+// toolkit or terminal default.
+type Theme struct {
+	Name       string
+	Focus      string // ORP character
+	Word       string // word text
+	Status     string // status line
+	Controls   string // key hints
+	Accent     string // paused marker and panel titles
+	Complete   string // completion message
+	Border     string // panel borders
+	Background string
+	// Dark makes the GUI use the toolkit's dark variant for the colors
+	// the theme leaves out, whatever the system's setting.
+	Dark bool
+}
+
+// Themes lists the built-in color schemes in cycling order.
+var Themes = []Theme{
+	{
+		Name:     "default",
+		Focus:    "#FF0000",
+		Word:     "#FFFFFF",
+		Status:   "#888888",
+		Controls: "#666666",
+		Accent:   "#FFAA00",
+		Complete: "#00FF00",
+		Border:   "#666666",
+	},
+	{
+		Name:       "solarized",
+		Focus:      "#DC322F",
+		Word:       "#93A1A1",
+		Status:     "#586E75",
+		Controls:   "#586E75",
+		Accent:     "#B58900",
+		Complete:   "#859900",
+		Border:     "#073642",
+		Background: "#002B36",
+	},
+	{
+		Name:       "dracula",
+		Focus:      "#FF5555",
+		Word:       "#F8F8F2",
+		Status:     "#6272A4",
+		Controls:   "#6272A4",
+		Accent:     "#FFB86C",
+		Complete:   "#50FA7B",
+		Border:     "#44475A",
+		Background: "#282A36",
+	},
+	{
+		Name:       "high-contrast",
+		Focus:      "#FFFF00",
+		Word:       "#FFFFFF",
+		Status:     "#FFFFFF",
+		Controls:   "#FFFFFF",
+		Accent:     "#00FFFF",
+		Complete:   "#00FF00",
+		Border:     "#FFFFFF",
+		Background: "#000000",
+	},
+	{
+		Name:     "monochrome",
+		Focus:    "#FFFFFF",
+		Word:     "#AAAAAA",
+		Status:   "#888888",
+		Controls: "#666666",
+		Accent:   "#FFFFFF",
+		Complete: "#FFFFFF",
+		Border:   "#888888",
+	},
+	{
+		// night is warm and dim on black, for reading in the dark.
+		Name:       "night",
+		Focus:      "#C8641E",
+		Word:       "#B49673",
+		Status:     "#6E5A46",
+		Controls:   "#5A4A3A",
+		Accent:     "#A0782D",
+		Complete:   "#7D8C46",
+		Border:     "#3C3228",
+		Background: "#000000",
+		Dark:       true,
+	},
+}
+
+// Night returns the night reading theme.
+func Night() Theme {
+	t, _ := ByName("night")
+	return t
+}
+
+// Default returns the default theme.
+func Default() Theme {
+	return Themes[0]
+}
+
+// ByName returns the theme with the given name.
+func ByName(name string) (Theme, bool) {
+	for _, t := range Themes {
+		if strings.EqualFold(t.Name, name) {
+			return t, true
+		}
+	}
+	return Theme{}, false
+}
+
+// Next returns the theme after cur, wrapping around.
+func Next(cur Theme) Theme {
+	for i, t := range Themes {
+		if t.Name == cur.Name {
+			return Themes[(i+1)%len(Themes)]
+		}
+	}
+	return Themes[0]
+}
+
+// Names lists the names of the built-in themes.
+func Names() []string {
+	names := make([]string, len(Themes))
+	for i, t := range Themes {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// RGBA converts a "#RRGGBB" hex color to a color.RGBA.
+func RGBA(hex string) (color.RGBA, error) {
+	h := strings.TrimPrefix(hex, "#")
+	if len(h) != 6 {
+		return color.RGBA{}, fmt.Errorf("invalid color %q", hex)
+	}
+	v, err := strconv.ParseUint(h, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("invalid color %q", hex)
+	}
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 255}, nil
+}
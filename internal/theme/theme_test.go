@@ -0,0 +1,49 @@
+package theme
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestThemesValid(t *testing.T) {
+	for _, th := range Themes {
+		for _, c := range []string{th.Focus, th.Word, th.Status, th.Controls, th.Accent, th.Complete, th.Border} {
+			if _, err := RGBA(c); err != nil {
+				t.Errorf("theme %s: %v", th.Name, err)
+			}
+		}
+		if th.Background != "" {
+			if _, err := RGBA(th.Background); err != nil {
+				t.Errorf("theme %s background: %v", th.Name, err)
+			}
+		}
+	}
+}
+
+func TestByNameAndNext(t *testing.T) {
+	th, ok := ByName("Dracula")
+	if !ok || th.Name != "dracula" {
+		t.Fatalf("ByName(Dracula) = %v, %v", th.Name, ok)
+	}
+	if _, ok := ByName("nope"); ok {
+		t.Error("ByName should fail for unknown theme")
+	}
+
+	last := Themes[len(Themes)-1]
+	if Next(last).Name != Themes[0].Name {
+		t.Error("Next should wrap around")
+	}
+}
+
+func TestRGBA(t *testing.T) {
+	got, err := RGBA("#FF8000")
+	if err != nil {
+		t.Fatalf("RGBA: %v", err)
+	}
+	if got != (color.RGBA{R: 255, G: 128, B: 0, A: 255}) {
+		t.Errorf("RGBA(#FF8000) = %v", got)
+	}
+	if _, err := RGBA("red"); err == nil {
+		t.Error("expected error for invalid color")
+	}
+}
@@ -0,0 +1,151 @@
+// Package translate translates sentences for readers working through a
+// book in a language they are still learning.
+package translate
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Translator translates text from one language to another.
+type Translator interface {
+	Name() string
+	Translate(ctx context.Context, text string) (string, error)
+}
+
+// Client is the HTTP client used to reach translation servers.
+var Client = &http.Client{Timeout: 15 * time.Second}
+
+// New returns a translator for backend: the URL of a LibreTranslate server,
+// or the path of a dictionary file for word-by-word glosses (see
+// LoadDictionary). from and to are language codes such as "fr" and "en";
+// from may be "auto" for servers that detect the language. apiKey, if set,
+// is sent to servers that need one.
+func New(backend, from, to, apiKey string) (Translator, error) {
+	lower := strings.ToLower(backend)
+	if strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") {
+		return &LibreTranslate{URL: strings.TrimSuffix(backend, "/"), From: from, To: to, APIKey: apiKey}, nil
+	}
+	d, err := LoadDictionary(backend)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// LibreTranslate translates through a LibreTranslate server's /translate
+// endpoint.
+type LibreTranslate struct {
+	URL      string
+	From, To string
+	APIKey   string
+}
+
+func (l *LibreTranslate) Name() string { return "LibreTranslate" }
+
+func (l *LibreTranslate) Translate(ctx context.Context, text string) (string, error) {
+	body, err := json.Marshal(map[string]string{
+		"q":       text,
+		"source":  l.From,
+		"target":  l.To,
+		"format":  "text",
+		"api_key": l.APIKey,
+	})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.URL+"/translate", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	var result struct {
+		TranslatedText string `json:"translatedText"`
+		Error          string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil && resp.StatusCode == http.StatusOK {
+		return "", fmt.Errorf("unreadable reply from %s: %w", l.URL, err)
+	}
+	switch {
+	case result.Error != "":
+		return "", errors.New(result.Error)
+	case resp.StatusCode != http.StatusOK:
+		return "", fmt.Errorf("%s: %s", l.URL, resp.Status)
+	}
+	return result.TranslatedText, nil
+}
+
+// Dictionary glosses text word by word from a word list, for reading
+// without a server.
+type Dictionary struct {
+	words map[string]string
+}
+
+// LoadDictionary reads a dictionary file: one entry a line, the word and
+// its translation separated by a tab or " = ", as exported by most
+// flashcard and dictionary apps. Blank lines and lines starting with #
+// are skipped, and words are matched ignoring case.
+func LoadDictionary(path string) (*Dictionary, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	d := &Dictionary{words: make(map[string]string)}
+	sc := bufio.NewScanner(f)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		word, translation, ok := strings.Cut(text, "\t")
+		if !ok {
+			word, translation, ok = strings.Cut(text, " = ")
+		}
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: want a word, a tab or \" = \", and its translation", path, line)
+		}
+		d.words[strings.ToLower(strings.TrimSpace(word))] = strings.TrimSpace(translation)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *Dictionary) Name() string { return "dictionary" }
+
+// Translate replaces each word of text found in the dictionary with its
+// translation, keeping the punctuation around it. Words it lacks are kept
+// as they are.
+func (d *Dictionary) Translate(ctx context.Context, text string) (string, error) {
+	fields := strings.Fields(text)
+	for i, field := range fields {
+		lead := len(field) - len(strings.TrimLeftFunc(field, isPunct))
+		word := strings.TrimRightFunc(field[lead:], isPunct)
+		if t, ok := d.words[strings.ToLower(word)]; ok && word != "" {
+			fields[i] = field[:lead] + t + field[lead+len(word):]
+		}
+	}
+	return strings.Join(fields, " "), nil
+}
+
+func isPunct(r rune) bool {
+	return unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
@@ -0,0 +1,58 @@
+package translate
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLibreTranslate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]string
+		json.NewDecoder(r.Body).Decode(&req)
+		if r.URL.Path != "/translate" || req["source"] != "fr" || req["target"] != "en" || req["api_key"] != "key" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "bad request"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"translatedText": "The black cat."})
+	}))
+	defer srv.Close()
+
+	tr, err := New(srv.URL+"/", "fr", "en", "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := tr.Translate(context.Background(), "Le chat noir.")
+	if err != nil || got != "The black cat." {
+		t.Errorf("Translate = %q, %v", got, err)
+	}
+
+	tr, _ = New(srv.URL, "fr", "de", "key")
+	if _, err := tr.Translate(context.Background(), "Le chat noir."); err == nil || err.Error() != "bad request" {
+		t.Errorf("the server's error should be returned, got %v", err)
+	}
+}
+
+func TestDictionary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fr-en.tsv")
+	os.WriteFile(path, []byte("# French to English\nchat\tcat\n\nnoir = black\nl'homme\tthe man\n"), 0o644)
+
+	tr, err := New(path, "fr", "en", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := tr.Translate(context.Background(), "« Le Chat noir, » dit l'homme.")
+	if want := "« Le cat black, » dit the man."; got != want {
+		t.Errorf("Translate = %q, want %q", got, want)
+	}
+
+	os.WriteFile(path, []byte("chat cat\n"), 0o644)
+	if _, err := LoadDictionary(path); err == nil {
+		t.Error("a line without a separator should fail")
+	}
+}
@@ -0,0 +1,98 @@
+// Package tts speaks text through the platform's speech synthesizer so
+// audio can accompany the RSVP display.
+package tts
+
+import (
+	"fmt"
+	"os/exec"
+	"sync"
+)
+
+// Speaker speaks text aloud. Speak interrupts any speech in progress and
+// returns without waiting for the new speech to finish.
+type Speaker interface {
+	Name() string
+	Speak(text string, wpm int) error
+	Stop() error
+}
+
+// commandSpeaker speaks by running an external command per utterance.
+type commandSpeaker struct {
+	name  string
+	build func(text string, wpm int) *exec.Cmd
+
+	mu   sync.Mutex
+	cmd  *exec.Cmd
+	done chan struct{} // closed when cmd exits
+}
+
+func (s *commandSpeaker) Name() string { return s.name }
+
+func (s *commandSpeaker) Speak(text string, wpm int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopLocked()
+
+	cmd := s.build(text, wpm)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("%s: %w", s.name, err)
+	}
+	done := make(chan struct{})
+	s.cmd, s.done = cmd, done
+	go func() {
+		cmd.Wait()
+		close(done)
+	}()
+	return nil
+}
+
+func (s *commandSpeaker) Stop() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stopLocked()
+}
+
+func (s *commandSpeaker) stopLocked() error {
+	if s.cmd == nil || s.cmd.Process == nil {
+		return nil
+	}
+	err := s.cmd.Process.Kill()
+	s.cmd = nil
+	return err
+}
+
+// backends maps backend names to constructors. Platform files register the
+// synthesizers available on their OS.
+var backends = map[string]func() *commandSpeaker{}
+
+// preferred lists backend names to try, in order, for "auto".
+var preferred []string
+
+// New returns a speaker for the named backend, or the first available one
+// when backend is "auto".
+func New(backend string) (Speaker, error) {
+	if backend == "auto" {
+		for _, name := range preferred {
+			s := backends[name]()
+			if _, err := exec.LookPath(s.build("", 0).Path); err == nil {
+				return s, nil
+			}
+		}
+		return nil, fmt.Errorf("no speech synthesizer found (tried %v)", preferred)
+	}
+
+	ctor, ok := backends[backend]
+	if !ok {
+		return nil, fmt.Errorf("unknown tts backend %q (choose from %v)", backend, Backends())
+	}
+	s := ctor()
+	if _, err := exec.LookPath(s.build("", 0).Path); err != nil {
+		return nil, fmt.Errorf("%s not available: %w", backend, err)
+	}
+	return s, nil
+}
+
+// Backends lists the backend names supported on this platform.
+func Backends() []string {
+	return append([]string{"auto"}, preferred...)
+}
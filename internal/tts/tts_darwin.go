@@ -0,0 +1,23 @@
+//go:build darwin
+
+package tts
+
+import (
+	"os/exec"
+	"strconv"
+)
+
+func init() {
+	backends["say"] = func() *commandSpeaker {
+		return &commandSpeaker{name: "say", build: sayCommand}
+	}
+	preferred = []string{"say"}
+}
+
+// sayCommand speaks at the reading speed; say's -r is words per minute.
+func sayCommand(text string, wpm int) *exec.Cmd {
+	if wpm <= 0 {
+		wpm = 200
+	}
+	return exec.Command("say", "-r", strconv.Itoa(wpm), "--", text)
+}
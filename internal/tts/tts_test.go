@@ -0,0 +1,52 @@
+package tts
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestCommandSpeakerInterrupts(t *testing.T) {
+	if _, err := exec.LookPath("sleep"); err != nil {
+		t.Skip("sleep not available")
+	}
+
+	var started []string
+	s := &commandSpeaker{
+		name: "fake",
+		build: func(text string, wpm int) *exec.Cmd {
+			started = append(started, text)
+			return exec.Command("sleep", "5")
+		},
+	}
+
+	if err := s.Speak("first sentence", 300); err != nil {
+		t.Fatalf("Speak: %v", err)
+	}
+	first := s.done
+	if err := s.Speak("second sentence", 300); err != nil {
+		t.Fatalf("Speak: %v", err)
+	}
+
+	select {
+	case <-first:
+	case <-time.After(2 * time.Second):
+		t.Error("first utterance should be interrupted")
+	}
+
+	if err := s.Stop(); err != nil {
+		t.Errorf("Stop: %v", err)
+	}
+	if s.cmd != nil {
+		t.Error("Stop should clear the running command")
+	}
+	if len(started) != 2 || started[1] != "second sentence" {
+		t.Errorf("unexpected utterances %v", started)
+	}
+}
+
+func TestNewUnknownBackend(t *testing.T) {
+	if _, err := New("does-not-exist"); err == nil {
+		t.Error("expected error for unknown backend")
+	}
+}
@@ -0,0 +1,28 @@
+//go:build !darwin && !windows
+
+package tts
+
+import (
+	"os/exec"
+	"strconv"
+)
+
+func init() {
+	backends["espeak-ng"] = func() *commandSpeaker {
+		return &commandSpeaker{name: "espeak-ng", build: espeakCommand("espeak-ng")}
+	}
+	backends["espeak"] = func() *commandSpeaker {
+		return &commandSpeaker{name: "espeak", build: espeakCommand("espeak")}
+	}
+	preferred = []string{"espeak-ng", "espeak"}
+}
+
+// espeakCommand speaks at the reading speed; espeak's -s is words per minute.
+func espeakCommand(bin string) func(string, int) *exec.Cmd {
+	return func(text string, wpm int) *exec.Cmd {
+		if wpm < 80 {
+			wpm = 80
+		}
+		return exec.Command(bin, "-s", strconv.Itoa(wpm), "--", text)
+	}
+}
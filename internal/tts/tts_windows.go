@@ -0,0 +1,31 @@
+//go:build windows
+
+package tts
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+func init() {
+	backends["sapi"] = func() *commandSpeaker {
+		return &commandSpeaker{name: "sapi", build: sapiCommand}
+	}
+	preferred = []string{"sapi"}
+}
+
+// sapiCommand speaks through System.Speech via PowerShell. SAPI rates run
+// from -10 to 10 with 0 at roughly 180 WPM.
+func sapiCommand(text string, wpm int) *exec.Cmd {
+	rate := (wpm - 180) / 30
+	if rate < -10 {
+		rate = -10
+	}
+	if rate > 10 {
+		rate = 10
+	}
+	quoted := strings.ReplaceAll(text, "'", "''")
+	script := fmt.Sprintf("Add-Type -AssemblyName System.Speech; $s = New-Object System.Speech.Synthesis.SpeechSynthesizer; $s.Rate = %d; $s.Speak('%s')", rate, quoted)
+	return exec.Command("powershell", "-NoProfile", "-Command", script)
+}
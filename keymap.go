@@ -0,0 +1,55 @@
+package main
+
+// keymap maps the keys of an alternative control scheme to the default
+// keys whose actions they take. Keys it leaves out keep their usual
+// actions.
+type keymap map[string]string
+
+// keymaps are the built-in control schemes selected with --keys.
+var keymaps = map[string]keymap{
+	"default": nil,
+	// one-handed puts every control within reach of the left hand, for
+	// reading with a coffee in the other: speed and sentences on WASD,
+	// with the actions of keys on the right moved next to them. Presets
+	// stay on the number keys and +, - and Enter work on the numpad.
+	"one-handed": {
+		"w":   "up",
+		"s":   "down",
+		"a":   "left",
+		"d":   "right",
+		"e":   "a",
+		"f":   "m",
+		"x":   "s",
+		"g":   "/",
+		"G":   "g",
+		"F":   "i",
+		"E":   "o",
+		"D":   "l",
+		"X":   "h",
+		"v":   "n",
+		"V":   "N",
+		"z":   "u",
+		"Z":   "ctrl+r",
+		"C":   "y",
+		"tab": "enter",
+		"`":   "]",
+		"~":   "}",
+	},
+}
+
+// keymapNames lists the built-in keymaps, default first.
+var keymapNames = []string{"default", "one-handed"}
+
+// parseKeymap returns the built-in keymap called name.
+func parseKeymap(name string) (keymap, bool) {
+	k, ok := keymaps[name]
+	return k, ok
+}
+
+// translate returns the default key whose action key takes.
+func (k keymap) translate(key string) string {
+	if to, ok := k[key]; ok {
+		return to
+	}
+	return key
+}
@@ -0,0 +1,92 @@
+//go:build !gui
+
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/metcalfc/brr/internal/reader"
+	"github.com/metcalfc/brr/internal/state"
+)
+
+func TestOneHandedKeys(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	m := newModel("One two. Three four. Five six.", 300, nil, nil)
+	m.keys, _ = parseKeymap("one-handed")
+	key := func(s string) {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+		if s == "tab" {
+			msg = tea.KeyMsg{Type: tea.KeyTab}
+		}
+		updated, _ := m.Update(msg)
+		m = updated.(model)
+	}
+
+	key("w")
+	key("w")
+	key("s")
+	if m.WPM != 350 {
+		t.Errorf("after w, w, s WPM = %d, want 350", m.WPM)
+	}
+	key("d")
+	key("d")
+	key("a")
+	if m.CurrentIndex != 2 {
+		t.Errorf("after d, d, a at word %d, want 2", m.CurrentIndex)
+	}
+	key("z")
+	if m.CurrentIndex != 4 {
+		t.Errorf("after z at word %d, want 4", m.CurrentIndex)
+	}
+	key("Z")
+	if m.CurrentIndex != 2 {
+		t.Errorf("after Z at word %d, want 2", m.CurrentIndex)
+	}
+	key("x")
+	if m.StopwordMode != reader.StopwordsHalf {
+		t.Errorf("after x function words %s, want half", m.StopwordMode)
+	}
+	key("t")
+	key("b")
+	if m.renderer.Name() != "bionic" {
+		t.Errorf("b should still change the display, got %s", m.renderer.Name())
+	}
+
+	// The keys on the right have left-hand stand-ins.
+	key("G")
+	if !m.goingTo {
+		t.Error("G should ask for a position to go to")
+	}
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(model)
+	store, _ := state.NewStateStore()
+	m.stateStore, m.fileHash = store, "abcdef1234567890abcdef1234567890"
+	key("F")
+	if !m.annotating {
+		t.Error("F should ask for a note at the word")
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(model)
+	key("D")
+	if m.notice != noAnnotationsNotice {
+		t.Errorf("D should list the notes at positions, notice %q", m.notice)
+	}
+	m.Paused = true
+	key("X")
+	if m.handoff == nil {
+		t.Error("X should show the hand-off code")
+	}
+	key("E")
+	if m.handoff != nil || m.notesOpen {
+		t.Error("a key should first close the hand-off code")
+	}
+	key("E")
+	if !m.notesOpen {
+		t.Error("E should open the book's notes")
+	}
+
+	if _, ok := parseKeymap("dvorak"); ok {
+		t.Error("parseKeymap accepted an unknown scheme")
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"flag"
+	"path/filepath"
+	"strings"
+
+	"github.com/metcalfc/brr/internal/reader"
+	"github.com/metcalfc/brr/internal/state"
+)
+
+// recordBook stores library metadata for a file so it shows up in the
+// reading history.
+func recordBook(store *state.StateStore, hash, sourceFile string, book reader.Metadata, totalWords int) {
+	abs, err := filepath.Abs(sourceFile)
+	if err != nil {
+		abs = sourceFile
+	}
+	store.SetInfo(hash, abs, book.Title, book.Author, totalWords)
+}
+
+// recordLoaded records a document read early once all of it has loaded,
+// with its full length.
+func recordLoaded(store *state.StateStore, hash, sourceFile string, book reader.Metadata, r *reader.Reader) {
+	if store == nil || hash == "" {
+		return
+	}
+	_, total := r.Progress()
+	recordBook(store, hash, sourceFile, book, total)
+}
+
+// bookInfo returns the title and author a file records, such as an EPUB's
+// dc:title and dc:creator, or a title derived from its filename.
+func bookInfo(sourceFile string) reader.Metadata {
+	if f, ok := reader.FormatFor(sourceFile); ok {
+		if p, ok := f.(reader.MetadataProvider); ok {
+			if meta, err := p.Metadata(sourceFile); err == nil && meta.Title != "" {
+				return meta
+			}
+		}
+	}
+	return reader.Metadata{Title: bookTitle(sourceFile)}
+}
+
+// verticalDisplay switches to the vertical display for a book typeset in
+// vertical lines, such as a Japanese novel, unless --display was given.
+// A display mode saved for the book still takes its place.
+func verticalDisplay(book reader.Metadata, renderer *reader.WordRenderer) {
+	if r, ok := reader.RendererByName("vertical"); ok && book.Vertical && !flagPassed("display") {
+		*renderer = r
+	}
+}
+
+// bookTitle derives a display title from a filename.
+func bookTitle(sourceFile string) string {
+	base := filepath.Base(sourceFile)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// restoreSettings applies the WPM, display mode, pacing and function word
+// mode last used for a book, unless they were given explicitly on the
+// command line.
+func restoreSettings(saved state.Settings, r *reader.Reader, renderer *reader.WordRenderer) {
+	if saved.WPM > 0 && !flagPassed("w") {
+		r.WPM = saved.WPM
+	}
+	if d, ok := reader.RendererByName(saved.Display); ok && !flagPassed("display") {
+		*renderer = d
+	}
+	if p, ok := reader.ParsePacer(saved.Pacing); ok && !flagPassed("pacing") {
+		r.Pacer = p
+	}
+	if mode, ok := reader.ParseStopwordMode(saved.Stopwords); ok && !flagPassed("stopwords") {
+		r.StopwordMode = mode
+	}
+}
+
+// currentSettings captures the reader settings to remember for a book.
+func currentSettings(r *reader.Reader, renderer reader.WordRenderer) state.Settings {
+	return state.Settings{WPM: r.WPM, Display: renderer.Name(), Pacing: reader.PacerName(r.Pacer), Stopwords: r.StopwordMode.String()}
+}
+
+// flagPassed reports whether the named command-line flag was set.
+func flagPassed(name string) bool {
+	passed := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			passed = true
+		}
+	})
+	return passed
+}
@@ -0,0 +1,42 @@
+//go:build !gui
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+func TestBookInfo(t *testing.T) {
+	book := bookInfo("SherlockHolmes.epub")
+	if book.Title != "Adventures of Sherlock Holmes / Illustrated" || book.Author != "Arthur Conan Doyle" {
+		t.Errorf("bookInfo(epub) = %+v, want its dc:title and dc:creator", book)
+	}
+	if got := bookInfo("sample.txt"); got != (reader.Metadata{Title: "sample"}) {
+		t.Errorf("bookInfo(txt) = %+v, want the filename as title", got)
+	}
+
+	m := newModel("one two three", 300, nil, nil)
+	m.book = book
+	m.width, m.height = 120, 10
+	if view := m.View(); !strings.Contains(view, "Adventures of Sherlock Holmes / Illustrated by Arthur Conan Doyle | Word 1/3") {
+		t.Errorf("status bar should name the book, got:\n%s", view)
+	}
+}
+
+// Benchmark tests
+func TestBookSettings(t *testing.T) {
+	r := reader.NewReader("Call me Ishmael.", 550)
+	r.Pacer = reader.SyllablePacer{}
+	r.StopwordMode = reader.StopwordsHalf
+	saved := currentSettings(r, reader.BionicRenderer{Fraction: 0.4})
+
+	restored := reader.NewReader("Call me Ishmael.", 300)
+	var renderer reader.WordRenderer = reader.ORPRenderer{}
+	restoreSettings(saved, restored, &renderer)
+	if restored.WPM != 550 || renderer.Name() != "bionic" || restored.Pacer != (reader.SyllablePacer{}) || restored.StopwordMode != reader.StopwordsHalf {
+		t.Errorf("settings should be restored from %+v, got %d WPM, %s, %T, %v", saved, restored.WPM, renderer.Name(), restored.Pacer, restored.StopwordMode)
+	}
+}
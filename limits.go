@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+// Default limits on what is loaded into memory at once. Plain text beyond
+// them can still be streamed.
+const (
+	defaultMaxWords = 2_000_000
+	defaultMaxSize  = "512MB"
+)
+
+// parseSize parses a byte count such as "512MB", "2G" or "1048576". Units
+// are powers of 1024; "0" means no limit.
+func parseSize(s string) (int64, error) {
+	t := strings.ToUpper(strings.TrimSpace(s))
+	t = strings.TrimSuffix(t, "B")
+	shift := 0
+	if n := len(t); n > 0 {
+		switch t[n-1] {
+		case 'K':
+			shift = 10
+		case 'M':
+			shift = 20
+		case 'G':
+			shift = 30
+		}
+		if shift > 0 {
+			t = strings.TrimSpace(t[:n-1])
+		}
+	}
+	n, err := strconv.ParseInt(t, 10, 64)
+	if err != nil || n < 0 || n > math.MaxInt64>>shift {
+		return 0, fmt.Errorf("invalid size %q (use a number of bytes or KB, MB, GB)", s)
+	}
+	return n << shift, nil
+}
+
+// formatSize describes a byte count in the units parseSize accepts.
+func formatSize(n int64) string {
+	switch {
+	case n >= 1<<30:
+		return strconv.FormatFloat(float64(n)/(1<<30), 'f', 1, 64) + "GB"
+	case n >= 1<<20:
+		return strconv.FormatInt(n>>20, 10) + "MB"
+	case n >= 1<<10:
+		return strconv.FormatInt(n>>10, 10) + "KB"
+	}
+	return strconv.FormatInt(n, 10) + " bytes"
+}
+
+// formatCount abbreviates a word count: 950, 12K, 4.2M.
+func formatCount(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return strings.TrimSuffix(strconv.FormatFloat(float64(n)/1e6, 'f', 1, 64), ".0") + "M"
+	case n >= 10_000:
+		return strconv.Itoa(n/1000) + "K"
+	}
+	return strconv.Itoa(n)
+}
+
+// readingTime roughly describes how long words take at wpm.
+func readingTime(words, wpm int) string {
+	d := time.Duration(words) * time.Minute / time.Duration(max(wpm, 1))
+	switch {
+	case d >= 48*time.Hour:
+		return fmt.Sprintf("%d days", int(d.Hours()/24+0.5))
+	case d >= 2*time.Hour:
+		return fmt.Sprintf("%d hours", int(d.Hours()+0.5))
+	case d >= 2*time.Minute:
+		return fmt.Sprintf("%d minutes", int(d.Minutes()+0.5))
+	}
+	return "a minute"
+}
+
+// checkSize refuses a local file larger than limit bytes, which would be
+// read whole into memory. A limit of 0 allows any size.
+func checkSize(path string, limit int64) error {
+	if limit <= 0 {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() || info.Size() <= limit {
+		return nil
+	}
+	return fmt.Errorf("%s is larger than the %s limit; raise it with --max-size", formatSize(info.Size()), formatSize(limit))
+}
+
+// readLimited reads all of r if it holds at most limit bytes. Otherwise it
+// returns nil data and a reader of everything r holds, read so far or not,
+// for streaming instead. A limit of 0 allows any size.
+func readLimited(r io.Reader, limit int64) ([]byte, io.Reader, error) {
+	if limit <= 0 {
+		data, err := io.ReadAll(r)
+		return data, nil, err
+	}
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil || int64(len(data)) <= limit {
+		return data, nil, err
+	}
+	return nil, io.MultiReader(bytes.NewReader(data), r), nil
+}
+
+// hugeInput is a loaded document with more words than the --max-words
+// limit, until the reader chooses to read all of it, only the start, or
+// nothing.
+type hugeInput struct {
+	words, limit int
+}
+
+// findHugeInput returns the hugeInput for r, or nil if r is within limit,
+// limit is 0 or r is streamed, holding only part of its words at a time.
+func findHugeInput(r *reader.Reader, limit int) *hugeInput {
+	if limit <= 0 || r.Streaming() || len(r.Words) <= limit {
+		return nil
+	}
+	return &hugeInput{words: len(r.Words), limit: limit}
+}
+
+// prompt asks whether to read the whole document, giving its length and
+// how long it takes at wpm.
+func (h *hugeInput) prompt(wpm int) string {
+	return fmt.Sprintf("This file has %s words, ~%s at %d WPM. C) continue, F) read the first %s or Q) cancel?",
+		formatCount(h.words), readingTime(h.words, wpm), wpm, formatCount(h.limit))
+}
+
+// resolve acts on key, C or F, returning a notice describing what will be
+// read. It reports false for any other key; Q quits as it always does.
+func (h *hugeInput) resolve(key string, r *reader.Reader) (string, bool) {
+	switch key {
+	case "c", "C":
+		return fmt.Sprintf("Reading all %s words", formatCount(h.words)), true
+	case "f", "F":
+		r.Truncate(h.limit)
+		return fmt.Sprintf("Reading the first %s words", formatCount(h.limit)), true
+	}
+	return "", false
+}
@@ -0,0 +1,83 @@
+//go:build !gui
+
+package main
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+		ok   bool
+	}{
+		{"0", 0, true},
+		{"1048576", 1 << 20, true},
+		{"512MB", 512 << 20, true},
+		{"2g", 2 << 30, true},
+		{" 64 KB ", 64 << 10, true},
+		{"", 0, false},
+		{"-1", 0, false},
+		{"lots", 0, false},
+		{"99999999999G", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := parseSize(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("parseSize(%q) = %d, %v; want %d, ok %v", tt.in, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestReadLimited(t *testing.T) {
+	data, rest, err := readLimited(strings.NewReader("short text"), 10)
+	if err != nil || rest != nil || string(data) != "short text" {
+		t.Errorf("within the limit: %q, %v, %v", data, rest, err)
+	}
+	data, rest, err = readLimited(strings.NewReader("a little longer"), 10)
+	if err != nil || data != nil || rest == nil {
+		t.Fatalf("over the limit: %q, %v, %v", data, rest, err)
+	}
+	if all, _ := io.ReadAll(rest); string(all) != "a little longer" {
+		t.Errorf("streamed %q, want all of the input", all)
+	}
+}
+
+func TestHugeInputPrompt(t *testing.T) {
+	chapters := []reader.Chapter{{Title: "One", WordStart: 0, WordEnd: 2}, {Title: "Two", WordStart: 3, WordEnd: 5}}
+	toc := []reader.TOCEntry{{Title: "One", WordIndex: 0}, {Title: "Two", WordIndex: 3}}
+	m := newModel("one two three four five six", 300, toc, chapters)
+	if findHugeInput(m.Reader, 6) != nil || findHugeInput(m.Reader, 0) != nil {
+		t.Error("documents within the limit, or with no limit, should not ask")
+	}
+	m.huge = findHugeInput(m.Reader, 2)
+	if m.huge == nil {
+		t.Fatal("expected to ask about a document over the limit")
+	}
+	if want := "This file has 6 words, ~a minute at 300 WPM. C) continue, F) read the first 2 or Q) cancel?"; m.huge.prompt(m.WPM) != want {
+		t.Errorf("prompt = %q, want %q", m.huge.prompt(m.WPM), want)
+	}
+	if got := (&hugeInput{words: 4_200_000, limit: 1_000_000}).prompt(400); got != "This file has 4.2M words, ~7 days at 400 WPM. C) continue, F) read the first 1M or Q) cancel?" {
+		t.Errorf("prompt = %q", got)
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	m = updated.(model)
+	if m.huge == nil {
+		t.Error("keys other than the answers should be ignored while the prompt is shown")
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+	m = updated.(model)
+	if m.huge != nil || len(m.Words) != 2 || len(m.TOC) != 1 || len(m.tocList.Items()) != 1 {
+		t.Errorf("after F: %d words, %d TOC entries, %d listed; want 2, 1, 1", len(m.Words), len(m.TOC), len(m.tocList.Items()))
+	}
+	if m.notice != "Reading the first 2 words" {
+		t.Errorf("notice = %q", m.notice)
+	}
+}
@@ -0,0 +1,849 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+// loadOptions controls how sources are loaded.
+type loadOptions struct {
+	// archive is a URL template used to retry truncated web articles
+	// through an archive or proxy endpoint. Empty disables the fallback.
+	archive string
+	// cookies is a Netscape-format cookie file sent with web requests.
+	cookies string
+	// client, once set by withClient, makes the web requests of one load.
+	client *http.Client
+	// stream reads any plain-text file lazily, not just large ones.
+	stream bool
+	// follow keeps reading as text is appended instead of ending at the
+	// end of input.
+	follow bool
+	// figures marks described images in EPUBs with "[Figure: ...]".
+	figures bool
+	// keepEmpty keeps EPUB sections with no text in the TOC and chapters.
+	keepEmpty bool
+	// notes sets what becomes of EPUB footnotes and endnotes.
+	notes reader.NoteMode
+	// ruby sets what becomes of EPUB ruby annotations.
+	ruby reader.RubyMode
+	// skipMatter leaves out EPUB front and back matter.
+	skipMatter bool
+	// maxSize refuses local files and GitHub documents larger than this
+	// many bytes, which would be read whole into memory; 0 allows any size.
+	maxSize int64
+	// strict fails on unreadable document sections instead of skipping them.
+	strict bool
+	// warnings collects the sections that were skipped.
+	warnings *reader.Warnings
+	// transcript, if set, cleans fillers and timestamps from the text.
+	// Transcripts are always loaded whole.
+	transcript *reader.Transcript
+	// progress receives loading events; nil prints them to stderr.
+	progress func(loadEvent)
+	// zotero, if set, records the Zotero item opened by the zotero
+	// subcommand so progress can be noted on it afterwards.
+	zotero *zoteroSession
+}
+
+// report passes on a loading event. Without a loading screen to show
+// them, events other than text being ready are printed to stderr.
+func (o loadOptions) report(e loadEvent) {
+	if o.progress != nil {
+		o.progress(e)
+	} else if e.stage != loadReady {
+		fmt.Fprintln(os.Stderr, e)
+	}
+}
+
+// reportReady reports a whole document loaded. Its words are counted from
+// its chapters, which were parsed as it loaded; one without chapters opens
+// at once, so it isn't parsed again just to count them.
+func (o loadOptions) reportReady(chapters []reader.Chapter) {
+	if n := len(chapters); n > 0 {
+		o.report(loadEvent{stage: loadReady, words: chapters[n-1].WordEnd + 1})
+	}
+}
+
+// logf reports a message about loading for the user.
+func (o loadOptions) logf(format string, args ...any) {
+	o.report(loadEvent{text: strings.TrimSpace(fmt.Sprintf(format, args...))})
+}
+
+// withClient returns the options with a new HTTP client for a load, which
+// sends the cookies file's cookies. Each load reads the file afresh, so the
+// cookies a site sets during one load aren't sent by the next.
+func (o loadOptions) withClient() (loadOptions, error) {
+	if o.cookies == "" || o.client != nil {
+		return o, nil
+	}
+	jar, err := reader.LoadCookieFile(o.cookies)
+	if err != nil {
+		return o, fmt.Errorf("failed to load cookies: %w", err)
+	}
+	o.client = &http.Client{Timeout: reader.URLClient.Timeout, Jar: jar}
+	return o, nil
+}
+
+// fetchContext returns ctx with web requests made by the options' client.
+func (o loadOptions) fetchContext(ctx context.Context) context.Context {
+	if o.client == nil {
+		return ctx
+	}
+	return reader.WithClient(ctx, o.client)
+}
+
+// Environment variables holding defaults for command-line options.
+const (
+	archiveEnv         = "BRR_ARCHIVE"
+	cookiesEnv         = "BRR_COOKIES"
+	themeEnv           = "BRR_THEME"
+	nightEnv           = "BRR_NIGHT"
+	captureEnv         = "BRR_CAPTURE"
+	captureTemplateEnv = "BRR_CAPTURE_TEMPLATE"
+	zoteroEnv          = "BRR_ZOTERO"
+	presetsEnv         = "BRR_PRESETS"
+	keysEnv            = "BRR_KEYS"
+	handoffURLEnv      = "BRR_HANDOFF_URL"
+	translateEnv       = "BRR_TRANSLATE"
+)
+
+// envOr returns the value of the environment variable key, or def if unset.
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+// streamThreshold is the plain-text file size above which words are read
+// lazily instead of loading the whole file.
+const streamThreshold = 16 << 20
+
+// lazyEPUBThreshold is the size of an EPUB's text, uncompressed, above
+// which its chapters are parsed as they are reached instead of up front.
+const lazyEPUBThreshold = 4 << 20
+
+// openStream returns a streaming reader for a large plain-text file or a
+// followed file, a reader parsing a large EPUB a chapter at a time, or nil
+// if the file should be loaded whole.
+func openStream(sourceFile string, wpm int, opts loadOptions) (*reader.Reader, error) {
+	if opts.follow {
+		return openFollow(sourceFile, wpm)
+	}
+	if opts.transcript != nil || reader.IsURL(sourceFile) {
+		return nil, nil
+	}
+	if strings.HasSuffix(strings.ToLower(sourceFile), ".epub") {
+		return openLazyEPUB(sourceFile, wpm, opts)
+	}
+	if _, ok := reader.FormatFor(sourceFile); ok {
+		return nil, nil
+	}
+	info, err := os.Stat(sourceFile)
+	if err != nil || !info.Mode().IsRegular() {
+		return nil, nil
+	}
+	if !opts.stream && info.Size() <= streamThreshold {
+		return nil, nil
+	}
+	f, err := os.Open(sourceFile)
+	if err != nil {
+		return nil, err
+	}
+	return reader.NewStreamReader(f, info.Size(), wpm), nil
+}
+
+// openLazyEPUB returns a reader parsing a large EPUB, or any with
+// --stream, a chapter at a time, or nil if it should be loaded whole.
+func openLazyEPUB(sourceFile string, wpm int, opts loadOptions) (*reader.Reader, error) {
+	chapters, err := opts.epubFormat().OpenChapters(sourceFile)
+	if err != nil {
+		// Loading it whole reports the error.
+		return nil, nil
+	}
+	var size int64
+	for i := range chapters.Len() {
+		size += chapters.Size(i)
+	}
+	if !opts.stream && size <= lazyEPUBThreshold {
+		chapters.Close()
+		return nil, nil
+	}
+	return reader.NewChapterReader(chapters, wpm), nil
+}
+
+// skipWatch reports the sections skipped as a document read a chapter at a
+// time is parsed, after the warnings shown when reading starts.
+type skipWatch struct {
+	warnings *reader.Warnings
+	shown    int
+}
+
+// newSkipWatch watches warnings for any added from now on.
+func newSkipWatch(warnings *reader.Warnings) *skipWatch {
+	return &skipWatch{warnings: warnings, shown: len(*warnings)}
+}
+
+// notice returns a warning notice if sections were skipped since the last
+// one, or "".
+func (s *skipWatch) notice() string {
+	if s == nil || len(*s.warnings) == s.shown {
+		return ""
+	}
+	s.shown = len(*s.warnings)
+	return "Warning: " + s.warnings.Summary()
+}
+
+// openFollow returns a reader that waits for text appended to sourceFile.
+// Named pipes are polled so the display never blocks on them.
+func openFollow(sourceFile string, wpm int) (*reader.Reader, error) {
+	if reader.IsURL(sourceFile) {
+		return nil, errors.New("cannot follow a URL")
+	}
+	f, err := os.Open(sourceFile)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if !info.Mode().IsRegular() {
+		return reader.NewTailReader(reader.NewPollReader(f), wpm), nil
+	}
+	return reader.NewTailReader(f, wpm), nil
+}
+
+// newTranscript returns the transcript sanitizer selected by the command-line
+// options, or nil if transcript mode is off. A filler list implies
+// transcript mode and replaces the default fillers.
+func newTranscript(enabled bool, fillersFile string) (*reader.Transcript, error) {
+	if fillersFile != "" {
+		fillers, err := reader.LoadFillerList(fillersFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load filler list: %w", err)
+		}
+		return reader.NewTranscript(fillers), nil
+	}
+	if !enabled {
+		return nil, nil
+	}
+	return reader.NewTranscript(reader.DefaultFillers), nil
+}
+
+// readDocument reads a JSON document (--stdin-json) of at most limit bytes
+// from r, returning its text, table of contents, chapters and metadata.
+func readDocument(r io.Reader, limit int64) (string, []reader.TOCEntry, []reader.Chapter, reader.Metadata, error) {
+	data, rest, err := readLimited(r, limit)
+	if err != nil {
+		return "", nil, nil, reader.Metadata{}, err
+	}
+	if rest != nil {
+		return "", nil, nil, reader.Metadata{}, fmt.Errorf("input is larger than the %s limit; raise it with --max-size", formatSize(limit))
+	}
+	doc, err := reader.ReadDocument(bytes.NewReader(data))
+	if err != nil {
+		return "", nil, nil, reader.Metadata{}, err
+	}
+	text, toc, chapters, err := doc.Content()
+	return text, toc, chapters, doc.Metadata(), err
+}
+
+// exitIfCancelled exits quietly when a load was interrupted.
+func exitIfCancelled(err error) {
+	if errors.Is(err, context.Canceled) {
+		fmt.Fprintln(os.Stderr, "Loading cancelled.")
+		os.Exit(130)
+	}
+}
+
+// preloaded is what is read before the reader starts.
+type preloaded struct {
+	text     string
+	toc      []reader.TOCEntry
+	chapters []reader.Chapter
+	// parallel is the text of the --parallel translation.
+	parallel string
+}
+
+// preload reads the document with load, when set, and the translation at
+// parallelPath, when set. Interrupting a slow load (a huge EPUB, a slow
+// server) cancels it instead of leaving a frozen terminal.
+func preload(load loadFunc, sourceFile, parallelPath string, opts loadOptions) (preloaded, error) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	var p preloaded
+	if load != nil {
+		var err error
+		p.text, p.toc, p.chapters, err = load(ctx, nil)
+		if err != nil {
+			return p, fmt.Errorf("failed to read '%s': %w", sourceFile, err)
+		}
+	}
+	if parallelPath != "" {
+		var err error
+		p.parallel, err = extractText(ctx, parallelPath, opts)
+		if err != nil {
+			return p, fmt.Errorf("--parallel: %w", err)
+		}
+	}
+	return p, nil
+}
+
+// loadFunc loads a document, passing loading events to progress.
+type loadFunc func(ctx context.Context, progress func(loadEvent)) (string, []reader.TOCEntry, []reader.Chapter, error)
+
+// fileLoader returns a loadFunc for a file or URL.
+func fileLoader(sourceFile string, opts loadOptions) loadFunc {
+	return func(ctx context.Context, progress func(loadEvent)) (string, []reader.TOCEntry, []reader.Chapter, error) {
+		opts.progress = progress
+		opts.report(readingEvent(sourceFile))
+		text, toc, chapters, err := loadFile(ctx, sourceFile, opts)
+		if err == nil {
+			opts.reportReady(chapters)
+		}
+		return text, toc, chapters, err
+	}
+}
+
+// subcommand returns the first of args, the arguments left after parsing
+// input's flags, as the subcommand to run, or "" if there are none. After
+// a "--" the arguments are files, even one named like a subcommand.
+func subcommand(input, args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	if n := len(input) - len(args) - 1; n >= 0 && input[n] == "--" {
+		return ""
+	}
+	return args[0]
+}
+
+// isSubcommand reports whether arg names a source subcommand of
+// sourceLoader.
+func isSubcommand(arg string) bool {
+	switch arg {
+	case "site", "podcast", "yt", "releases", "zotero":
+		return true
+	}
+	return false
+}
+
+// sourceFunc fetches the document of a source subcommand, returning the
+// source identifier used for saved state along with the extracted content.
+type sourceFunc func(ctx context.Context, opts loadOptions) (string, string, []reader.TOCEntry, []reader.Chapter, error)
+
+// sourceLoader parses the arguments of a source subcommand such as "site"
+// and returns a loadFunc that fetches its document. *src is set to the
+// source identifier used for saved state: at once to what the arguments
+// name, and again once the document is fetched, as a podcast episode is
+// known by its page rather than its feed.
+func sourceLoader(args []string, opts loadOptions, src *string) (loadFunc, error) {
+	var fetch sourceFunc
+	var err error
+	switch args[0] {
+	case "site":
+		*src, fetch, err = siteSource(args[1:])
+	case "podcast":
+		*src, fetch, err = podcastSource(args[1:])
+	case "yt":
+		*src, fetch, err = youTubeSource(args[1:])
+	case "releases":
+		*src, fetch, err = releasesSource(args[1:])
+	case "zotero":
+		*src, fetch, err = zoteroSource(args[1:], opts.zotero)
+	default:
+		*src, err = args[0], errors.New("not a source subcommand")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, progress func(loadEvent)) (string, []reader.TOCEntry, []reader.Chapter, error) {
+		opts.progress = progress
+		opts, err := opts.withClient()
+		if err != nil {
+			return "", nil, nil, err
+		}
+		source, text, toc, chapters, err := fetch(opts.fetchContext(ctx), opts)
+		*src = source
+		if err == nil {
+			opts.reportReady(chapters)
+		}
+		return text, toc, chapters, err
+	}, nil
+}
+
+// sectionParts reports the sections of a source fetched one after another,
+// such as the pages of a site, as parts ready to read, so reading can start
+// before the rest arrive.
+type sectionParts struct {
+	opts  loadOptions
+	parts []loadedPart
+	// read counts the sections added, out of total, which is 0 while the
+	// number to come isn't known.
+	read, total int
+	words       int
+}
+
+// add reports section s ready to read.
+func (p *sectionParts) add(s reader.Section) {
+	p.read++
+	chapters, toc, words := reader.AssembleSections([]reader.Section{s})
+	if len(words) == 0 {
+		return
+	}
+	part := loadedPart{text: strings.Join(words, " "), words: len(words), toc: toc, chapters: chapters}
+	p.parts = append(p.parts, part)
+	p.words += part.words
+	p.opts.report(loadEvent{stage: loadReady, words: p.words, part: p.read, parts: p.total, loaded: &part})
+}
+
+// document puts the sections added together as the parts read early are.
+func (p *sectionParts) document() (string, []reader.TOCEntry, []reader.Chapter) {
+	return joinParts(p.parts)
+}
+
+// siteSource crawls a documentation section and reads its pages as
+// chapters.
+func siteSource(args []string) (string, sourceFunc, error) {
+	fs := flag.NewFlagSet("site", flag.ExitOnError)
+	depth := fs.Int("depth", reader.DefaultCrawlOptions.MaxDepth, "Maximum link depth to follow")
+	maxPages := fs.Int("max-pages", reader.DefaultCrawlOptions.MaxPages, "Maximum number of pages to read")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s site [options] <url>\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Crawl pages under the URL's path and read them as chapters.\n\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 || !reader.IsURL(fs.Arg(0)) {
+		fs.Usage()
+		return "site", nil, errors.New("site requires a single http(s) URL")
+	}
+
+	start := fs.Arg(0)
+	crawl := reader.CrawlOptions{MaxDepth: *depth, MaxPages: *maxPages}
+	return start, func(ctx context.Context, opts loadOptions) (string, string, []reader.TOCEntry, []reader.Chapter, error) {
+		opts.logf("Crawling %s...\n", start)
+		parts := &sectionParts{opts: opts}
+		crawl.OnPage = parts.add
+		if _, err := reader.CrawlSiteContext(ctx, start, crawl); err != nil {
+			return start, "", nil, nil, err
+		}
+		text, toc, chapters := parts.document()
+		return start, text, toc, chapters, nil
+	}, nil
+}
+
+// podcastSource reads a podcast episode's transcript, or its show notes
+// when it has none. Transcripts are always cleaned with the transcript
+// sanitizer.
+func podcastSource(args []string) (string, sourceFunc, error) {
+	fs := flag.NewFlagSet("podcast", flag.ExitOnError)
+	episode := fs.Int("episode", 1, "Episode to read from a feed, counting from the newest")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s podcast [options] <feed or episode url>\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Read a podcast episode's transcript, or its show notes.\n\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 || !reader.IsURL(fs.Arg(0)) {
+		fs.Usage()
+		return "podcast", nil, errors.New("podcast requires a single http(s) URL")
+	}
+
+	feed := fs.Arg(0)
+	index := *episode
+	return feed, func(ctx context.Context, opts loadOptions) (string, string, []reader.TOCEntry, []reader.Chapter, error) {
+		opts.report(readingEvent(feed))
+		ep, err := reader.FetchEpisodeContext(ctx, feed, index)
+		if err != nil {
+			return feed, "", nil, nil, err
+		}
+		opts.logf("Reading %q\n", ep.Title)
+		src := feed
+		if ep.Link != "" {
+			src = ep.Link
+		}
+
+		if text := reader.EpisodeTranscriptContext(ctx, ep); text != "" {
+			transcript := opts.transcript
+			if transcript == nil {
+				transcript = reader.NewTranscript(reader.DefaultFillers)
+			}
+			return src, episodeText(opts, ep, transcript.Sanitize(text)), nil, nil, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return src, "", nil, nil, err
+		}
+		opts.logf("No transcript available; reading the show notes.\n")
+		return src, episodeText(opts, ep, ep.NotesText()), nil, nil, nil
+	}, nil
+}
+
+// episodeText reports a podcast episode's text, which arrives in one piece,
+// as its only part, and returns it.
+func episodeText(opts loadOptions, ep reader.Episode, text string) string {
+	parts := &sectionParts{opts: opts, total: 1}
+	parts.add(reader.Section{Title: ep.Title, Text: text})
+	return text
+}
+
+// youTubeSource reads a YouTube video's captions, with a chapter for each
+// of the video's chapter markers.
+func youTubeSource(args []string) (string, sourceFunc, error) {
+	fs := flag.NewFlagSet("yt", flag.ExitOnError)
+	lang := fs.String("lang", "en", "Caption language code")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s yt [options] <video url>\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Read a YouTube video's captions.\n\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 || !reader.IsYouTubeURL(fs.Arg(0)) {
+		fs.Usage()
+		return "yt", nil, errors.New("yt requires a single YouTube video URL")
+	}
+
+	src := fs.Arg(0)
+	captions := *lang
+	return src, func(ctx context.Context, opts loadOptions) (string, string, []reader.TOCEntry, []reader.Chapter, error) {
+		opts.report(readingEvent(src))
+		video, err := reader.FetchYouTubeVideoContext(ctx, src, captions)
+		if err != nil {
+			return src, "", nil, nil, err
+		}
+		opts.logf("Reading %q\n", video.Title)
+		if video.Fallback {
+			opts.logf("No %s captions; reading the %s ones (available: %s)\n", captions, video.Language, strings.Join(video.Languages, ", "))
+		}
+
+		transcript := opts.transcript
+		if transcript == nil {
+			transcript = reader.NewTranscript(reader.DefaultFillers)
+		}
+		parts := &sectionParts{opts: opts, total: len(video.Sections)}
+		for _, s := range video.Sections {
+			s.Text = transcript.Sanitize(s.Text)
+			parts.add(s)
+		}
+		text, toc, chapters := parts.document()
+		if len(chapters) < 2 {
+			chapters, toc = nil, nil
+		}
+		return src, text, toc, chapters, nil
+	}, nil
+}
+
+// releasesSource reads a repository's recent GitHub release notes, one
+// chapter per release.
+func releasesSource(args []string) (string, sourceFunc, error) {
+	fs := flag.NewFlagSet("releases", flag.ExitOnError)
+	count := fs.Int("n", 10, "Number of recent releases to read")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s releases [options] <owner/repo>\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Read a repository's recent release notes, newest first.\n\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	var owner, repo string
+	if fs.NArg() == 1 {
+		if ref, ok := reader.ParseGitHubURL(fs.Arg(0)); ok {
+			owner, repo = ref.Owner, ref.Repo
+		} else if o, r, ok := strings.Cut(fs.Arg(0), "/"); ok && !strings.Contains(r, "/") {
+			owner, repo = o, r
+		}
+	}
+	if owner == "" || repo == "" || *count < 1 {
+		fs.Usage()
+		return "releases", nil, errors.New("releases requires a single owner/repo")
+	}
+
+	src := "https://github.com/" + owner + "/" + repo + "/releases"
+	n := *count
+	return src, func(ctx context.Context, opts loadOptions) (string, string, []reader.TOCEntry, []reader.Chapter, error) {
+		opts.report(readingEvent(src))
+		parts := &sectionParts{opts: opts}
+		sections, err := reader.FetchReleasesContext(ctx, owner, repo, n, parts.add)
+		if err != nil {
+			return src, "", nil, nil, err
+		}
+		if len(sections) == 0 {
+			return src, "", nil, nil, errors.New("repository has no releases")
+		}
+		text, toc, chapters := parts.document()
+		return src, text, toc, chapters, nil
+	}, nil
+}
+
+// parseZoteroArgs reads the options and query of the zotero subcommand,
+// returning the library's items and the key or title asked for, which is
+// empty when the library is to be listed.
+func parseZoteroArgs(args []string) ([]reader.ZoteroItem, string, error) {
+	fs := flag.NewFlagSet("zotero", flag.ExitOnError)
+	library := fs.String("library", os.Getenv(zoteroEnv), "Better BibTeX JSON export of the Zotero library")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s zotero [options] [key or title]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Read the EPUB, HTML or text attachment of a Zotero item.\n")
+		fmt.Fprintf(os.Stderr, "With no key or title, list the library.\n\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if *library == "" {
+		fs.Usage()
+		return nil, "", fmt.Errorf("zotero requires -library or %s", zoteroEnv)
+	}
+	items, err := reader.LoadZoteroLibrary(*library)
+	if err != nil {
+		return nil, "", err
+	}
+	return items, strings.Join(fs.Args(), " "), nil
+}
+
+// runZoteroList lists the Zotero library to w when the zotero subcommand
+// names no item, reporting whether it did. The library is kept on z for
+// zoteroSource.
+func runZoteroList(w io.Writer, args []string, z *zoteroSession) (bool, error) {
+	items, query, err := z.parse(args)
+	if err != nil || query != "" {
+		return false, err
+	}
+	for _, it := range items {
+		fmt.Fprintln(w, it)
+	}
+	return true, nil
+}
+
+// zoteroSource reads the attachment of an item in a Zotero library
+// exported with Better BibTeX. The item is found before loading starts, so
+// a query matching several is answered at once; listing the library is left
+// to runZoteroList, which has read the library onto z already.
+func zoteroSource(args []string, z *zoteroSession) (string, sourceFunc, error) {
+	items, query, err := z.parse(args)
+	if err != nil {
+		return "zotero", nil, err
+	}
+	if query == "" {
+		return "zotero", nil, errors.New("zotero requires a key or title to read")
+	}
+
+	matches := reader.FindZoteroItems(items, query)
+	switch {
+	case len(matches) == 0:
+		return "zotero", nil, fmt.Errorf("no Zotero item matches %q", query)
+	case len(matches) > 1:
+		var list []string
+		for _, it := range matches {
+			list = append(list, "  "+it.String())
+		}
+		return "zotero", nil, fmt.Errorf("%d Zotero items match %q; give a key:\n%s", len(matches), query, strings.Join(list, "\n"))
+	}
+	item := matches[0]
+	path, ok := item.ReadableAttachment()
+	if !ok {
+		return "zotero", nil, fmt.Errorf("%s has no EPUB, HTML or text attachment", item.Key)
+	}
+
+	return path, func(ctx context.Context, opts loadOptions) (string, string, []reader.TOCEntry, []reader.Chapter, error) {
+		opts.logf("Opening %s\n", filepath.Base(path))
+		if opts.zotero != nil {
+			opts.zotero.item = &item
+		}
+		text, toc, chapters, err := loadFile(ctx, path, opts)
+		return path, text, toc, chapters, err
+	}, nil
+}
+
+// loadFile extracts text, TOC and chapters from a file or URL using the
+// format-specific providers where available.
+func loadFile(ctx context.Context, sourceFile string, opts loadOptions) (string, []reader.TOCEntry, []reader.Chapter, error) {
+	var text string
+	var toc []reader.TOCEntry
+	var chapters []reader.Chapter
+
+	if reader.IsURL(sourceFile) {
+		opts, err := opts.withClient()
+		if err != nil {
+			return "", nil, nil, err
+		}
+		ctx := opts.fetchContext(ctx)
+		if ref, ok := reader.ParseGitHubURL(sourceFile); ok {
+			return loadGitHub(ctx, ref, opts)
+		}
+		text, err := loadURL(ctx, sourceFile, opts)
+		if err == nil && opts.transcript != nil {
+			text = opts.transcript.Sanitize(text)
+		}
+		return text, nil, nil, err
+	}
+
+	if info, err := os.Stat(sourceFile); err == nil && info.IsDir() {
+		return loadDocs(ctx, sourceFile, opts)
+	}
+
+	if err := checkSize(sourceFile, opts.maxSize); err != nil {
+		return "", nil, nil, err
+	}
+
+	if opts.transcript != nil {
+		text, err := extractText(ctx, sourceFile, opts)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		return opts.transcript.Sanitize(text), nil, nil, nil
+	}
+
+	if strings.HasSuffix(strings.ToLower(sourceFile), ".epub") {
+		// The TOC and the chapters come from one pass over the spine.
+		var words []string
+		var err error
+		toc, chapters, words, err = opts.epubFormat().TOCAndChaptersContext(ctx, sourceFile)
+		if err != nil && opts.strict {
+			return "", nil, nil, err
+		}
+		if err == nil && len(words) > 0 {
+			text = strings.Join(words, " ")
+		}
+	} else {
+		if provider, ok := getTOCProvider(sourceFile); ok {
+			var err error
+			toc, err = reader.TOCContext(ctx, provider, sourceFile)
+			var serr *reader.SectionError
+			if opts.strict && errors.As(err, &serr) {
+				return "", nil, nil, err
+			}
+			if err != nil {
+				toc = nil
+			}
+		}
+
+		if extractor, ok := getChapterExtractor(sourceFile); ok {
+			var words []string
+			var err error
+			chapters, words, err = reader.ExtractChaptersContext(ctx, extractor, sourceFile)
+			if err != nil && opts.strict {
+				return "", nil, nil, err
+			}
+			if err == nil && len(words) > 0 {
+				text = strings.Join(words, " ")
+			}
+		}
+	}
+
+	if text == "" {
+		var err error
+		text, err = extractText(ctx, sourceFile, opts)
+		if err != nil {
+			return "", nil, nil, err
+		}
+	}
+
+	return text, toc, chapters, nil
+}
+
+// loadGitHub reads a README, or an issue or pull request with each comment
+// as a chapter, through the GitHub API.
+func loadGitHub(ctx context.Context, ref reader.GitHubRef, opts loadOptions) (string, []reader.TOCEntry, []reader.Chapter, error) {
+	title, sections, err := reader.FetchGitHubContext(ctx, ref, opts.maxSize)
+	if errors.Is(err, reader.ErrTooLarge) {
+		return "", nil, nil, fmt.Errorf("the document is larger than the %s limit; raise it with --max-size", formatSize(opts.maxSize))
+	}
+	if err != nil {
+		return "", nil, nil, err
+	}
+	opts.logf("Reading %s\n", title)
+	if opts.transcript != nil {
+		for i := range sections {
+			sections[i].Text = opts.transcript.Sanitize(sections[i].Text)
+		}
+	}
+	chapters, toc, words := reader.AssembleSections(sections)
+	if len(chapters) < 2 {
+		chapters, toc = nil, nil
+	}
+	return strings.Join(words, " "), toc, chapters, nil
+}
+
+// extractText extracts a file's text, applying the EPUB options.
+func extractText(ctx context.Context, sourceFile string, opts loadOptions) (string, error) {
+	if strings.HasSuffix(strings.ToLower(sourceFile), ".epub") {
+		return opts.epubFormat().ExtractContext(ctx, sourceFile)
+	}
+	return reader.ExtractTextContext(ctx, sourceFile)
+}
+
+// epubFormat returns an EPUB reader configured by the options.
+func (o loadOptions) epubFormat() *reader.EPUBFormat {
+	return &reader.EPUBFormat{Figures: o.figures, Strict: o.strict, Warnings: o.warnings, KeepEmpty: o.keepEmpty, Notes: o.notes, Ruby: o.ruby, SkipMatter: o.skipMatter}
+}
+
+func getTOCProvider(filename string) (reader.TOCProvider, bool) {
+	lower := strings.ToLower(filename)
+	switch {
+	case strings.HasSuffix(lower, ".md"), strings.HasSuffix(lower, ".markdown"):
+		return &reader.MarkdownFormat{}, true
+	case strings.HasSuffix(lower, ".org"):
+		return &reader.OrgFormat{}, true
+	case strings.HasSuffix(lower, ".rst"), strings.HasSuffix(lower, ".rest"):
+		return &reader.RSTFormat{}, true
+	}
+	return nil, false
+}
+
+func getChapterExtractor(filename string) (reader.ChapterExtractor, bool) {
+	lower := strings.ToLower(filename)
+	switch {
+	case strings.HasSuffix(lower, ".md"), strings.HasSuffix(lower, ".markdown"):
+		return &reader.MarkdownFormat{}, true
+	case strings.HasSuffix(lower, ".org"):
+		return &reader.OrgFormat{}, true
+	case strings.HasSuffix(lower, ".rst"), strings.HasSuffix(lower, ".rest"):
+		return &reader.RSTFormat{}, true
+	}
+	return nil, false
+}
+
+// loadURL fetches a web article, retrying through the archive endpoint when
+// the page looks like a paywalled teaser.
+func loadURL(ctx context.Context, rawURL string, opts loadOptions) (string, error) {
+	text, err := reader.ExtractTextFromURLContext(ctx, rawURL)
+	if opts.archive == "" {
+		return text, err
+	}
+	if err == nil && !reader.LooksTruncated(text) {
+		return text, nil
+	}
+
+	if err != nil {
+		opts.logf("Fetch failed (%v); retrying via archive...\n", err)
+	} else {
+		opts.logf("Article looks truncated (%d words); retrying via archive...\n", len(strings.Fields(text)))
+	}
+
+	archived, archiveErr := reader.ExtractTextFromURLContext(ctx, reader.ArchiveURL(opts.archive, rawURL))
+	if archiveErr != nil {
+		if err != nil {
+			return "", fmt.Errorf("%w (archive: %v)", err, archiveErr)
+		}
+		opts.logf("Archive fetch failed: %v; reading the original page.\n", archiveErr)
+		return text, nil
+	}
+	if err == nil && len(strings.Fields(archived)) <= len(strings.Fields(text)) {
+		opts.logf("Archive copy is no longer than the original; reading the original page.\n")
+		return text, nil
+	}
+	return archived, nil
+}
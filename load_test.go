@@ -0,0 +1,376 @@
+//go:build !gui
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+func TestSubcommand(t *testing.T) {
+	dir := t.TempDir()
+	site := filepath.Join(dir, "site")
+	os.WriteFile(site, []byte("A file named like a subcommand."), 0644)
+	t.Chdir(dir)
+
+	for _, tt := range []struct {
+		input []string
+		want  string
+	}{
+		{[]string{"-w", "500", "site", "https://example.com/docs/"}, "site"},
+		{[]string{"-w", "500", "--", "site"}, ""},
+		{[]string{"--", "--"}, ""},
+		{[]string{"book.txt"}, "book.txt"},
+		{[]string{"-w", "500"}, ""},
+	} {
+		fs := flag.NewFlagSet("brr", flag.ContinueOnError)
+		fs.Int("w", 300, "")
+		fs.Parse(tt.input)
+		if got := subcommand(tt.input, fs.Args()); got != tt.want {
+			t.Errorf("subcommand(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+
+	// After --, a file named like a subcommand is read.
+	input := []string{"--", "site"}
+	fs := flag.NewFlagSet("brr", flag.ContinueOnError)
+	fs.Parse(input)
+	if isSubcommand(subcommand(input, fs.Args())) {
+		t.Fatal("site after -- should be a file")
+	}
+	text, _, _, err := loadFile(context.Background(), fs.Arg(0), loadOptions{progress: func(loadEvent) {}})
+	if err != nil || !strings.HasPrefix(text, "A file named") {
+		t.Errorf("the file should be read, got %q, %v", text, err)
+	}
+}
+
+func TestSourceLoader(t *testing.T) {
+	dir := t.TempDir()
+	book := filepath.Join(dir, "speed.txt")
+	os.WriteFile(book, []byte("Call me Ishmael."), 0644)
+	library := filepath.Join(dir, "library.json")
+	os.WriteFile(library, []byte(`{"items": [{"itemKey": "ABCD1234", "itemType": "book", "title": "Reading at Speed", "attachments": [{"path": "`+filepath.ToSlash(book)+`"}]}]}`), 0644)
+
+	var src string
+	load, err := sourceLoader([]string{"zotero", "-library", library, "ABCD1234"}, loadOptions{}, &src)
+	if err != nil || src != book {
+		t.Fatalf("the item should be found before loading: %q, %v", src, err)
+	}
+	var events []loadEvent
+	text, _, _, err := load(context.Background(), func(e loadEvent) { events = append(events, e) })
+	if err != nil || text != "Call me Ishmael." {
+		t.Errorf("loading should read the attachment: %q, %v", text, err)
+	}
+	if len(events) == 0 || events[0].String() != "Opening speed.txt" {
+		t.Errorf("progress should be reported to the loading screen, got %v", events)
+	}
+}
+
+func TestFileLoaderReady(t *testing.T) {
+	dir := t.TempDir()
+	book := filepath.Join(dir, "book.md")
+	os.WriteFile(book, []byte("# One\n\nCall me Ishmael.\n\n# Two\n\nSome years ago.\n"), 0644)
+	notes := filepath.Join(dir, "notes.txt")
+	os.WriteFile(notes, []byte("Call me Ishmael."), 0644)
+
+	ready := func(path string) []string {
+		var got []string
+		if _, _, _, err := fileLoader(path, loadOptions{})(context.Background(), func(e loadEvent) {
+			if e.stage == loadReady {
+				got = append(got, e.String())
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+	if got := ready(book); !reflect.DeepEqual(got, []string{"10 words ready"}) {
+		t.Errorf("a book's words should be counted from its chapters, got %q", got)
+	}
+	if got := ready(notes); got != nil {
+		t.Errorf("a document without chapters should not be parsed to count it, got %q", got)
+	}
+}
+
+func TestSiteSourceParts(t *testing.T) {
+	pages := map[string]string{
+		"/guide/":          `<html><body><h1>Guide</h1><p>Start here.</p><a href="next.html">Next</a></body></html>`,
+		"/guide/next.html": `<html><body><h1>Next</h1><p>Then read this.</p></body></html>`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	var src string
+	load, err := sourceLoader([]string{"site", srv.URL + "/guide/"}, loadOptions{}, &src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parts []loadedPart
+	var ready []string
+	text, toc, chapters, err := load(context.Background(), func(e loadEvent) {
+		if e.loaded != nil {
+			ready = append(ready, e.String())
+			if !e.more() {
+				t.Errorf("part %d of a crawl should not be taken as the last", e.part)
+			}
+			parts = append(parts, *e.loaded)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parts) != 2 || !strings.HasPrefix(parts[0].text, "Guide Start here.") || parts[1].chapters[0].Title != "Next" {
+		t.Fatalf("each page should be reported as it is read, got %+v", parts)
+	}
+	if want := []string{"4 words ready (part 1)", "8 words ready (part 2)"}; !reflect.DeepEqual(ready, want) {
+		t.Errorf("ready events = %q, want %q", ready, want)
+	}
+	wantText, wantTOC, wantChapters := joinParts(parts)
+	if text != wantText || !reflect.DeepEqual(toc, wantTOC) || !reflect.DeepEqual(chapters, wantChapters) {
+		t.Errorf("the document should be the parts put together: %q, %+v", text, chapters)
+	}
+}
+
+func TestLoadDocsDirectory(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "guide"), 0755)
+	for name, text := range map[string]string{
+		"README.md":        "# Welcome\n\nStart here.",
+		"guide/install.md": "# Install\n\nRun the installer.\n\n## Linux\n\nUse the package.",
+	} {
+		os.WriteFile(filepath.Join(dir, name), []byte(text), 0644)
+	}
+
+	text, toc, chapters, err := loadFile(context.Background(), dir, loadOptions{progress: func(loadEvent) {}})
+	if err != nil {
+		t.Fatalf("loadFile(dir): %v", err)
+	}
+	if len(chapters) != 2 || chapters[0].Title != "Welcome" || chapters[1].Title != "Install" {
+		t.Fatalf("chapters = %+v, want one per page", chapters)
+	}
+	var entries []string
+	words := reader.ParseText(text)
+	for _, e := range toc {
+		entries = append(entries, fmt.Sprintf("%d %s @%s", e.Level, e.Title, words[e.WordIndex+1]))
+	}
+	want := []string{"0 Welcome @Welcome", "0 guide @Install", "1 Install @Install", "2 Linux @Linux"}
+	if !slices.Equal(entries, want) {
+		t.Errorf("toc = %q, want %q", entries, want)
+	}
+
+	hash, err := sourceHash(dir, []string{dir})
+	if err != nil || len(hash) != 32 {
+		t.Errorf("sourceHash(dir) = %q, %v", hash, err)
+	}
+}
+
+func TestOpenLazyEPUB(t *testing.T) {
+	r, err := openStream("SherlockHolmes.epub", 300, loadOptions{})
+	if r != nil || err != nil {
+		t.Errorf("a small EPUB should be loaded whole, got %v, %v", r, err)
+	}
+	r, err = openStream("SherlockHolmes.epub", 300, loadOptions{stream: true})
+	if err != nil || r == nil || !r.Streaming() {
+		t.Fatalf("with --stream, an EPUB should be read a chapter at a time, got %v, %v", r, err)
+	}
+	if r.CurrentChapterTitle() == "" || len(r.Words) == 0 {
+		t.Errorf("the first chapter should be parsed, at %q in %q", r.CurrentWord(), r.CurrentChapterTitle())
+	}
+
+	// The chapters are listed in the contents before they are parsed.
+	m := newReaderModel(r)
+	if len(r.TOC) < 2 || len(m.tocList.Items()) != len(r.TOC) {
+		t.Fatalf("TOC = %+v", r.TOC)
+	}
+	last := len(r.TOC) - 1
+	m.tocList.Select(last)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m = updated.(model); m.Position() != r.TOC[last].WordIndex || m.CurrentChapterTitle() != r.TOC[last].Title {
+		t.Errorf("choosing the last chapter went to %d in %q", m.Position(), m.CurrentChapterTitle())
+	}
+}
+
+func TestSkipWatch(t *testing.T) {
+	warnings := reader.Warnings{errors.New("first")}
+	m := newModel("Call me Ishmael.", 300, nil, nil)
+	m.skipped = newSkipWatch(&warnings)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if m = updated.(model); m.notice != "" {
+		t.Errorf("warnings shown at the start should not be repeated, got %q", m.notice)
+	}
+	warnings.Add(errors.New("second"))
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if m = updated.(model); !strings.HasPrefix(m.notice, "Warning: ") {
+		t.Errorf("a section skipped while reading should be reported, got %q", m.notice)
+	}
+}
+
+func TestPreloadParallel(t *testing.T) {
+	dir := t.TempDir()
+	book, translation := filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt")
+	os.WriteFile(book, []byte("Call me Ishmael. Some years ago."), 0644)
+	os.WriteFile(translation, []byte("Appelez-moi Ismaël. Il y a quelques années."), 0644)
+
+	// The reader's loading screen loads the document, so only the
+	// translation is read before it starts.
+	pre, err := preload(nil, book, translation, loadOptions{})
+	if err != nil {
+		t.Fatalf("preload: %v", err)
+	}
+	if pre.text != "" || !strings.HasPrefix(pre.parallel, "Appelez-moi") {
+		t.Errorf("preload(nil) = %+v, want only the translation", pre)
+	}
+
+	pre, err = preload(fileLoader(book, loadOptions{}), book, translation, loadOptions{})
+	if err != nil {
+		t.Fatalf("preload: %v", err)
+	}
+	if !strings.HasPrefix(pre.text, "Call me") || !strings.HasPrefix(pre.parallel, "Appelez-moi") {
+		t.Errorf("preload = %+v, want the document and its translation", pre)
+	}
+
+	if _, err := preload(nil, book, filepath.Join(dir, "missing.txt"), loadOptions{}); err == nil || !strings.HasPrefix(err.Error(), "--parallel:") {
+		t.Errorf("a missing translation should be reported as --parallel, got %v", err)
+	}
+}
+
+func TestLoadURLArchiveFallback(t *testing.T) {
+	full := strings.Repeat("<p>A complete paragraph of the archived article text.</p>", 40)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if strings.HasPrefix(r.URL.Path, "/archive/") {
+			w.Write([]byte(full))
+			return
+		}
+		w.Write([]byte("<p>Teaser only. Subscribe to continue reading.</p>"))
+	}))
+	defer srv.Close()
+
+	text, err := loadURL(context.Background(), srv.URL+"/story", loadOptions{archive: srv.URL + "/archive/{url_escaped}"})
+	if err != nil {
+		t.Fatalf("loadURL: %v", err)
+	}
+	if !strings.Contains(text, "archived article") {
+		t.Errorf("expected archived text, got %q", text)
+	}
+
+	text, err = loadURL(context.Background(), srv.URL+"/story", loadOptions{})
+	if err != nil {
+		t.Fatalf("loadURL: %v", err)
+	}
+	if !strings.Contains(text, "Teaser only") {
+		t.Errorf("without archive the original should be used, got %q", text)
+	}
+}
+
+func TestLoadGitHub(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repos/o/r/issues/5":
+			w.Write([]byte(`{"title": "Speed", "body": "Um, we should read faster."}`))
+		case "/repos/o/r/issues/5/comments":
+			w.Write([]byte(`[]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	orig := reader.GitHubAPI
+	reader.GitHubAPI = srv.URL
+	defer func() { reader.GitHubAPI = orig }()
+
+	issue := "https://github.com/o/r/issues/5"
+	opts := loadOptions{transcript: reader.NewTranscript(reader.DefaultFillers), progress: func(loadEvent) {}}
+	text, _, _, err := loadFile(context.Background(), issue, opts)
+	if err != nil || strings.Contains(text, "Um") || !strings.Contains(text, "read faster") {
+		t.Errorf("the issue should be cleaned as a transcript, got %q, %v", text, err)
+	}
+
+	opts.maxSize = 16
+	if _, _, _, err := loadFile(context.Background(), issue, opts); err == nil || !strings.Contains(err.Error(), "--max-size") {
+		t.Errorf("an issue over --max-size should be refused, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, _, err := loadFile(ctx, issue, loadOptions{progress: func(loadEvent) {}}); !errors.Is(err, context.Canceled) {
+		t.Errorf("a cancelled load should stop, got %v", err)
+	}
+}
+
+func TestLoadCookies(t *testing.T) {
+	var tracked bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := r.Cookie("session"); err != nil {
+			http.Error(w, "login required", http.StatusForbidden)
+			return
+		}
+		if _, err := r.Cookie("tracker"); err == nil {
+			tracked = true
+		}
+		http.SetCookie(w, &http.Cookie{Name: "tracker", Value: "1", Path: "/"})
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<p>Members only: the full text of the page.</p>"))
+	}))
+	defer srv.Close()
+	host := strings.TrimPrefix(srv.URL, "http://")
+	host = host[:strings.Index(host, ":")]
+	cookies := filepath.Join(t.TempDir(), "cookies.txt")
+	os.WriteFile(cookies, []byte(host+"\tFALSE\t/\tFALSE\t0\tsession\tsubscriber\n"), 0644)
+	opts := loadOptions{cookies: cookies, progress: func(loadEvent) {}}
+
+	var src string
+	load, err := sourceLoader([]string{"site", srv.URL + "/docs/"}, opts, &src)
+	if err != nil {
+		t.Fatalf("sourceLoader: %v", err)
+	}
+	if text, _, _, err := load(context.Background(), nil); err != nil || !strings.Contains(text, "Members only") {
+		t.Errorf("site should send the cookies, got %q, %v", text, err)
+	}
+
+	tracked = false
+	text, _, _, err := loadFile(context.Background(), srv.URL+"/story", opts)
+	if err != nil || !strings.Contains(text, "Members only") {
+		t.Errorf("a URL should be fetched with the cookies, got %q, %v", text, err)
+	}
+	if tracked {
+		t.Error("cookies set during one load should not be sent by the next")
+	}
+	if reader.URLClient.Jar != nil {
+		t.Error("the cookies should not be left on the shared client")
+	}
+}
+
+func TestReadStdinDocument(t *testing.T) {
+	doc := `{"title": "Notes", "text": "First part. Second part.", "chapters": [{"title": "One", "start": 0}, {"title": "Two", "start": 2}]}`
+	text, toc, chapters, book, err := readDocument(strings.NewReader(doc), 0)
+	if err != nil || text != "First part. Second part." || len(toc) != 2 || len(chapters) != 2 || book.Title != "Notes" {
+		t.Errorf("readDocument = %q, %v, %v, %+v, %v", text, toc, chapters, book, err)
+	}
+	if _, _, _, _, err := readDocument(strings.NewReader(doc), 16); err == nil || !strings.Contains(err.Error(), "--max-size") {
+		t.Errorf("a document over the limit should fail, got %v", err)
+	}
+}
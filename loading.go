@@ -0,0 +1,251 @@
+//go:build !gui
+
+package main
+
+import (
+	"context"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+// loadingModel shows a spinner while a document is extracted in the
+// background, then hands over to the reading model.
+type loadingModel struct {
+	source  string
+	load    loadFunc
+	start   func(text string, toc []reader.TOCEntry, chapters []reader.Chapter, incomplete bool) (model, error)
+	ctx     context.Context
+	cancel  context.CancelFunc
+	spinner spinner.Model
+	// events receives the loader's events, and status shows the last.
+	events chan loadEvent
+	status string
+	// parts are the parts of a document loaded a part at a time so far,
+	// and more is set while others are still to come, when ENTER starts
+	// reading them.
+	parts []loadedPart
+	more  bool
+
+	width  int
+	height int
+	// err is set when loading failed or was cancelled.
+	err error
+}
+
+// loadedMsg carries the result of a background load.
+type loadedMsg struct {
+	text     string
+	toc      []reader.TOCEntry
+	chapters []reader.Chapter
+	err      error
+}
+
+// loadProgressMsg is an event from the loader; ok is false once loading
+// has finished.
+type loadProgressMsg struct {
+	event loadEvent
+	ok    bool
+}
+
+func newLoadingModel(source string, load loadFunc, start func(string, []reader.TOCEntry, []reader.Chapter, bool) (model, error)) loadingModel {
+	ctx, cancel := context.WithCancel(context.Background())
+	return loadingModel{
+		source:  source,
+		load:    load,
+		start:   start,
+		ctx:     ctx,
+		cancel:  cancel,
+		spinner: spinner.New(spinner.WithSpinner(spinner.Dot), spinner.WithStyle(pausedStyle)),
+		events:  make(chan loadEvent, 16),
+	}
+}
+
+func (m loadingModel) Init() tea.Cmd {
+	return tea.Batch(m.spinner.Tick, m.runLoad(), m.waitProgress())
+}
+
+// runLoad extracts the document off the UI goroutine.
+func (m loadingModel) runLoad() tea.Cmd {
+	return func() tea.Msg {
+		text, toc, chapters, err := m.load(m.ctx, m.report)
+		close(m.events)
+		return loadedMsg{text: text, toc: toc, chapters: chapters, err: err}
+	}
+}
+
+// report passes an event from the loader to the UI. Other events are
+// dropped rather than block when the UI falls behind, but not parts of the
+// document.
+func (m loadingModel) report(e loadEvent) {
+	if e.loaded != nil {
+		select {
+		case m.events <- e:
+		case <-m.ctx.Done():
+		}
+		return
+	}
+	select {
+	case m.events <- e:
+	default:
+	}
+}
+
+// waitProgress delivers the next event.
+func (m loadingModel) waitProgress() tea.Cmd {
+	return waitLoad(m.events)
+}
+
+// waitLoad delivers the next event from a loader.
+func waitLoad(events <-chan loadEvent) tea.Cmd {
+	return func() tea.Msg {
+		e, ok := <-events
+		return loadProgressMsg{event: e, ok: ok}
+	}
+}
+
+func (m loadingModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "Q", "ctrl+c", "esc":
+			m.cancel()
+			m.err = context.Canceled
+			return m, tea.Quit
+		case "enter":
+			if m.more {
+				return m.readEarly()
+			}
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case loadProgressMsg:
+		if !msg.ok {
+			return m, nil
+		}
+		m.status = msg.event.String()
+		if msg.event.loaded != nil {
+			m.parts = append(m.parts, *msg.event.loaded)
+			m.more = msg.event.more()
+		}
+		return m, m.waitProgress()
+
+	case loadedMsg:
+		m.cancel()
+		if msg.err != nil {
+			m.err = msg.err
+			return m, tea.Quit
+		}
+		rm, err := m.start(msg.text, msg.toc, msg.chapters, false)
+		if err != nil {
+			m.err = err
+			return m, tea.Quit
+		}
+		var next tea.Model = rm
+		if m.width > 0 {
+			next, _ = rm.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+		}
+		return next, next.Init()
+	}
+	return m, nil
+}
+
+// readEarly starts reading the parts loaded so far, leaving the rest to
+// arrive while reading.
+func (m loadingModel) readEarly() (tea.Model, tea.Cmd) {
+	text, toc, chapters := joinParts(m.parts)
+	rm, err := m.start(text, toc, chapters, true)
+	if err != nil {
+		m.cancel()
+		m.err = err
+		return m, tea.Quit
+	}
+	rm.Incomplete = true
+	rm.loading = m.events
+	rm.stopLoading = m.cancel
+	var next tea.Model = rm
+	if m.width > 0 {
+		next, _ = rm.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+	}
+	return next, tea.Batch(next.Init(), m.waitProgress())
+}
+
+// cancelLoading stops the load of a document still arriving while it is
+// read, so the loader doesn't wait on a reader that has gone.
+func (m model) cancelLoading() {
+	if m.stopLoading != nil {
+		m.stopLoading()
+	}
+}
+
+func (m loadingModel) View() string {
+	lines := []string{m.spinner.View() + " Loading " + m.source + "..."}
+	if m.status != "" {
+		lines = append(lines, controlsStyle.Render(m.status))
+	}
+	hint := "Q: cancel"
+	if m.more {
+		hint = "ENTER: start reading, Q: cancel"
+	}
+	lines = append(lines, "", controlsStyle.Render(hint))
+	body := strings.Join(lines, "\n")
+	if m.width == 0 || m.height == 0 {
+		return body
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, body)
+}
+
+// recordWhenLoaded records a document read early in the library once its
+// load has succeeded and its last part is in, whichever comes last.
+func (m *model) recordWhenLoaded() {
+	if m.loaded && m.loading == nil {
+		recordLoaded(m.stateStore, m.fileHash, m.sourceFile, m.book, m.Reader)
+		m.loaded = false
+	}
+}
+
+// updateLoading adds the parts of a document read early as they arrive.
+func (m model) updateLoading(msg loadProgressMsg) (tea.Model, tea.Cmd) {
+	if m.loading == nil {
+		// The document still loading was replaced by another.
+		return m, nil
+	}
+	if !msg.ok {
+		m.Incomplete = false
+		m.loading = nil
+		m.recordWhenLoaded()
+		if m.pendingResume > 0 {
+			// The saved position is past the end of the document.
+			m.pendingResume = 0
+			m.notice = ""
+		}
+		return m, nil
+	}
+	wait := waitLoad(m.loading)
+	if part := msg.event.loaded; part != nil {
+		m.Append(part.text, part.toc, part.chapters)
+		if m.pendingResume > 0 {
+			if m.resume = resumeLoaded(m.Reader, m.pendingResume); m.resume != nil {
+				m.pendingResume = 0
+				m.notice = m.resume.prompt(m.Reader)
+				m.Pause()
+				m.stopSpeech()
+			}
+		}
+		if len(m.tocList.Items()) != len(m.TOC) {
+			return m, tea.Batch(wait, m.tocList.SetItems(tocItems(m.TOC)))
+		}
+	}
+	return m, wait
+}
@@ -0,0 +1,165 @@
+//go:build !gui
+
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/metcalfc/brr/internal/reader"
+	"github.com/metcalfc/brr/internal/state"
+)
+
+func TestLoadingModel(t *testing.T) {
+	load := func(ctx context.Context, progress func(loadEvent)) (string, []reader.TOCEntry, []reader.Chapter, error) {
+		progress(loadEvent{stage: loadFetching, source: "chapter 1"})
+		return "one two three", nil, nil, nil
+	}
+	start := func(text string, toc []reader.TOCEntry, chapters []reader.Chapter, _ bool) (model, error) {
+		return newModel(text, 300, toc, chapters), nil
+	}
+
+	lm := newLoadingModel("book.epub", load, start)
+	loaded := lm.runLoad()()
+	progress := lm.waitProgress()()
+
+	next, _ := lm.Update(progress)
+	if view := next.View(); !strings.Contains(view, "Loading book.epub") || !strings.Contains(view, "Fetching chapter 1") {
+		t.Errorf("loading view = %q", view)
+	}
+	next, cmd := next.Update(loaded)
+	rm, ok := next.(model)
+	if !ok || cmd == nil {
+		t.Fatalf("loaded document should start the reader, got %T", next)
+	}
+	if rm.CurrentWord() != "one" {
+		t.Errorf("current word = %q, want one", rm.CurrentWord())
+	}
+
+	lm = newLoadingModel("book.epub", load, start)
+	next, _ = lm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	if got := next.(loadingModel).err; !errors.Is(got, context.Canceled) || lm.ctx.Err() == nil {
+		t.Errorf("q should cancel loading, err = %v", got)
+	}
+
+	failing := func(ctx context.Context, progress func(loadEvent)) (string, []reader.TOCEntry, []reader.Chapter, error) {
+		return "", nil, nil, errors.New("boom")
+	}
+	lm = newLoadingModel("book.epub", failing, start)
+	next, _ = lm.Update(lm.runLoad()())
+	if got := next.(loadingModel).err; got == nil || got.Error() != "boom" {
+		t.Errorf("failed load err = %v", got)
+	}
+}
+
+func TestLoadingReadEarly(t *testing.T) {
+	start := func(text string, toc []reader.TOCEntry, chapters []reader.Chapter, _ bool) (model, error) {
+		return newModel(text, 300, toc, chapters), nil
+	}
+	lm := newLoadingModel("a.md", nil, start)
+	part := func(text, title string, n int) loadProgressMsg {
+		words := len(reader.ParseText(text))
+		loaded := &loadedPart{text: text, words: words, chapters: []reader.Chapter{{Title: title, WordEnd: words - 1}}, toc: []reader.TOCEntry{{Title: title}}}
+		return loadProgressMsg{event: loadEvent{stage: loadReady, words: words, part: n, parts: 2, loaded: loaded}, ok: true}
+	}
+
+	next, _ := lm.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if _, ok := next.(loadingModel); !ok {
+		t.Fatal("ENTER should do nothing before any part is loaded")
+	}
+	next, _ = next.Update(part("One two.", "First", 1))
+	if view := next.View(); !strings.Contains(view, "2 words ready (1 of 2)") || !strings.Contains(view, "ENTER: start reading") {
+		t.Errorf("loading view = %q", view)
+	}
+	next, cmd := next.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m, ok := next.(model)
+	if !ok || cmd == nil || !m.Incomplete || len(m.Words) != 2 {
+		t.Fatalf("ENTER should start reading the first part, got %T", next)
+	}
+	m.JumpToChapter(1)
+	if !m.Waiting() || m.AtEnd() {
+		t.Error("the last word loaded should wait for the rest")
+	}
+
+	next, _ = m.Update(part("Three four five.", "Second", 2))
+	m = next.(model)
+	if len(m.Words) != 5 || len(m.Chapters) != 2 || m.Chapters[1].WordStart != 2 || len(m.tocList.Items()) != 2 {
+		t.Errorf("the second part should be appended: words %q, chapters %+v", m.Words, m.Chapters)
+	}
+	next, _ = m.Update(loadProgressMsg{})
+	if m = next.(model); m.Incomplete || m.loading != nil {
+		t.Error("the end of loading should complete the document")
+	}
+	next, _ = m.Update(loadedMsg{err: errors.New("boom")})
+	if m = next.(model); m.notice != "Loading stopped: boom" {
+		t.Errorf("notice = %q", m.notice)
+	}
+}
+
+func TestLoadingReadEarlyResume(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	store, _ := state.NewStateStore()
+	hash := "abcdef1234567890abcdef1234567890"
+	store.SetPosition(hash, 3)
+	start := func(text string, toc []reader.TOCEntry, chapters []reader.Chapter, incomplete bool) (model, error) {
+		m := newModel(text, 300, toc, chapters)
+		m.stateStore, m.fileHash, m.sourceFile = store, hash, "a.md"
+		if pos := store.GetPosition(hash); incomplete && !m.SeekTo(pos) {
+			m.pendingResume = pos
+		}
+		return m, nil
+	}
+	lm := newLoadingModel("a.md", nil, start)
+	part := func(text string, n int) loadProgressMsg {
+		words := len(reader.ParseText(text))
+		return loadProgressMsg{event: loadEvent{stage: loadReady, words: words, part: n, parts: 2, loaded: &loadedPart{text: text, words: words}}, ok: true}
+	}
+	next, _ := lm.Update(part("One two.", 1))
+	next, _ = next.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m := next.(model)
+	if m.pendingResume != 3 || m.Position() != 0 {
+		t.Fatalf("a position past the first part should wait for it: pending %d, at %d", m.pendingResume, m.Position())
+	}
+	m.savePosition()
+	if got := store.GetPosition(hash); got != 3 {
+		t.Errorf("saving while loading overwrote the position with %d", got)
+	}
+
+	next, _ = m.Update(part("Three four five.", 2))
+	if m = next.(model); m.resume == nil || m.pendingResume != 0 || m.Position() != 3 {
+		t.Errorf("the saved position should resume once loaded: at %d", m.Position())
+	}
+	next, _ = m.Update(loadedMsg{})
+	m = next.(model)
+	if st, _ := store.Get(hash); st.TotalWords != 0 {
+		t.Errorf("the book was recorded with %d words before its last part", st.TotalWords)
+	}
+	next, _ = m.Update(loadProgressMsg{})
+	m = next.(model)
+	if st, _ := store.Get(hash); st.TotalWords != 5 {
+		t.Errorf("recorded %d words, want 5", st.TotalWords)
+	}
+}
+
+func TestLoadingReadEarlyQuit(t *testing.T) {
+	start := func(text string, toc []reader.TOCEntry, chapters []reader.Chapter, _ bool) (model, error) {
+		return newModel(text, 300, toc, chapters), nil
+	}
+	lm := newLoadingModel("a.md", nil, start)
+	loaded := &loadedPart{text: "One two.", words: 2}
+	next, _ := lm.Update(loadProgressMsg{event: loadEvent{stage: loadReady, words: 2, part: 1, parts: 2, loaded: loaded}, ok: true})
+	next, _ = next.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if _, ok := next.(model); !ok {
+		t.Fatalf("ENTER should start reading, got %T", next)
+	}
+	if lm.ctx.Err() != nil {
+		t.Fatal("reading early should not cancel the load")
+	}
+	next.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	if lm.ctx.Err() == nil {
+		t.Error("quitting should cancel the load still running")
+	}
+}
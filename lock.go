@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// lockHold is how long a locked key must be held to go through.
+	lockHold = 2 * time.Second
+	// lockRepeatGap is the longest gap between repeats of a held key,
+	// allowing for the delay before a key starts repeating.
+	lockRepeatGap = 700 * time.Millisecond
+)
+
+// focusLock keeps quitting and the table of contents behind a held key
+// for the first stretch of a session (--lock), so a reflex to bail out of a
+// focused reading block takes a deliberate effort. It ends by itself.
+type focusLock struct {
+	until time.Time
+
+	// key is the locked key being held, pressed first at held and last
+	// repeated at last.
+	key        string
+	held, last time.Time
+}
+
+// newFocusLock returns a lock lasting d from now, or nil if d is 0.
+func newFocusLock(d time.Duration, now time.Time) *focusLock {
+	if d <= 0 {
+		return nil
+	}
+	return &focusLock{until: now.Add(d)}
+}
+
+// locked reports whether the lock is still on at now.
+func (l *focusLock) locked(now time.Time) bool {
+	return l != nil && now.Before(l.until)
+}
+
+// left returns how long the lock lasts after now.
+func (l *focusLock) left(now time.Time) time.Duration {
+	if l == nil {
+		return 0
+	}
+	return max(l.until.Sub(now), 0)
+}
+
+// hold records key, a locked key, pressed or repeated by holding it down at
+// now, and reports whether it goes through: once the lock is off, or once
+// the key has been held for lockHold.
+func (l *focusLock) hold(key string, now time.Time) bool {
+	if !l.locked(now) {
+		return true
+	}
+	if key != l.key || now.Sub(l.last) > lockRepeatGap {
+		l.key, l.held = key, now
+	}
+	l.last = now
+	if now.Sub(l.held) < lockHold {
+		return false
+	}
+	l.key = ""
+	return true
+}
+
+// notice explains that doing action with key needs it held, at now.
+func (l *focusLock) notice(key, action string, now time.Time) string {
+	left := l.left(now).Round(time.Second)
+	return fmt.Sprintf("Focus lock: %d:%02d left. Hold %s to %s anyway", int(left.Minutes()), int(left.Seconds())%60, key, action)
+}
+
+// status describes what is left of the lock for the status bar.
+func (l *focusLock) status(now time.Time) string {
+	if !l.locked(now) {
+		return ""
+	}
+	left := l.left(now).Round(time.Second)
+	return fmt.Sprintf("Locked %d:%02d", int(left.Minutes()), int(left.Seconds())%60)
+}
+
+// lockOverNotice is shown when the focus lock ends.
+const lockOverNotice = "Focus lock over"
@@ -0,0 +1,58 @@
+//go:build !gui
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+func TestFocusLock(t *testing.T) {
+	now := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	if newFocusLock(0, now) != nil {
+		t.Error("no --lock should mean no lock")
+	}
+	l := newFocusLock(20*time.Minute, now)
+	if l.hold("q", now) {
+		t.Fatal("a locked key should not go through at once")
+	}
+	// Key repeats while held.
+	at := now
+	for at.Sub(now) < lockHold-100*time.Millisecond {
+		at = at.Add(50 * time.Millisecond)
+		if l.hold("q", at) {
+			t.Fatalf("q went through after %v", at.Sub(now))
+		}
+	}
+	if !l.hold("q", now.Add(lockHold)) {
+		t.Error("holding q for lockHold should go through")
+	}
+	if l.hold("q", now.Add(3*time.Second)) || l.hold("q", now.Add(3*time.Second+lockRepeatGap+time.Millisecond+lockHold)) {
+		t.Error("letting go should start the hold over")
+	}
+	if got := l.notice("Q", "quit", now.Add(90*time.Second)); got != "Focus lock: 18:30 left. Hold Q to quit anyway" {
+		t.Errorf("notice = %q", got)
+	}
+	if l.locked(now.Add(20*time.Minute)) || !l.hold("t", now.Add(20*time.Minute)) || l.status(now.Add(20*time.Minute)) != "" {
+		t.Error("the lock should end by itself")
+	}
+
+	m := newModel("one two three", 300, []reader.TOCEntry{{Title: "One"}}, nil)
+	m.lock = newFocusLock(time.Minute, time.Now())
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	if m = updated.(model); m.quitting || cmd != nil || !strings.HasPrefix(m.notice, "Focus lock:") {
+		t.Errorf("q should not quit while locked: notice %q", m.notice)
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	if m = updated.(model); m.tocVisible || !strings.Contains(m.notice, "Hold T to open the contents") {
+		t.Errorf("t should not open the contents while locked: notice %q", m.notice)
+	}
+	updated, _ = m.Update(lockMsg{})
+	if m = updated.(model); m.notice != lockOverNotice {
+		t.Errorf("notice = %q", m.notice)
+	}
+}
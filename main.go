@@ -3,18 +3,26 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
-	"io"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/metcalfc/brr/internal/reader"
 	"github.com/metcalfc/brr/internal/state"
+	"github.com/metcalfc/brr/internal/theme"
+	"github.com/metcalfc/brr/internal/translate"
+	"github.com/metcalfc/brr/internal/tts"
 )
 
 // Version info (injected via ldflags)
@@ -25,51 +33,90 @@ var (
 )
 
 var (
+	erpStyle        lipgloss.Style
+	wordBeforeStyle lipgloss.Style
+	wordBoldStyle   lipgloss.Style
+	contextStyle    lipgloss.Style
+	statusStyle     lipgloss.Style
+	controlsStyle   lipgloss.Style
+	pausedStyle     lipgloss.Style
+	completeStyle   lipgloss.Style
+	tocPanelStyle   lipgloss.Style
+	tocTitleStyle   lipgloss.Style
+)
+
+// handoffStyle shows QR codes dark on light whatever the theme, as
+// scanners expect.
+var handoffStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("#000000")).
+	Background(lipgloss.Color("#FFFFFF"))
+
+func init() {
+	applyTheme(theme.Default())
+}
+
+// applyTheme rebuilds the lipgloss styles from a color scheme.
+func applyTheme(t theme.Theme) {
 	erpStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#FF0000"))
+		Bold(true).
+		Foreground(lipgloss.Color(t.Focus))
 
 	wordBeforeStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFFFFF"))
+		Foreground(lipgloss.Color(t.Word))
+
+	wordBoldStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color(t.Word))
 
-	wordAfterStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFFFFF"))
+	contextStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color(t.Controls))
 
 	statusStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#888888")).
-			Padding(0, 1)
+		Foreground(lipgloss.Color(t.Status)).
+		Padding(0, 1)
 
 	controlsStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#666666")).
-			Italic(true)
+		Foreground(lipgloss.Color(t.Controls)).
+		Italic(true)
 
 	pausedStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFAA00")).
-			Bold(true)
+		Foreground(lipgloss.Color(t.Accent)).
+		Bold(true)
 
 	completeStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#00FF00")).
-			Bold(true)
+		Foreground(lipgloss.Color(t.Complete)).
+		Bold(true)
 
 	tocPanelStyle = lipgloss.NewStyle().
-			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#666666")).
-			Padding(0, 1)
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(t.Border)).
+		Padding(0, 1)
 
 	tocTitleStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFAA00")).
-			Bold(true)
-)
+		Foreground(lipgloss.Color(t.Accent)).
+		Bold(true)
+}
 
 // tocItem implements list.Item for the TOC list
 type tocItem struct {
 	entry reader.TOCEntry
+	// index is the entry's place in the TOC, for Reader.JumpToTOC.
+	index int
 }
 
 func (i tocItem) Title() string       { return i.entry.Title }
 func (i tocItem) Description() string { return i.entry.Preview }
 func (i tocItem) FilterValue() string { return i.entry.Title }
 
+// annotationItem implements list.Item for the list of notes at positions
+type annotationItem struct {
+	annotation state.Annotation
+}
+
+func (i annotationItem) Title() string       { return i.annotation.Text }
+func (i annotationItem) Description() string { return annotationRef(i.annotation) }
+func (i annotationItem) FilterValue() string { return i.annotation.Text }
+
 type model struct {
 	*reader.Reader
 	quitting   bool
@@ -77,30 +124,332 @@ type model struct {
 	height     int
 	tocVisible bool
 	tocList    list.Model
+	// tocPreview is the table of contents item whose opening words are
+	// shown beside it, or nil.
+	tocPreview *tocItem
+	// order edits the chapters to read and their order, opened from the
+	// table of contents.
+	order      *readingOrder
 	sourceFile string
+	// book is the title and author of sourceFile.
+	book       reader.Metadata
 	stateStore *state.StateStore
 	fileHash   string
+	// session names the saved position read and saved in place of the
+	// book's own (--session), and conflict is a sync conflict over the
+	// book's position awaiting the reader's choice.
+	session  string
+	conflict *syncConflict
+	// huge is a document over the --max-words limit, until the reader
+	// chooses how much of it to read.
+	huge *hugeInput
+	// loading delivers the rest of a document being read before it has
+	// finished loading; see loadingModel.readEarly.
+	loading <-chan loadEvent
+	// stopLoading cancels the load delivering on loading.
+	stopLoading context.CancelFunc
+	// loaded is set once the load delivering on loading has succeeded.
+	loaded bool
+	// skipped reports sections skipped as a document read a chapter at a
+	// time is parsed.
+	skipped *skipWatch
+	// resume is a book reopened at its saved position, until the reader
+	// continues from there or starts over.
+	resume *resumePrompt
+	// pendingResume is a saved position in the part of a document read
+	// early that is still loading; reading resumes there once it arrives.
+	pendingResume int
+
+	// capturePath and captureTemplate configure the A key; see
+	// captureSentence.
+	capturePath     string
+	captureTemplate string
+
+	// handoffURL is the template of the link the H key shows as a QR
+	// code, and handoff that code, shown until a key is pressed.
+	handoffURL string
+	handoff    *handoffScreen
+
+	// translator translates the sentence paused in (--translate) into
+	// translation.
+	translator  translate.Translator
+	translation *sentenceTranslation
+
+	// parallel is the --parallel translation, shown by the parallel
+	// display.
+	parallel *reader.ParallelText
+
+	searching   bool
+	searchInput textinput.Model
+	notice      string
+
+	// goingTo asks in jumpInput for a position to go to.
+	goingTo   bool
+	jumpInput textinput.Model
+
+	// notesOpen shows the book's scratch notes for editing in notesInput
+	// in place of the reader.
+	notesOpen  bool
+	notesInput textarea.Model
+
+	// annotating asks in annotationInput for a note to attach to the
+	// current word. annotationsOpen lists the book's notes at positions in
+	// annotationList, beside the reader like the table of contents.
+	annotating      bool
+	annotationInput textinput.Model
+	annotationsOpen bool
+	annotationList  list.Model
+
+	renderer reader.WordRenderer
+	presets  []preset
+	keys     keymap
+	theme    theme.Theme
+	speaker  tts.Speaker
+	goal     *sessionGoal
+	eyes     *eyeBreak
+	lock     *focusLock
+	recap    *chapterCheckpoint
+	stats    *sessionStats
+	frames   *frameStats
+	marks    *sessionMarks
+
+	// night switches to the night theme while --night says to.
+	night *nightSwitch
+
+	// finishAt is the percentage of the document past which it counts as
+	// finished (--finish-at).
+	finishAt float64
+
+	// overflow is how words too wide for the terminal are shown. marquee
+	// counts the moves made scrolling the current word, and showLong
+	// shows it whole, wrapped, while paused.
+	overflow overflowMode
+	marquee  int
+	showLong bool
+
+	// server is set by brr serve, which is controlled through remoteMsg
+	// and keeps running when a document ends.
+	server *remoteServer
 }
 
 type tickMsg time.Time
 
+// marqueeMsg moves the marquee of an overflowing word on by one character.
+type marqueeMsg struct{}
+
+// notesEditedMsg reports that $EDITOR has exited after editing the
+// book's notes.
+type notesEditedMsg struct{ err error }
+
+// eyeBreakMsg counts down an eye break.
+type eyeBreakMsg struct{}
+
+func eyeBreakTick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg { return eyeBreakMsg{} })
+}
+
+// lockMsg ends the focus lock.
+type lockMsg struct{}
+
+// translatedMsg delivers the translation of the sentence starting at
+// start.
+type translatedMsg struct {
+	start int
+	text  string
+	err   error
+}
+
+// nightMsg checks whether night mode has come or gone.
+type nightMsg struct{}
+
+func nightTick() tea.Cmd {
+	return tea.Tick(nightCheckInterval, func(time.Time) tea.Msg { return nightMsg{} })
+}
+
+// updateNight switches to or from the night theme when night mode has
+// come or gone, whatever else is open.
+func (m model) updateNight() (tea.Model, tea.Cmd) {
+	if th, ok := m.night.update(time.Now(), systemDark, m.theme); ok {
+		m.theme = th
+		applyTheme(th)
+		m.notice = m.night.notice()
+	}
+	return m, nightTick()
+}
+
+// writeClipboard copies text to the system clipboard; tests replace it.
+var writeClipboard = clipboard.WriteAll
+
 func (m model) Init() tea.Cmd {
-	return tick(m.GetDelay())
+	cmds := []tea.Cmd{m.next()}
+	if title := m.book.String(); title != "" {
+		cmds = append(cmds, tea.SetWindowTitle(title+" - brr"))
+	}
+	if m.night != nil && m.night.mode.changes() {
+		cmds = append(cmds, nightTick())
+	}
+	if left := m.lock.left(time.Now()); left > 0 {
+		cmds = append(cmds, tea.Tick(left, func(time.Time) tea.Msg { return lockMsg{} }))
+	}
+	return tea.Batch(cmds...)
+}
+
+// next schedules what follows the current word: marquee moves while an
+// overflowing word scrolls, then the tick to the next word.
+func (m model) next() tea.Cmd {
+	if m.overflow == overflowMarquee && m.marquee < marqueeSteps(m.CurrentWord(), m.width-2) {
+		return tea.Tick(marqueeStep, func(time.Time) tea.Msg { return marqueeMsg{} })
+	}
+	return tick(m.WordDelay())
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	updated, cmd := m.update(msg)
+	if m, ok := updated.(model); ok {
+		if notice := m.skipped.notice(); notice != "" {
+			m.notice = notice
+		}
+		if translating := m.translatePaused(); translating != nil {
+			return m, tea.Batch(cmd, translating)
+		}
+		return m, cmd
+	}
+	return updated, cmd
+}
+
+// translatePaused starts translating the sentence paused in, with
+// --translate, unless it is translated already or on its way.
+func (m *model) translatePaused() tea.Cmd {
+	if m.translator == nil || !m.Paused || len(m.Words) == 0 || m.resume != nil || m.conflict != nil || m.huge != nil {
+		return nil
+	}
+	start, sentence := pausedSentence(m.Reader)
+	if m.translation != nil && m.translation.start == start {
+		return nil
+	}
+	m.translation = &sentenceTranslation{start: start, pending: true}
+	translator := m.translator
+	return func() tea.Msg {
+		text, err := translator.Translate(context.Background(), sentence)
+		return translatedMsg{start: start, text: text, err: err}
+	}
+}
+
+func (m model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(translatedMsg); ok {
+		if m.translation != nil && m.translation.start == msg.start {
+			m.translation = &sentenceTranslation{start: msg.start, text: msg.text, err: msg.err}
+		}
+		return m, nil
+	}
+	if msg, ok := msg.(remoteMsg); ok {
+		return m.handleRemote(msg)
+	}
+	if _, ok := msg.(nightMsg); ok {
+		return m.updateNight()
+	}
+	switch msg := msg.(type) {
+	case loadProgressMsg:
+		return m.updateLoading(msg)
+	case loadedMsg:
+		// The parts read early already hold the document.
+		if msg.err != nil && !errors.Is(msg.err, context.Canceled) {
+			m.notice = "Loading stopped: " + msg.err.Error()
+		}
+		if msg.err == nil {
+			m.loaded = true
+			m.recordWhenLoaded()
+		}
+		return m, nil
+	}
 	if m.tocVisible {
 		return m.updateTOC(msg)
 	}
+	if m.searching {
+		return m.updateSearch(msg)
+	}
+	if m.goingTo {
+		return m.updateGoTo(msg)
+	}
+	if m.notesOpen {
+		return m.updateNotes(msg)
+	}
+	if m.annotating {
+		return m.updateAnnotating(msg)
+	}
+	if m.annotationsOpen {
+		return m.updateAnnotations(msg)
+	}
+	if _, ok := msg.(tea.KeyMsg); ok && m.handoff != nil {
+		m.handoff = nil
+		return m, nil
+	}
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
+		if m.eyes.active() {
+			// SPACE skips the break and reads on; only quitting also works.
+			if s := msg.String(); s == " " {
+				m.eyes.end(m.stats, true)
+			} else if s != "q" && s != "Q" && s != "ctrl+c" {
+				return m, nil
+			}
+		}
+		if m.resume != nil {
+			// Only the answers to the resume prompt, or quitting.
+			if notice, read, ok := m.resume.resolve(msg.String(), m.Reader); ok {
+				m.resume = nil
+				m.notice = notice
+				if m.huge != nil {
+					m.notice = m.huge.prompt(m.WPM)
+				} else if read {
+					m.ResumeWithRewind()
+					m.speak(m.CurrentIndex)
+					return m, m.next()
+				}
+				return m, nil
+			}
+			if s := msg.String(); s != "q" && s != "Q" && s != "ctrl+c" {
+				return m, nil
+			}
+		} else if m.conflict != nil {
+			// Likewise for the sync conflict prompt.
+			if notice, ok := m.conflict.resolve(msg.String(), m.Reader, m.stateStore, m.fileHash); ok {
+				m.conflict = nil
+				m.notice = notice
+				if m.huge != nil {
+					m.notice = m.huge.prompt(m.WPM)
+				}
+				return m, nil
+			}
+			if s := msg.String(); s != "q" && s != "Q" && s != "ctrl+c" {
+				return m, nil
+			}
+		} else if m.huge != nil {
+			// And for the prompt over a document's length.
+			if notice, ok := m.huge.resolve(msg.String(), m.Reader); ok {
+				m.huge = nil
+				m.notice = notice
+				if len(m.tocList.Items()) != len(m.TOC) {
+					return m, m.tocList.SetItems(tocItems(m.TOC))
+				}
+				return m, nil
+			}
+			if s := msg.String(); s != "q" && s != "Q" && s != "ctrl+c" {
+				return m, nil
+			}
+		}
+		m.notice = ""
+		m.showLong = false
+		switch m.keys.translate(msg.String()) {
 		case " ":
-			m.Paused = !m.Paused
-			if !m.Paused {
-				return m, tick(m.GetDelay())
+			if m.Paused {
+				m.ResumeWithRewind()
+				m.speak(m.CurrentIndex)
+				return m, m.next()
 			}
+			m.Pause()
+			m.stopSpeech()
 			return m, nil
 
 		case "+", "=":
@@ -134,6 +483,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			m.LastArrowPress = now
 			m.JumpToPrevSentence()
+			m.stats.regress()
 			return m, nil
 
 		case "right":
@@ -145,22 +495,141 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.JumpToNextSentence()
 			return m, nil
 
+		case "]":
+			m.skipRest(false)
+			return m, nil
+
+		case "}":
+			m.skipRest(true)
+			return m, nil
+
 		case "t":
 			if len(m.TOC) > 0 {
+				if now := time.Now(); !m.lock.hold("t", now) {
+					m.notice = m.lock.notice("T", "open the contents", now)
+					return m, nil
+				}
 				m.tocVisible = true
 				m.Paused = true
 			}
 			return m, nil
 
+		case "b":
+			m.renderer = nextRenderer(m.renderer, m.parallel)
+			return m, nil
+
+		case "s":
+			m.StopwordMode = m.StopwordMode.Next()
+			m.notice = "Function words: " + m.StopwordMode.String()
+			return m, nil
+
+		case "c":
+			m.theme = theme.Next(m.theme)
+			applyTheme(m.theme)
+			m.notice = "Theme: " + m.theme.Name
+			return m, nil
+
+		case "/":
+			m.searching = true
+			m.Paused = true
+			m.searchInput.SetValue("")
+			return m, m.searchInput.Focus()
+
+		case "g":
+			m.goingTo = true
+			m.Paused = true
+			m.jumpInput.SetValue("")
+			return m, m.jumpInput.Focus()
+
+		case "o":
+			return m, m.openNotes()
+
+		case "n":
+			if m.SearchQuery != "" && !m.SearchNext() {
+				m.notice = "No matches for " + m.SearchQuery
+			}
+			return m, nil
+
+		case "i":
+			return m, m.startAnnotation()
+
+		case "l":
+			return m, m.openAnnotations()
+
+		case "N":
+			if m.SearchQuery != "" && !m.SearchPrev() {
+				m.notice = "No matches for " + m.SearchQuery
+			}
+			return m, nil
+
+		case "a":
+			m.capture()
+			return m, nil
+
+		case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+			if p, ok := presetKey(m.presets, msg.String()); ok {
+				m.notice = p.apply(m.Reader, &m.renderer)
+			}
+			return m, nil
+
+		case "m":
+			if len(m.Words) > 0 {
+				added := m.marks.add(newMark(m.Reader, time.Now()), m.stateStore, m.fileHash)
+				m.notice = m.marks.markNotice(added)
+			}
+			return m, nil
+
+		case "enter":
+			if marqueeSteps(m.CurrentWord(), m.width-2) > 0 {
+				m.Pause()
+				m.stopSpeech()
+				m.showLong = true
+			}
+			return m, nil
+
+		case "h":
+			if m.Paused && len(m.Words) > 0 {
+				m.showHandoff()
+			}
+			return m, nil
+
+		case "y":
+			if m.Paused && len(m.Words) > 0 {
+				if err := writeClipboard(sentenceQuote(m.Reader)); err != nil {
+					m.notice = "Could not copy: " + err.Error()
+				} else {
+					m.notice = "Copied sentence to clipboard"
+				}
+			}
+			return m, nil
+
+		case "u":
+			if !m.Back() {
+				m.notice = "No earlier position"
+			}
+			return m, nil
+
+		case "ctrl+r":
+			if !m.Forward() {
+				m.notice = "No later position"
+			}
+			return m, nil
+
 		case "r":
-			m.CurrentIndex = 0
+			m.Jump(m.Start())
 			if m.stateStore != nil && m.fileHash != "" {
-				m.stateStore.Clear(m.fileHash)
+				m.stateStore.SetPosition(m.fileHash, m.Start())
 			}
 			return m, nil
 
 		case "q", "Q", "ctrl+c":
+			if now := time.Now(); !m.lock.hold(msg.String(), now) {
+				m.notice = m.lock.notice("Q", "quit", now)
+				return m, nil
+			}
+			m.stopSpeech()
 			m.savePosition()
+			m.cancelLoading()
 			m.quitting = true
 			return m, tea.Quit
 		}
@@ -173,28 +642,125 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tickMsg:
 		if m.Paused {
+			m.stopSpeech()
 			return m, nil
 		}
 
+		late := time.Since(time.Time(msg))
+		delay := m.WordDelay()
+		m.recap.advance(delay)
 		if m.Advance() {
+			m.marquee = 0
+			m.stats.advance(m.Reader, delay)
+			if notice := m.frames.shown(m.Reader, late); notice != "" {
+				m.notice = notice
+			}
+			// The word counts toward the goal even when a chapter pause
+			// follows it.
+			reached := m.goal.advance(delay)
+			if m.Paused {
+				// Paused at a new chapter by --checkpoints or
+				// --pause-at-chapters.
+				m.stopSpeech()
+				if m.notice = m.recap.take(); m.notice == "" {
+					m.notice = chapterEndNotice(m.Reader)
+				}
+				if reached {
+					m.goalReached()
+				}
+				return m, nil
+			}
+			if m.Frame() == 0 && m.IsSentenceStart(m.CurrentIndex) {
+				m.speak(m.CurrentIndex)
+			}
+			if reached {
+				m.goalReached()
+				return m, nil
+			}
+			if m.eyes.advance(delay) {
+				m.Pause()
+				m.stopSpeech()
+				m.eyes.start(time.Now())
+				return m, eyeBreakTick()
+			}
+			return m, m.next()
+		}
+		if m.Waiting() {
 			return m, tick(m.GetDelay())
 		}
+		if recap := m.recap.end(m.Reader); recap != "" {
+			m.Pause()
+			m.stopSpeech()
+			m.notice = recap
+			return m, nil
+		}
 
 		m.savePosition()
+		if m.server != nil {
+			return m.finished()
+		}
+		m.cancelLoading()
 		m.quitting = true
 		return m, tea.Quit
+
+	case lockMsg:
+		m.notice = lockOverNotice
+		return m, nil
+
+	case eyeBreakMsg:
+		if !m.eyes.active() {
+			return m, nil
+		}
+		if m.eyes.left(time.Now()) > 0 {
+			return m, eyeBreakTick()
+		}
+		m.eyes.end(m.stats, false)
+		m.notice = breakOverNotice
+		return m, nil
+
+	case marqueeMsg:
+		if m.Paused {
+			return m, nil
+		}
+		m.marquee++
+		return m, m.next()
 	}
 
 	return m, nil
 }
 
 func (m model) updateTOC(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.order != nil {
+		return m.updateReadingOrder(msg)
+	}
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.tocPreview != nil {
+			// Enter goes to the chapter previewed; any other key closes
+			// the preview.
+			if msg.String() == "enter" {
+				m.JumpToTOC(m.tocPreview.index)
+				m.tocVisible = false
+			}
+			m.tocPreview = nil
+			return m, nil
+		}
+		if m.tocList.FilterState() != list.Filtering {
+			switch msg.String() {
+			case "e":
+				m.order = newReadingOrder(m.Reader)
+				return m, nil
+			case "p":
+				if item, ok := m.tocList.SelectedItem().(tocItem); ok {
+					m.tocPreview = &item
+				}
+				return m, nil
+			}
+		}
 		switch msg.String() {
 		case "enter":
 			if item, ok := m.tocList.SelectedItem().(tocItem); ok {
-				m.JumpToChapter(item.entry.WordIndex)
+				m.JumpToTOC(item.index)
 			}
 			m.tocVisible = false
 			return m, nil
@@ -216,105 +782,616 @@ func (m model) updateTOC(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
-func (m *model) savePosition() {
-	if m.stateStore != nil && m.fileHash != "" {
-		m.stateStore.SetPosition(m.fileHash, m.CurrentIndex)
-	}
-}
-
-func (m model) View() string {
-	if m.quitting {
-		if m.AtEnd() {
-			return completeStyle.Render("\n  Reading complete!\n")
+// updateReadingOrder handles keys in the reading order editor.
+func (m model) updateReadingOrder(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			m.order.selectBy(-1)
+		case "down", "j":
+			m.order.selectBy(1)
+		case "shift+up", "K":
+			m.order.move(-1)
+		case "shift+down", "J":
+			m.order.move(1)
+		case " ", "x":
+			m.order.toggle()
+		case "d", "D":
+			m.order.reset()
+		case "enter":
+			m.notice = applyReadingOrder(m.Reader, m.stateStore, m.fileHash, m.order.sequence())
+			m.order = nil
+			m.tocVisible = false
+		case "esc":
+			m.order = nil
+		case "ctrl+c":
+			m.cancelLoading()
+			return m, tea.Quit
 		}
-		return ""
-	}
 
-	if len(m.Words) == 0 {
-		return "No text to read."
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
 	}
+	return m, nil
+}
 
-	if m.tocVisible {
-		return m.viewWithTOC()
+func (m model) updateSearch(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "enter":
+			m.searching = false
+			m.searchInput.Blur()
+			if query := strings.TrimSpace(m.searchInput.Value()); query != "" && !m.Search(query) {
+				m.notice = "No matches for " + query
+			}
+			return m, nil
+
+		case "esc", "ctrl+c":
+			m.searching = false
+			m.searchInput.Blur()
+			return m, nil
+		}
 	}
 
-	return m.viewReading(m.width)
+	var cmd tea.Cmd
+	m.searchInput, cmd = m.searchInput.Update(msg)
+	return m, cmd
 }
 
-func (m model) viewReading(width int) string {
-	word := m.CurrentWord()
-	formatted := formatWord(word)
-
-	pause := ""
-	if m.Paused {
-		pause = pausedStyle.Render(" [PAUSED]")
-	}
+func (m model) updateGoTo(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "enter":
+			m.goingTo = false
+			m.jumpInput.Blur()
+			if input := strings.TrimSpace(m.jumpInput.Value()); input != "" {
+				if p, err := parsePosition(input); err != nil {
+					m.notice = "Cannot go to " + input + ": use a percentage such as 45% or a word number"
+				} else {
+					m.notice = goTo(m.Reader, p)
+				}
+			}
+			return m, nil
 
-	current, total := m.Progress()
-	chapterInfo := ""
-	if title := m.CurrentChapterTitle(); title != "" {
-		chapterInfo = fmt.Sprintf(" | %s", title)
+		case "esc", "ctrl+c":
+			m.goingTo = false
+			m.jumpInput.Blur()
+			return m, nil
+		}
 	}
-	status := statusStyle.Render(
-		fmt.Sprintf("Word %d/%d | %d WPM%s%s",
-			current,
-			total,
-			m.WPM,
-			pause,
-			chapterInfo,
-		),
-	)
 
-	tocHint := ""
-	if len(m.TOC) > 0 {
-		tocHint = "  T: TOC"
-	}
-	controls := controlsStyle.Render("SPACE: pause  ↑/↓: speed  ←/→: sentence  R: restart" + tocHint + "  Q: quit")
+	var cmd tea.Cmd
+	m.jumpInput, cmd = m.jumpInput.Update(msg)
+	return m, cmd
+}
 
-	avail := m.height - 2
-	if avail < 1 {
-		avail = 1
+// openNotes pauses and opens the book's scratch notes for editing.
+func (m *model) openNotes() tea.Cmd {
+	if m.stateStore == nil || m.fileHash == "" {
+		m.notice = noNotesNotice
+		return nil
 	}
-	vPad := avail / 2
-	if vPad < 0 {
-		vPad = 0
+	notes, err := m.stateStore.Notes(m.fileHash)
+	if err != nil {
+		m.notice = "Could not read notes: " + err.Error()
+		return nil
 	}
+	m.Pause()
+	m.stopSpeech()
+	m.notesOpen = true
+	m.notesInput.SetWidth(m.width - 2)
+	m.notesInput.SetHeight(max(m.height-4, 1))
+	m.notesInput.SetValue(notes)
+	return m.notesInput.Focus()
+}
 
-	var sb strings.Builder
-
-	sb.WriteString(status)
-	sb.WriteString("\n")
+func (m model) updateNotes(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "ctrl+c":
+			m.notice = "Notes saved"
+			if err := m.stateStore.SetNotes(m.fileHash, m.notesInput.Value()); err != nil {
+				m.notice = "Could not save notes: " + err.Error()
+			}
+			m.notesOpen = false
+			m.notesInput.Blur()
+			return m, nil
 
-	for i := 0; i < vPad; i++ {
-		sb.WriteString("\n")
-	}
+		case "ctrl+e":
+			// The editor opens what has been typed so far.
+			if err := m.stateStore.SetNotes(m.fileHash, m.notesInput.Value()); err != nil {
+				m.notice = "Could not save notes: " + err.Error()
+				return m, nil
+			}
+			return m, tea.ExecProcess(editorCommand(m.stateStore.NotesPath(m.fileHash)), func(err error) tea.Msg {
+				return notesEditedMsg{err}
+			})
+		}
+		m.notice = ""
 
-	line := anchorORPText(formatted, word, width)
-	sb.WriteString(line)
+	case notesEditedMsg:
+		if msg.err != nil {
+			m.notice = "Editor failed: " + msg.err.Error()
+		}
+		if notes, err := m.stateStore.Notes(m.fileHash); err == nil {
+			m.notesInput.SetValue(notes)
+		}
+		return m, nil
 
-	remaining := avail - vPad
-	for i := 0; i < remaining; i++ {
-		sb.WriteString("\n")
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.tocList.SetSize(m.width/3-4, m.height-4)
+		m.notesInput.SetWidth(m.width - 2)
+		m.notesInput.SetHeight(max(m.height-4, 1))
+		return m, nil
 	}
 
-	sb.WriteString(controls)
-
-	return sb.String()
+	var cmd tea.Cmd
+	m.notesInput, cmd = m.notesInput.Update(msg)
+	return m, cmd
 }
 
-func (m model) viewWithTOC() string {
-	tocWidth := m.width / 3
-	readingWidth := m.width - tocWidth - 1
+// startAnnotation pauses and asks for a note to attach to the current
+// word.
+func (m *model) startAnnotation() tea.Cmd {
+	if len(m.Words) == 0 {
+		return nil
+	}
+	if m.stateStore == nil || m.fileHash == "" {
+		m.notice = noNotesNotice
+		return nil
+	}
+	m.Pause()
+	m.stopSpeech()
+	m.annotating = true
+	m.annotationInput.SetValue("")
+	return m.annotationInput.Focus()
+}
+
+func (m model) updateAnnotating(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "enter":
+			m.annotating = false
+			m.annotationInput.Blur()
+			m.notice = addAnnotation(m.stateStore, m.fileHash, m.Reader, m.annotationInput.Value(), time.Now())
+			return m, nil
+
+		case "esc", "ctrl+c":
+			m.annotating = false
+			m.annotationInput.Blur()
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.annotationInput, cmd = m.annotationInput.Update(msg)
+	return m, cmd
+}
+
+// openAnnotations pauses and lists the book's notes at positions.
+func (m *model) openAnnotations() tea.Cmd {
+	if m.stateStore == nil || m.fileHash == "" {
+		m.notice = noNotesNotice
+		return nil
+	}
+	annotations := m.stateStore.Annotations(m.fileHash)
+	if len(annotations) == 0 {
+		m.notice = noAnnotationsNotice
+		return nil
+	}
+	items := make([]list.Item, len(annotations))
+	for i, a := range annotations {
+		items[i] = annotationItem{annotation: a}
+	}
+	m.annotationsOpen = true
+	m.Pause()
+	m.stopSpeech()
+	m.annotationList.ResetFilter()
+	m.annotationList.Select(0)
+	return m.annotationList.SetItems(items)
+}
+
+func (m model) updateAnnotations(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.annotationList.FilterState() == list.Filtering {
+			break
+		}
+		switch msg.String() {
+		case "enter":
+			if item, ok := m.annotationList.SelectedItem().(annotationItem); ok {
+				m.notice = goToAnnotation(m.Reader, item.annotation)
+			}
+			m.annotationsOpen = false
+			return m, nil
+
+		case "l", "esc", "q":
+			m.annotationsOpen = false
+			return m, nil
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.tocList.SetSize(m.width/3-4, m.height-4)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.annotationList, cmd = m.annotationList.Update(msg)
+	return m, cmd
+}
+
+// viewNotes shows the book's scratch notes being edited.
+func (m model) viewNotes() string {
+	title := "Notes"
+	if m.book.Title != "" {
+		title += ": " + m.book.Title
+	}
+	controls := "ESC: save and close  Ctrl+E: open in $EDITOR"
+	if m.notice != "" {
+		controls = m.notice
+	}
+	return lipgloss.JoinVertical(lipgloss.Left,
+		" "+wordBoldStyle.Render(title),
+		m.notesInput.View(),
+		controlsStyle.Render(controls),
+	)
+}
+
+// skipRest skips the rest of the sentence or paragraph while reading goes
+// on, reading the next sentence aloud when TTS is enabled.
+func (m *model) skipRest(paragraph bool) {
+	if len(m.Words) == 0 {
+		return
+	}
+	m.notice = skipRest(m.Reader, m.stats, paragraph)
+	if !m.Paused {
+		m.stopSpeech()
+		m.speak(m.CurrentIndex)
+	}
+}
+
+// speak reads the sentence from idx aloud when TTS is enabled.
+func (m *model) speak(idx int) {
+	if m.speaker != nil {
+		m.speaker.Speak(m.SentenceFrom(idx), m.WPM)
+	}
+}
+
+func (m *model) stopSpeech() {
+	if m.speaker != nil {
+		m.speaker.Stop()
+	}
+}
+
+// goalReached pauses reading and announces the session goal.
+func (m *model) goalReached() {
+	m.Pause()
+	m.stopSpeech()
+	m.notice = "Goal reached! Press SPACE to keep reading."
+	if m.stateStore != nil && m.fileHash != "" {
+		m.stateStore.RecordGoal(m.fileHash)
+	}
+}
+
+func (m *model) savePosition() {
+	// A document read early could overwrite the saved position with one
+	// short of it, so nothing is saved until it has loaded.
+	if m.stateStore != nil && m.fileHash != "" && !m.Incomplete {
+		p := state.Progress{
+			Settings:  currentSettings(m.Reader, m.renderer),
+			WordIndex: m.Position(),
+			Session:   m.session,
+		}
+		if !m.Streaming() {
+			p.Bookmark = &state.Bookmark{
+				Chapter:  m.ChapterIndex(m.CurrentIndex),
+				Sentence: m.SentenceIndex(m.CurrentIndex),
+			}
+		}
+		if bookFinished(m.Reader, m.finishAt) {
+			p.Finished = time.Now()
+		}
+		m.stateStore.SaveProgress(m.fileHash, p)
+	}
+}
+
+// capture appends the current sentence to the capture file and reports the
+// result in the status line.
+func (m *model) capture() {
+	if m.capturePath == "" {
+		m.notice = "Set --capture FILE to capture sentences"
+		return
+	}
+	if len(m.Words) == 0 {
+		return
+	}
+	path, err := captureSentence(m.Reader, m.capturePath, m.captureTemplate, m.sourceFile, time.Now())
+	if err != nil {
+		m.notice = "Could not capture: " + err.Error()
+		return
+	}
+	m.notice = "Captured to " + path
+}
+
+// showHandoff shows a QR code of a link to the current position, for
+// reading on from there on another device.
+func (m *model) showHandoff() {
+	if m.fileHash == "" {
+		m.notice = noHandoffNotice
+		return
+	}
+	link := handoffLink(m.handoffURL, m.fileHash, m.Reader)
+	code, err := handoffCode(link)
+	if err != nil {
+		m.notice = "Could not make a QR code: " + err.Error()
+		return
+	}
+	m.handoff = &handoffScreen{link: link, code: qrBlocks(code)}
+}
+
+func (m model) View() string {
+	if m.quitting {
+		if bookFinished(m.Reader, m.finishAt) {
+			return completeStyle.Render("\n  Reading complete!\n")
+		}
+		return ""
+	}
+
+	if len(m.Words) == 0 && !m.Waiting() {
+		if m.server != nil {
+			return "Waiting for a document (POST /load)"
+		}
+		return "No text to read."
+	}
+
+	if m.eyes.active() {
+		return m.viewEyeBreak()
+	}
+
+	if m.notesOpen {
+		return m.viewNotes()
+	}
+
+	if m.handoff != nil {
+		return m.viewHandoff()
+	}
+
+	if m.tocVisible || m.annotationsOpen {
+		return m.viewWithTOC()
+	}
+
+	return m.viewReading(m.width)
+}
+
+// viewHandoff shows the QR code of a handoff link, with the link for
+// terminals too small to show the code.
+func (m model) viewHandoff() string {
+	code := handoffStyle.Render(strings.Join(m.handoff.code, "\n"))
+	if lipgloss.Width(code) > m.width || lipgloss.Height(code)+4 > m.height {
+		code = "Enlarge the terminal to show the QR code"
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center,
+		lipgloss.JoinVertical(lipgloss.Center, code, "", m.handoff.link, "", controlsStyle.Render("Scan to read on from here. Any key: close")))
+}
+
+// viewEyeBreak shows the countdown of an eye break.
+func (m model) viewEyeBreak() string {
+	lines := m.eyes.screen(time.Now())
+	var blocks []string
+	for i, line := range lines {
+		switch i {
+		case 0:
+			line = wordBoldStyle.Render(line)
+		case len(lines) - 1:
+			line = controlsStyle.Render(line)
+		}
+		blocks = append(blocks, line, "")
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center,
+		lipgloss.JoinVertical(lipgloss.Center, blocks[:len(blocks)-1]...))
+}
+
+func (m model) viewReading(width int) string {
+	word := m.CurrentWord()
+	formatted := formatWord(word, m.renderer)
+
+	pause := ""
+	if m.Paused {
+		pause = pausedStyle.Render(" [PAUSED]")
+	} else if m.Waiting() {
+		pause = pausedStyle.Render(" [WAITING]")
+	}
+
+	current, total := m.Progress()
+	chapterInfo := ""
+	if title := m.CurrentChapterTitle(); title != "" {
+		chapterInfo = fmt.Sprintf(" | %s", title)
+	}
+	if cur, n := m.ChapterProgress(); len(m.Chapters) > 1 {
+		chapterInfo += fmt.Sprintf(" | Word %d/%d in this chapter", cur, n)
+	}
+	goalInfo := ""
+	if g := m.goal.status(); g != "" {
+		goalInfo = " | " + g
+	}
+	if l := m.lock.status(time.Now()); l != "" {
+		goalInfo += " | " + l
+	}
+	bookInfo := ""
+	if title := m.book.String(); title != "" {
+		bookInfo = title + " | "
+	}
+	status := statusStyle.Render(
+		fmt.Sprintf("%sWord %d/%d | %d WPM%s%s%s",
+			bookInfo,
+			current,
+			total,
+			m.WPM,
+			pause,
+			chapterInfo,
+			goalInfo,
+		),
+	)
+
+	tocHint := ""
+	if len(m.TOC) > 0 {
+		tocHint = "  T: TOC"
+	}
+	copyHint := ""
+	if m.Paused {
+		copyHint = "  Y: copy"
+	}
+	controls := controlsStyle.Render("SPACE: pause  ↑/↓: speed  ←/→: sentence  /: search  B: mode  C: theme  R: restart" + tocHint + copyHint + "  Q: quit")
+	if m.searching {
+		controls = m.searchInput.View()
+	} else if m.goingTo {
+		controls = m.jumpInput.View()
+	} else if m.annotating {
+		controls = m.annotationInput.View()
+	} else if m.notice != "" {
+		controls = pausedStyle.Render(m.notice)
+	}
+
+	avail := m.height - 2
+	if avail < 1 {
+		avail = 1
+	}
+	vPad := avail / 2
+	if vPad < 0 {
+		vPad = 0
+	}
+
+	rows := make([]string, avail)
+	switch capacity := width - 2; {
+	case marqueeSteps(word, capacity) == 0:
+		rows[vPad] = anchorORPText(formatted, word, width)
+		if sr, ok := m.renderer.(reader.StripRenderer); ok {
+			prev, next := m.WordsAround(sr.Strip())
+			rows[vPad] = stripRow(prev, formatted, word, next, width)
+		}
+	case m.showLong:
+		// The whole word, wrapped onto as many rows as it needs.
+		runes := []rune(word)
+		for i := 0; i < len(runes) && vPad+i/capacity < avail; i += capacity {
+			rows[vPad+i/capacity] = " " + wordBoldStyle.Render(string(runes[i:min(i+capacity, len(runes))]))
+		}
+	case m.overflow == overflowPlaceholder:
+		rows[vPad] = lipgloss.PlaceHorizontal(width, lipgloss.Center, contextStyle.Render(longTokenPlaceholder))
+	default:
+		rows[vPad] = " " + wordBoldStyle.Render(marqueeWindow(word, capacity, m.marquee))
+	}
+	if cr, ok := m.renderer.(reader.ContextRenderer); ok {
+		// Neighbouring words fill the rows above and below, each anchored
+		// at its own ORP so the column stays fixed.
+		prev, next := m.WordsAround(cr.Context())
+		for i, w := range prev {
+			if row := vPad - len(prev) + i; row >= 0 {
+				rows[row] = anchorORPText(contextStyle.Render(visualWord(w)), w, width)
+			}
+		}
+		for i, w := range next {
+			if row := vPad + 1 + i; row < avail {
+				rows[row] = anchorORPText(contextStyle.Render(visualWord(w)), w, width)
+			}
+		}
+	}
+	if line := parallelLine(m.Reader, m.renderer, m.parallel); line != "" {
+		// The translation of the sentence, wrapped onto the rows below the
+		// word as far as they go.
+		wrapped := contextStyle.Italic(true).Width(max(width-6, 1)).Align(lipgloss.Center).Render(line)
+		for i, l := range strings.Split(wrapped, "\n") {
+			if row := vPad + 2 + i; row < avail {
+				rows[row] = lipgloss.PlaceHorizontal(width, lipgloss.Center, l)
+			}
+		}
+	}
+	if m.Paused {
+		m.viewPaused(rows, vPad, width)
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(status)
+	sb.WriteString("\n")
+	sb.WriteString(strings.Join(rows, "\n"))
+	sb.WriteString("\n")
+	sb.WriteString(controls)
+
+	return sb.String()
+}
+
+// pauseHints are the keys the pause dashboard suggests, besides those on
+// the controls line.
+const pauseHints = "G: go to  M: mark  I: note  O: notes  U: undo jump  A: capture  H: hand off"
+
+// viewPaused fills the rows around the word at row vPad with the pause
+// dashboard: the chapter above the word, the rest of its sentence below,
+// and the session's figures and more keys at the bottom, clear of it. Where
+// rows are short, the figures come first, then the sentence, then the keys.
+// Rows already showing something, such as neighbouring words, are left
+// alone.
+func (m model) viewPaused(rows []string, vPad, width int) {
+	d := newPauseDashboard(m.Reader, m.stats)
+	set := func(row int, line string) {
+		if row >= 0 && row < len(rows) && rows[row] == "" {
+			rows[row] = lipgloss.PlaceHorizontal(width, lipgloss.Center, line)
+		}
+	}
+	if d.heading != "" {
+		set(vPad-2, tocTitleStyle.Render(d.heading))
+	}
+	free := len(rows) - vPad - 1
+	figures := free > len(d.lines)
+	if figures {
+		free -= len(d.lines)
+	}
+	if (d.before != "" || d.after != "") && free > 2 {
+		before, after := d.around(width - 6 - lipgloss.Width(d.word))
+		set(vPad+2, contextStyle.Render(before+" ")+wordBoldStyle.Render(visualWord(d.word))+contextStyle.Render(" "+after))
+		free -= 2
+		if line := m.translation.line(m.Reader); line != "" && free > 1 {
+			set(vPad+3, contextStyle.Italic(true).MaxWidth(width-6).Render(line))
+			free--
+		}
+	}
+	bottom := len(rows)
+	if figures && free > 2 {
+		set(len(rows)-1, controlsStyle.Render(pauseHints))
+		bottom -= 2
+	}
+	if figures {
+		for i, line := range d.lines {
+			set(bottom-len(d.lines)+i, contextStyle.Render(line))
+		}
+	}
+}
+
+func (m model) viewWithTOC() string {
+	tocWidth := m.width / 3
+	readingWidth := m.width - tocWidth - 1
 
 	tocPanel := m.renderTOCPanel(tocWidth, m.height)
+	if m.annotationsOpen {
+		tocPanel = m.renderAnnotationsPanel(tocWidth, m.height)
+	}
 	readingArea := m.viewReading(readingWidth)
+	if m.tocPreview != nil {
+		readingArea = m.viewTOCPreview(readingWidth)
+	}
 
 	return lipgloss.JoinHorizontal(lipgloss.Top, tocPanel, readingArea)
 }
 
 func (m model) renderTOCPanel(width, height int) string {
 	title := tocTitleStyle.Render("Table of Contents")
-	instructions := controlsStyle.Render("↑/↓: navigate  Enter: select  T/Esc: close")
+	instructions := controlsStyle.Render("↑/↓: navigate  Enter: select  P: preview  E: reading order  T/Esc: close")
 
 	listHeight := height - 4
 	if listHeight < 3 {
@@ -323,74 +1400,237 @@ func (m model) renderTOCPanel(width, height int) string {
 	m.tocList.SetSize(width-4, listHeight)
 
 	content := fmt.Sprintf("%s\n\n%s\n\n%s", title, m.tocList.View(), instructions)
+	if m.order != nil {
+		title = tocTitleStyle.Render("Reading order")
+		instructions = controlsStyle.Width(width - 4).Render(readingOrderHint)
+		lines := m.order.lines()
+		// Scroll to keep the selected chapter in view.
+		rows := max(listHeight-lipgloss.Height(instructions), 1)
+		first := min(max(m.order.cursor-rows/2, 0), max(len(lines)-rows, 0))
+		lines = lines[first:min(first+rows, len(lines))]
+		chapters := lipgloss.NewStyle().MaxWidth(width - 4).Render(strings.Join(lines, "\n"))
+		content = fmt.Sprintf("%s\n\n%s\n\n%s", title, chapters, instructions)
+	}
 
 	return tocPanelStyle.Width(width - 2).Height(height - 2).Render(content)
 }
 
-func formatWord(word string) string {
-	runes := []rune(word)
-	orp := reader.GetORPPosition(word)
-	if orp >= len(runes) {
-		orp = len(runes) - 1
-	}
-	if orp < 0 {
-		orp = 0
-	}
+// viewTOCPreview shows the opening words of the chapter previewed from the
+// table of contents, in place of the reader.
+func (m model) viewTOCPreview(width int) string {
+	text := lipgloss.NewStyle().Width(max(width-4, 1)).Render(chapterPreview(m.Reader, m.tocPreview.index))
+	content := fmt.Sprintf("%s\n\n%s\n\n%s",
+		tocTitleStyle.Render(m.tocPreview.entry.Title),
+		text,
+		controlsStyle.Render("Enter: go there  any other key: close"),
+	)
+	return lipgloss.NewStyle().Width(width).Height(m.height).Padding(1, 2).Render(content)
+}
+
+// renderAnnotationsPanel lists the book's notes at positions, in the
+// place of the table of contents.
+func (m model) renderAnnotationsPanel(width, height int) string {
+	title := tocTitleStyle.Render("Notes at positions")
+	instructions := controlsStyle.Render("↑/↓: navigate  Enter: go to  /: filter  L/Esc: close")
+	m.annotationList.SetSize(width-4, max(height-4, 3))
+	content := fmt.Sprintf("%s\n\n%s\n\n%s", title, m.annotationList.View(), instructions)
+	return tocPanelStyle.Width(width - 2).Height(height - 2).Render(content)
+}
 
-	before := string(runes[:orp])
-	focus := string(runes[orp])
-	after := ""
-	if orp+1 < len(runes) {
-		after = string(runes[orp+1:])
+// reverseRTL makes the terminal UI reverse right-to-left words for
+// display. It is turned off with --bidi for terminals that reorder
+// right-to-left text themselves.
+var reverseRTL = true
+
+// visualWord returns word as it should be written to the terminal.
+func visualWord(word string) string {
+	if !reverseRTL || !reader.IsRTL(word) {
+		return word
 	}
+	runes := []rune(word)
+	slices.Reverse(runes)
+	return string(runes)
+}
 
-	return wordBeforeStyle.Render(before) +
-		erpStyle.Render(focus) +
-		wordAfterStyle.Render(after)
+func formatWord(word string, renderer reader.WordRenderer) string {
+	segs := renderer.Segments(word)
+	if reverseRTL {
+		segs = reader.VisualSegments(word, segs)
+	}
+	var sb strings.Builder
+	for _, seg := range segs {
+		switch seg.Kind {
+		case reader.SegmentFocus:
+			sb.WriteString(erpStyle.Render(seg.Text))
+		case reader.SegmentBold:
+			sb.WriteString(wordBoldStyle.Render(seg.Text))
+		case reader.SegmentPunct:
+			sb.WriteString(contextStyle.Render(seg.Text))
+		default:
+			sb.WriteString(wordBeforeStyle.Render(seg.Text))
+		}
+	}
+	return sb.String()
 }
 
 func anchorORPText(text string, word string, width int) string {
+	return strings.Repeat(" ", orpPad(word, width)) + text
+}
+
+// orpPad returns the indent that puts word's ORP in the middle of a line
+// width cells wide.
+func orpPad(word string, width int) int {
 	anchor := width / 2
+	// Measure in terminal cells, since CJK characters are two cells wide.
+	// A right-to-left word shows the rest of the word left of its ORP,
+	// whether reversed here or by a bidi terminal.
+	runes := []rune(word)
 	orp := reader.GetORPPosition(word)
+	before := runes[:orp]
+	if reader.IsRTL(word) {
+		before = runes[orp+1:]
+	}
+	orp = lipgloss.Width(string(before))
 	pad := anchor - orp
 	if pad < 0 {
 		pad = 0
 	}
-	return strings.Repeat(" ", pad) + text
+	return pad
+}
+
+// ghostGap separates the ghost words of a preview strip from the current
+// word.
+const ghostGap = "   "
+
+// stripRow lays out the current word anchored at its ORP with the words
+// before and after it dimmed on either side, cut short with an ellipsis
+// where they run off the line.
+func stripRow(prev []string, formatted, word string, next []string, width int) string {
+	ghost := func(words []string) string {
+		visual := make([]string, len(words))
+		for i, w := range words {
+			visual[i] = visualWord(w)
+		}
+		return strings.Join(visual, " ")
+	}
+	pad := orpPad(word, width)
+	left := fitGhost(ghost(prev), pad-len(ghostGap), true)
+	right := fitGhost(ghost(next), width-pad-lipgloss.Width(word)-len(ghostGap), false)
+
+	var sb strings.Builder
+	if left != "" {
+		sb.WriteString(strings.Repeat(" ", pad-len(ghostGap)-lipgloss.Width(left)))
+		sb.WriteString(contextStyle.Render(left))
+		sb.WriteString(ghostGap)
+	} else {
+		sb.WriteString(strings.Repeat(" ", pad))
+	}
+	sb.WriteString(formatted)
+	if right != "" {
+		sb.WriteString(ghostGap)
+		sb.WriteString(contextStyle.Render(right))
+	}
+	return sb.String()
 }
 
+// fitGhost shortens s to at most n cells, dropping its start (fromLeft) or
+// end and marking the cut with an ellipsis.
+func fitGhost(s string, n int, fromLeft bool) string {
+	if lipgloss.Width(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && lipgloss.Width(string(runes))+1 > n {
+		if fromLeft {
+			runes = runes[1:]
+		} else {
+			runes = runes[:len(runes)-1]
+		}
+	}
+	if len(runes) == 0 {
+		return ""
+	}
+	if fromLeft {
+		return "…" + string(runes)
+	}
+	return string(runes) + "…"
+}
+
+// tick moves to the next word after d. Its tickMsg holds the time the
+// word is due, so late words can be counted.
 func tick(d time.Duration) tea.Cmd {
-	return tea.Tick(d, func(t time.Time) tea.Msg {
-		return tickMsg(t)
+	due := time.Now().Add(d)
+	return tea.Tick(d, func(time.Time) tea.Msg {
+		return tickMsg(due)
 	})
 }
 
 func newModel(text string, wpm int, toc []reader.TOCEntry, chapters []reader.Chapter) model {
 	r := reader.NewReader(text, wpm)
 	r.SetChapters(chapters, toc)
+	return newReaderModel(r)
+}
 
+// tocItems returns the entries of the table of contents list.
+func tocItems(toc []reader.TOCEntry) []list.Item {
 	items := make([]list.Item, len(toc))
 	for i, entry := range toc {
-		items[i] = tocItem{entry: entry}
+		items[i] = tocItem{entry: entry, index: i}
 	}
+	return items
+}
 
+// newReaderModel wraps an existing Reader, such as a streaming one.
+func newReaderModel(r *reader.Reader) model {
 	delegate := list.NewDefaultDelegate()
 	delegate.ShowDescription = true
 	delegate.SetHeight(2)
 
-	tocList := list.New(items, delegate, 30, 20)
+	tocList := list.New(tocItems(r.TOC), delegate, 30, 20)
 	tocList.Title = ""
 	tocList.SetShowTitle(false)
 	tocList.SetShowStatusBar(false)
 	tocList.SetFilteringEnabled(true)
 	tocList.SetShowHelp(false)
 
+	searchInput := textinput.New()
+	searchInput.Prompt = "/"
+	searchInput.Placeholder = "search"
+
+	jumpInput := textinput.New()
+	jumpInput.Prompt = "Go to: "
+	jumpInput.Placeholder = "45% or word number"
+
+	annotationInput := textinput.New()
+	annotationInput.Prompt = "Note: "
+	annotationInput.Placeholder = "a thought on this passage"
+
+	annotationList := list.New(nil, delegate, 30, 20)
+	annotationList.SetShowTitle(false)
+	annotationList.SetShowStatusBar(false)
+	annotationList.SetShowHelp(false)
+
+	notesInput := textarea.New()
+	notesInput.Placeholder = "Thoughts on the book so far"
+	notesInput.ShowLineNumbers = false
+	notesInput.CharLimit = 0
+
 	return model{
-		Reader:   r,
-		quitting: false,
-		width:    80,
-		height:   24,
-		tocList:  tocList,
+		Reader:      r,
+		quitting:    false,
+		width:       80,
+		height:      24,
+		tocList:     tocList,
+		searchInput: searchInput,
+		jumpInput:   jumpInput,
+		notesInput:  notesInput,
+		renderer:    reader.Renderers[0],
+		theme:       theme.Default(),
+		marks:       &sessionMarks{},
+		finishAt:    defaultFinishAt,
+
+		annotationInput: annotationInput,
+		annotationList:  annotationList,
 	}
 }
 
@@ -400,10 +1640,77 @@ func main() {
 	showVersionLong := flag.Bool("version", false, "Show version information")
 	showTOC := flag.Bool("toc", false, "Show table of contents at startup")
 	freshStart := flag.Bool("fresh", false, "Ignore saved reading position")
+	display := flag.String("display", "orp", "Word display mode: "+strings.Join(reader.RendererNames(), ", "))
+	themeName := flag.String("theme", envOr(themeEnv, "default"), "Color theme: "+strings.Join(theme.Names(), ", "))
+	nightName := flag.String("night", envOr(nightEnv, "off"), "Switch to the night theme: off, on, system (with the system's dark mode) or a daily time range such as 21:00-07:00")
+	ttsBackend := flag.String("tts", "", "Speak each sentence aloud using a speech backend: "+strings.Join(tts.Backends(), ", "))
+	streamInput := flag.Bool("stream", false, "Read words lazily instead of loading the whole input (automatic for plain text over 16MB and EPUBs with over 4MB of text)")
+	follow := flag.Bool("f", false, "Follow the file or stdin, waiting for appended text at the end")
+	followLong := flag.Bool("follow", false, "Follow the file or stdin, waiting for appended text at the end")
+	transcriptMode := flag.Bool("transcript", false, "Clean up a meeting or podcast transcript (drop fillers, cues and timestamps)")
+	goalMinutes := flag.Float64("minutes", 0, "Session goal: pause after this many minutes of reading")
+	goalWords := flag.Int("words", 0, "Session goal: pause after reading this many words")
+	eyeBreaks := flag.Duration("eye-breaks", 0, "Rest the eyes after every stretch of reading this long, such as 20m (0 disables)")
+	eyeBreakLength := flag.Duration("eye-break-length", 20*time.Second, "How long each eye break lasts")
+	lockFor := flag.Duration("lock", 0, "Keep quitting and the table of contents behind a held key for this long, such as 20m (0 disables)")
+	strict := flag.Bool("strict", false, "Fail on unreadable parts of a document instead of skipping them")
+	paragraphPause := flag.Float64("paragraph-pause", reader.DefaultParagraphPause, "Show the first word of a paragraph this many times longer (1 disables)")
+	chapterPause := flag.Float64("chapter-pause", reader.DefaultChapterPause, "Show the first word of a chapter this many times longer (1 disables)")
+	rewind := flag.Int("rewind", 1, "Sentences to back up when resuming after a long pause (0 disables)")
+	rewindAfter := flag.Duration("rewind-after", reader.DefaultRewindAfter, "Shortest pause after which resuming rewinds")
+	maxWordLength := flag.Int("max-word-length", reader.DefaultMaxWordLength, "Split longer words, such as URLs, into hyphenated parts (0 disables)")
+	maxWords := flag.Int("max-words", defaultMaxWords, "Ask before reading a document with more words than this (0 disables)")
+	maxSizeFlag := flag.String("max-size", defaultMaxSize, "Largest file read into memory whole; larger piped text is streamed (0 disables)")
+	pacing := flag.String("pacing", "fixed", "How long each word is shown: "+strings.Join(reader.PacerNames(), ", "))
+	difficulty := flag.Float64("difficulty", 0, "Show rare and long words up to this much longer, such as 0.5 for half as long again (0 disables)")
+	autoSlow := flag.Bool("auto-slow", false, "Lower the speed by 50 WPM whenever the display keeps showing words late")
+	announceRecords := flag.Bool("records", false, "Announce personal records broken when the session ends")
+	stdinJSON := flag.Bool("stdin-json", false, "Read a JSON document with chapters, a table of contents and metadata from stdin (see brr(1))")
+	jsonSummaryPath := flag.String("json-summary", "", "On exit, write a JSON summary of the session to this file (- for stdout)")
+	presetsPath := flag.String("presets", envOr(presetsEnv, defaultPresetsPath()), "File of named presets switched to with the keys 1-9")
+	keysFlag := flag.String("keys", envOr(keysEnv, "default"), "Control scheme: default or one-handed (every control within reach of the left hand)")
+	linksFlag := flag.String("links", "full", "URLs and email addresses: full (shown longer), domain, placeholder or skip")
+	overflowFlag := flag.String("overflow", "marquee", "Words too wide for the display: marquee (scroll through them) or placeholder (Enter shows them)")
+	stopwordFlag := flag.String("stopwords", "off", "Function words like \"the\" and \"of\": off, half (shown for half the time) or skip")
+	lang := flag.String("lang", "en", "Language of the text, for its punctuation, function words and word frequencies: "+strings.Join(reader.Languages(), ", "))
+	translateWith := flag.String("translate", os.Getenv(translateEnv), "Translate the sentence paused in with a LibreTranslate server URL or a dictionary file")
+	translateTo := flag.String("translate-to", "en", "Language to translate into with --translate")
+	parallelPath := flag.String("parallel", "", "Translation of the document, shown a sentence at a time below its words (see --display parallel)")
+	capturePath := flag.String("capture", os.Getenv(captureEnv), "Markdown file that A appends the current sentence to ({date} is replaced, e.g. a daily note)")
+	captureTemplate := flag.String("capture-template", envOr(captureTemplateEnv, defaultCaptureTemplate), "Template for captured sentences: {sentence}, {source}, {chapter}, {position}, {ref}, {date}, {time}")
+	handoffURL := flag.String("handoff-url", envOr(handoffURLEnv, defaultHandoffURL), "Link shown as a QR code by H while paused: {hash}, {word}, {percent}")
+	bidi := flag.Bool("bidi", false, "The terminal lays out right-to-left text itself: don't reverse Hebrew and Arabic words")
+	checkpoints := flag.Bool("checkpoints", false, "Pause at each new chapter with a recap of the one just read")
+	pauseChapters := flag.Bool("pause-at-chapters", false, "Pause at the end of each chapter")
+	finishAt := flag.Float64("finish-at", defaultFinishAt, "Count a book as finished once past this percentage of it, since back matter is rarely read")
+	figures := flag.Bool("figures", false, "Show EPUB images as [Figure: alt text] so captions keep their context")
+	keepEmpty := flag.Bool("keep-empty", false, "Keep EPUB sections with no text, such as image-only pages, in the table of contents")
+	footnotes := flag.String("footnotes", "skip", "EPUB footnotes and endnotes: skip, appendix (read them in a final Notes chapter) or inline")
+	rubyFlag := flag.String("ruby", "skip", "EPUB ruby annotations such as furigana: skip (read the text alone) or inline (read each reading after its text)")
+	skipMatter := flag.Bool("skip-matter", false, "Leave out EPUB covers, copyright pages, dedications, contents and indexes, starting at the first chapter")
+	subtitles := flag.String("export-subtitles", "", "Write word-timed subtitles (.srt or .vtt) at the -w pace to this file and exit")
+	simulateOnly := flag.Bool("simulate", false, "Print how long each chapter and the whole document take to read at the -w pace and --pacing, then exit")
+	fillers := flag.String("fillers", "", "Filler word list for --transcript, one word or phrase per line")
+	archive := flag.String("archive", os.Getenv(archiveEnv), "Archive/proxy URL template to retry truncated web articles ({url} is replaced)")
+	cookies := flag.String("cookies", os.Getenv(cookiesEnv), "Netscape-format cookie file for fetching web articles")
+	listHistory := flag.Bool("list", false, "List previously read files and exit")
+	notesPath := flag.String("notes", "", "Append the sentences marked with M to this Markdown file on quit (default: print them)")
+	exportNotesFor := flag.String("export-notes", "", "Print all saved marks for this book as Markdown and exit")
+	session := flag.String("session", "", "Read from and save to a named reading session, such as one kept from another device")
+	startFlag := flag.String("start", "", "Start at a percentage of the document, such as 45%, or a word number, instead of the saved position")
+	startWord := flag.Int("start-word", 0, "Start at this word number, instead of the saved position")
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Brr - Terminal Speed Reading Tool\n\n")
 		fmt.Fprintf(os.Stderr, "Usage:\n")
-		fmt.Fprintf(os.Stderr, "  brr [options] [file]\n\n")
+		fmt.Fprintf(os.Stderr, "  brr [options] [--] [file|url ...]\n")
+		fmt.Fprintf(os.Stderr, "  brr [options] site [-depth n] [-max-pages n] <url>\n")
+		fmt.Fprintf(os.Stderr, "  brr [options] podcast [-episode n] <feed or episode url>\n")
+		fmt.Fprintf(os.Stderr, "  brr [options] yt [-lang code] <video url>\n")
+		fmt.Fprintf(os.Stderr, "  brr [options] releases [-n count] <owner/repo>\n")
+		fmt.Fprintf(os.Stderr, "  brr [options] zotero [-library file] [key or title]\n")
+		fmt.Fprintf(os.Stderr, "  brr [options] serve [-addr host:port | -socket path] [file|url]\n")
+		fmt.Fprintf(os.Stderr, "  brr state backup | restore [n]\n")
+		fmt.Fprintf(os.Stderr, "  brr formats [--json]\n\n")
 		fmt.Fprintf(os.Stderr, "Options:\n")
 		flag.PrintDefaults()
 		fmt.Fprintf(os.Stderr, "\nExamples:\n")
@@ -412,126 +1719,635 @@ func main() {
 		fmt.Fprintf(os.Stderr, "  brr --toc book.epub       Show TOC panel at startup\n")
 		fmt.Fprintf(os.Stderr, "  brr --fresh book.epub     Start from beginning\n")
 		fmt.Fprintf(os.Stderr, "  cat file.txt | brr        Read from stdin\n")
+		fmt.Fprintf(os.Stderr, "  tool | brr --stdin-json  Read a JSON document with chapters\n")
+		fmt.Fprintf(os.Stderr, "  brr https://example.com  Read a web article\n")
+		fmt.Fprintf(os.Stderr, "  brr site https://docs.example.com/guide/\n")
+		fmt.Fprintf(os.Stderr, "                            Read a documentation section as chapters\n")
+		fmt.Fprintf(os.Stderr, "  brr --minutes 20 book.epub Read for 20 minutes, then pause\n")
+		fmt.Fprintf(os.Stderr, "  brr -f app.log            Keep reading as the log grows\n")
+		fmt.Fprintf(os.Stderr, "  brr --transcript call.vtt Read a transcript without fillers\n")
+		fmt.Fprintf(os.Stderr, "  brr --export-subtitles talk.srt -w 150 talk.md\n")
+		fmt.Fprintf(os.Stderr, "                            Subtitle a narration word by word\n")
+		fmt.Fprintf(os.Stderr, "  brr --simulate --pacing adaptive book.epub\n")
+		fmt.Fprintf(os.Stderr, "                            Print how long each chapter takes to read\n")
+		fmt.Fprintf(os.Stderr, "  brr serve                 Read what scripts send to a local HTTP API\n")
+		fmt.Fprintf(os.Stderr, "  brr --list                List reading history\n")
+		fmt.Fprintf(os.Stderr, "  brr                       Pick a book from reading history\n")
 		fmt.Fprintf(os.Stderr, "\nControls:\n")
 		fmt.Fprintf(os.Stderr, "  SPACE    Pause/play\n")
 		fmt.Fprintf(os.Stderr, "  +/-      Increase/decrease speed by 50 WPM\n")
 		fmt.Fprintf(os.Stderr, "  ↑/↓      Increase/decrease speed by 50 WPM\n")
 		fmt.Fprintf(os.Stderr, "  ←/→      Jump to previous/next sentence\n")
+		fmt.Fprintf(os.Stderr, "  ]/}      Skip the rest of the sentence/paragraph and read on\n")
+		fmt.Fprintf(os.Stderr, "  /        Search; n/N jump to next/previous match\n")
+		fmt.Fprintf(os.Stderr, "  G        Go to a percentage or word number\n")
+		fmt.Fprintf(os.Stderr, "  U/^R     Undo/redo the last jump\n")
+		fmt.Fprintf(os.Stderr, "  B        Cycle display mode (ORP, bionic, vertical, preview, parallel)\n")
+		fmt.Fprintf(os.Stderr, "  S        Cycle how function words are shown\n")
+		fmt.Fprintf(os.Stderr, "  1-9      Switch to a preset from the --presets file\n")
+		fmt.Fprintf(os.Stderr, "  C        Cycle color theme\n")
 		fmt.Fprintf(os.Stderr, "  T        Toggle table of contents\n")
-		fmt.Fprintf(os.Stderr, "  R        Restart from beginning\n")
+		fmt.Fprintf(os.Stderr, "  ENTER    Show a word too wide for the display in full\n")
+		fmt.Fprintf(os.Stderr, "  M        Mark the current sentence\n")
+		fmt.Fprintf(os.Stderr, "  A        Append the current sentence to the --capture file\n")
+		fmt.Fprintf(os.Stderr, "  I        Write a note at the current word (N is taken by search)\n")
+		fmt.Fprintf(os.Stderr, "  L        List the notes at positions\n")
+		fmt.Fprintf(os.Stderr, "  O        Open the book's scratch notes\n")
+		fmt.Fprintf(os.Stderr, "  Y        Copy the current sentence while paused\n")
+		fmt.Fprintf(os.Stderr, "  H        Show a QR code of the position while paused\n")
+		fmt.Fprintf(os.Stderr, "  R        Jump back to the start\n")
 		fmt.Fprintf(os.Stderr, "  Q        Quit\n")
 	}
 	flag.Parse()
 
+	// brr serve takes its own options, then reads what follows them as
+	// usual.
+	var server *remoteServer
+	command := subcommand(os.Args[1:], flag.Args())
+	if command == "serve" {
+		serveArgs := flag.Args()[1:]
+		var rest []string
+		server, rest = parseServe(serveArgs)
+		flag.CommandLine.Parse(rest)
+		if command = ""; subcommand(serveArgs, rest) != "" {
+			command = subcommand(rest, flag.Args())
+		}
+	}
+
+	renderer, ok := reader.RendererByName(*display)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: Unknown display mode '%s' (choose from %s)\n", *display, strings.Join(reader.RendererNames(), ", "))
+		os.Exit(1)
+	}
+
+	pacer, ok := reader.ParsePacer(*pacing)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: Unknown --pacing strategy '%s' (choose from %s)\n", *pacing, strings.Join(reader.PacerNames(), ", "))
+		os.Exit(1)
+	}
+	presets, err := loadPresets(*presetsPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	linkPolicy, ok := reader.ParseLinkPolicy(*linksFlag)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: Unknown --links policy '%s' (choose from %s)\n", *linksFlag, strings.Join(reader.LinkPolicyNames(), ", "))
+		os.Exit(1)
+	}
+	noteMode, ok := reader.ParseNoteMode(*footnotes)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: Unknown --footnotes mode '%s' (choose from %s)\n", *footnotes, strings.Join(reader.NoteModeNames(), ", "))
+		os.Exit(1)
+	}
+	rubyMode, ok := reader.ParseRubyMode(*rubyFlag)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: Unknown --ruby mode '%s' (choose from %s)\n", *rubyFlag, strings.Join(reader.RubyModeNames(), ", "))
+		os.Exit(1)
+	}
+	keys, ok := parseKeymap(*keysFlag)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: Unknown --keys scheme '%s' (choose from %s)\n", *keysFlag, strings.Join(keymapNames, ", "))
+		os.Exit(1)
+	}
+	overflow, ok := parseOverflowMode(*overflowFlag)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: Unknown --overflow mode '%s' (choose from %s)\n", *overflowFlag, strings.Join(overflowModeNames, ", "))
+		os.Exit(1)
+	}
+	stopwordMode, ok := reader.ParseStopwordMode(*stopwordFlag)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: Unknown --stopwords mode '%s' (choose from off, half, skip)\n", *stopwordFlag)
+		os.Exit(1)
+	}
+	language, ok := reader.LanguageFor(*lang)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: Unknown language '%s' (choose from %s)\n", *lang, strings.Join(reader.Languages(), ", "))
+		os.Exit(1)
+	}
+	// Languages without a function word list have none shortened or
+	// skipped.
+	stopwords, _ := reader.Stopwords(*lang)
+	// Languages without a frequency list have their words paced by length
+	// alone.
+	frequencies, _ := reader.Frequencies(*lang)
+	maxSize, err := parseSize(*maxSizeFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --max-size: %v\n", err)
+		os.Exit(1)
+	}
+	if err := checkFinishAt(*finishAt); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	reverseRTL = !*bidi
+
+	colors, ok := theme.ByName(*themeName)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: Unknown theme '%s' (choose from %s)\n", *themeName, strings.Join(theme.Names(), ", "))
+		os.Exit(1)
+	}
+	night, err := parseNightMode(*nightName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	startAt, err := startPosition(*startFlag, *startWord)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
 	if *showVersion || *showVersionLong {
 		fmt.Printf("brr %s (commit: %s, built: %s)\n", version, commit, date)
 		os.Exit(0)
 	}
 
+	if *listHistory {
+		if err := printHistory(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	if *exportNotesFor != "" {
+		if err := exportNotes(os.Stdout, *exportNotesFor); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	if command == "state" {
+		if err := runStateCommand(os.Stdout, flag.Args()[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	if command == "stats" {
+		if err := runStatsCommand(os.Stdout, flag.Args()[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	if command == "formats" {
+		if err := runFormatsCommand(os.Stdout, flag.Args()[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	zotero := &zoteroSession{}
+	if command == "zotero" {
+		// With no item named, the library is listed rather than read.
+		listed, err := runZoteroList(os.Stdout, flag.Args()[1:], zotero)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if listed {
+			os.Exit(0)
+		}
+	}
+
 	var text string
+	// stdinBook is the title and author of a --stdin-json document.
+	var stdinBook reader.Metadata
 	var toc []reader.TOCEntry
 	var chapters []reader.Chapter
 	var sourceFile string
 
-	if flag.NArg() > 0 {
-		sourceFile = flag.Arg(0)
+	transcript, err := newTranscript(*transcriptMode, *fillers)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	var warnings reader.Warnings
+	opts := loadOptions{archive: *archive, cookies: *cookies, stream: *streamInput, follow: *follow || *followLong, figures: *figures, keepEmpty: *keepEmpty, notes: noteMode, ruby: rubyMode, skipMatter: *skipMatter, strict: *strict, warnings: &warnings, transcript: transcript, maxSize: maxSize, zotero: zotero}
+
+	// files are the files or URLs to read, one after another when there
+	// are several.
+	var files []string
+	if flag.NArg() > 0 && !isSubcommand(command) {
+		files, err = expandFiles(flag.Args())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
 
-		if provider, ok := getTOCProvider(sourceFile); ok {
-			var err error
-			toc, err = provider.TOC(sourceFile)
-			if err != nil {
-				toc = nil
-			}
+	var stream *reader.Reader
+	if len(files) == 1 {
+		var err error
+		stream, err = openStream(files[0], *wpm, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to read file '%s': %v\n", files[0], err)
+			os.Exit(1)
+		}
+		if stream != nil {
+			sourceFile = files[0]
 		}
+	}
 
-		if extractor, ok := getChapterExtractor(sourceFile); ok {
-			var words []string
-			var err error
-			chapters, words, err = extractor.ExtractChapters(sourceFile)
-			if err == nil && len(words) > 0 {
-				text = strings.Join(words, " ")
-			}
+	// load, when set, extracts the document in the background behind a
+	// loading screen.
+	var load loadFunc
+	// sizeNotice explains that piped input too large to hold was streamed.
+	var sizeNotice string
+	switch {
+	case stream != nil:
+		// Words are read lazily as the reader advances.
+
+	case len(files) == 1:
+		sourceFile = files[0]
+		load = fileLoader(sourceFile, opts)
+
+	case len(files) > 1:
+		sourceFile = files[0]
+		load = playlistLoader(files, opts)
+
+	case flag.NArg() > 0:
+		var err error
+		load, err = sourceLoader(flag.Args(), opts, &sourceFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to read '%s': %v\n", sourceFile, err)
+			os.Exit(1)
 		}
 
-		if text == "" {
-			var err error
-			text, err = reader.ExtractText(sourceFile)
+	case server != nil:
+		// Documents arrive through the API.
+
+	case *stdinJSON:
+		var err error
+		text, toc, chapters, stdinBook, err = readDocument(os.Stdin, maxSize)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading stdin: %v\n", err)
+			os.Exit(1)
+		}
+
+	default:
+		stat, _ := os.Stdin.Stat()
+		if (stat.Mode() & os.ModeCharDevice) != 0 {
+			picked, err := pickFromHistory()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if picked == "" {
+				fmt.Fprintln(os.Stderr, "Error: No input provided. Provide a file or pipe text to stdin.")
+				fmt.Fprintln(os.Stderr, "Try: brr -h")
+				os.Exit(1)
+			}
+			sourceFile = picked
+			stream, err = openStream(sourceFile, *wpm, opts)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error: Failed to read file '%s': %v\n", sourceFile, err)
 				os.Exit(1)
 			}
+			if stream == nil {
+				load = fileLoader(sourceFile, opts)
+			}
+		} else if opts.follow {
+			stream = reader.NewTailReader(reader.NewPollReader(os.Stdin), *wpm)
+		} else if opts.stream && opts.transcript == nil {
+			stream = reader.NewStreamReader(os.Stdin, 0, *wpm)
+		} else {
+			data, rest, err := readLimited(os.Stdin, maxSize)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error reading stdin: %v\n", err)
+				os.Exit(1)
+			}
+			if rest != nil {
+				if opts.transcript != nil {
+					fmt.Fprintf(os.Stderr, "Error: Input is larger than the %s limit; raise it with --max-size\n", formatSize(maxSize))
+					os.Exit(1)
+				}
+				stream = reader.NewStreamReader(rest, 0, *wpm)
+				sizeNotice = fmt.Sprintf("Input is over %s, so it is read as it goes", formatSize(maxSize))
+				break
+			}
+			text = reader.DecodeText(data)
+			if opts.transcript != nil {
+				text = opts.transcript.Sanitize(text)
+			}
 		}
-	} else {
-		stat, _ := os.Stdin.Stat()
-		if (stat.Mode() & os.ModeCharDevice) != 0 {
-			fmt.Fprintln(os.Stderr, "Error: No input provided. Provide a file or pipe text to stdin.")
-			fmt.Fprintln(os.Stderr, "Try: brr -h")
+	}
+
+	// now is the load done before the reader starts.
+	var now loadFunc
+	if load != nil && (*subtitles != "" || *simulateOnly) {
+		// Exporting and simulating have no UI to show a loading screen in.
+		now, load = load, nil
+	}
+	pre, err := preload(now, sourceFile, *parallelPath, opts)
+	if err != nil {
+		exitIfCancelled(err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if now != nil {
+		text, toc, chapters = pre.text, pre.toc, pre.chapters
+	}
+	parallel := pre.parallel
+
+	// pacedReader returns a reader of the document paced as reading it
+	// would be, for exporting and simulating.
+	pacedReader := func() *reader.Reader {
+		r := reader.NewReader(text, *wpm)
+		r.SetChapters(chapters, toc)
+		r.ParagraphPause = *paragraphPause
+		r.ChapterPause = *chapterPause
+		r.Stopwords = stopwords
+		r.StopwordMode = stopwordMode
+		r.Frequencies = frequencies
+		r.DifficultyStrength = *difficulty
+		r.MaxWordLength = *maxWordLength
+		r.LinkPolicy = linkPolicy
+		r.Pacer = pacer
+		r.SetLanguage(language)
+		return r
+	}
+
+	if *subtitles != "" {
+		if stream != nil {
+			fmt.Fprintln(os.Stderr, "Error: Cannot export subtitles from streamed or followed input.")
+			os.Exit(1)
+		}
+		if err := exportSubtitles(*subtitles, pacedReader()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to export subtitles: %v\n", err)
 			os.Exit(1)
 		}
+		os.Exit(0)
+	}
+	if *simulateOnly {
+		if stream != nil {
+			fmt.Fprintln(os.Stderr, "Error: Cannot simulate streamed or followed input.")
+			os.Exit(1)
+		}
+		if err := printSimulation(os.Stdout, simulate(pacedReader()), *wpm); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
 
-		data, err := io.ReadAll(os.Stdin)
+	var speaker tts.Speaker
+	if *ttsBackend != "" {
+		speaker, err = tts.New(*ttsBackend)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error reading stdin: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
-		text = string(data)
 	}
-
-	if strings.TrimSpace(text) == "" {
-		fmt.Fprintln(os.Stderr, "Error: No text to read.")
+	translator, err := newTranslator(*translateWith, *translateTo, *lang)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --translate: %v\n", err)
 		os.Exit(1)
 	}
+	applyTheme(colors)
+
+	// start builds the reading model once the document is loaded, or
+	// with incomplete set, once its first parts have loaded.
+	start := func(text string, toc []reader.TOCEntry, chapters []reader.Chapter, incomplete bool) (model, error) {
+		var m model
+		if stream != nil {
+			if len(stream.Words) == 0 && !stream.Waiting() {
+				return m, errors.New("no text to read")
+			}
+			m = newReaderModel(stream)
+		} else {
+			if strings.TrimSpace(text) == "" && server == nil {
+				return m, errors.New("no text to read")
+			}
+			m = newModel(text, *wpm, toc, chapters)
+		}
+		m.server = server
+		m.sourceFile = sourceFile
+		m.renderer = renderer
+		m.goal = newSessionGoal(*goalMinutes, *goalWords)
+		m.eyes = newEyeBreak(*eyeBreaks, *eyeBreakLength)
+		m.lock = newFocusLock(*lockFor, time.Now())
+		m.stats = &sessionStats{}
+		m.frames = &frameStats{autoSlow: *autoSlow}
+		m.recap = newChapterCheckpoint(*checkpoints)
+		if *pauseChapters {
+			pauseAtChapters(m.Reader)
+		}
+		m.recap.watch(m.Reader)
+		m.overflow = overflow
+		m.finishAt = *finishAt
+		m.ParagraphPause = *paragraphPause
+		m.ChapterPause = *chapterPause
+		m.RewindSentences = *rewind
+		m.RewindAfter = *rewindAfter
+		m.Stopwords = stopwords
+		m.StopwordMode = stopwordMode
+		m.Frequencies = frequencies
+		m.DifficultyStrength = *difficulty
+		m.MaxWordLength = *maxWordLength
+		m.LinkPolicy = linkPolicy
+		m.Pacer = pacer
+		m.SetLanguage(language)
+		m.presets = presets
+		m.keys = keys
+		m.capturePath = *capturePath
+		m.captureTemplate = *captureTemplate
+		m.handoffURL = *handoffURL
+		if len(warnings) > 0 {
+			m.notice = "Warning: " + warnings.Summary()
+		}
+		if stream != nil {
+			m.skipped = newSkipWatch(&warnings)
+		}
+		if sizeNotice != "" {
+			m.notice = sizeNotice
+		}
+		m.speaker = speaker
+		m.translator = translator
+		m.theme = colors
+		m.night = &nightSwitch{mode: night}
+		if th, ok := m.night.update(time.Now(), systemDark, m.theme); ok {
+			m.theme = th
+			applyTheme(th)
+		}
 
-	m := newModel(text, *wpm, toc, chapters)
-	m.sourceFile = sourceFile
-
-	if sourceFile != "" {
-		store, err := state.NewStateStore()
-		if err == nil {
-			m.stateStore = store
-			hash, err := state.ComputeHash(sourceFile)
+		m.book = stdinBook
+		if sourceFile != "" {
+			m.book = bookInfo(sourceFile)
+			verticalDisplay(m.book, &m.renderer)
+			if len(files) > 1 {
+				m.book = playlistInfo(files)
+			}
+			store, err := state.NewStateStore()
 			if err == nil {
-				m.fileHash = hash
-				if !*freshStart {
-					if pos := store.GetPosition(hash); pos > 0 && pos < len(m.Words) {
-						m.CurrentIndex = pos
+				m.stateStore = store
+				if msg := store.Recovered(); msg != "" {
+					m.notice = msg
+				}
+				hash, err := sourceHash(sourceFile, files)
+				if err == nil {
+					m.fileHash = hash
+					if !incomplete {
+						// A document read early is recorded once it has
+						// loaded, with its full length.
+						_, total := m.Progress()
+						recordBook(store, hash, sourceFile, m.book, total)
+					}
+					restoreSettings(store.GetSettings(hash), m.Reader, &m.renderer)
+					m.SetSequence(store.GetSequence(hash))
+					if *session != "" {
+						m.session = *session
+						if s, ok := store.GetSession(hash, *session); ok && !*freshStart {
+							if m.SeekTo(s.WordIndex) {
+								m.resume = newResumePrompt(s.WordIndex)
+							} else if incomplete {
+								m.pendingResume = s.WordIndex
+							}
+						}
+					} else {
+						if !*freshStart {
+							if pos := store.GetPosition(hash); pos > 0 && m.SeekTo(pos) {
+								m.resume = newResumePrompt(pos)
+							} else if pos > 0 && incomplete {
+								m.pendingResume = pos
+							}
+						}
+						if c := findSyncConflict(store, hash); c != nil {
+							// The conflict prompt already says where each copy left off.
+							m.resume, m.pendingResume = nil, 0
+							m.conflict = c
+							m.notice = c.prompt(m.Reader)
+							m.Paused = true
+						}
+					}
+					if m.resume != nil {
+						m.notice = m.resume.prompt(m.Reader)
+						m.Paused = true
+					}
+					if m.pendingResume > 0 {
+						m.notice = pendingResumeNotice(m.pendingResume)
+						m.Paused = true
 					}
 				}
 			}
 		}
+		if parallel != "" {
+			p, err := useParallel(m.Reader, parallel, &m.renderer)
+			if err != nil {
+				m.notice = "Warning: " + err.Error()
+			}
+			m.parallel = p
+		}
+		if startAt != nil {
+			// An explicit start replaces the saved position.
+			m.resume, m.pendingResume = nil, 0
+			notice := goTo(m.Reader, *startAt)
+			if m.conflict == nil {
+				m.notice = notice
+			}
+		}
+		m.stats.begin(m.Reader, time.Now())
+		if h := findHugeInput(m.Reader, *maxWords); h != nil {
+			// Asked once any sync conflict or resume prompt is settled.
+			m.huge = h
+			m.Paused = true
+			if m.conflict == nil && m.resume == nil && m.pendingResume == 0 {
+				m.notice = h.prompt(m.WPM)
+			}
+		}
+
+		if *showTOC && len(toc) > 0 {
+			m.tocVisible = true
+			m.Paused = true
+		}
+		if len(m.Words) == 0 && !m.Waiting() {
+			m.Paused = true
+		}
+		return m, nil
+	}
+	if server != nil {
+		server.maxSize = maxSize
+		server.load = func(ctx context.Context, src string) (*document, error) {
+			// Each load has options of its own, as it runs beside the
+			// reader, and reports nothing over it. Only files and URLs are
+			// loaded: source subcommands such as zotero take over the
+			// terminal to list or ask.
+			doc := &document{src: src}
+			docOpts := opts
+			docOpts.warnings = &doc.warnings
+			docOpts.zotero = nil
+			docOpts.progress = func(loadEvent) {}
+			var err error
+			doc.text, doc.toc, doc.chapters, err = loadFile(ctx, src, docOpts)
+			return doc, err
+		}
+		server.open = func(doc *document) (model, error) {
+			sourceFile, files, stream = doc.src, nil, nil
+			warnings, sizeNotice = doc.warnings, ""
+			return start(doc.text, doc.toc, doc.chapters, false)
+		}
 	}
 
-	if *showTOC && len(toc) > 0 {
-		m.tocVisible = true
-		m.Paused = true
+	var initial tea.Model
+	if load != nil {
+		initial = newLoadingModel(sourceFile, load, start)
+	} else {
+		m, err := start(text, toc, chapters, false)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error: No text to read.")
+			os.Exit(1)
+		}
+		initial = m
 	}
 
-	p := tea.NewProgram(m, tea.WithAltScreen())
+	p := tea.NewProgram(initial, tea.WithAltScreen())
+	if server != nil {
+		go server.serve(p.Send)
+	}
 
-	if _, err := p.Run(); err != nil {
+	final, err := p.Run()
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
-}
-
-func getTOCProvider(filename string) (reader.TOCProvider, bool) {
-	lower := strings.ToLower(filename)
-	switch {
-	case strings.HasSuffix(lower, ".epub"):
-		return &reader.EPUBFormat{}, true
-	case strings.HasSuffix(lower, ".md"), strings.HasSuffix(lower, ".markdown"):
-		return &reader.MarkdownFormat{}, true
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", w)
 	}
-	return nil, false
-}
-
-func getChapterExtractor(filename string) (reader.ChapterExtractor, bool) {
-	lower := strings.ToLower(filename)
-	switch {
-	case strings.HasSuffix(lower, ".epub"):
-		return &reader.EPUBFormat{}, true
-	case strings.HasSuffix(lower, ".md"), strings.HasSuffix(lower, ".markdown"):
-		return &reader.MarkdownFormat{}, true
+	if lm, ok := final.(loadingModel); ok && lm.err != nil {
+		exitIfCancelled(lm.err)
+		fmt.Fprintf(os.Stderr, "Error: Failed to read '%s': %v\n", sourceFile, lm.err)
+		os.Exit(1)
+	}
+	if fm, ok := final.(model); ok {
+		if fm.stateStore != nil {
+			if msg := fm.stateStore.Recovered(); msg != "" {
+				fmt.Fprintf(os.Stderr, "Warning: %s\n", msg)
+			}
+		}
+		if summary := fm.frames.summary(); summary != "" {
+			fmt.Fprintln(os.Stderr, summary)
+		}
+		if toast, err := recordSession(fm.stats, *announceRecords, time.Now()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to save personal records: %v\n", err)
+		} else if toast != "" {
+			fmt.Fprintln(os.Stderr, toast)
+		}
+		if *jsonSummaryPath != "" {
+			sum := newJSONSummary(fm.Reader, fm.stats, sourceFile, fm.fileHash, fm.book, fm.finishAt, time.Now())
+			if err := writeJSONSummary(*jsonSummaryPath, sum); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Failed to write session summary: %v\n", err)
+			}
+		}
+		opts.zotero.noteProgress(fm.Reader)
+		if err := saveSessionMarks(fm.marks, *notesPath, notesTitle(sourceFile)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to write notes: %v\n", err)
+		}
+		if err := fm.Err(); err != nil {
+			// A section --strict would not skip ended the text early.
+			fmt.Fprintf(os.Stderr, "Error: Failed to read '%s': %v\n", sourceFile, err)
+			os.Exit(1)
+		}
 	}
-	return nil, false
 }
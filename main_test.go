@@ -1,95 +1,22 @@
+//go:build !gui
+
 package main
 
 import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/metcalfc/brr/internal/reader"
+	"github.com/metcalfc/brr/internal/theme"
 )
 
-func TestParseText(t *testing.T) {
-	tests := []struct {
-		name     string
-		input    string
-		expected []string
-	}{
-		{
-			name:     "simple sentence",
-			input:    "Hello world this is a test",
-			expected: []string{"Hello", "world", "this", "is", "a", "test"},
-		},
-		{
-			name:     "multiple spaces",
-			input:    "Hello    world     test",
-			expected: []string{"Hello", "world", "test"},
-		},
-		{
-			name:     "newlines and tabs",
-			input:    "Hello\nworld\ttest",
-			expected: []string{"Hello", "world", "test"},
-		},
-		{
-			name:     "empty string",
-			input:    "",
-			expected: []string{},
-		},
-		{
-			name:     "single word",
-			input:    "Hello",
-			expected: []string{"Hello"},
-		},
-		{
-			name:     "punctuation",
-			input:    "Hello, world! How are you?",
-			expected: []string{"Hello,", "world!", "How", "are", "you?"},
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := reader.ParseText(tt.input)
-			if len(result) != len(tt.expected) {
-				t.Errorf("ParseText() length = %v, want %v", len(result), len(tt.expected))
-				return
-			}
-			for i := range result {
-				if result[i] != tt.expected[i] {
-					t.Errorf("ParseText()[%d] = %v, want %v", i, result[i], tt.expected[i])
-				}
-			}
-		})
-	}
-}
-
-func TestGetORPPosition(t *testing.T) {
-	tests := []struct {
-		name     string
-		word     string
-		expected int
-	}{
-		{"single char", "a", 0},
-		{"two chars", "ab", 1},
-		{"three chars", "abc", 1},
-		{"four chars", "abcd", 1},
-		{"five chars", "abcde", 1},
-		{"six chars", "abcdef", 2},
-		{"nine chars", "abcdefghi", 3},
-		{"twelve chars", "abcdefghijkl", 4},
-		{"empty string", "", 0},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := reader.GetORPPosition(tt.word)
-			if result != tt.expected {
-				t.Errorf("GetORPPosition(%q) = %v, want %v", tt.word, result, tt.expected)
-			}
-		})
-	}
-}
-
 func TestFormatWord(t *testing.T) {
 	tests := []struct {
 		name string
@@ -102,7 +29,7 @@ func TestFormatWord(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := formatWord(tt.word)
+			result := formatWord(tt.word, reader.ORPRenderer{})
 			// Just check that we get a non-empty result
 			if result == "" {
 				t.Errorf("formatWord(%q) returned empty string", tt.word)
@@ -115,6 +42,161 @@ func TestFormatWord(t *testing.T) {
 	}
 }
 
+func TestFormatWordBionic(t *testing.T) {
+	result := formatWord("reading", reader.BionicRenderer{Fraction: 0.4})
+	if !strings.Contains(result, "rea") || !strings.Contains(result, "ding") {
+		t.Errorf("bionic formatWord should contain both halves, got %q", result)
+	}
+}
+
+func TestModelToggleDisplayMode(t *testing.T) {
+	m := newModel("hello world", 300, nil, nil)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'b'}})
+	if name := updated.(model).renderer.Name(); name != "bionic" {
+		t.Errorf("b should switch to bionic mode, got %s", name)
+	}
+}
+
+func TestViewVerticalMode(t *testing.T) {
+	m := newModel("alpha beta gamma delta epsilon", 300, nil, nil)
+	m.renderer = reader.VerticalRenderer{Before: 2, After: 1}
+	m.JumpToChapter(2)
+
+	lines := strings.Split(m.View(), "\n")
+	if len(lines) != m.height {
+		t.Fatalf("view has %d lines, want %d", len(lines), m.height)
+	}
+	vPad := (m.height - 2) / 2
+	for offset, want := range map[int]string{-2: "alpha", -1: "beta", 0: "gamma", 1: "delta"} {
+		if got := lines[1+vPad+offset]; !strings.Contains(got, want) {
+			t.Errorf("row %d = %q, want %q", offset, got, want)
+		}
+	}
+	if strings.Contains(m.View(), "epsilon") {
+		t.Error("words beyond the context should not be shown")
+	}
+}
+
+func TestViewParallelMode(t *testing.T) {
+	m := newModel("Call me Ishmael. Some years ago, never mind how long.", 300, nil, nil)
+	m.parallel = reader.NewParallelText(m.Reader, "Appelez-moi Ismaël. Il y a quelques années, peu importe combien.")
+	translation := func() string {
+		return strings.Split(m.View(), "\n")[1+(m.height-2)/2+2]
+	}
+	if got := translation(); strings.Contains(got, "Appelez") {
+		t.Errorf("the orp display should not show the translation, got %q", got)
+	}
+	m.renderer = reader.ParallelRenderer{}
+	if got := translation(); !strings.Contains(got, "Appelez-moi Ismaël.") {
+		t.Errorf("translation row = %q, want the first sentence's", got)
+	}
+	m.SeekTo(4)
+	if got := translation(); !strings.Contains(got, "Il y a quelques années") {
+		t.Errorf("translation row = %q, want the second sentence's", got)
+	}
+}
+
+func TestViewPreviewMode(t *testing.T) {
+	m := newModel("alpha beta gamma delta epsilon", 300, nil, nil)
+	m.renderer = reader.PreviewRenderer{Before: 1, After: 1}
+	m.JumpToChapter(2)
+
+	row := strings.Split(m.View(), "\n")[1+(m.height-2)/2]
+	if !strings.Contains(row, "beta   gamma   delta") || strings.Contains(m.View(), "alpha") {
+		t.Errorf("preview row = %q, want the neighbours either side of gamma", row)
+	}
+	if got := orpPad("gamma", m.width); strings.Index(row, "gamma") != got {
+		t.Errorf("gamma starts at %d, want %d to keep its ORP centred", strings.Index(row, "gamma"), got)
+	}
+
+	tests := []struct {
+		s        string
+		n        int
+		fromLeft bool
+		want     string
+	}{
+		{"short", 10, true, "short"},
+		{"a long ghost", 6, true, "…ghost"},
+		{"a long ghost", 6, false, "a lon…"},
+		{"word", 0, false, ""},
+	}
+	for _, tt := range tests {
+		if got := fitGhost(tt.s, tt.n, tt.fromLeft); got != tt.want {
+			t.Errorf("fitGhost(%q, %d, %v) = %q, want %q", tt.s, tt.n, tt.fromLeft, got, tt.want)
+		}
+	}
+}
+
+func TestModelCopySentence(t *testing.T) {
+	var copied string
+	defer func(orig func(string) error) { writeClipboard = orig }(writeClipboard)
+	writeClipboard = func(text string) error {
+		copied = text
+		return nil
+	}
+
+	chapters := []reader.Chapter{{Title: "Opening", WordStart: 0, WordEnd: 5}}
+	m := newModel("Call me Ishmael. Some years ago", 300, nil, chapters)
+	m.JumpToChapter(4)
+	key := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}}
+
+	updated, _ := m.Update(key)
+	if copied != "" {
+		t.Fatalf("y should only copy while paused, copied %q", copied)
+	}
+
+	m = updated.(model)
+	m.Paused = true
+	updated, _ = m.Update(key)
+	want := "“Some years ago”\n— Opening, word 4 of 6"
+	if copied != want {
+		t.Errorf("copied %q, want %q", copied, want)
+	}
+	if got := updated.(model).notice; got != "Copied sentence to clipboard" {
+		t.Errorf("notice = %q", got)
+	}
+
+	writeClipboard = func(string) error { return errors.New("no clipboard utility") }
+	updated, _ = m.Update(key)
+	if got := updated.(model).notice; got != "Could not copy: no clipboard utility" {
+		t.Errorf("notice = %q", got)
+	}
+}
+
+func TestModelCaptureWithoutFile(t *testing.T) {
+	m := newModel("hello world", 300, nil, nil)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	if got := updated.(model).notice; got != "Set --capture FILE to capture sentences" {
+		t.Errorf("notice = %q", got)
+	}
+}
+
+func TestModelCycleStopwords(t *testing.T) {
+	m := newModel("hello world", 300, nil, nil)
+	key := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}}
+	for _, want := range []string{"half", "skip", "off"} {
+		updated, _ := m.Update(key)
+		m = updated.(model)
+		if m.notice != "Function words: "+want {
+			t.Errorf("notice = %q, want mode %s", m.notice, want)
+		}
+	}
+}
+
+func TestModelCycleTheme(t *testing.T) {
+	m := newModel("hello world", 300, nil, nil)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	defer applyTheme(theme.Default())
+
+	got := updated.(model)
+	if got.theme.Name != theme.Themes[1].Name {
+		t.Errorf("c should switch to %s, got %s", theme.Themes[1].Name, got.theme.Name)
+	}
+	if !strings.Contains(got.View(), "Theme: "+got.theme.Name) {
+		t.Error("view should announce the new theme")
+	}
+}
+
 func TestNewModel(t *testing.T) {
 	text := "Hello world test"
 	wpm := 500
@@ -300,6 +382,41 @@ func TestModelUpdate(t *testing.T) {
 	})
 }
 
+func TestModelSearch(t *testing.T) {
+	m := newModel("alpha beta gamma beta delta", 300, nil, nil)
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	m = updated.(model)
+	if !m.searching || !m.Paused {
+		t.Fatal("/ should open search and pause")
+	}
+
+	for _, r := range "beta" {
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updated.(model)
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	if m.searching {
+		t.Error("enter should close search")
+	}
+	if m.CurrentIndex != 1 {
+		t.Errorf("search should jump to 1, got %d", m.CurrentIndex)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	m = updated.(model)
+	if m.CurrentIndex != 3 {
+		t.Errorf("n should jump to 3, got %d", m.CurrentIndex)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'N'}})
+	m = updated.(model)
+	if m.CurrentIndex != 1 {
+		t.Errorf("N should jump back to 1, got %d", m.CurrentIndex)
+	}
+}
+
 func TestModelView(t *testing.T) {
 	t.Run("shows word", func(t *testing.T) {
 		m := newModel("hello world", 300, nil, nil)
@@ -349,7 +466,7 @@ func TestAnchorORPText(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			text := formatWord(tt.word)
+			text := formatWord(tt.word, reader.ORPRenderer{})
 			result := anchorORPText(text, tt.word, tt.width)
 			if result == "" && tt.word != "" {
 				t.Error("anchorORPText should return non-empty result")
@@ -361,22 +478,28 @@ func TestAnchorORPText(t *testing.T) {
 	}
 }
 
-// Benchmark tests
-func BenchmarkParseText(b *testing.B) {
-	text := strings.Repeat("Hello world this is a test sentence with multiple words. ", 100)
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		reader.ParseText(text)
+func TestAnchorORPTextWide(t *testing.T) {
+	// The ORP of 日本語 is 本, two cells in; it should sit at column 40.
+	if got := anchorORPText("日本語", "日本語", 80); got != strings.Repeat(" ", 38)+"日本語" {
+		t.Errorf("anchorORPText should pad by display width, got %q", got)
 	}
 }
 
-func BenchmarkGetORPPosition(b *testing.B) {
-	words := []string{"a", "hello", "testing", "extraordinary", "supercalifragilisticexpialidocious"}
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		for _, word := range words {
-			reader.GetORPPosition(word)
-		}
+func TestFormatWordRTL(t *testing.T) {
+	defer func() { reverseRTL = true }()
+
+	// The ORP of שלום is ל; the two letters after it are shown to its left.
+	want := strings.Repeat(" ", 38)
+	if got := anchorORPText("", "שלום", 80); got != want {
+		t.Errorf("anchorORPText should pad by the letters left of the ORP, got %q", got)
+	}
+	if got := formatWord("שלום", reader.ORPRenderer{}); !strings.HasPrefix(got, "םול") {
+		t.Errorf("formatWord should reverse right-to-left words, got %q", got)
+	}
+
+	reverseRTL = false
+	if got := formatWord("שלום", reader.ORPRenderer{}); !strings.HasPrefix(got, "של") {
+		t.Errorf("with --bidi formatWord should keep reading order, got %q", got)
 	}
 }
 
@@ -385,7 +508,7 @@ func BenchmarkFormatWord(b *testing.B) {
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		for _, word := range words {
-			formatWord(word)
+			formatWord(word, reader.ORPRenderer{})
 		}
 	}
 }
@@ -399,3 +522,146 @@ func BenchmarkModelView(b *testing.B) {
 		m.View()
 	}
 }
+
+var updateGolden = flag.Bool("update", false, "Rewrite the golden files in testdata/view")
+
+// ansiStyle matches the color and style escapes lipgloss writes when the
+// tests run in a terminal, which golden files leave out.
+var ansiStyle = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// goldenText is two short chapters for the golden view tests.
+const goldenText = "Call me Ishmael. Some years ago, never mind how long precisely, having little money in my purse.\n\n" +
+
+	"There now is your insular city of the Manhattoes, belted round by wharves."
+
+func goldenModel(width, height int) model {
+	chapters := []reader.Chapter{{Title: "Loomings", WordStart: 0, WordEnd: 16}, {Title: "The Carpet-Bag", WordStart: 17, WordEnd: 29}}
+	toc := []reader.TOCEntry{
+		{Title: "Loomings", Preview: "Call me Ishmael.", WordIndex: 0},
+		{Title: "The Carpet-Bag", Preview: "There now is your insular city", WordIndex: 17},
+	}
+	m := newModel(goldenText, 300, toc, chapters)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: width, Height: height})
+	return updated.(model)
+}
+
+// TestViewGolden compares the terminal view in representative states with
+// the files in testdata/view. Run go test -run TestViewGolden -update to
+// rewrite them after an intended layout change, and review the diff.
+func TestViewGolden(t *testing.T) {
+	reverseRTL = true
+	tests := []struct {
+		name  string
+		model func() model
+	}{
+		{"reading", func() model {
+			m := goldenModel(80, 12)
+			m.JumpToChapter(2)
+			return m
+		}},
+		{"paused", func() model {
+			m := goldenModel(80, 12)
+			m.JumpToChapter(20)
+			m.Paused = true
+			return m
+		}},
+		{"dashboard", func() model {
+			m := goldenModel(80, 24)
+			m.JumpToChapter(20)
+			m.stats = &sessionStats{words: 120, elapsed: 30 * time.Second}
+			m.Paused = true
+			return m
+		}},
+		{"toc", func() model {
+			m := goldenModel(100, 16)
+			m.tocVisible = true
+			m.Paused = true
+			return m
+		}},
+		{"narrow", func() model {
+			m := goldenModel(10, 8)
+			m.JumpToChapter(10) // "precisely," scrolls in a marquee
+			m.marquee = 2
+			return m
+		}},
+		{"wide-runes", func() model {
+			m := newModel("東京は日本の首都です。", 300, nil, nil)
+			updated, _ := m.Update(tea.WindowSizeMsg{Width: 40, Height: 7})
+			m = updated.(model)
+			m.JumpToChapter(1)
+			return m
+		}},
+		{"rtl", func() model {
+			m := newModel("שלום עולם", 300, nil, nil)
+			updated, _ := m.Update(tea.WindowSizeMsg{Width: 40, Height: 7})
+			return updated.(model)
+		}},
+		{"vertical", func() model {
+			m := goldenModel(60, 11)
+			m.renderer, _ = reader.RendererByName("vertical")
+			m.JumpToChapter(6)
+			return m
+		}},
+		{"preview", func() model {
+			m := goldenModel(60, 7)
+			m.renderer, _ = reader.RendererByName("preview")
+			m.JumpToChapter(6)
+			return m
+		}},
+		{"notice", func() model {
+			m := goldenModel(60, 7)
+			m.notice = "Theme: default"
+			return m
+		}},
+	}
+	for _, tt := range tests {
+		got := ansiStyle.ReplaceAllString(tt.model().View(), "")
+		path := filepath.Join("testdata", "view", tt.name+".golden")
+		if *updateGolden {
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		want, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("%s: %v (run with -update to create it)", tt.name, err)
+		}
+		if got != string(want) {
+			t.Errorf("%s: view differs from %s\ngot:\n%s\nwant:\n%s", tt.name, path, got, want)
+		}
+	}
+}
+
+func TestHistoryKeys(t *testing.T) {
+	m := newModel("One two. Three four. Five six.", 300, nil, nil)
+	key := func(msg tea.KeyMsg) {
+		updated, _ := m.Update(msg)
+		m = updated.(model)
+	}
+	u := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'u'}}
+	redo := tea.KeyMsg{Type: tea.KeyCtrlR}
+
+	key(tea.KeyMsg{Type: tea.KeyRight})
+	key(tea.KeyMsg{Type: tea.KeyRight})
+	key(u)
+	if m.CurrentIndex != 2 {
+		t.Errorf("after two sentence jumps and u at word %d, want 2", m.CurrentIndex)
+	}
+	key(redo)
+	if m.CurrentIndex != 4 {
+		t.Errorf("after ctrl+r at word %d, want 4", m.CurrentIndex)
+	}
+	key(redo)
+	if m.notice != "No later position" {
+		t.Errorf("ctrl+r with nothing to redo: notice %q", m.notice)
+	}
+	key(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	key(u)
+	if m.CurrentIndex != 4 {
+		t.Errorf("u after restarting at word %d, want 4", m.CurrentIndex)
+	}
+}
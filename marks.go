@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/metcalfc/brr/internal/reader"
+	"github.com/metcalfc/brr/internal/state"
+)
+
+// sessionMarks collects the sentences marked with M during a session, to
+// be written out as Markdown on quit.
+type sessionMarks struct {
+	marks []state.Mark
+}
+
+// newMark records the sentence around the current word.
+func newMark(r *reader.Reader, now time.Time) state.Mark {
+	start := r.SentenceStart(r.CurrentIndex)
+	return state.Mark{
+		WordIndex: r.Base + start,
+		Quote:     r.SentenceFrom(start),
+		Chapter:   r.CurrentChapterTitle(),
+		Created:   now,
+	}
+}
+
+// add records mark for the session and, with a store, for the book. It
+// reports false if the sentence was already marked this session or before.
+func (s *sessionMarks) add(mark state.Mark, store *state.StateStore, hash string) bool {
+	for _, m := range s.marks {
+		if m.WordIndex == mark.WordIndex {
+			return false
+		}
+	}
+	if store != nil && hash != "" {
+		if added, err := store.AddMark(hash, mark); err == nil && !added {
+			return false
+		}
+	}
+	s.marks = append(s.marks, mark)
+	return true
+}
+
+// markNotice describes the result of marking for the status line.
+func (s *sessionMarks) markNotice(added bool) string {
+	if !added {
+		return "Sentence already marked"
+	}
+	if len(s.marks) == 1 {
+		return "Marked sentence"
+	}
+	return fmt.Sprintf("Marked sentence (%d this session)", len(s.marks))
+}
+
+// writeMarks writes marks as Markdown: a heading for the book, then each
+// sentence as a quote followed by its chapter and position, in reading
+// order.
+func writeMarks(w io.Writer, title string, marks []state.Mark) error {
+	marks = append([]state.Mark(nil), marks...)
+	sort.SliceStable(marks, func(i, j int) bool { return marks[i].WordIndex < marks[j].WordIndex })
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Notes: %s\n", title)
+	for _, m := range marks {
+		ref := fmt.Sprintf("word %d", m.WordIndex+1)
+		if m.Chapter != "" {
+			ref = m.Chapter + ", " + ref
+		}
+		fmt.Fprintf(&sb, "\n> %s\n>\n> — %s\n", m.Quote, ref)
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// saveSessionMarks writes the session's marks to path, appending to it, or
+// to stdout when path is empty. It does nothing when nothing was marked.
+func saveSessionMarks(s *sessionMarks, path, title string) error {
+	if s == nil || len(s.marks) == 0 {
+		return nil
+	}
+	if path == "" {
+		return writeMarks(os.Stdout, title, s.marks)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if err := writeMarks(f, title, s.marks); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// notesTitle names the source in notes headings.
+func notesTitle(sourceFile string) string {
+	if sourceFile == "" {
+		return "stdin"
+	}
+	return bookInfo(sourceFile).Title
+}
+
+// exportNotes writes all saved marks for the book at path as Markdown,
+// followed by its notes at positions and its scratch notes.
+func exportNotes(w io.Writer, path string) error {
+	hash, err := state.ComputeHash(path)
+	if err != nil {
+		return err
+	}
+	store, err := state.NewStateStore()
+	if err != nil {
+		return err
+	}
+	st, _ := store.Get(hash)
+	notes, err := store.Notes(hash)
+	if err != nil {
+		return err
+	}
+	if len(st.Marks) == 0 && len(st.Annotations) == 0 && notes == "" {
+		return fmt.Errorf("no saved marks or notes for %s", path)
+	}
+	title := st.Title
+	if title == "" {
+		title = bookTitle(path)
+	}
+	if err := writeMarks(w, title, st.Marks); err != nil {
+		return err
+	}
+	if len(st.Annotations) > 0 {
+		if err := writeAnnotations(w, st.Annotations); err != nil {
+			return err
+		}
+	}
+	if notes == "" {
+		return nil
+	}
+	return writeBookNotes(w, notes)
+}
@@ -0,0 +1,40 @@
+//go:build !gui
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+func TestMarks(t *testing.T) {
+	m := newModel("Call me Ishmael. Some years ago, never mind how long.", 300, []reader.TOCEntry{{Title: "Loomings"}}, []reader.Chapter{{Title: "Loomings", WordStart: 0, WordEnd: 10}})
+	m.JumpToChapter(5)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	m = updated.(model)
+	if m.notice != "Marked sentence" {
+		t.Errorf("notice = %q", m.notice)
+	}
+	m.JumpToChapter(7)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	m = updated.(model)
+	if m.notice != "Sentence already marked" {
+		t.Errorf("marking the same sentence again: notice = %q", m.notice)
+	}
+	m.JumpToChapter(0)
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+
+	var sb strings.Builder
+	if err := writeMarks(&sb, "moby", m.marks.marks); err != nil {
+		t.Fatalf("writeMarks: %v", err)
+	}
+	want := "# Notes: moby\n" +
+		"\n> Call me Ishmael.\n>\n> — Loomings, word 1\n" +
+		"\n> Some years ago, never mind how long.\n>\n> — Loomings, word 4\n"
+	if sb.String() != want {
+		t.Errorf("writeMarks =\n%s\nwant\n%s", sb.String(), want)
+	}
+}
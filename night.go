@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/metcalfc/brr/internal/theme"
+)
+
+// nightCheckInterval is how often a night mode that comes and goes is
+// checked.
+const nightCheckInterval = time.Minute
+
+// nightKind is when night mode is on.
+type nightKind int
+
+const (
+	nightOff nightKind = iota
+	nightOn
+	// nightSystem follows the system's dark mode.
+	nightSystem
+	// nightSchedule is on daily between two times.
+	nightSchedule
+)
+
+// nightMode is when to read with the night theme, set by --night.
+type nightMode struct {
+	kind nightKind
+	// from and to bound a schedule, as times since midnight; a schedule
+	// ending before it starts runs past midnight.
+	from, to time.Duration
+}
+
+// parseNightMode parses "off", "on", "system" or a daily schedule such as
+// "21:00-07:00".
+func parseNightMode(s string) (nightMode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "off":
+		return nightMode{}, nil
+	case "on":
+		return nightMode{kind: nightOn}, nil
+	case "system", "auto":
+		return nightMode{kind: nightSystem}, nil
+	}
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return nightMode{}, fmt.Errorf("night mode %q is not off, on, system or a time range such as 21:00-07:00", s)
+	}
+	start, err := parseClock(from)
+	if err != nil {
+		return nightMode{}, err
+	}
+	end, err := parseClock(to)
+	if err != nil {
+		return nightMode{}, err
+	}
+	return nightMode{kind: nightSchedule, from: start, to: end}, nil
+}
+
+// parseClock parses a 24-hour time of day, "21:00" or "7:30", as the time
+// since midnight.
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q (use HH:MM)", strings.TrimSpace(s))
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// changes reports whether night mode can come and go while reading, and so
+// needs checking every nightCheckInterval.
+func (n nightMode) changes() bool {
+	return n.kind == nightSystem || n.kind == nightSchedule
+}
+
+// active reports whether night mode is on at now, asking systemDark
+// whether the system is in dark mode when following it.
+func (n nightMode) active(now time.Time, systemDark func() bool) bool {
+	switch n.kind {
+	case nightOn:
+		return true
+	case nightSystem:
+		return systemDark()
+	case nightSchedule:
+		h, m, _ := now.Clock()
+		t := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute
+		if n.from <= n.to {
+			return t >= n.from && t < n.to
+		}
+		return t >= n.from || t < n.to
+	}
+	return false
+}
+
+// nightSwitch puts the night theme on while night mode is active, and the
+// theme read with before it back when it ends. A theme chosen with C in
+// the meantime stays until night mode next comes or goes.
+type nightSwitch struct {
+	mode nightMode
+	on   bool
+	day  theme.Theme
+}
+
+// update checks night mode at now, returning the theme to switch to and
+// true when it has come or gone since the last check.
+func (s *nightSwitch) update(now time.Time, systemDark func() bool, current theme.Theme) (theme.Theme, bool) {
+	if s == nil {
+		return current, false
+	}
+	on := s.mode.active(now, systemDark)
+	if on == s.on {
+		return current, false
+	}
+	s.on = on
+	if on {
+		s.day = current
+		return theme.Night(), true
+	}
+	return s.day, true
+}
+
+// notice describes night mode coming or going.
+func (s *nightSwitch) notice() string {
+	if s.on {
+		return "Night mode on"
+	}
+	return "Night mode off"
+}
+
+// systemDark reports whether the system is in dark mode, where brr can
+// tell; tests replace it.
+var systemDark = func() bool {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		// Only set, to "Dark", in dark mode.
+		cmd = exec.Command("defaults", "read", "-g", "AppleInterfaceStyle")
+	case "windows":
+		cmd = exec.Command("reg", "query", `HKCU\Software\Microsoft\Windows\CurrentVersion\Themes\Personalize`, "/v", "AppsUseLightTheme")
+	default:
+		cmd = exec.Command("gsettings", "get", "org.gnome.desktop.interface", "color-scheme")
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return false
+	}
+	s := strings.TrimSpace(string(out))
+	switch runtime.GOOS {
+	case "darwin":
+		return strings.EqualFold(s, "Dark")
+	case "windows":
+		return strings.HasSuffix(s, "0x0")
+	}
+	return strings.Contains(s, "dark")
+}
@@ -0,0 +1,75 @@
+//go:build !gui
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metcalfc/brr/internal/theme"
+)
+
+func TestNightMode(t *testing.T) {
+	at := func(clock string) time.Time {
+		tm, _ := time.Parse("15:04", clock)
+		return time.Date(2026, 1, 2, tm.Hour(), tm.Minute(), 0, 0, time.Local)
+	}
+	light := func() bool { return false }
+
+	for _, bad := range []string{"sometimes", "21:00", "25:00-07:00", "21:00-7"} {
+		if _, err := parseNightMode(bad); err == nil {
+			t.Errorf("parseNightMode(%q) should fail", bad)
+		}
+	}
+	overnight, err := parseNightMode("21:00-07:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	evening, _ := parseNightMode("18:30-23:00")
+	tests := []struct {
+		mode  nightMode
+		clock string
+		want  bool
+	}{
+		{overnight, "20:59", false},
+		{overnight, "21:00", true},
+		{overnight, "02:00", true},
+		{overnight, "07:00", false},
+		{evening, "18:29", false},
+		{evening, "22:59", true},
+		{evening, "23:30", false},
+	}
+	for _, tt := range tests {
+		if got := tt.mode.active(at(tt.clock), light); got != tt.want {
+			t.Errorf("%+v at %s = %v, want %v", tt.mode, tt.clock, got, tt.want)
+		}
+	}
+	if system, _ := parseNightMode("system"); !system.active(at("12:00"), func() bool { return true }) || !system.changes() {
+		t.Error("system night mode should follow dark mode")
+	}
+
+	defer func(orig func() bool) { systemDark = orig }(systemDark)
+	dark := false
+	systemDark = func() bool { return dark }
+	defer applyTheme(theme.Default())
+
+	m := newModel("one two three", 300, nil, nil)
+	m.theme, _ = theme.ByName("dracula")
+	m.night = &nightSwitch{mode: nightMode{kind: nightSystem}}
+	if cmd := m.Init(); cmd == nil {
+		t.Fatal("Init should schedule night mode checks")
+	}
+	dark = true
+	next, cmd := m.Update(nightMsg{})
+	m = next.(model)
+	if m.theme.Name != "night" || m.notice != "Night mode on" || cmd == nil {
+		t.Errorf("at dark mode theme %s, notice %q", m.theme.Name, m.notice)
+	}
+	m.tocVisible = true
+	dark = false
+	next, _ = m.Update(nightMsg{})
+	m = next.(model)
+	if m.theme.Name != "dracula" || m.notice != "Night mode off" {
+		t.Errorf("after dark mode theme %s, notice %q, want dracula back even over the TOC", m.theme.Name, m.notice)
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"strings"
+	"time"
+)
+
+// overflowMode is how a word too wide for the display is shown.
+type overflowMode int
+
+const (
+	// overflowMarquee scrolls the word through the display before moving on.
+	overflowMarquee overflowMode = iota
+	// overflowPlaceholder shows longTokenPlaceholder; Enter shows the word.
+	overflowPlaceholder
+)
+
+var overflowModeNames = []string{"marquee", "placeholder"}
+
+// parseOverflowMode parses "marquee" or "placeholder".
+func parseOverflowMode(s string) (overflowMode, bool) {
+	for i, name := range overflowModeNames {
+		if strings.EqualFold(s, name) {
+			return overflowMode(i), true
+		}
+	}
+	return overflowMarquee, false
+}
+
+// longTokenPlaceholder stands in for a word too wide to show.
+const longTokenPlaceholder = "[long token — press Enter to view]"
+
+// marqueeStep is how long a marquee shows each position.
+const marqueeStep = 100 * time.Millisecond
+
+// marqueeSteps returns how many one-character moves it takes to scroll a
+// word through a display width characters wide, or 0 if it fits or the
+// width is not known yet.
+func marqueeSteps(word string, width int) int {
+	if width < 1 {
+		return 0
+	}
+	return max(len([]rune(word))-width, 0)
+}
+
+// marqueeWindow returns the width characters of word shown after step
+// moves, stopping at its end.
+func marqueeWindow(word string, width, step int) string {
+	runes := []rune(word)
+	if width < 1 || len(runes) <= width {
+		return word
+	}
+	start := min(max(step, 0), len(runes)-width)
+	return string(runes[start : start+width])
+}
@@ -0,0 +1,53 @@
+//go:build !gui
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestMarquee(t *testing.T) {
+	if got := marqueeSteps("abcdefghij", 4); got != 6 {
+		t.Errorf("marqueeSteps = %d, want 6", got)
+	}
+	if got := marqueeSteps("abcdefghij", 0); got != 0 {
+		t.Errorf("marqueeSteps with unknown width = %d, want 0", got)
+	}
+	for step, want := range []string{"abcd", "bcde", "cdef"} {
+		if got := marqueeWindow("abcdefghij", 4, step); got != want {
+			t.Errorf("marqueeWindow step %d = %q, want %q", step, got, want)
+		}
+	}
+	if got := marqueeWindow("abcdefghij", 4, 99); got != "ghij" {
+		t.Errorf("marqueeWindow past the end = %q, want ghij", got)
+	}
+
+	m := newModel("go https://example.com/a/very/long/path done", 300, nil, nil)
+	m.width, m.height = 20, 10
+	updated, cmd := m.Update(tickMsg(time.Now()))
+	m = updated.(model)
+	if _, ok := cmd().(marqueeMsg); !ok {
+		t.Fatal("an overflowing word should start a marquee")
+	}
+	for range marqueeSteps(m.CurrentWord(), m.width-2) {
+		updated, cmd = m.Update(marqueeMsg{})
+		m = updated.(model)
+	}
+	if _, ok := cmd().(tickMsg); !ok || !strings.Contains(m.View(), "long/path") {
+		t.Errorf("after scrolling, the word's end should show and the next tick follow:\n%s", m.View())
+	}
+
+	m.overflow = overflowPlaceholder
+	if !strings.Contains(m.View(), longTokenPlaceholder) {
+		t.Errorf("placeholder mode should show the placeholder:\n%s", m.View())
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	if !m.Paused || !strings.Contains(m.View(), "https://example.co") {
+		t.Errorf("Enter should pause and show the word:\n%s", m.View())
+	}
+}
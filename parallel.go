@@ -0,0 +1,39 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+// useParallel aligns a --parallel translation with r's document and,
+// unless --display was given, switches to the parallel display, which
+// shows the translation of each sentence below its words.
+func useParallel(r *reader.Reader, translation string, renderer *reader.WordRenderer) (*reader.ParallelText, error) {
+	if r.Streaming() || r.Incomplete {
+		return nil, errors.New("--parallel needs the whole document, not one read as it streams in")
+	}
+	if !flagPassed("display") {
+		*renderer = reader.ParallelRenderer{}
+	}
+	return reader.NewParallelText(r, translation), nil
+}
+
+// parallelLine returns the translation of the sentence being read, shown
+// below the word by the parallel display, or "" if there is none to show.
+func parallelLine(r *reader.Reader, renderer reader.WordRenderer, p *reader.ParallelText) string {
+	if _, ok := renderer.(reader.ParallelRenderer); !ok || len(r.Words) == 0 {
+		return ""
+	}
+	return p.Sentence(r.SentenceIndex(r.CurrentIndex))
+}
+
+// nextRenderer returns the display mode B switches to after cur, passing
+// over the parallel display when there is no translation to show in it.
+func nextRenderer(cur reader.WordRenderer, p *reader.ParallelText) reader.WordRenderer {
+	next := reader.NextRenderer(cur)
+	if _, ok := next.(reader.ParallelRenderer); ok && p == nil {
+		next = reader.NextRenderer(next)
+	}
+	return next
+}
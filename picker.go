@@ -0,0 +1,159 @@
+//go:build !gui
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/metcalfc/brr/internal/state"
+)
+
+// historyItem implements list.Item for the reading history picker
+type historyItem struct {
+	entry state.HistoryEntry
+}
+
+func (i historyItem) Title() string {
+	if i.entry.Title != "" {
+		return i.entry.Title
+	}
+	return filepath.Base(i.entry.Filename)
+}
+
+func (i historyItem) Description() string {
+	desc := fmt.Sprintf("%s | %s | %s", historyProgress(i.entry), i.entry.LastRead.Format(time.DateOnly), i.entry.Filename)
+	if i.entry.Author != "" {
+		desc = i.entry.Author + " | " + desc
+	}
+	return desc
+}
+
+func (i historyItem) FilterValue() string {
+	return i.Title() + " " + i.entry.Author + " " + i.entry.Filename
+}
+
+type pickerModel struct {
+	list     list.Model
+	selected string
+}
+
+func (m pickerModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m pickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.list.FilterState() == list.Filtering {
+			break
+		}
+		switch msg.String() {
+		case "enter":
+			if item, ok := m.list.SelectedItem().(historyItem); ok {
+				m.selected = item.entry.Filename
+			}
+			return m, tea.Quit
+
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		}
+
+	case tea.WindowSizeMsg:
+		m.list.SetSize(msg.Width, msg.Height)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func (m pickerModel) View() string {
+	return m.list.View()
+}
+
+// historyProgress formats how far into a book the reader got, or when
+// they finished it.
+func historyProgress(entry state.HistoryEntry) string {
+	if !entry.Finished.IsZero() {
+		return "finished " + entry.Finished.Format(time.DateOnly)
+	}
+	if entry.TotalWords <= 0 {
+		return fmt.Sprintf("word %d", entry.WordIndex+1)
+	}
+	return fmt.Sprintf("%d%%", (entry.WordIndex+1)*100/entry.TotalWords)
+}
+
+// readingHistory returns history entries whose files still exist.
+func readingHistory() ([]state.HistoryEntry, error) {
+	store, err := state.NewStateStore()
+	if err != nil {
+		return nil, err
+	}
+	var out []state.HistoryEntry
+	for _, entry := range store.History() {
+		if _, err := os.Stat(entry.Filename); err == nil {
+			out = append(out, entry)
+		}
+	}
+	return out, nil
+}
+
+// printHistory writes the reading history as a table.
+func printHistory(w io.Writer) error {
+	history, err := readingHistory()
+	if err != nil {
+		return err
+	}
+	if len(history) == 0 {
+		fmt.Fprintln(w, "No reading history.")
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "LAST READ\tPROGRESS\tTITLE\tAUTHOR\tFILE")
+	for _, entry := range history {
+		item := historyItem{entry: entry}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			entry.LastRead.Format(time.DateOnly),
+			historyProgress(entry),
+			item.Title(),
+			entry.Author,
+			entry.Filename,
+		)
+	}
+	return tw.Flush()
+}
+
+// pickFromHistory shows the reading history and returns the chosen file,
+// or "" if there is no history or the user cancelled.
+func pickFromHistory() (string, error) {
+	history, err := readingHistory()
+	if err != nil {
+		return "", err
+	}
+	if len(history) == 0 {
+		return "", nil
+	}
+
+	items := make([]list.Item, len(history))
+	for i, entry := range history {
+		items[i] = historyItem{entry: entry}
+	}
+
+	l := list.New(items, list.NewDefaultDelegate(), 80, 24)
+	l.Title = "Reading history"
+	l.SetShowStatusBar(false)
+
+	result, err := tea.NewProgram(pickerModel{list: l}, tea.WithAltScreen()).Run()
+	if err != nil {
+		return "", err
+	}
+	return result.(pickerModel).selected, nil
+}
@@ -0,0 +1,47 @@
+//go:build !gui
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/metcalfc/brr/internal/state"
+)
+
+func TestHistoryProgress(t *testing.T) {
+	tests := []struct {
+		name     string
+		entry    state.HistoryEntry
+		expected string
+	}{
+		{"unknown total", state.HistoryEntry{ReadingState: state.ReadingState{WordIndex: 9}}, "word 10"},
+		{"halfway", state.HistoryEntry{ReadingState: state.ReadingState{WordIndex: 49, TotalWords: 100}}, "50%"},
+		{"finished", state.HistoryEntry{ReadingState: state.ReadingState{WordIndex: 97, TotalWords: 100,
+			Finished: time.Date(2026, 10, 1, 21, 0, 0, 0, time.UTC)}}, "finished 2026-10-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := historyProgress(tt.entry); got != tt.expected {
+				t.Errorf("historyProgress() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPickFromHistory(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	if picked, err := pickFromHistory(); picked != "" || err != nil {
+		t.Errorf("with no history nothing should be picked: %q, %v", picked, err)
+	}
+
+	notDir := filepath.Join(t.TempDir(), "state")
+	os.WriteFile(notDir, nil, 0644)
+	t.Setenv("XDG_STATE_HOME", notDir)
+	if _, err := pickFromHistory(); err == nil {
+		t.Error("a state directory that can't be read should be reported")
+	}
+}
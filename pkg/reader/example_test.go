@@ -0,0 +1,37 @@
+package reader_test
+
+import (
+	"fmt"
+
+	"github.com/metcalfc/brr/pkg/reader"
+)
+
+func ExampleGetORPPosition() {
+	for _, word := range []string{"a", "read", "recognition"} {
+		fmt.Println(word, reader.GetORPPosition(word))
+	}
+	// Output:
+	// a 0
+	// read 1
+	// recognition 3
+}
+
+func ExampleNewReader() {
+	r := reader.NewReader("Speed reading one word at a time.", 300)
+	for {
+		fmt.Println(r.CurrentWord())
+		if !r.Advance() {
+			break
+		}
+	}
+	fmt.Println(r.GetDelay())
+	// Output:
+	// Speed
+	// reading
+	// one
+	// word
+	// at
+	// a
+	// time.
+	// 200ms
+}
@@ -0,0 +1,369 @@
+// Package reader is the public, stable API of brr's RSVP engine.
+//
+// It lets other Go programs embed the reading session logic, format
+// extraction and ORP (Optimal Recognition Point) calculation without
+// copying code. The types are aliases of brr's internal implementation, so
+// values can be passed freely between this package and the brr binaries.
+//
+// A minimal session:
+//
+//	text, err := reader.ExtractText("book.epub")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	r := reader.NewReader(text, 400)
+//	for {
+//		word := r.CurrentWord()
+//		orp := reader.GetORPPosition(word)
+//		// render word with rune orp highlighted, then wait r.GetDelay()
+//		if !r.Advance() {
+//			break
+//		}
+//	}
+package reader
+
+import (
+	"context"
+	"io"
+
+	internal "github.com/metcalfc/brr/internal/reader"
+)
+
+// Reader holds the state for an RSVP speed reading session: the word list,
+// current position, speed, pause state and chapter data.
+type Reader = internal.Reader
+
+// TOCEntry is a single table of contents entry pointing at a word index.
+type TOCEntry = internal.TOCEntry
+
+// Chapter is a titled range of words within a document.
+type Chapter = internal.Chapter
+
+// Format extracts plain text from a file type. Implementations can be added
+// with Register.
+type Format = internal.Format
+
+// TOCProvider is implemented by formats that can produce a table of contents.
+type TOCProvider = internal.TOCProvider
+
+// ChapterExtractor is implemented by formats that can split a document into
+// chapters while extracting its words.
+type ChapterExtractor = internal.ChapterExtractor
+
+// ContextFormat is implemented by formats whose extraction can be cancelled.
+type ContextFormat = internal.ContextFormat
+
+// ContextTOCProvider is implemented by TOC providers that can be cancelled.
+type ContextTOCProvider = internal.ContextTOCProvider
+
+// ContextChapterExtractor is implemented by chapter extractors that can be
+// cancelled.
+type ContextChapterExtractor = internal.ContextChapterExtractor
+
+// MetadataProvider is implemented by formats that record their title and
+// author.
+type MetadataProvider = internal.MetadataProvider
+
+// Metadata is the title and author a document records about itself.
+type Metadata = internal.Metadata
+
+// ChapterProvider supplies a document's words a chapter at a time.
+type ChapterProvider = internal.ChapterProvider
+
+// ChapterTitler is implemented by chapter providers that know their
+// chapters' titles before parsing them.
+type ChapterTitler = internal.ChapterTitler
+
+// ChapterOpener is implemented by formats that can be read a chapter at a
+// time.
+type ChapterOpener = internal.ChapterOpener
+
+// Document is a structured document handed over as JSON: text or words,
+// with chapters, a table of contents and metadata.
+type Document = internal.Document
+
+// DocumentChapter is a chapter of a Document.
+type DocumentChapter = internal.DocumentChapter
+
+// DocumentEntry is a table of contents entry of a Document.
+type DocumentEntry = internal.DocumentEntry
+
+// ReadDocument decodes a Document from JSON.
+func ReadDocument(r io.Reader) (Document, error) {
+	return internal.ReadDocument(r)
+}
+
+// EPUBFormat reads EPUB books. It implements Format, TOCProvider,
+// ChapterExtractor, MetadataProvider and ChapterOpener.
+type EPUBFormat = internal.EPUBFormat
+
+// MarkdownFormat reads Markdown files. It implements Format, TOCProvider and
+// ChapterExtractor.
+type MarkdownFormat = internal.MarkdownFormat
+
+// OrgFormat reads Emacs Org-mode files. It implements Format, TOCProvider and
+// ChapterExtractor.
+type OrgFormat = internal.OrgFormat
+
+// RSTFormat reads reStructuredText files. It implements Format, TOCProvider
+// and ChapterExtractor.
+type RSTFormat = internal.RSTFormat
+
+// NewReader creates a Reader from text at the given words-per-minute speed.
+func NewReader(text string, wpm int) *Reader {
+	return internal.NewReader(text, wpm)
+}
+
+// ParallelText is a translation of a Reader's document, aligned with it
+// sentence by sentence.
+type ParallelText = internal.ParallelText
+
+// NewParallelText aligns translation with the sentences of r's whole
+// document by their lengths.
+func NewParallelText(r *Reader, translation string) *ParallelText {
+	return internal.NewParallelText(r, translation)
+}
+
+// ParseText splits text into display words.
+func ParseText(text string) []string {
+	return internal.ParseText(text)
+}
+
+// Token is a word and its place in the structure of the text: whether it
+// starts a sentence, paragraph or chapter, whether it is a number, and its
+// length class.
+type Token = internal.Token
+
+// LengthClass buckets a word by the length of its core.
+type LengthClass = internal.LengthClass
+
+// Length classes of a Token.
+const (
+	LengthShort    = internal.LengthShort
+	LengthMedium   = internal.LengthMedium
+	LengthLong     = internal.LengthLong
+	LengthVeryLong = internal.LengthVeryLong
+)
+
+// Default pause multipliers at paragraph and chapter starts.
+const (
+	DefaultParagraphPause = internal.DefaultParagraphPause
+	DefaultChapterPause   = internal.DefaultChapterPause
+)
+
+// NewToken describes a single word, leaving its structural flags unset.
+func NewToken(word string) Token {
+	return internal.NewToken(word)
+}
+
+// ParseTokens splits text into tokens, marking the first word of each
+// sentence and paragraph.
+func ParseTokens(text string) []Token {
+	return internal.ParseTokens(text)
+}
+
+// StopwordMode controls how common function words are shown.
+type StopwordMode = internal.StopwordMode
+
+// Stopword modes of a Reader.
+const (
+	StopwordsShow = internal.StopwordsShow
+	StopwordsHalf = internal.StopwordsHalf
+	StopwordsSkip = internal.StopwordsSkip
+)
+
+// Stopwords returns the built-in function word set for a language code
+// such as "en" or "de".
+func Stopwords(lang string) (map[string]bool, bool) {
+	return internal.Stopwords(lang)
+}
+
+// Frequencies returns the ranks of the built-in commonest words of a
+// language code such as "en", for a Reader's Frequencies.
+func Frequencies(lang string) (map[string]int, bool) {
+	return internal.Frequencies(lang)
+}
+
+// LinkPolicy controls how URLs and email addresses are shown.
+type LinkPolicy = internal.LinkPolicy
+
+// Link policies of a Reader.
+const (
+	LinksFull        = internal.LinksFull
+	LinksDomain      = internal.LinksDomain
+	LinksPlaceholder = internal.LinksPlaceholder
+	LinksSkip        = internal.LinksSkip
+)
+
+// LinkKind tells URLs and email addresses apart from ordinary words.
+type LinkKind = internal.LinkKind
+
+// Link kinds of a Token.
+const (
+	LinkNone  = internal.LinkNone
+	LinkURL   = internal.LinkURL
+	LinkEmail = internal.LinkEmail
+)
+
+// ParseLinkPolicy parses "full", "domain", "placeholder" or "skip".
+func ParseLinkPolicy(s string) (LinkPolicy, bool) {
+	return internal.ParseLinkPolicy(s)
+}
+
+// NoteMode sets what becomes of an EPUB's footnotes and endnotes; see
+// EPUBFormat.Notes.
+type NoteMode = internal.NoteMode
+
+// Note modes of an EPUBFormat.
+const (
+	NotesSkip     = internal.NotesSkip
+	NotesAppendix = internal.NotesAppendix
+	NotesInline   = internal.NotesInline
+)
+
+// ParseNoteMode parses "skip", "appendix" or "inline".
+func ParseNoteMode(s string) (NoteMode, bool) {
+	return internal.ParseNoteMode(s)
+}
+
+// RubyMode sets what becomes of an EPUB's ruby annotations, such as
+// furigana; see EPUBFormat.Ruby.
+type RubyMode = internal.RubyMode
+
+// Ruby modes of an EPUBFormat.
+const (
+	RubySkip   = internal.RubySkip
+	RubyInline = internal.RubyInline
+)
+
+// ParseRubyMode parses "skip" or "inline".
+func ParseRubyMode(s string) (RubyMode, bool) {
+	return internal.ParseRubyMode(s)
+}
+
+// Pacer decides how long each word is shown; see Reader.Pacer.
+type Pacer = internal.Pacer
+
+// Built-in pacers.
+type (
+	FixedPacer       = internal.FixedPacer
+	PunctuationPacer = internal.PunctuationPacer
+	LengthPacer      = internal.LengthPacer
+	SyllablePacer    = internal.SyllablePacer
+	AdaptivePacer    = internal.AdaptivePacer
+)
+
+// ParsePacer returns the built-in pacer called name: "fixed",
+// "punctuation", "length", "syllable" or "adaptive".
+func ParsePacer(name string) (Pacer, bool) {
+	return internal.ParsePacer(name)
+}
+
+// PacerName returns the name ParsePacer accepts for a built-in pacer, or
+// "" for any other.
+func PacerName(p Pacer) string {
+	return internal.PacerName(p)
+}
+
+// PacerNames lists the pacers accepted by ParsePacer.
+func PacerNames() []string {
+	return internal.PacerNames()
+}
+
+// DefaultRewindAfter is the shortest pause after which resuming rewinds.
+const DefaultRewindAfter = internal.DefaultRewindAfter
+
+// DefaultMaxWordLength is the longest word, in runes, shown in one frame.
+const DefaultMaxWordLength = internal.DefaultMaxWordLength
+
+// SplitLongWord splits a word longer than max runes into hyphenated frames.
+func SplitLongWord(word string, max int) []string {
+	return internal.SplitLongWord(word, max)
+}
+
+// FindSentenceStarts returns the indices of words that begin sentences.
+func FindSentenceStarts(words []string) []int {
+	return internal.FindSentenceStarts(words)
+}
+
+// GetORPPosition returns the rune index of the Optimal Recognition Point
+// of word, the character the eye should fixate on.
+func GetORPPosition(word string) int {
+	return internal.GetORPPosition(word)
+}
+
+// Language is how text in a language is read where it differs from
+// English; the zero Language is English.
+type Language = internal.Language
+
+// LanguageFor returns the sentence punctuation of a language code such as
+// "el", for Reader.SetLanguage. It reports whether the language is known;
+// unknown ones are read as English is.
+func LanguageFor(code string) (Language, bool) {
+	return internal.LanguageFor(code)
+}
+
+// Languages lists the language codes accepted by LanguageFor.
+func Languages() []string {
+	return internal.Languages()
+}
+
+// ExtractText extracts text from a file using the registered format for its
+// extension, falling back to reading it as plain text.
+func ExtractText(filename string) (string, error) {
+	return internal.ExtractText(filename)
+}
+
+// ExtractTextContext is ExtractText stopping early when ctx is cancelled.
+func ExtractTextContext(ctx context.Context, filename string) (string, error) {
+	return internal.ExtractTextContext(ctx, filename)
+}
+
+// ExtractTextFromURL fetches a web page and extracts its readable text.
+func ExtractTextFromURL(rawURL string) (string, error) {
+	return internal.ExtractTextFromURL(rawURL)
+}
+
+// ExtractTextFromURLContext is ExtractTextFromURL with requests cancelled
+// when ctx is.
+func ExtractTextFromURLContext(ctx context.Context, rawURL string) (string, error) {
+	return internal.ExtractTextFromURLContext(ctx, rawURL)
+}
+
+// DecodeText returns text in UTF-8, UTF-16 or Windows-1252 (Latin-1) as
+// UTF-8.
+func DecodeText(data []byte) string {
+	return internal.DecodeText(data)
+}
+
+// Normalize converts HTML entities, typographic quotes, soft hyphens,
+// ligatures and em-dashes between words to the forms words are shown in.
+func Normalize(text string) string {
+	return internal.Normalize(text)
+}
+
+// ExtractHTML returns the readable text of an HTML page, such as one sent
+// by a browser extension.
+func ExtractHTML(page string) string {
+	return internal.ExtractHTML(page)
+}
+
+// Register adds a format to the registry used by ExtractText.
+func Register(f Format) {
+	internal.Register(f)
+}
+
+// SupportedFormats lists registered format names with their extensions.
+func SupportedFormats() []string {
+	return internal.SupportedFormats()
+}
+
+// FormatInfo describes a registered format: its extensions and which
+// optional interfaces it implements.
+type FormatInfo = internal.FormatInfo
+
+// Formats describes the registered formats in the order they were
+// registered.
+func Formats() []FormatInfo {
+	return internal.Formats()
+}
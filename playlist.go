@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/metcalfc/brr/internal/reader"
+	"github.com/metcalfc/brr/internal/state"
+)
+
+// expandFiles expands the glob patterns among the file arguments, for
+// shells that leave them alone and for patterns quoted to keep them from
+// the shell. An argument naming a file or URL is kept as it is.
+func expandFiles(args []string) ([]string, error) {
+	var files []string
+	for _, arg := range args {
+		if reader.IsURL(arg) || !strings.ContainsAny(arg, "*?[") {
+			files = append(files, arg)
+			continue
+		}
+		if _, err := os.Stat(arg); err == nil {
+			files = append(files, arg)
+			continue
+		}
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			return nil, fmt.Errorf("bad pattern %q: %w", arg, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no files match %q", arg)
+		}
+		files = append(files, matches...)
+	}
+	return files, nil
+}
+
+// playlistLoader returns a loadFunc reading files one after another as a
+// single document, each file a chapter. The files' own tables of contents
+// are kept a level below.
+func playlistLoader(files []string, opts loadOptions) loadFunc {
+	return func(ctx context.Context, progress func(loadEvent)) (string, []reader.TOCEntry, []reader.Chapter, error) {
+		opts.progress = progress
+		pages := make([]reader.DocsPage, len(files))
+		for i, file := range files {
+			pages[i] = reader.DocsPage{Title: bookInfo(file).Title, File: file}
+		}
+		return loadPages(ctx, pages, opts)
+	}
+}
+
+// loadDocs reads the Markdown pages under dir in reading order as a single
+// document, each page a chapter, nested in the table of contents as the
+// tree is.
+func loadDocs(ctx context.Context, dir string, opts loadOptions) (string, []reader.TOCEntry, []reader.Chapter, error) {
+	pages, err := reader.DocsTree(dir)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return loadPages(ctx, pages, opts)
+}
+
+// loadPages reads pages one after another as a single document. Each page
+// with a file is a chapter with a TOC entry at its level, and the file's
+// own TOC entries are kept a level below; a heading is a TOC entry at the
+// start of the pages after it. Each page is reported ready as it is read,
+// so reading can start before the rest arrive.
+func loadPages(ctx context.Context, pages []reader.DocsPage, opts loadOptions) (string, []reader.TOCEntry, []reader.Chapter, error) {
+	var parts []loadedPart
+	var headings []reader.TOCEntry
+	files := 0
+	for _, page := range pages {
+		if page.File != "" {
+			files++
+		}
+	}
+	read, words := 0, 0
+	for _, page := range pages {
+		if page.File == "" {
+			headings = append(headings, reader.TOCEntry{Title: page.Title, Level: page.Level})
+			continue
+		}
+		read++
+		e := readingEvent(page.File)
+		e.part, e.parts = read, files
+		opts.report(e)
+		text, fileTOC, _, err := loadFile(ctx, page.File, opts)
+		if err != nil {
+			return "", nil, nil, fmt.Errorf("%s: %w", page.File, err)
+		}
+		fileWords := reader.ParseText(text)
+		if len(fileWords) == 0 {
+			continue
+		}
+		part := loadedPart{text: text, words: len(fileWords)}
+		for _, h := range headings {
+			h.Preview = previewOf(fileWords)
+			part.toc = append(part.toc, h)
+		}
+		headings = nil
+		part.chapters = []reader.Chapter{{Title: page.Title, WordEnd: len(fileWords) - 1}}
+		part.toc = append(part.toc, reader.TOCEntry{Title: page.Title, Preview: previewOf(fileWords), Level: page.Level})
+		// The file's own entries go a level below the page's, leaving out
+		// its title heading, which the page's entry stands for.
+		var own []reader.TOCEntry
+		for _, entry := range fileTOC {
+			if entry.WordIndex != 0 || entry.Title != page.Title {
+				own = append(own, entry)
+			}
+		}
+		top := 0
+		if len(own) > 0 {
+			top = slices.MinFunc(own, func(a, b reader.TOCEntry) int { return a.Level - b.Level }).Level
+		}
+		for _, entry := range own {
+			entry.Level += page.Level + 1 - top
+			part.toc = append(part.toc, entry)
+		}
+		parts = append(parts, part)
+		words += part.words
+		opts.report(loadEvent{stage: loadReady, words: words, part: read, parts: files, loaded: &part})
+	}
+	if len(parts) == 0 {
+		return "", nil, nil, errors.New("no text to read")
+	}
+	text, toc, chapters := joinParts(parts)
+	return text, toc, chapters, nil
+}
+
+// previewOf returns the first few words of a file for its TOC entry.
+func previewOf(words []string) string {
+	if len(words) > 10 {
+		words = words[:10]
+	}
+	return strings.Join(words, " ") + "..."
+}
+
+// playlistInfo returns the title a set of files read together records:
+// the first file's, counting the rest.
+func playlistInfo(files []string) reader.Metadata {
+	book := bookInfo(files[0])
+	book.Title = fmt.Sprintf("%s (+%d more)", book.Title, len(files)-1)
+	return book
+}
+
+// sourceHash returns the hash saved state is kept under: the source file's
+// or, when several files are read together, as listed or as the pages of a
+// directory, the set's.
+func sourceHash(sourceFile string, files []string) (string, error) {
+	if len(files) > 1 {
+		return state.ComputeSetHash(files)
+	}
+	if info, err := os.Stat(sourceFile); err == nil && info.IsDir() {
+		pages, err := reader.DocsTree(sourceFile)
+		if err != nil {
+			return "", err
+		}
+		var pageFiles []string
+		for _, page := range pages {
+			if page.File != "" {
+				pageFiles = append(pageFiles, page.File)
+			}
+		}
+		if len(pageFiles) == 0 {
+			return "", errors.New("no pages to read")
+		}
+		return state.ComputeSetHash(pageFiles)
+	}
+	return state.ComputeHash(sourceFile)
+}
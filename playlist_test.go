@@ -0,0 +1,64 @@
+//go:build !gui
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestPlaylist(t *testing.T) {
+	dir := t.TempDir()
+	for name, text := range map[string]string{
+		"ch1.md": "# One\n\nCall me Ishmael.",
+		"ch2.md": "Some years ago.\n\nNever mind how long.",
+		"ch3.md": "",
+	} {
+		os.WriteFile(filepath.Join(dir, name), []byte(text), 0644)
+	}
+	files, err := expandFiles([]string{filepath.Join(dir, "ch*.md")})
+	if err != nil || len(files) != 3 || filepath.Base(files[0]) != "ch1.md" {
+		t.Fatalf("expandFiles = %v, %v", files, err)
+	}
+	if _, err := expandFiles([]string{filepath.Join(dir, "*.epub")}); err == nil {
+		t.Error("a pattern matching nothing should fail")
+	}
+
+	var events []string
+	text, toc, chapters, err := playlistLoader(files, loadOptions{})(context.Background(), func(e loadEvent) {
+		events = append(events, e.String())
+	})
+	if err != nil {
+		t.Fatalf("playlistLoader: %v", err)
+	}
+	wantEvents := []string{"Reading ch1.md (1 of 3)", "5 words ready (1 of 3)", "Reading ch2.md (2 of 3)", "12 words ready (2 of 3)", "Reading ch3.md (3 of 3)"}
+	if !reflect.DeepEqual(events, wantEvents) {
+		t.Errorf("events = %q, want %q", events, wantEvents)
+	}
+	if len(chapters) != 2 || chapters[0].Title != "ch1" || chapters[1].Title != "ch2" {
+		t.Fatalf("chapters = %+v, want one per file with text", chapters)
+	}
+	r := newModel(text, 300, toc, chapters)
+	if got := r.Words[chapters[1].WordStart]; got != "Some" {
+		t.Errorf("the second file starts at %q", got)
+	}
+	if !r.IsParagraphStart(chapters[1].WordStart) {
+		t.Error("each file should start a paragraph")
+	}
+	if len(toc) < 2 || toc[len(toc)-1].WordIndex != chapters[1].WordStart {
+		t.Errorf("toc = %+v", toc)
+	}
+
+	book := playlistInfo(files)
+	if book.Title != "ch1 (+2 more)" {
+		t.Errorf("playlistInfo = %+v", book)
+	}
+	hash, err := sourceHash(files[0], files)
+	single, _ := sourceHash(files[0], files[:1])
+	if err != nil || hash == single {
+		t.Errorf("the set should be saved apart from its first file: %s, %s, %v", hash, single, err)
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+// maxPresets is how many presets the number keys 1-9 can switch to.
+const maxPresets = 9
+
+// preset is a named set of reading settings, switched to with a number key
+// while reading. Settings left out of a preset are not changed by it.
+type preset struct {
+	name         string
+	wpm          int
+	renderer     reader.WordRenderer
+	pacer        reader.Pacer
+	pacerName    string
+	stopwordMode reader.StopwordMode
+	setStopwords bool
+}
+
+// defaultPresetsPath returns XDG_CONFIG_HOME/brr/presets or
+// ~/.config/brr/presets.
+func defaultPresetsPath() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "brr", "presets")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".config", "brr", "presets")
+}
+
+// loadPresets reads the presets file at path. A missing file has no
+// presets.
+func loadPresets(path string) ([]preset, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	presets, err := parsePresets(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return presets, nil
+}
+
+// parsePresets reads presets, one per line: a name followed by settings,
+// such as
+//
+//	skim  wpm=700 pacing=fixed stopwords=skip
+//	study wpm=250 display=vertical pacing=adaptive
+//
+// Blank lines and lines starting with # are ignored. The first preset is
+// switched to with 1, the second with 2 and so on.
+func parsePresets(r io.Reader) ([]preset, error) {
+	var presets []preset
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if len(presets) == maxPresets {
+			return nil, fmt.Errorf("line %d: at most %d presets fit the number keys", line, maxPresets)
+		}
+		p := preset{name: fields[0]}
+		for _, field := range fields[1:] {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				return nil, fmt.Errorf("line %d: setting %q is not key=value", line, field)
+			}
+			if err := p.set(key, value); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+		}
+		presets = append(presets, p)
+	}
+	return presets, scanner.Err()
+}
+
+// set applies one key=value setting of a presets file line.
+func (p *preset) set(key, value string) error {
+	var ok bool
+	switch key {
+	case "wpm":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 100 || n > 1500 {
+			return fmt.Errorf("wpm %q is not between 100 and 1500", value)
+		}
+		p.wpm = n
+		return nil
+	case "display":
+		if p.renderer, ok = reader.RendererByName(value); !ok {
+			return fmt.Errorf("unknown display mode %q (choose from %s)", value, strings.Join(reader.RendererNames(), ", "))
+		}
+	case "pacing":
+		if p.pacer, ok = reader.ParsePacer(value); !ok {
+			return fmt.Errorf("unknown pacing %q (choose from %s)", value, strings.Join(reader.PacerNames(), ", "))
+		}
+		p.pacerName = strings.ToLower(value)
+	case "stopwords":
+		if p.stopwordMode, ok = reader.ParseStopwordMode(value); !ok {
+			return fmt.Errorf("unknown stopwords mode %q (choose from off, half, skip)", value)
+		}
+		p.setStopwords = true
+	default:
+		return fmt.Errorf("unknown setting %q (choose from wpm, display, pacing, stopwords)", key)
+	}
+	return nil
+}
+
+// apply switches r and renderer to the preset's settings and returns a
+// notice describing them.
+func (p preset) apply(r *reader.Reader, renderer *reader.WordRenderer) string {
+	var parts []string
+	if p.wpm > 0 {
+		r.WPM = p.wpm
+		parts = append(parts, fmt.Sprintf("%d WPM", p.wpm))
+	}
+	if p.renderer != nil {
+		*renderer = p.renderer
+		parts = append(parts, p.renderer.Name())
+	}
+	if p.pacer != nil {
+		r.Pacer = p.pacer
+		parts = append(parts, p.pacerName+" pacing")
+	}
+	if p.setStopwords {
+		r.StopwordMode = p.stopwordMode
+		parts = append(parts, "function words "+p.stopwordMode.String())
+	}
+	if len(parts) == 0 {
+		return "Preset " + p.name
+	}
+	return "Preset " + p.name + ": " + strings.Join(parts, ", ")
+}
+
+// presetKey returns the preset switched to by key, a number from 1 to 9.
+func presetKey(presets []preset, key string) (preset, bool) {
+	n, err := strconv.Atoi(key)
+	if err != nil || n < 1 || n > len(presets) {
+		return preset{}, false
+	}
+	return presets[n-1], true
+}
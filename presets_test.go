@@ -0,0 +1,70 @@
+//go:build !gui
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+func TestParsePresets(t *testing.T) {
+	presets, err := parsePresets(strings.NewReader(`# reading presets
+skim  wpm=700 pacing=fixed stopwords=skip
+
+study wpm=250 display=vertical pacing=Adaptive
+plain
+`))
+	if err != nil {
+		t.Fatalf("parsePresets: %v", err)
+	}
+	if len(presets) != 3 {
+		t.Fatalf("got %d presets, want 3", len(presets))
+	}
+	if p := presets[0]; p.name != "skim" || p.wpm != 700 || p.pacerName != "fixed" || !p.setStopwords || p.stopwordMode != reader.StopwordsSkip {
+		t.Errorf("skim = %+v", p)
+	}
+	if p := presets[1]; p.renderer == nil || p.renderer.Name() != "vertical" || p.pacer != (reader.AdaptivePacer{}) {
+		t.Errorf("study = %+v", p)
+	}
+
+	for _, bad := range []string{
+		"fast wpm=9000",
+		"fast wpm",
+		"fast speed=500",
+		"fast display=sideways",
+		"fast pacing=random",
+		strings.Repeat("p wpm=300\n", maxPresets+1),
+	} {
+		if _, err := parsePresets(strings.NewReader(bad)); err == nil {
+			t.Errorf("parsePresets(%q) succeeded, want an error", bad)
+		}
+	}
+}
+
+func TestPresetKeys(t *testing.T) {
+	m := newModel("one two three four", 300, nil, nil)
+	m.presets, _ = parsePresets(strings.NewReader("skim wpm=700 stopwords=half\nstudy wpm=250 display=vertical\n"))
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'2'}})
+	m = updated.(model)
+	if m.WPM != 250 || m.renderer.Name() != "vertical" || m.StopwordMode != reader.StopwordsShow {
+		t.Errorf("after 2: WPM %d, display %s, function words %s", m.WPM, m.renderer.Name(), m.StopwordMode)
+	}
+	if want := "Preset study: 250 WPM, vertical"; m.notice != want {
+		t.Errorf("notice = %q, want %q", m.notice, want)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'1'}})
+	m = updated.(model)
+	if m.WPM != 700 || m.renderer.Name() != "vertical" || m.StopwordMode != reader.StopwordsHalf {
+		t.Errorf("after 1: WPM %d, display %s, function words %s", m.WPM, m.renderer.Name(), m.StopwordMode)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'3'}})
+	if m = updated.(model); m.WPM != 700 || m.notice != "" {
+		t.Errorf("3 with two presets changed WPM to %d, notice %q", m.WPM, m.notice)
+	}
+}
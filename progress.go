@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+// loadStage is how far loading a document has got.
+type loadStage int
+
+const (
+	// loadNote is a message about loading, such as a retry.
+	loadNote loadStage = iota
+	// loadFetching is downloading a source.
+	loadFetching
+	// loadExtracting is reading the text out of a source.
+	loadExtracting
+	// loadReady is text ready to read.
+	loadReady
+)
+
+// loadEvent is a step in loading a document, shown on the loading screen.
+type loadEvent struct {
+	stage loadStage
+	// source is the file or URL fetched or extracted.
+	source string
+	// part counts the sources of a document read from several, such as a
+	// directory of pages, out of parts, which is 0 while the number to
+	// come isn't known.
+	part, parts int
+	// words is how many words are ready, at loadReady.
+	words int
+	// loaded, at loadReady, is the source just read, when a document is
+	// loaded a part at a time and can be read before the rest arrives.
+	loaded *loadedPart
+	// text is a note's message.
+	text string
+}
+
+func (e loadEvent) String() string {
+	var s string
+	switch e.stage {
+	case loadFetching:
+		s = "Fetching " + e.source
+	case loadExtracting:
+		s = "Reading " + e.source
+	case loadReady:
+		s = groupDigits(e.words) + " words ready"
+	default:
+		return e.text
+	}
+	if e.parts > 1 {
+		s += fmt.Sprintf(" (%d of %d)", e.part, e.parts)
+	} else if e.parts == 0 && e.part > 0 {
+		s += fmt.Sprintf(" (part %d)", e.part)
+	}
+	return s
+}
+
+// more reports whether a part loaded may not be the last to come.
+func (e loadEvent) more() bool {
+	return e.loaded != nil && (e.parts == 0 || e.part < e.parts)
+}
+
+// readingEvent is the event for starting to load source: fetching a URL,
+// or reading the text out of a file.
+func readingEvent(source string) loadEvent {
+	if reader.IsURL(source) {
+		return loadEvent{stage: loadFetching, source: source}
+	}
+	return loadEvent{stage: loadExtracting, source: filepath.Base(source)}
+}
+
+// loadedPart is a piece of a document loaded from several sources, with
+// its TOC entries and chapters counted from its first word.
+type loadedPart struct {
+	text     string
+	words    int
+	toc      []reader.TOCEntry
+	chapters []reader.Chapter
+}
+
+// joinParts puts loaded parts together into one document.
+func joinParts(parts []loadedPart) (string, []reader.TOCEntry, []reader.Chapter) {
+	texts := make([]string, len(parts))
+	var toc []reader.TOCEntry
+	var chapters []reader.Chapter
+	words := 0
+	for i, p := range parts {
+		texts[i] = p.text
+		for _, e := range p.toc {
+			e.WordIndex += words
+			toc = append(toc, e)
+		}
+		for _, c := range p.chapters {
+			c.WordStart += words
+			c.WordEnd += words
+			chapters = append(chapters, c)
+		}
+		words += p.words
+	}
+	return strings.Join(texts, "\n\n"), toc, chapters
+}
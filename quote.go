@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+// defaultCaptureTemplate formats a captured sentence as a Markdown quote.
+const defaultCaptureTemplate = `> {sentence}\n>\n> — {source}, {ref} ({date})\n\n`
+
+// sentenceRef returns the index of the first word of the sentence around
+// the current word and a reference to it: its chapter and position.
+func sentenceRef(r *reader.Reader) (int, string) {
+	start := r.SentenceStart(r.CurrentIndex)
+	_, total := r.Progress()
+	ref := fmt.Sprintf("word %d of %d", r.Base+start+1, total)
+	if title := r.CurrentChapterTitle(); title != "" {
+		ref = title + ", " + ref
+	}
+	return start, ref
+}
+
+// sentenceQuote formats the sentence around the current word for pasting
+// into notes: the sentence in quotes, then its chapter and the position of
+// its first word.
+func sentenceQuote(r *reader.Reader) string {
+	start, ref := sentenceRef(r)
+	return fmt.Sprintf("“%s”\n— %s", r.SentenceFrom(start), ref)
+}
+
+// captureSentence appends the sentence around the current word to a
+// Markdown file, such as a notes app's daily note, and returns the file's
+// path. In path, a leading ~ is the home directory and {date} is today's
+// date. The template's {sentence}, {source}, {chapter}, {position}, {ref},
+// {date} and {time} are filled in, and \n starts a new line.
+func captureSentence(r *reader.Reader, path, template, source string, now time.Time) (string, error) {
+	date := now.Format("2006-01-02")
+	path = strings.ReplaceAll(path, "{date}", date)
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(home, rest)
+	}
+	if source == "" {
+		source = "stdin"
+	}
+
+	start, ref := sentenceRef(r)
+	entry := strings.NewReplacer(
+		`\n`, "\n",
+		"{sentence}", r.SentenceFrom(start),
+		"{source}", source,
+		"{chapter}", r.CurrentChapterTitle(),
+		"{position}", strconv.Itoa(r.Base+start+1),
+		"{ref}", ref,
+		"{date}", date,
+		"{time}", now.Format("15:04"),
+	).Replace(template)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.WriteString(entry); err != nil {
+		f.Close()
+		return "", err
+	}
+	return path, f.Close()
+}
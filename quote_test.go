@@ -0,0 +1,41 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+func TestCaptureSentence(t *testing.T) {
+	dir := t.TempDir()
+	chapters := []reader.Chapter{{Title: "Loomings", WordStart: 0, WordEnd: 5}}
+	r := reader.NewReader("Call me Ishmael. Some years ago", 300)
+	r.SetChapters(chapters, nil)
+	r.JumpToChapter(4)
+	now := time.Date(2026, 3, 14, 9, 30, 0, 0, time.UTC)
+
+	path, err := captureSentence(r, filepath.Join(dir, "{date}.md"), defaultCaptureTemplate, "moby.epub", now)
+	if err != nil {
+		t.Fatalf("captureSentence: %v", err)
+	}
+	if want := filepath.Join(dir, "2026-03-14.md"); path != want {
+		t.Errorf("path = %q, want %q", path, want)
+	}
+	r.JumpToChapter(0)
+	if _, err := captureSentence(r, path, `- {sentence} ({chapter} #{position}, {time})\n`, "", now); err != nil {
+		t.Fatalf("captureSentence: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "> Some years ago\n>\n> — moby.epub, Loomings, word 4 of 6 (2026-03-14)\n\n" +
+		"- Call me Ishmael. (Loomings #1, 09:30)\n"
+	if string(data) != want {
+		t.Errorf("capture file = %q, want %q", data, want)
+	}
+}
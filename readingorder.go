@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/metcalfc/brr/internal/reader"
+	"github.com/metcalfc/brr/internal/state"
+)
+
+// readingOrderHint lists the keys of the reading order editor.
+const readingOrderHint = "↑/↓: select  SPACE: include  Shift+↑/↓ or K/J: move  D: document order  Enter: save  Esc: cancel"
+
+// readingOrder edits which chapters to read and in what order, opened from
+// the table of contents with E. The chapters read come first, in the
+// order read, followed by those left out in document order.
+type readingOrder struct {
+	chapters []reader.Chapter
+	items    []orderItem
+	cursor   int
+}
+
+// orderItem is a chapter in the reading order editor.
+type orderItem struct {
+	chapter  int
+	included bool
+}
+
+// newReadingOrder returns an editor over r's chapters and current
+// sequence, or nil if r has too few chapters to reorder.
+func newReadingOrder(r *reader.Reader) *readingOrder {
+	if len(r.Chapters) < 2 || r.Streaming() {
+		return nil
+	}
+	o := &readingOrder{chapters: r.Chapters}
+	sequence := r.Sequence()
+	if len(sequence) == 0 {
+		o.reset()
+		return o
+	}
+	for _, c := range sequence {
+		o.items = append(o.items, orderItem{chapter: c, included: true})
+	}
+	for c := range r.Chapters {
+		if !slices.Contains(sequence, c) {
+			o.items = append(o.items, orderItem{chapter: c})
+		}
+	}
+	return o
+}
+
+// reset includes every chapter in document order.
+func (o *readingOrder) reset() {
+	o.items = o.items[:0]
+	for c := range o.chapters {
+		o.items = append(o.items, orderItem{chapter: c, included: true})
+	}
+}
+
+// selectBy moves the cursor by delta chapters.
+func (o *readingOrder) selectBy(delta int) {
+	o.cursor = min(max(o.cursor+delta, 0), len(o.items)-1)
+}
+
+// toggle includes or leaves out the selected chapter.
+func (o *readingOrder) toggle() {
+	o.items[o.cursor].included = !o.items[o.cursor].included
+}
+
+// move moves the selected chapter delta places earlier or later.
+func (o *readingOrder) move(delta int) {
+	to := o.cursor + delta
+	if to < 0 || to >= len(o.items) {
+		return
+	}
+	o.items[o.cursor], o.items[to] = o.items[to], o.items[o.cursor]
+	o.cursor = to
+}
+
+// sequence returns the chapters to read in order, or nil when that is
+// every chapter in document order, or none.
+func (o *readingOrder) sequence() []int {
+	var seq []int
+	for _, item := range o.items {
+		if item.included {
+			seq = append(seq, item.chapter)
+		}
+	}
+	inOrder := len(seq) == len(o.chapters)
+	for i, c := range seq {
+		inOrder = inOrder && c == i
+	}
+	if inOrder {
+		return nil
+	}
+	return seq
+}
+
+// lines returns a line per chapter, marking those included and the
+// selected one.
+func (o *readingOrder) lines() []string {
+	lines := make([]string, len(o.items))
+	for i, item := range o.items {
+		cursor, check := " ", "[ ]"
+		if i == o.cursor {
+			cursor = ">"
+		}
+		if item.included {
+			check = "[x]"
+		}
+		lines[i] = fmt.Sprintf("%s %s %s", cursor, check, o.chapters[item.chapter].Title)
+	}
+	return lines
+}
+
+// sequenceNotice describes the reading order set by sequence, read out of
+// chapters in all.
+func sequenceNotice(sequence []int, chapters int) string {
+	switch {
+	case len(sequence) == 0:
+		return "Reading every chapter in order"
+	case len(sequence) == chapters:
+		return fmt.Sprintf("Reading all %d chapters in a custom order", chapters)
+	}
+	return fmt.Sprintf("Reading %d of %d chapters", len(sequence), chapters)
+}
+
+// applyReadingOrder reads r's chapters in sequence from now on, saving it
+// for the book with hash, and returns a notice describing it.
+func applyReadingOrder(r *reader.Reader, store *state.StateStore, hash string, sequence []int) string {
+	r.SetSequence(sequence)
+	notice := sequenceNotice(r.Sequence(), len(r.Chapters))
+	if store != nil && hash != "" {
+		if err := store.SetSequence(hash, r.Sequence()); err != nil {
+			notice = "Could not save: " + err.Error()
+		}
+	}
+	return notice
+}
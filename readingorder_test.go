@@ -0,0 +1,56 @@
+//go:build !gui
+
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/metcalfc/brr/internal/reader"
+	"github.com/metcalfc/brr/internal/state"
+)
+
+func TestReadingOrder(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	store, _ := state.NewStateStore()
+	chapters := []reader.Chapter{{Title: "One", WordStart: 0, WordEnd: 1}, {Title: "Exercises", WordStart: 2, WordEnd: 3}, {Title: "Appendix", WordStart: 4, WordEnd: 5}}
+	toc := []reader.TOCEntry{{Title: "One", WordIndex: 0}, {Title: "Exercises", WordIndex: 2}, {Title: "Appendix", WordIndex: 4}}
+	m := newModel("one two three four five six", 300, toc, chapters)
+	m.stateStore, m.fileHash = store, "abcdef1234567890abcdef1234567890"
+	m.tocVisible = true
+
+	press := func(keys ...tea.KeyMsg) {
+		for _, k := range keys {
+			updated, _ := m.Update(k)
+			m = updated.(model)
+		}
+	}
+	key := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+	press(key("e"))
+	if m.order == nil || !strings.Contains(m.View(), "> [x] One") {
+		t.Fatalf("E should open the reading order:\n%s", m.View())
+	}
+	// Leave out the exercises and read the appendix first.
+	press(tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}, tea.KeyMsg{Type: tea.KeyDown}, key("K"), key("K"), tea.KeyMsg{Type: tea.KeyEnter})
+	if m.order != nil || m.tocVisible || m.notice != "Reading 2 of 3 chapters" {
+		t.Errorf("saving should close the panel with a notice, got %q", m.notice)
+	}
+	if !slices.Equal(m.Sequence(), []int{2, 0}) || m.CurrentWord() != "five" {
+		t.Errorf("sequence %v at %q, want [2 0] at five", m.Sequence(), m.CurrentWord())
+	}
+	if !slices.Equal(store.GetSequence(m.fileHash), []int{2, 0}) {
+		t.Errorf("saved sequence = %v", store.GetSequence(m.fileHash))
+	}
+
+	m.tocVisible = true
+	press(key("e"), key("d"), tea.KeyMsg{Type: tea.KeyEsc})
+	if m.order != nil || !m.tocVisible || m.Sequence() == nil {
+		t.Error("Esc should leave the reading order as it was")
+	}
+	press(key("e"), key("d"), tea.KeyMsg{Type: tea.KeyEnter})
+	if m.Sequence() != nil || store.GetSequence(m.fileHash) != nil || m.notice != "Reading every chapter in order" {
+		t.Errorf("D should go back to document order, notice %q", m.notice)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/metcalfc/brr/internal/state"
+)
+
+// recordSession counts the session toward the personal records saved
+// with the reading state. When announce is set (--records), it returns a
+// line naming the records the session broke, or "" if it broke none.
+func recordSession(s *sessionStats, announce bool, now time.Time) (string, error) {
+	if s == nil || s.words == 0 {
+		return "", nil
+	}
+	store, err := state.NewStateStore()
+	if err != nil {
+		return "", err
+	}
+	broken, err := store.AddSession(state.SessionSummary{
+		Reading:     s.elapsed,
+		Words:       s.words,
+		WPM:         s.wpm(),
+		Regressions: s.regressions,
+	}, now)
+	if err != nil || !announce || len(broken) == 0 {
+		return "", err
+	}
+	records, err := store.Records()
+	if err != nil {
+		return "", err
+	}
+	var parts []string
+	for _, name := range broken {
+		parts = append(parts, fmt.Sprintf("%s (%s)", name, recordValue(records, name)))
+	}
+	return "New personal best: " + strings.Join(parts, ", "), nil
+}
+
+// recordValue formats the value of the named record.
+func recordValue(r state.Records, name string) string {
+	switch name {
+	case state.RecordLongestSession:
+		return formatReadingTime(time.Duration(r.LongestSession.Value) * time.Second)
+	case state.RecordFastestWPM:
+		return fmt.Sprintf("%d WPM", r.FastestWPM.Value)
+	case state.RecordLongestStreak:
+		return formatDays(r.LongestStreak.Value)
+	}
+	return ""
+}
+
+// formatReadingTime formats d as hours and minutes, or minutes and seconds
+// under an hour.
+func formatReadingTime(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d >= time.Hour {
+		return fmt.Sprintf("%dh %02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return fmt.Sprintf("%dm %02ds", int(d.Minutes()), int(d.Seconds())%60)
+}
+
+// formatDays formats a count of days.
+func formatDays(n int) string {
+	if n == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", n)
+}
+
+// runStatsCommand runs brr stats, which prints the personal records.
+func runStatsCommand(w io.Writer, args []string) error {
+	if len(args) > 0 {
+		return errors.New("usage: brr stats")
+	}
+	store, err := state.NewStateStore()
+	if err != nil {
+		return err
+	}
+	records, err := store.Records()
+	if err != nil {
+		return err
+	}
+	return printRecords(w, records, time.Now())
+}
+
+// printRecords writes the personal records as a table, with when each was
+// set, followed by the current streak.
+func printRecords(w io.Writer, r state.Records, now time.Time) error {
+	if r.LongestSession.Value == 0 {
+		fmt.Fprintln(w, "No personal records yet.")
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "RECORD\tBEST\tSET")
+	for _, row := range []struct {
+		name   string
+		record state.Record
+	}{
+		{state.RecordLongestSession, r.LongestSession},
+		{state.RecordFastestWPM, r.FastestWPM},
+		{state.RecordLongestStreak, r.LongestStreak},
+	} {
+		if row.record.Value == 0 {
+			fmt.Fprintf(tw, "%s\t-\t-\n", row.name)
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", row.name, recordValue(r, row.name), row.record.Set.Format(time.DateOnly))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "\nCurrent streak: %s\n", formatDays(r.CurrentStreak(now)))
+	return nil
+}
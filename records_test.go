@@ -0,0 +1,42 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/metcalfc/brr/internal/state"
+)
+
+func TestRecordSession(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	now := time.Date(2026, 10, 1, 21, 0, 0, 0, time.Local)
+	var out strings.Builder
+	if err := runStatsCommand(&out, nil); err != nil || out.String() != "No personal records yet.\n" {
+		t.Errorf("stats before reading = %q, %v", out.String(), err)
+	}
+
+	s := &sessionStats{words: 1500, elapsed: 5 * time.Minute}
+	s.regress()
+	if got, err := recordSession(s, false, now); err != nil || got != "" {
+		t.Errorf("recordSession without --records = %q, %v", got, err)
+	}
+	s = &sessionStats{words: 2000, elapsed: 5 * time.Minute}
+	got, err := recordSession(s, true, now.AddDate(0, 0, 1))
+	if err != nil || got != "New personal best: fastest sustained speed (400 WPM), longest streak (2 days)" {
+		t.Errorf("recordSession = %q, %v", got, err)
+	}
+
+	store, _ := state.NewStateStore()
+	records, _ := store.Records()
+	out.Reset()
+	printRecords(&out, records, now.AddDate(0, 0, 2))
+	for _, want := range []string{"longest session          5m 00s   2026-10-01", "fastest sustained speed  400 WPM", "Current streak: 2 days"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("printRecords missing %q:\n%s", want, out.String())
+		}
+	}
+	if err := runStatsCommand(&out, []string{"reset"}); err == nil {
+		t.Error("brr stats should take no arguments")
+	}
+}
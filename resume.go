@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+// resumeTitleLength is the most of a chapter's title the resume prompt
+// shows.
+const resumeTitleLength = 30
+
+// resumePrompt is a book reopened at its saved position, until the reader
+// chooses to continue from there or start over.
+type resumePrompt struct {
+	pos int
+}
+
+// newResumePrompt returns the prompt for a book reopened at pos, or nil if
+// pos is the start.
+func newResumePrompt(pos int) *resumePrompt {
+	if pos <= 0 {
+		return nil
+	}
+	return &resumePrompt{pos: pos}
+}
+
+// prompt says where reading resumes and asks whether to continue.
+func (p *resumePrompt) prompt(r *reader.Reader) string {
+	where := "word " + groupDigits(p.pos+1)
+	if ch := resumeChapter(r, p.pos); ch != "" {
+		where += " (" + ch + ")"
+	}
+	return "Resuming at " + where + ". ENTER: continue, R: restart"
+}
+
+// pendingResumeNotice says reading resumes at pos, a saved position in the
+// part of a document still loading, once it arrives.
+func pendingResumeNotice(pos int) string {
+	return "Resuming at word " + groupDigits(pos+1) + " once it has loaded"
+}
+
+// resumeLoaded moves r to pos, a saved position beyond the part of a
+// document read early, once the parts appended since reach it. It returns
+// the resume prompt, or nil while pos is still to load.
+func resumeLoaded(r *reader.Reader, pos int) *resumePrompt {
+	if !r.SeekTo(pos) {
+		return nil
+	}
+	return newResumePrompt(pos)
+}
+
+// resolve acts on key, ENTER or R, returning a notice describing where
+// reading starts and whether to start reading. It reports false for any
+// other key.
+func (p *resumePrompt) resolve(key string, r *reader.Reader) (notice string, read, ok bool) {
+	switch key {
+	case "enter":
+		return "", true, true
+	case "r", "R":
+		if !r.SeekTo(r.Start()) {
+			// A book read a chapter at a time has let go of its start.
+			return "Cannot go back to the start here; reopen with --fresh", false, true
+		}
+		return "Starting over from the beginning", false, true
+	}
+	return "", false, false
+}
+
+// resumeChapter names the chapter holding pos, as "Chapter 7: Title", or ""
+// if the document has no chapters or pos is not loaded.
+func resumeChapter(r *reader.Reader, pos int) string {
+	if len(r.Chapters) == 0 || pos < r.Base {
+		return ""
+	}
+	i := r.ChapterIndex(pos - r.Base)
+	name := "Chapter " + strconv.Itoa(i+1)
+	title := strings.TrimSpace(r.Chapters[i].Title)
+	if title == "" || strings.EqualFold(title, name) {
+		return name
+	}
+	if runes := []rune(title); len(runes) > resumeTitleLength {
+		title = strings.TrimSpace(string(runes[:resumeTitleLength])) + "…"
+	}
+	return fmt.Sprintf("%s: %s", name, title)
+}
+
+// groupDigits writes n with commas between thousands, as in 12,345.
+func groupDigits(n int) string {
+	s := strconv.Itoa(n)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
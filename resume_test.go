@@ -0,0 +1,46 @@
+//go:build !gui
+
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+func TestResumePrompt(t *testing.T) {
+	chapters := []reader.Chapter{{Title: "One", WordStart: 0, WordEnd: 2}, {Title: "The Adventure of the Speckled Band", WordStart: 3, WordEnd: 5}}
+	m := newModel("one two three four five six", 300, nil, chapters)
+	if newResumePrompt(0) != nil {
+		t.Error("a book opened at its start needs no prompt")
+	}
+	m.SeekTo(4)
+	m.resume = newResumePrompt(4)
+	m.Paused = true
+	want := "Resuming at word 5 (Chapter 2: The Adventure of the Speckled…). ENTER: continue, R: restart"
+	if got := m.resume.prompt(m.Reader); got != want {
+		t.Errorf("prompt = %q, want %q", got, want)
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	m = updated.(model)
+	if m.resume == nil || !m.Paused {
+		t.Error("keys other than the answers should be ignored while the prompt is shown")
+	}
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m = updated.(model); m.resume != nil || m.Position() != 4 || m.Paused || cmd == nil {
+		t.Errorf("ENTER should read on from word 5: position %d, paused %v", m.Position(), m.Paused)
+	}
+
+	m.resume = newResumePrompt(4)
+	m.Paused = true
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	if m = updated.(model); m.resume != nil || m.Position() != 0 || !m.Paused {
+		t.Errorf("R should go back to the start: position %d, paused %v", m.Position(), m.Paused)
+	}
+
+	if got := groupDigits(12345); got != "12,345" {
+		t.Errorf("groupDigits(12345) = %q", got)
+	}
+}
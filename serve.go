@@ -0,0 +1,483 @@
+//go:build !gui
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+// defaultServeAddr is where brr serve listens unless told otherwise. Only
+// local connections are accepted.
+const defaultServeAddr = "127.0.0.1:7878"
+
+// remoteTimeout bounds how long an API request waits for the reader.
+const remoteTimeout = 5 * time.Second
+
+// remoteHeader marks an API request as sent by a script or extension
+// rather than a web page. Requests that change the reader need it or a
+// JSON body, since browsers send neither cross-origin without asking brr
+// first, which it never allows.
+const remoteHeader = "X-Brr"
+
+// extensionEnv lists the browser extensions allowed to use the API, as
+// serve's -extension option does.
+const extensionEnv = "BRR_EXTENSION"
+
+// remoteServer is the remote control API of brr serve.
+type remoteServer struct {
+	listener net.Listener
+	// unix is set when listening on a Unix socket, which browsers cannot
+	// reach, so requests' Host is not checked.
+	unix bool
+	// extensions are the IDs of the browser extensions whose requests are
+	// answered; any other extension's are refused like a web page's.
+	extensions []string
+	// maxSize caps the bodies of POST /load and /read as --max-size caps
+	// input; 0 allows any size.
+	maxSize int64
+	// open builds a reading model for a document loaded through the API.
+	open func(doc *document) (model, error)
+	// load extracts a document named by a path or URL. It runs on the
+	// API's goroutine, so it must share nothing with the reader.
+	load func(ctx context.Context, src string) (*document, error)
+	// queue holds documents sent to be read after the current one. Like
+	// the model, it is only used from the Bubble Tea event loop.
+	queue []*document
+}
+
+// document is a loaded document waiting to be opened by the reader.
+type document struct {
+	src      string
+	text     string
+	toc      []reader.TOCEntry
+	chapters []reader.Chapter
+	// warnings are the parts of the document that could not be read.
+	warnings reader.Warnings
+}
+
+// remoteMsg carries an API request to the reader, which answers on reply.
+type remoteMsg struct {
+	action string
+	value  int
+	doc    *document
+	queue  bool
+	reply  chan remoteStatus
+}
+
+// remoteStatus is the reader's state as reported by the API.
+type remoteStatus struct {
+	Source  string `json:"source,omitempty"`
+	Word    string `json:"word,omitempty"`
+	Current int    `json:"current"`
+	Total   int    `json:"total"`
+	Chapter string `json:"chapter,omitempty"`
+	WPM     int    `json:"wpm"`
+	Paused  bool   `json:"paused"`
+	Queued  int    `json:"queued"`
+	Error   string `json:"error,omitempty"`
+}
+
+// sentPage is a page sent to POST /read, typically by a browser
+// extension's "Send to brr" button.
+type sentPage struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+	HTML  string `json:"html"`
+	Text  string `json:"text"`
+	// Queue reads the page after the current document instead of now.
+	Queue bool `json:"queue"`
+}
+
+// document returns the page as a document, its title as the first
+// paragraph, or nil if it has no readable text.
+func (p sentPage) document() *document {
+	text := strings.TrimSpace(p.Text)
+	if text == "" && p.HTML != "" {
+		text = reader.ExtractHTML(p.HTML)
+	}
+	if text == "" {
+		return nil
+	}
+	if title := strings.TrimSpace(p.Title); title != "" {
+		text = title + "\n\n" + text
+	}
+	src := p.URL
+	if src == "" {
+		src = p.Title
+	}
+	return &document{src: src, text: text}
+}
+
+// parseServe reads the options of brr serve and starts listening. It
+// returns the arguments naming the document to open first, if any.
+func parseServe(args []string) (*remoteServer, []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", defaultServeAddr, "Address to listen on")
+	socket := fs.String("socket", "", "Listen on this Unix socket instead of a TCP address")
+	extension := fs.String("extension", os.Getenv(extensionEnv), "Comma-separated IDs of the browser extensions allowed to use the API")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [options] serve [-addr host:port | -socket path] [-extension id] [file|url]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Read with a local HTTP API for remote control:\n")
+		fmt.Fprintf(os.Stderr, "  GET /status, POST /pause, /resume, /toggle,\n")
+		fmt.Fprintf(os.Stderr, "  POST /wpm?value=N, /jump?word=N or ?chapter=N,\n")
+		fmt.Fprintf(os.Stderr, "  POST /load?path=FILE|URL or /load with JSON {path or text},\n")
+		fmt.Fprintf(os.Stderr, "  POST /read with JSON {title, url, html or text, queue}\n")
+		fmt.Fprintf(os.Stderr, "POST requests need a JSON body or an %s header.\n\n", remoteHeader)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	network, address := "tcp", *addr
+	if *socket != "" {
+		network, address = "unix", *socket
+		if err := removeSocket(address); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	l, err := net.Listen(network, address)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	s := &remoteServer{listener: l, unix: network == "unix"}
+	for id := range strings.SplitSeq(*extension, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			s.extensions = append(s.extensions, id)
+		}
+	}
+	return s, fs.Args()
+}
+
+// removeSocket removes the socket an earlier brr serve left at path,
+// refusing to remove anything else.
+func removeSocket(path string) error {
+	info, err := os.Lstat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s exists and is not a socket", path)
+	}
+	return os.Remove(path)
+}
+
+// serve answers API requests, passing them to the reader through send.
+func (s *remoteServer) serve(send func(tea.Msg)) {
+	http.Serve(s.listener, s.handler(send))
+}
+
+// handler returns the API's HTTP handler.
+func (s *remoteServer) handler(send func(tea.Msg)) http.Handler {
+	ask := func(w http.ResponseWriter, msg remoteMsg) {
+		msg.reply = make(chan remoteStatus, 1)
+		send(msg)
+		select {
+		case st := <-msg.reply:
+			w.Header().Set("Content-Type", "application/json")
+			if st.Error != "" {
+				w.WriteHeader(http.StatusBadRequest)
+			}
+			json.NewEncoder(w).Encode(st)
+		case <-time.After(remoteTimeout):
+			http.Error(w, "reader is busy", http.StatusServiceUnavailable)
+		}
+	}
+	action := func(name string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			ask(w, remoteMsg{action: name})
+		}
+	}
+	number := func(name, param string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			n, err := strconv.Atoi(r.FormValue(param))
+			if err != nil {
+				http.Error(w, "missing or invalid "+param, http.StatusBadRequest)
+				return
+			}
+			ask(w, remoteMsg{action: name, value: n})
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /status", action("status"))
+	mux.HandleFunc("POST /pause", action("pause"))
+	mux.HandleFunc("POST /resume", action("resume"))
+	mux.HandleFunc("POST /toggle", action("toggle"))
+	mux.HandleFunc("POST /wpm", number("wpm", "value"))
+	mux.HandleFunc("POST /jump", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("chapter") != "" {
+			number("chapter", "chapter")(w, r)
+			return
+		}
+		number("jump", "word")(w, r)
+	})
+	mux.HandleFunc("POST /load", func(w http.ResponseWriter, r *http.Request) {
+		var req loadRequest
+		if isJSON(r) {
+			if err := s.readJSON(w, r, &req); err != nil && err != io.EOF {
+				http.Error(w, "invalid request: "+err.Error(), bodyStatus(err))
+				return
+			}
+		}
+		if req.Path == "" && req.Text == "" {
+			req.Path, req.Text = r.URL.Query().Get("path"), r.URL.Query().Get("text")
+		}
+		if req.Path == "" && req.Text == "" {
+			http.Error(w, "missing path or text", http.StatusBadRequest)
+			return
+		}
+		doc := &document{text: req.Text}
+		if req.Path != "" {
+			var err error
+			doc, err = s.load(r.Context(), req.Path)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+				return
+			}
+		}
+		ask(w, remoteMsg{action: "load", doc: doc})
+	})
+	mux.HandleFunc("POST /read", func(w http.ResponseWriter, r *http.Request) {
+		// Pages are only sent as JSON, even with remoteHeader.
+		if !isJSON(r) {
+			http.Error(w, "send the page as application/json", http.StatusUnsupportedMediaType)
+			return
+		}
+		var page sentPage
+		if err := s.readJSON(w, r, &page); err != nil {
+			http.Error(w, "invalid page: "+err.Error(), bodyStatus(err))
+			return
+		}
+		doc := page.document()
+		if doc == nil {
+			http.Error(w, "no readable text in page", http.StatusUnprocessableEntity)
+			return
+		}
+		ask(w, remoteMsg{action: "load", doc: doc, queue: page.Queue})
+	})
+	return s.guard(mux)
+}
+
+// readJSON decodes the JSON body of r into v, reading no more than
+// maxSize.
+func (s *remoteServer) readJSON(w http.ResponseWriter, r *http.Request, v any) error {
+	body := r.Body
+	if s.maxSize > 0 {
+		body = http.MaxBytesReader(w, r.Body, s.maxSize)
+	}
+	return json.NewDecoder(body).Decode(v)
+}
+
+// bodyStatus is the status of a response to a request whose body could
+// not be read.
+func bodyStatus(err error) int {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
+
+// loadRequest is the JSON body of POST /load.
+type loadRequest struct {
+	Path string `json:"path"`
+	Text string `json:"text"`
+}
+
+// guard refuses API requests that a web page may have sent: those naming
+// another host, as after DNS rebinding, those from another origin than
+// the machine itself or an allowed browser extension, and requests changing the
+// reader with neither a JSON body nor remoteHeader, as a cross-origin form
+// can post.
+func (s *remoteServer) guard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.unix && !loopbackHost(r.Host) {
+			http.Error(w, "only requests to localhost are answered", http.StatusForbidden)
+			return
+		}
+		if !s.allowedOrigin(r.Header.Get("Origin")) {
+			http.Error(w, "requests from web pages are refused", http.StatusForbidden)
+			return
+		}
+		if r.Method != http.MethodGet && r.Method != http.MethodHead && !isJSON(r) && r.Header.Get(remoteHeader) == "" {
+			http.Error(w, "send JSON or an "+remoteHeader+" header", http.StatusUnsupportedMediaType)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isJSON reports whether a request's body is JSON.
+func isJSON(r *http.Request) bool {
+	mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return mt == "application/json"
+}
+
+// loopbackHost reports whether a Host header names this machine by a
+// loopback address or localhost.
+func loopbackHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.Trim(host, "[]")
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// allowedOrigin reports whether a request's Origin may use the API: none,
+// as from scripts, a page served from this machine, or one of the browser
+// extensions allowed, such as one sending pages to POST /read. Any
+// extension can be installed, so one not named with -extension is refused.
+func (s *remoteServer) allowedOrigin(origin string) bool {
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	switch u.Scheme {
+	case "chrome-extension", "moz-extension", "safari-web-extension":
+		return slices.Contains(s.extensions, u.Host)
+	case "http", "https":
+		return loopbackHost(u.Host)
+	}
+	return false
+}
+
+// handleRemote carries out an API request and replies with the resulting
+// status.
+func (m model) handleRemote(msg remoteMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	var err error
+	resume := func() {
+		if m.Paused && len(m.Words) > 0 {
+			m.ResumeWithRewind()
+			m.speak(m.CurrentIndex)
+			cmd = m.next()
+		}
+	}
+	switch msg.action {
+	case "pause":
+		if !m.Paused {
+			m.Pause()
+			m.stopSpeech()
+		}
+	case "resume":
+		resume()
+	case "toggle":
+		if m.Paused {
+			resume()
+		} else {
+			m.Pause()
+			m.stopSpeech()
+		}
+	case "wpm":
+		m.WPM = min(max(msg.value, 100), 1500)
+	case "jump":
+		if msg.value < 1 || !m.SeekTo(msg.value-1) {
+			err = fmt.Errorf("word %d is out of range", msg.value)
+		}
+	case "chapter":
+		if msg.value < 1 || msg.value > len(m.Chapters) {
+			err = fmt.Errorf("chapter %d is out of range", msg.value)
+		} else {
+			m.Jump(m.Chapters[msg.value-1].WordStart)
+		}
+	case "load":
+		if msg.queue && len(m.Words) > 0 && !m.AtEnd() {
+			m.server.queue = append(m.server.queue, msg.doc)
+			break
+		}
+		var next model
+		next, err = m.openDocument(msg.doc)
+		if err == nil {
+			m = next
+			if !m.tocVisible {
+				resume()
+			}
+		}
+	}
+	msg.reply <- m.remoteStatus(err)
+	return m, cmd
+}
+
+// openDocument saves the position in the current document and returns a
+// paused model reading doc, keeping the session's goal, stats and marks.
+func (m model) openDocument(doc *document) (model, error) {
+	m.savePosition()
+	next, err := m.server.open(doc)
+	if err != nil {
+		return m, err
+	}
+	m.stopSpeech()
+	m.cancelLoading()
+	next.server, next.goal, next.stats, next.marks = m.server, m.goal, m.stats, m.marks
+	next.width, next.height = m.width, m.height
+	next.Paused = true
+	return next, nil
+}
+
+// finished handles the end of a served document: reading goes on with the
+// next queued document if there is one, otherwise it pauses.
+func (m model) finished() (tea.Model, tea.Cmd) {
+	for len(m.server.queue) > 0 {
+		doc := m.server.queue[0]
+		m.server.queue = m.server.queue[1:]
+		if next, err := m.openDocument(doc); err == nil {
+			next.ResumeWithRewind()
+			next.speak(next.CurrentIndex)
+			return next, next.next()
+		}
+	}
+	m.Pause()
+	m.stopSpeech()
+	return m, nil
+}
+
+// remoteStatus describes the reader for the API.
+func (m model) remoteStatus(err error) remoteStatus {
+	current, total := m.Progress()
+	if len(m.Words) == 0 {
+		current = 0
+	}
+	st := remoteStatus{
+		Source:  m.sourceFile,
+		Word:    m.CurrentWord(),
+		Current: current,
+		Total:   total,
+		Chapter: m.CurrentChapterTitle(),
+		WPM:     m.WPM,
+		Paused:  m.Paused,
+		Queued:  len(m.server.queue),
+	}
+	if err != nil {
+		st.Error = err.Error()
+	}
+	return st
+}
@@ -0,0 +1,239 @@
+//go:build !gui
+
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+func TestRemoteControl(t *testing.T) {
+	m := newModel("", 300, nil, nil)
+	m.Paused = true
+	s := &remoteServer{
+		open: func(doc *document) (model, error) {
+			nm := newModel(doc.text, 300, doc.toc, doc.chapters)
+			nm.sourceFile = doc.src
+			return nm, nil
+		},
+		load: func(ctx context.Context, src string) (*document, error) {
+			if src != "book.txt" {
+				return nil, errors.New("not found")
+			}
+			return &document{src: src, text: "one two three. four five six.", chapters: []reader.Chapter{{Title: "One", WordStart: 0, WordEnd: 3}, {Title: "Two", WordStart: 3, WordEnd: 6}}}, nil
+		},
+	}
+	m.server = s
+	srv := httptest.NewServer(s.handler(func(msg tea.Msg) {
+		updated, _ := m.Update(msg)
+		m = updated.(model)
+	}))
+	defer srv.Close()
+
+	if !strings.Contains(m.View(), "POST /load") {
+		t.Errorf("an empty served reader should wait for a document:\n%s", m.View())
+	}
+
+	tests := []struct {
+		method, path string
+		code         int
+		want         string
+	}{
+		{"POST", "/load?path=missing.txt", http.StatusUnprocessableEntity, "not found"},
+		{"POST", "/load?path=book.txt", http.StatusOK, `"current":1,"total":6,"chapter":"One","wpm":300,"paused":false`},
+		{"POST", "/pause", http.StatusOK, `"paused":true`},
+		{"POST", "/wpm?value=5000", http.StatusOK, `"wpm":1500`},
+		{"POST", "/jump?chapter=2", http.StatusOK, `"word":"four","current":4`},
+		{"POST", "/jump?word=6", http.StatusOK, `"current":6`},
+		{"POST", "/jump?word=60", http.StatusBadRequest, "out of range"},
+		{"POST", "/jump", http.StatusBadRequest, "missing or invalid word"},
+		{"POST", "/toggle", http.StatusOK, `"paused":false`},
+		{"GET", "/status", http.StatusOK, `"source":"book.txt"`},
+		{"GET", "/pause", http.StatusMethodNotAllowed, ""},
+		{"POST", "/load?text=hello+there", http.StatusOK, `"word":"hello","current":1,"total":2`},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest(tt.method, srv.URL+tt.path, nil)
+		req.Header.Set(remoteHeader, "1")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s: %v", tt.method, tt.path, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != tt.code || !strings.Contains(string(body), tt.want) {
+			t.Errorf("%s %s = %d %s, want %d containing %s", tt.method, tt.path, resp.StatusCode, body, tt.code, tt.want)
+		}
+	}
+
+	m.Paused = false
+	m.SeekTo(1)
+	updated, cmd := m.Update(tickMsg(time.Now()))
+	m = updated.(model)
+	if cmd != nil || m.quitting || !m.Paused {
+		t.Error("a served reader should pause at the end of a document instead of quitting")
+	}
+}
+
+func TestRemoteRead(t *testing.T) {
+	m := newModel("", 300, nil, nil)
+	m.Paused = true
+	s := &remoteServer{open: func(doc *document) (model, error) {
+		nm := newModel(doc.text, 300, doc.toc, doc.chapters)
+		nm.sourceFile = doc.src
+		return nm, nil
+	}}
+	m.server = s
+	srv := httptest.NewServer(s.handler(func(msg tea.Msg) {
+		updated, _ := m.Update(msg)
+		m = updated.(model)
+	}))
+	defer srv.Close()
+
+	post := func(contentType, body string) (int, string) {
+		resp, err := http.Post(srv.URL+"/read", contentType, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(data)
+	}
+
+	if code, _ := post("application/x-www-form-urlencoded", "text=hi"); code != http.StatusUnsupportedMediaType {
+		t.Errorf("a form post should be refused, got %d", code)
+	}
+	if code, _ := post("application/json", `{"html": "<script>x()</script>"}`); code != http.StatusUnprocessableEntity {
+		t.Errorf("a page without text should be refused, got %d", code)
+	}
+	s.maxSize = 64
+	if code, _ := post("application/json", `{"text": "`+strings.Repeat("word ", 20)+`"}`); code != http.StatusRequestEntityTooLarge {
+		t.Errorf("a page over --max-size should be refused, got %d", code)
+	}
+	s.maxSize = 0
+	code, body := post("application/json", `{"title": "Article", "url": "https://example.com/a", "html": "<nav>Menu</nav><p>Body text here.</p>", "queue": true}`)
+	if code != http.StatusOK || !strings.Contains(body, `"source":"https://example.com/a","word":"Article","current":1,"total":4`) {
+		t.Errorf("with nothing being read, a queued page should open now: %d %s", code, body)
+	}
+	code, body = post("application/json; charset=utf-8", `{"title": "Next", "text": "Second page.", "queue": true}`)
+	if code != http.StatusOK || !strings.Contains(body, `"queued":1`) || !strings.Contains(body, `"word":"Article"`) {
+		t.Errorf("a page sent while reading should be queued: %d %s", code, body)
+	}
+
+	m.SeekTo(3)
+	updated, _ := m.Update(tickMsg(time.Now()))
+	m = updated.(model)
+	if m.sourceFile != "Next" || m.CurrentWord() != "Next" || m.Paused || len(s.queue) != 0 {
+		t.Errorf("the queued page should follow the first: source %q, word %q, paused %v", m.sourceFile, m.CurrentWord(), m.Paused)
+	}
+}
+
+func TestRemoveSocket(t *testing.T) {
+	dir := t.TempDir()
+	if err := removeSocket(filepath.Join(dir, "missing.sock")); err != nil {
+		t.Errorf("a missing socket should be no error, got %v", err)
+	}
+
+	file := filepath.Join(dir, "notes.txt")
+	os.WriteFile(file, []byte("keep me"), 0644)
+	if err := removeSocket(file); err == nil {
+		t.Error("a file that isn't a socket should be refused")
+	}
+	if _, err := os.Stat(file); err != nil {
+		t.Errorf("the file should be kept: %v", err)
+	}
+
+	sock := filepath.Join(dir, "brr.sock")
+	l, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Skipf("no Unix sockets: %v", err)
+	}
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	l.Close()
+	if err := removeSocket(sock); err != nil {
+		t.Errorf("removeSocket: %v", err)
+	}
+	if _, err := os.Lstat(sock); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("the stale socket should be removed, got %v", err)
+	}
+}
+
+func TestRemoteGuard(t *testing.T) {
+	m := newModel("one two three", 300, nil, nil)
+	loaded := false
+	s := &remoteServer{
+		extensions: []string{"abc"},
+		open: func(doc *document) (model, error) {
+			return newModel(doc.text, 300, doc.toc, doc.chapters), nil
+		},
+		load: func(ctx context.Context, src string) (*document, error) {
+			loaded = true
+			return &document{src: src, text: "loaded text"}, nil
+		},
+	}
+	m.server = s
+	srv := httptest.NewServer(s.handler(func(msg tea.Msg) {
+		updated, _ := m.Update(msg)
+		m = updated.(model)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name        string
+		path        string
+		contentType string
+		body        string
+		headers     map[string]string
+		code        int
+	}{
+		{"form post", "/load", "application/x-www-form-urlencoded", "path=/etc/passwd", nil, http.StatusUnsupportedMediaType},
+		{"form post from a page", "/load", "application/x-www-form-urlencoded", "path=/etc/passwd", map[string]string{"Origin": "https://evil.example"}, http.StatusForbidden},
+		{"plain post", "/pause", "", "", nil, http.StatusUnsupportedMediaType},
+		{"rebound host", "/pause", "", "", map[string]string{"Host": "evil.example:7878", remoteHeader: "1"}, http.StatusForbidden},
+		{"JSON from a page", "/load", "application/json", `{"path": "book.txt"}`, map[string]string{"Origin": "http://evil.example"}, http.StatusForbidden},
+		{"scripted", "/pause", "", "", map[string]string{remoteHeader: "1"}, http.StatusOK},
+		{"local page", "/pause", "", "", map[string]string{"Origin": "http://localhost:8000", remoteHeader: "1"}, http.StatusOK},
+		{"other extension", "/read", "application/json", `{"text": "Sent text."}`, map[string]string{"Origin": "chrome-extension://evil"}, http.StatusForbidden},
+		{"extension", "/read", "application/json", `{"text": "Sent text."}`, map[string]string{"Origin": "moz-extension://abc"}, http.StatusOK},
+		{"JSON load", "/load", "application/json", `{"path": "book.txt"}`, nil, http.StatusOK},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest("POST", srv.URL+tt.path, strings.NewReader(tt.body))
+		if tt.contentType != "" {
+			req.Header.Set("Content-Type", tt.contentType)
+		}
+		for k, v := range tt.headers {
+			req.Header.Set(k, v)
+		}
+		if host := tt.headers["Host"]; host != "" {
+			req.Host = host
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tt.code {
+			t.Errorf("%s: POST %s = %d, want %d", tt.name, tt.path, resp.StatusCode, tt.code)
+		}
+		if tt.code != http.StatusOK && loaded {
+			t.Fatalf("%s: the refused request loaded a document", tt.name)
+		}
+	}
+	if !loaded || m.CurrentWord() != "loaded" {
+		t.Errorf("a JSON load should open the document, at %q", m.CurrentWord())
+	}
+}
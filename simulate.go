@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+// chapterTiming is how many words a chapter shows and how long they take,
+// as worked out by simulate.
+type chapterTiming struct {
+	title string
+	words int
+	time  time.Duration
+}
+
+// simulate reads r from its current word to the end without a display,
+// as --simulate does, adding up how long each word is shown with r's
+// pacing, pauses and skipping. It returns a timing for each chapter, or a
+// single untitled one if r has no chapters.
+func simulate(r *reader.Reader) []chapterTiming {
+	timings := []chapterTiming{{}}
+	if len(r.Chapters) > 0 {
+		timings = make([]chapterTiming, len(r.Chapters))
+		for i, c := range r.Chapters {
+			timings[i].title = c.Title
+		}
+	}
+	if len(r.Words) == 0 {
+		return timings
+	}
+	for {
+		t := &timings[min(r.CurrentChapter, len(timings)-1)]
+		if r.Frame() == 0 {
+			t.words++
+		}
+		t.time += r.WordDelay()
+		if !r.Advance() {
+			break
+		}
+	}
+	return timings
+}
+
+// printSimulation writes timings as a table with a total, saying the pace
+// they were worked out at.
+func printSimulation(w io.Writer, timings []chapterTiming, wpm int) error {
+	var words int
+	var total time.Duration
+	for _, t := range timings {
+		words += t.words
+		total += t.time
+	}
+	fmt.Fprintf(w, "Reading at %d WPM\n\n", wpm)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CHAPTER\tWORDS\tTIME")
+	if len(timings) > 1 || timings[0].title != "" {
+		for i, t := range timings {
+			title := t.title
+			if title == "" {
+				title = fmt.Sprintf("Chapter %d", i+1)
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", title, groupDigits(t.words), clockTime(t.time))
+		}
+	}
+	fmt.Fprintf(tw, "Total\t%s\t%s\n", groupDigits(words), clockTime(total))
+	return tw.Flush()
+}
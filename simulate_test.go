@@ -0,0 +1,47 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+func TestSimulate(t *testing.T) {
+	r := reader.NewReader("One two. Three four five six. Seven eight nine ten.", 60)
+	r.SetChapters([]reader.Chapter{
+		{Title: "Start", WordStart: 0, WordEnd: 5},
+		{WordStart: 6, WordEnd: 9},
+	}, nil)
+	r.ChapterPause = 3
+
+	// A second a word at 60 WPM, and three for the word opening the
+	// second chapter.
+	got := simulate(r)
+	want := []chapterTiming{
+		{title: "Start", words: 6, time: 6 * time.Second},
+		{words: 4, time: 6 * time.Second},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("simulate = %+v, want %+v", got, want)
+	}
+
+	var b strings.Builder
+	if err := printSimulation(&b, got, 60); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"Reading at 60 WPM", "Start      6      0:06", "Chapter 2  4      0:06", "Total      10     0:12"} {
+		if !strings.Contains(b.String(), line) {
+			t.Errorf("output lacks %q:\n%s", line, b.String())
+		}
+	}
+
+	r = reader.NewReader("the cat and the hat", 60)
+	r.Stopwords, _ = reader.Stopwords("en")
+	r.StopwordMode = reader.StopwordsSkip
+	if got := simulate(r); len(got) != 1 || got[0].words != 3 {
+		t.Errorf("simulate skipping function words = %+v, want 3 words", got)
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+// skipRest skips the rest of the current sentence, or of the paragraph if
+// paragraph is set, counting the words passed over in s, and returns a
+// notice saying how many. Reading goes on, so boilerplate can be skipped
+// without breaking the rhythm.
+func skipRest(r *reader.Reader, s *sessionStats, paragraph bool) string {
+	what, n := "sentence", 0
+	if paragraph {
+		what, n = "paragraph", r.SkipParagraph()
+	} else {
+		n = r.SkipSentence()
+	}
+	if n == 0 {
+		return "Nothing left to skip"
+	}
+	s.skip(n)
+	return fmt.Sprintf("Skipped the rest of the %s (%s words)", what, groupDigits(n))
+}
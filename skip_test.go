@@ -0,0 +1,46 @@
+//go:build !gui
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+func TestSkipKeys(t *testing.T) {
+	m := newModel("One two. Three four five. Six\n\nSeven eight. Nine", 300, nil, nil)
+	m.stats = &sessionStats{}
+	m.SeekTo(3)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{']'}})
+	m = updated.(model)
+	if m.CurrentIndex != 5 || m.Paused {
+		t.Fatalf("] should skip to the next sentence and read on, at %d paused %v", m.CurrentIndex, m.Paused)
+	}
+	if m.notice != "Skipped the rest of the sentence (2 words)" {
+		t.Errorf("notice = %q", m.notice)
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'}'}})
+	m = updated.(model)
+	if m.CurrentIndex != 6 {
+		t.Fatalf("} should skip to the next paragraph, at %d", m.CurrentIndex)
+	}
+	if m.stats.skipped != 3 {
+		t.Errorf("skipped = %d, want 3", m.stats.skipped)
+	}
+	if hud := strings.Join(pausedHUD(m.Reader, m.stats), "\n"); !strings.Contains(hud, "Skipped 3 words") {
+		t.Errorf("the paused panel should count skipped words:\n%s", hud)
+	}
+	if sum := newJSONSummary(m.Reader, m.stats, "", "", reader.Metadata{}, defaultFinishAt, time.Now()); sum.WordsSkipped != 3 {
+		t.Errorf("WordsSkipped = %d, want 3", sum.WordsSkipped)
+	}
+
+	m.keys = keymaps["one-handed"]
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'`'}})
+	if m = updated.(model); m.CurrentIndex != 8 {
+		t.Errorf("` should skip the sentence with --keys one-handed, at %d", m.CurrentIndex)
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/metcalfc/brr/internal/state"
+)
+
+// stateUsage describes the brr state command.
+const stateUsage = "usage: brr state backup | restore [n]"
+
+// runStateCommand runs brr state. "backup" backs up the reading state now;
+// "restore" lists the backups and "restore n" restores the nth newest.
+func runStateCommand(w io.Writer, args []string) error {
+	if len(args) == 0 || len(args) > 2 {
+		return errors.New(stateUsage)
+	}
+	store, err := state.NewStateStore()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+
+	switch {
+	case args[0] == "backup" && len(args) == 1:
+		if err := store.Backup(now); err != nil {
+			return err
+		}
+		fmt.Fprintln(w, "Backed up reading state.")
+		return nil
+
+	case args[0] == "restore" && len(args) == 1:
+		return printBackups(w, store.Backups())
+
+	case args[0] == "restore":
+		backups := store.Backups()
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 1 || n > len(backups) {
+			return fmt.Errorf("no backup %q; run brr state restore to list them", args[1])
+		}
+		b := backups[n-1]
+		if err := store.Restore(b, now); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "Restored reading state from %s. The state it replaced was backed up first.\n", b.Taken.Format(time.DateTime))
+		return nil
+	}
+	return errors.New(stateUsage)
+}
+
+// printBackups writes the backups as a numbered table, newest first.
+func printBackups(w io.Writer, backups []state.Backup) error {
+	if len(backups) == 0 {
+		fmt.Fprintln(w, "No backups.")
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "#\tTAKEN\tBOOKS")
+	for i, b := range backups {
+		fmt.Fprintf(tw, "%d\t%s\t%d\n", i+1, b.Taken.Format(time.DateTime), b.Books)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "\nRestore one with: brr state restore <#>")
+	return nil
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/metcalfc/brr/internal/state"
+)
+
+func TestStateCommand(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	store, _ := state.NewStateStore()
+	store.SetPosition("abcdef1234567890abcdef1234567890", 42)
+
+	var out strings.Builder
+	if err := runStateCommand(&out, []string{"backup"}); err != nil {
+		t.Fatalf("backup: %v", err)
+	}
+	out.Reset()
+	if err := runStateCommand(&out, []string{"restore"}); err != nil || !strings.Contains(out.String(), "\n1  ") {
+		t.Errorf("restore should list the backup:\n%s", out.String())
+	}
+	store.SetPosition("abcdef1234567890abcdef1234567890", 7)
+	if err := runStateCommand(&out, []string{"restore", "1"}); err != nil {
+		t.Fatalf("restore 1: %v", err)
+	}
+	if reloaded, _ := state.NewStateStore(); reloaded.GetPosition("abcdef1234567890abcdef1234567890") != 42 {
+		t.Error("restore 1 should bring back the backed-up position")
+	}
+	for _, args := range [][]string{{}, {"restore", "9"}, {"prune"}} {
+		if err := runStateCommand(&out, args); err == nil {
+			t.Errorf("runStateCommand(%q) should fail", args)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+// jsonSummary is what --json-summary writes when a session ends, for
+// wrapper scripts to act on.
+type jsonSummary struct {
+	File   string `json:"file,omitempty"`
+	Hash   string `json:"hash,omitempty"`
+	Title  string `json:"title,omitempty"`
+	Author string `json:"author,omitempty"`
+	// StartIndex and EndIndex are the word the session started at and the
+	// word it ended on, counting from 0 as saved positions do.
+	StartIndex int `json:"start_index"`
+	EndIndex   int `json:"end_index"`
+	TotalWords int `json:"total_words"`
+	// Duration is the whole session in seconds; Reading leaves out time
+	// spent paused.
+	Duration  float64 `json:"duration_seconds"`
+	Reading   float64 `json:"reading_seconds"`
+	WordsRead int     `json:"words_read"`
+	AvgWPM    int     `json:"avg_wpm"`
+	Completed bool    `json:"completed"`
+
+	// WordsSkipped counts the words passed over by skipping the rest of
+	// a sentence or paragraph.
+	WordsSkipped int `json:"words_skipped"`
+}
+
+// newJSONSummary summarizes a session that ended at now with r on its last
+// word, counting the book completed past finishAt percent of it.
+func newJSONSummary(r *reader.Reader, s *sessionStats, sourceFile, hash string, book reader.Metadata, finishAt float64, now time.Time) jsonSummary {
+	_, total := r.Progress()
+	sum := jsonSummary{
+		File:       sourceFile,
+		Hash:       hash,
+		Title:      book.Title,
+		Author:     book.Author,
+		EndIndex:   r.Position(),
+		TotalWords: total,
+		Completed:  bookFinished(r, finishAt),
+	}
+	if s != nil {
+		sum.StartIndex = s.start
+		if !s.began.IsZero() {
+			sum.Duration = now.Sub(s.began).Seconds()
+		}
+		sum.Reading = s.elapsed.Seconds()
+		sum.WordsRead = s.words
+		sum.WordsSkipped = s.skipped
+		sum.AvgWPM = s.wpm()
+	}
+	return sum
+}
+
+// writeJSONSummary writes sum as one line of JSON to path, or to stdout if
+// path is "-". An existing file is replaced.
+func writeJSONSummary(path string, sum jsonSummary) error {
+	if path == "-" {
+		return json.NewEncoder(os.Stdout).Encode(sum)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := json.NewEncoder(f).Encode(sum); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,41 @@
+//go:build !gui
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+func TestJSONSummary(t *testing.T) {
+	m := newModel("One two three four.", 300, nil, nil)
+	began := time.Date(2026, 10, 1, 21, 0, 0, 0, time.UTC)
+	m.stats = &sessionStats{}
+	m.SeekTo(1)
+	m.stats.begin(m.Reader, began)
+	for range 3 {
+		m.Advance()
+		m.stats.advance(m.Reader, 200*time.Millisecond)
+	}
+
+	sum := newJSONSummary(m.Reader, m.stats, "book.txt", "abc", reader.Metadata{Title: "Book"}, defaultFinishAt, began.Add(time.Minute))
+	want := jsonSummary{File: "book.txt", Hash: "abc", Title: "Book", StartIndex: 1, EndIndex: 3, TotalWords: 4,
+		Duration: 60, Reading: 0.6, WordsRead: 3, AvgWPM: 300, Completed: true}
+	if sum != want {
+		t.Errorf("summary = %+v\nwant %+v", sum, want)
+	}
+
+	path := filepath.Join(t.TempDir(), "summary.json")
+	if err := writeJSONSummary(path, sum); err != nil {
+		t.Fatalf("writeJSONSummary: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(data), `{"file":"book.txt","hash":"abc","title":"Book","start_index":1,"end_index":3,`) {
+		t.Errorf("wrote %s", data)
+	}
+}
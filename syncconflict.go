@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/metcalfc/brr/internal/reader"
+	"github.com/metcalfc/brr/internal/state"
+)
+
+// syncConflict is a book's saved position that disagrees with the one in
+// a copy of the state synced from another device, until the reader keeps
+// one of them or both.
+type syncConflict struct {
+	local, other state.ReadingState
+}
+
+// findSyncConflict returns the sync conflict over the book with hash, or
+// nil if there is none.
+func findSyncConflict(store *state.StateStore, hash string) *syncConflict {
+	other, ok := store.Conflict(hash)
+	if !ok {
+		return nil
+	}
+	local, _ := store.Get(hash)
+	return &syncConflict{local: local, other: other}
+}
+
+// prompt asks which position to keep, placing each in its chapter.
+func (c *syncConflict) prompt(r *reader.Reader) string {
+	local, other := c.names()
+	return fmt.Sprintf("Saved positions differ (%s: %s, %s: %s). Keep 1) %s, 2) %s or B) both?",
+		local, positionLabel(r, c.local.WordIndex), other, positionLabel(r, c.other.WordIndex), local, other)
+}
+
+// names returns the names of the devices that saved the two positions.
+func (c *syncConflict) names() (local, other string) {
+	local, other = c.local.Device, c.other.Device
+	if local == "" || local == other {
+		local = "this device"
+	}
+	if other == "" || other == local {
+		other = "other device"
+	}
+	return local, other
+}
+
+// resolve acts on key, one of 1, 2 and B, returning a notice describing
+// what was kept. It reports false for any other key. Keeping both saves
+// the other device's position as a session named after it.
+func (c *syncConflict) resolve(key string, r *reader.Reader, store *state.StateStore, hash string) (string, bool) {
+	local, other := c.names()
+	var notice string
+	var err error
+	switch key {
+	case "1":
+		err = store.ResolveConflict(hash, c.local)
+		notice = "Kept the position from " + local
+	case "2":
+		err = store.ResolveConflict(hash, c.other)
+		r.SeekTo(c.other.WordIndex)
+		notice = "Kept the position from " + other
+	case "b", "B":
+		err = store.SetSession(hash, other, state.Session{WordIndex: c.other.WordIndex, Bookmark: c.other.Bookmark})
+		if err == nil {
+			err = store.ResolveConflict(hash, c.local)
+		}
+		notice = fmt.Sprintf("Kept both; read from %s's position with --session %q", other, other)
+	default:
+		return "", false
+	}
+	if err != nil {
+		notice = "Could not save: " + err.Error()
+	}
+	return notice, true
+}
+
+// positionLabel describes a saved position by its chapter, or by word when
+// the document has no chapters.
+func positionLabel(r *reader.Reader, pos int) string {
+	if len(r.Chapters) > 0 && pos >= r.Base {
+		return fmt.Sprintf("Ch %d", r.ChapterIndex(pos-r.Base)+1)
+	}
+	return fmt.Sprintf("word %d", pos+1)
+}
@@ -0,0 +1,60 @@
+//go:build !gui
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/metcalfc/brr/internal/reader"
+	"github.com/metcalfc/brr/internal/state"
+)
+
+func TestSyncConflictPrompt(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", dir)
+	store, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := "abcdef1234567890abcdef1234567890"
+	defer func(name string) { state.DeviceName = name }(state.DeviceName)
+	state.DeviceName = "laptop"
+	store.SetPosition(hash, 1)
+	conflicted := filepath.Join(dir, "brr", "reading_positions (phone's conflicted copy 2026-10-01).json")
+	if err := os.WriteFile(conflicted, []byte(`{"`+hash+`": {"word_index": 4, "device": "phone"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	chapters := []reader.Chapter{{Title: "One", WordStart: 0, WordEnd: 2}, {Title: "Two", WordStart: 3, WordEnd: 5}}
+	m := newModel("one two three four five six", 300, nil, chapters)
+	m.stateStore, m.fileHash = store, hash
+	m.conflict = findSyncConflict(store, hash)
+	if m.conflict == nil {
+		t.Fatal("expected a sync conflict")
+	}
+	want := "Saved positions differ (laptop: Ch 1, phone: Ch 2). Keep 1) laptop, 2) phone or B) both?"
+	if got := m.conflict.prompt(m.Reader); got != want {
+		t.Errorf("prompt = %q, want %q", got, want)
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	m = updated.(model)
+	if m.conflict == nil {
+		t.Error("keys other than the answers should be ignored while the prompt is shown")
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	m = updated.(model)
+	if m.conflict != nil || m.Position() != 0 || !strings.Contains(m.notice, `--session "phone"`) {
+		t.Errorf("keeping both should stay put and name the session: position %d, notice %q", m.Position(), m.notice)
+	}
+	if s, ok := store.GetSession(hash, "phone"); !ok || s.WordIndex != 4 {
+		t.Errorf("phone session = %+v, %v; want word 4", s, ok)
+	}
+	if _, ok := store.Conflict(hash); ok {
+		t.Error("the conflict should be resolved")
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/metcalfc/brr/internal/reader"
+	"github.com/metcalfc/brr/internal/translate"
+)
+
+// translateKeyEnv holds the API key sent to translation servers that need
+// one.
+const translateKeyEnv = "BRR_TRANSLATE_KEY"
+
+// newTranslator returns the translator --translate names, translating
+// into the language to, or nil if backend is empty. The text is taken to
+// be in lang if --lang was given, and otherwise left to the server to
+// detect.
+func newTranslator(backend, to, lang string) (translate.Translator, error) {
+	if backend == "" {
+		return nil, nil
+	}
+	from := "auto"
+	if flagPassed("lang") {
+		from = lang
+	}
+	return translate.New(backend, from, to, os.Getenv(translateKeyEnv))
+}
+
+// sentenceTranslation is the translation of the sentence paused in, shown
+// on the pause dashboard with --translate.
+type sentenceTranslation struct {
+	// start is the absolute index of the sentence's first word.
+	start int
+	text  string
+	err   error
+	// pending is set while the translation is on its way.
+	pending bool
+}
+
+// pausedSentence returns the absolute index of the first word of the
+// sentence around the current word, and the sentence.
+func pausedSentence(r *reader.Reader) (int, string) {
+	start := r.SentenceStart(r.CurrentIndex)
+	return r.Base + start, r.SentenceFrom(start)
+}
+
+// line describes t for the pause dashboard, or is empty if the reader has
+// moved to another sentence since.
+func (t *sentenceTranslation) line(r *reader.Reader) string {
+	if t == nil || len(r.Words) == 0 {
+		return ""
+	}
+	if start, _ := pausedSentence(r); start != t.start {
+		return ""
+	}
+	switch {
+	case t.pending:
+		return "Translating…"
+	case t.err != nil:
+		return "Could not translate: " + t.err.Error()
+	}
+	return strings.TrimSpace(t.text)
+}
@@ -0,0 +1,57 @@
+//go:build !gui
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fakeTranslator translates by putting text in upper case.
+type fakeTranslator struct{ calls int }
+
+func (f *fakeTranslator) Name() string { return "fake" }
+
+func (f *fakeTranslator) Translate(ctx context.Context, text string) (string, error) {
+	f.calls++
+	return strings.ToUpper(text), nil
+}
+
+func TestTranslatePaused(t *testing.T) {
+	tr := &fakeTranslator{}
+	m := newModel("Le chat dort. Il fait beau.", 300, nil, nil)
+	m.translator = tr
+	m.width, m.height = 80, 24
+
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	if m = updated.(model); m.translation != nil {
+		t.Fatal("nothing should be translated while reading")
+	}
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	if m = updated.(model); m.translation == nil || !m.translation.pending || cmd == nil {
+		t.Fatal("pausing should start translating the sentence")
+	}
+	if !strings.Contains(m.View(), "Translating…") {
+		t.Errorf("view while translating:\n%s", m.View())
+	}
+	updated, _ = m.Update(translatedMsg{start: 0, text: "LE CHAT DORT."})
+	if m = updated.(model); !strings.Contains(m.View(), "LE CHAT DORT.") {
+		t.Errorf("the translation should show under the sentence:\n%s", m.View())
+	}
+	// The same sentence is not translated twice.
+	if _, cmd := m.Update(tea.WindowSizeMsg{Width: 80, Height: 24}); cmd != nil {
+		t.Error("an update in the same sentence should not translate again")
+	}
+
+	m.JumpToNextSentence()
+	updated, cmd = m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	if m = updated.(model); m.translation.start != 3 || cmd == nil {
+		t.Fatalf("moving to the next sentence should translate it: %+v", m.translation)
+	}
+	if msg, ok := cmd().(translatedMsg); !ok || msg.text != "IL FAIT BEAU." || tr.calls != 1 {
+		t.Errorf("translating = %+v after %d calls", msg, tr.calls)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"time"
+
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+// Environment variables granting write access to the Zotero Web API, used
+// to note reading progress on items opened with the zotero subcommand.
+const (
+	zoteroKeyEnv  = "ZOTERO_API_KEY"
+	zoteroUserEnv = "ZOTERO_USER_ID"
+)
+
+// zoteroSession remembers the Zotero item being read, and the library it
+// was found in so the zotero subcommand reads it once.
+type zoteroSession struct {
+	item *reader.ZoteroItem
+	// library and query are the parsed arguments of the zotero subcommand,
+	// once parsed is set.
+	library []reader.ZoteroItem
+	query   string
+	parsed  bool
+}
+
+// parse returns the library and query of the zotero subcommand's
+// arguments, reading the library only the first time.
+func (z *zoteroSession) parse(args []string) ([]reader.ZoteroItem, string, error) {
+	if z == nil {
+		return parseZoteroArgs(args)
+	}
+	if !z.parsed {
+		library, query, err := parseZoteroArgs(args)
+		if err != nil {
+			return nil, "", err
+		}
+		z.library, z.query, z.parsed = library, query, true
+	}
+	return z.library, z.query, nil
+}
+
+// zoteroProgressNote formats the reader's position as an HTML item note.
+func zoteroProgressNote(r *reader.Reader, now time.Time) string {
+	current, total := r.Progress()
+	note := fmt.Sprintf("Read with brr on %s: word %d of %d (%d%%)",
+		now.Format("2006-01-02"), current, total, current*100/max(total, 1))
+	if title := r.CurrentChapterTitle(); title != "" {
+		note += ", in “" + title + "”"
+	}
+	return "<p>" + html.EscapeString(note) + ".</p>"
+}
+
+// noteProgress writes the reader's position back to the Zotero item as a
+// note. It does nothing unless an item was opened and the API key and user
+// ID are set.
+func (z *zoteroSession) noteProgress(r *reader.Reader) {
+	if z == nil || z.item == nil || r == nil {
+		return
+	}
+	key, user := os.Getenv(zoteroKeyEnv), os.Getenv(zoteroUserEnv)
+	if key == "" || user == "" {
+		return
+	}
+	if err := reader.AddZoteroNote(user, key, z.item.Key, zoteroProgressNote(r, time.Now())); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
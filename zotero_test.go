@@ -0,0 +1,49 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/metcalfc/brr/internal/reader"
+)
+
+func TestZoteroList(t *testing.T) {
+	library := filepath.Join(t.TempDir(), "library.json")
+	os.WriteFile(library, []byte(`{"items": [{"itemKey": "ABCD1234", "itemType": "book", "title": "Reading at Speed", "date": "2020"}]}`), 0644)
+
+	var out strings.Builder
+	listed, err := runZoteroList(&out, []string{"-library", library}, &zoteroSession{})
+	if err != nil || !listed || !strings.Contains(out.String(), "ABCD1234") {
+		t.Errorf("with no item named the library should be listed: %v, %v, %q", listed, err, out.String())
+	}
+	out.Reset()
+	args := []string{"-library", library, "speed"}
+	z := &zoteroSession{}
+	if listed, err := runZoteroList(&out, args, z); err != nil || listed || out.Len() > 0 {
+		t.Errorf("naming an item should leave it to be read: %v, %v, %q", listed, err, out.String())
+	}
+	// The item is found in the library already read, not read again.
+	os.Remove(library)
+	if _, _, err := zoteroSource(args, z); err == nil || !strings.Contains(err.Error(), "no EPUB, HTML or text attachment") {
+		t.Errorf("the source should find the item in the library read for the list: %v", err)
+	}
+	var src string
+	if _, err := sourceLoader([]string{"zotero", "-library", library}, loadOptions{}, &src); err == nil {
+		t.Error("the zotero source should fail, not list, with no item named")
+	}
+}
+
+func TestZoteroProgressNote(t *testing.T) {
+	r := reader.NewReader("Call me Ishmael. Some years ago", 300)
+	r.SetChapters([]reader.Chapter{{Title: "Loomings & More", WordStart: 0, WordEnd: 6}}, nil)
+	r.SeekTo(2)
+	now := time.Date(2026, 3, 14, 9, 30, 0, 0, time.UTC)
+
+	want := "<p>Read with brr on 2026-03-14: word 3 of 6 (50%), in “Loomings &amp; More”.</p>"
+	if got := zoteroProgressNote(r, now); got != want {
+		t.Errorf("zoteroProgressNote = %q, want %q", got, want)
+	}
+}